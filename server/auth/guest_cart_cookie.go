@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"store.api/config"
+	"store.api/security"
+)
+
+const guestCartCookieName string = "guestCart"
+
+// GuestCartCookie identifies the cart of a user that hasn't logged in, the guest id is signed with the auth key
+type GuestCartCookie struct {
+	key    string
+	domain string
+	maxAge int
+}
+
+func NewGuestCartCookie(c *config.Configuration) *GuestCartCookie {
+	return &GuestCartCookie{
+		key:    c.AuthKey,
+		domain: c.Host,
+		maxAge: int(c.Store.GuestCartTtl),
+	}
+}
+
+// Read returns the guest id stored in the request's cookie, ok is false if there is none or it was tampered with
+func (g *GuestCartCookie) Read(c *gin.Context) (guestId string, ok bool) {
+	signed, err := c.Cookie(guestCartCookieName)
+	if err != nil || len(signed) == 0 {
+		return "", false
+	}
+	return security.VerifySignedValue(g.key, signed)
+}
+
+func (g *GuestCartCookie) Write(c *gin.Context, guestId string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(guestCartCookieName, security.SignValue(g.key, guestId), g.maxAge, "/", g.domain, false, true)
+}
+
+func (g *GuestCartCookie) Clear(c *gin.Context) {
+	c.SetCookie(guestCartCookieName, "", -1, "/", g.domain, false, true)
+}
@@ -17,15 +17,26 @@ import (
 
 const (
 	IDKey string = "id"
+	// unix time of the login as a string, unlike orig_iat it's kept when the token is refreshed
+	LoginTimeKey string = "loginAt"
+
+	loginLockedKey string = "loginLocked"
 )
 
 type JwtMiddleware struct {
 	Middle                *jwt.GinJWTMiddleware
 	AuthorizationCheckers []AuthorizationChecker
+
+	cartService service.CartService
+	guestCart   *GuestCartCookie
 }
 
-func NewJwtMiddleware(c *config.Configuration, authService service.AuthService, userRepo repository.UserRepository) *JwtMiddleware {
-	result := new(JwtMiddleware)
+func NewJwtMiddleware(c *config.Configuration, authService service.AuthService, cartService service.CartService, userRepo repository.UserRepository, guestCart *GuestCartCookie) *JwtMiddleware {
+	result := &JwtMiddleware{
+		cartService: cartService,
+		guestCart:   guestCart,
+	}
+	requireAdminTwoFactor := c.Auth.RequireAdminTwoFactor
 	authMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
 		Realm:          c.JwtRealm,
 		Key:            []byte(c.AuthKey),
@@ -43,7 +54,8 @@ func NewJwtMiddleware(c *config.Configuration, authService service.AuthService,
 		PayloadFunc: func(data interface{}) jwt.MapClaims {
 			if v, ok := data.(*dto.PrivateUserInfo); ok {
 				return jwt.MapClaims{
-					IDKey: v.Id,
+					IDKey:        v.Id,
+					LoginTimeKey: strconv.FormatInt(time.Now().Unix(), 10),
 				}
 			}
 			return jwt.MapClaims{}
@@ -60,21 +72,47 @@ func NewJwtMiddleware(c *config.Configuration, authService service.AuthService,
 			if err := c.BindJSON(&loginVals); err != nil {
 				return "", jwt.ErrMissingLoginValues
 			}
+			loginVals.ClientIp = c.ClientIP()
 
-			result, err := authService.Login(&loginVals)
+			user, err := authService.Login(&loginVals)
 			if err != nil {
+				if err == service.ErrLoginLocked {
+					c.Set(loginLockedKey, true)
+					return nil, err
+				}
+				switch err {
+				case service.ErrTwoFactorRequired, service.ErrInvalidTwoFactorCode, service.ErrUserSuspended, service.ErrPasswordResetRequired:
+					return nil, err
+				}
 				return nil, jwt.ErrFailedAuthentication
 			}
 
-			return result, nil
+			result.mergeGuestCart(c, user)
+			return user, nil
 
 		},
 		Authorizator: func(data interface{}, c *gin.Context) bool {
 			user, ok := data.(*model.User)
-			if !ok {
+			if !ok || user == nil {
+				return false
+			}
+
+			if user.IsSuspended(time.Now()) {
 				return false
 			}
 
+			// tokens issued before an admin forced a reset stop working, the reset endpoint itself is public
+			if user.PasswordResetRequired {
+				return false
+			}
+
+			// admins that haven't enabled 2FA are treated as regular users when the policy requires it
+			if requireAdminTwoFactor && user.IsAdmin && !user.TwoFactorEnabled {
+				demoted := *user
+				demoted.IsAdmin = false
+				user = &demoted
+			}
+
 			for _, checker := range result.AuthorizationCheckers {
 				authorized, matches := checker.Check(c, user)
 				if !matches {
@@ -86,6 +124,9 @@ func NewJwtMiddleware(c *config.Configuration, authService service.AuthService,
 			return false
 		},
 		Unauthorized: func(c *gin.Context, code int, message string) {
+			if c.GetBool(loginLockedKey) {
+				code = http.StatusTooManyRequests
+			}
 			c.AbortWithStatusJSON(code, message)
 		},
 
@@ -121,3 +162,56 @@ func NewJwtMiddleware(c *config.Configuration, authService service.AuthService,
 	result.Middle = authMiddleware
 	return result
 }
+
+// IssueToken responds with a token for data the same way the login handler does,
+// used by login flows that don't go through the Authenticator
+func (m *JwtMiddleware) IssueToken(c *gin.Context, data interface{}) {
+	mw := m.Middle
+	token, expire, err := mw.TokenGenerator(data)
+	if err != nil {
+		mw.Unauthorized(c, http.StatusUnauthorized, mw.HTTPStatusMessageFunc(jwt.ErrFailedTokenCreation, c))
+		return
+	}
+
+	if mw.SendCookie {
+		maxAge := int(mw.CookieMaxAge.Seconds())
+		if mw.CookieSameSite != 0 {
+			c.SetSameSite(mw.CookieSameSite)
+		}
+		c.SetCookie(
+			mw.CookieName,
+			token,
+			maxAge,
+			"/",
+			mw.CookieDomain,
+			mw.SecureCookie,
+			mw.CookieHTTPOnly,
+		)
+	}
+
+	if user, ok := data.(*dto.PrivateUserInfo); ok {
+		m.mergeGuestCart(c, user)
+	}
+
+	mw.LoginResponse(c, http.StatusOK, token, expire)
+}
+
+// mergeGuestCart moves the cart the user filled before logging in into their own cart,
+// a failed merge doesn't fail the login
+func (m *JwtMiddleware) mergeGuestCart(c *gin.Context, user *dto.PrivateUserInfo) {
+	guestId, ok := m.guestCart.Read(c)
+	if !ok {
+		return
+	}
+	m.guestCart.Clear(c)
+
+	userId, err := strconv.ParseUint(user.Id, 10, 32)
+	if err != nil {
+		log.Printf("failed to merge guest cart: %s is an invalid user id", user.Id)
+		return
+	}
+	err = m.cartService.MergeGuest(uint(userId), guestId)
+	if err != nil {
+		log.Printf("failed to merge guest cart of user %d: %s", userId, err)
+	}
+}
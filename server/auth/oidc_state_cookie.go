@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"store.api/config"
+	"store.api/security"
+)
+
+const (
+	oidcStateCookieName string = "oidcState"
+	// as long as the login flow is remembered by the OIDC service
+	oidcStateCookieMaxAge int = 10 * 60
+)
+
+// OidcStateCookie ties an OIDC login to the browser that started it, so that a callback URL of someone else's
+// login can't log the browser into their account. The state is signed with the auth key
+type OidcStateCookie struct {
+	key    string
+	domain string
+}
+
+func NewOidcStateCookie(c *config.Configuration) *OidcStateCookie {
+	return &OidcStateCookie{
+		key:    c.AuthKey,
+		domain: c.Host,
+	}
+}
+
+// Matches checks if the request's cookie holds the given state and wasn't tampered with
+func (o *OidcStateCookie) Matches(c *gin.Context, state string) bool {
+	signed, err := c.Cookie(oidcStateCookieName)
+	if err != nil || len(signed) == 0 {
+		return false
+	}
+	value, ok := security.VerifySignedValue(o.key, signed)
+	return ok && value == state
+}
+
+func (o *OidcStateCookie) Write(c *gin.Context, state string) {
+	// lax, so that the cookie is sent along with the provider's redirect
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookieName, security.SignValue(o.key, state), oidcStateCookieMaxAge, "/", o.domain, false, true)
+}
+
+func (o *OidcStateCookie) Clear(c *gin.Context) {
+	c.SetCookie(oidcStateCookieName, "", -1, "/", o.domain, false, true)
+}
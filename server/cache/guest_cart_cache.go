@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+// GuestCartCache keeps the carts of users that haven't logged in, guest carts are never persisted to the database
+type GuestCartCache interface {
+	Remember(guestId string, cart *model.Cart, ttl time.Duration)
+	Get(guestId string) *model.Cart
+	// Take returns the cart of the guest and forgets it, so that a guest cart can only be merged once
+	Take(guestId string) *model.Cart
+}
+
+type NoGuestCartCache struct {
+}
+
+func (c *NoGuestCartCache) Remember(string, *model.Cart, time.Duration) {
+}
+
+func (c *NoGuestCartCache) Get(string) *model.Cart {
+	return nil
+}
+
+func (c *NoGuestCartCache) Take(string) *model.Cart {
+	return nil
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+	"store.api/model"
+)
+
+type GuestCartValkeyCache struct {
+	client valkey.Client
+}
+
+func NewGuestCartValkeyCache(client valkey.Client) *GuestCartValkeyCache {
+	return &GuestCartValkeyCache{
+		client: client,
+	}
+}
+
+func (c *GuestCartValkeyCache) ToKey(guestId string) string {
+	return fmt.Sprintf("guestCart-%v", guestId)
+}
+
+func (c *GuestCartValkeyCache) Remember(guestId string, cart *model.Cart, ttl time.Duration) {
+	json, err := json.Marshal(cart)
+	if err != nil {
+		panic(err)
+	}
+	err = c.client.Do(context.Background(), c.client.
+		B().
+		Set().
+		Key(c.ToKey(guestId)).
+		Value(string(json)).
+		Px(ttl).
+		Build()).
+		Error()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *GuestCartValkeyCache) Get(guestId string) *model.Cart {
+	get := c.client.Do(context.Background(), c.client.
+		B().
+		Get().
+		Key(c.ToKey(guestId)).
+		Build())
+	return c.decode(get)
+}
+
+func (c *GuestCartValkeyCache) Take(guestId string) *model.Cart {
+	get := c.client.Do(context.Background(), c.client.
+		B().
+		Getdel().
+		Key(c.ToKey(guestId)).
+		Build())
+	return c.decode(get)
+}
+
+func (c *GuestCartValkeyCache) decode(result valkey.ValkeyResult) *model.Cart {
+	err := result.Error()
+	if err != nil {
+		if err == valkey.Nil {
+			return nil
+		}
+		panic(err)
+	}
+	var cart model.Cart
+	err = result.DecodeJSON(&cart)
+	if err != nil {
+		panic(err)
+	}
+	return &cart
+}
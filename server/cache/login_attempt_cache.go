@@ -0,0 +1,28 @@
+package cache
+
+import "time"
+
+type LoginAttemptCache interface {
+	// Fail increments the failure counter for the key, the counter expires after window
+	Fail(key string, window time.Duration) int64
+	Lock(key string, duration time.Duration)
+	LockedFor(key string) time.Duration
+	Reset(key string)
+}
+
+type NoLoginAttemptCache struct {
+}
+
+func (c *NoLoginAttemptCache) Fail(string, time.Duration) int64 {
+	return 0
+}
+
+func (c *NoLoginAttemptCache) Lock(string, time.Duration) {
+}
+
+func (c *NoLoginAttemptCache) LockedFor(string) time.Duration {
+	return 0
+}
+
+func (c *NoLoginAttemptCache) Reset(string) {
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+type LoginAttemptValkeyCache struct {
+	client valkey.Client
+}
+
+func NewLoginAttemptValkeyCache(client valkey.Client) *LoginAttemptValkeyCache {
+	return &LoginAttemptValkeyCache{
+		client: client,
+	}
+}
+
+func (c *LoginAttemptValkeyCache) ToFailuresKey(key string) string {
+	return fmt.Sprintf("loginFailures-%v", key)
+}
+
+func (c *LoginAttemptValkeyCache) ToLockKey(key string) string {
+	return fmt.Sprintf("loginLock-%v", key)
+}
+
+func (c *LoginAttemptValkeyCache) Fail(key string, window time.Duration) int64 {
+	result, err := c.client.Do(context.Background(), c.client.
+		B().
+		Incr().
+		Key(c.ToFailuresKey(key)).
+		Build()).
+		AsInt64()
+	if err != nil {
+		panic(err)
+	}
+
+	if result == 1 {
+		err = c.client.Do(context.Background(), c.client.
+			B().
+			Expire().
+			Key(c.ToFailuresKey(key)).
+			Seconds(int64(window.Seconds())).
+			Build()).
+			Error()
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return result
+}
+
+func (c *LoginAttemptValkeyCache) Lock(key string, duration time.Duration) {
+	err := c.client.Do(context.Background(), c.client.
+		B().
+		Set().
+		Key(c.ToLockKey(key)).
+		Value("1").
+		Px(duration).
+		Build()).
+		Error()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *LoginAttemptValkeyCache) LockedFor(key string) time.Duration {
+	ttl, err := c.client.Do(context.Background(), c.client.
+		B().
+		Pttl().
+		Key(c.ToLockKey(key)).
+		Build()).
+		AsInt64()
+	if err != nil {
+		panic(err)
+	}
+
+	// negative values mean that the key doesn't exist or has no expiration
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Duration(ttl) * time.Millisecond
+}
+
+func (c *LoginAttemptValkeyCache) Reset(key string) {
+	err := c.client.Do(context.Background(), c.client.
+		B().
+		Del().
+		Key(c.ToFailuresKey(key), c.ToLockKey(key)).
+		Build()).Error()
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+type OidcFlowCache interface {
+	Remember(state string, flow *model.OidcFlow, ttl time.Duration)
+	// Take returns the flow for the state and forgets it, so that every state can only be used once
+	Take(state string) *model.OidcFlow
+}
+
+type NoOidcFlowCache struct {
+}
+
+func (c *NoOidcFlowCache) Remember(string, *model.OidcFlow, time.Duration) {
+}
+
+func (c *NoOidcFlowCache) Take(string) *model.OidcFlow {
+	return nil
+}
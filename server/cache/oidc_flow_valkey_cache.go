@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+	"store.api/model"
+)
+
+type OidcFlowValkeyCache struct {
+	client valkey.Client
+}
+
+func NewOidcFlowValkeyCache(client valkey.Client) *OidcFlowValkeyCache {
+	return &OidcFlowValkeyCache{
+		client: client,
+	}
+}
+
+func (c *OidcFlowValkeyCache) ToKey(state string) string {
+	return fmt.Sprintf("oidcFlow-%v", state)
+}
+
+func (c *OidcFlowValkeyCache) Remember(state string, flow *model.OidcFlow, ttl time.Duration) {
+	json, err := json.Marshal(flow)
+	if err != nil {
+		panic(err)
+	}
+	err = c.client.Do(context.Background(), c.client.
+		B().
+		Set().
+		Key(c.ToKey(state)).
+		Value(string(json)).
+		Px(ttl).
+		Build()).
+		Error()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c *OidcFlowValkeyCache) Take(state string) *model.OidcFlow {
+	get := c.client.Do(context.Background(), c.client.
+		B().
+		Getdel().
+		Key(c.ToKey(state)).
+		Build())
+	err := get.Error()
+	if err != nil {
+		if err == valkey.Nil {
+			return nil
+		}
+		panic(err)
+	}
+	var result model.OidcFlow
+	err = get.DecodeJSON(&result)
+	if err != nil {
+		panic(err)
+	}
+	return &result
+}
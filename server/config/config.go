@@ -6,35 +6,103 @@ import (
 	"os"
 
 	"github.com/sethvargo/go-envconfig"
+	"store.api/model"
 )
 
 type StoreConfiguration struct {
-	QueryKeywordLimit uint `json:"queryKeywordLimit" env:"QUERY_KEYWORD_LIMIT"`
+	QueryKeywordLimit uint                  `json:"queryKeywordLimit" env:"QUERY_KEYWORD_LIMIT"`
+	Shipping          ShippingConfiguration `json:"shipping" env:",prefix=SHIPPING_"`
+	// seconds a guest cart is kept after its last change
+	GuestCartTtl uint `json:"guestCartTtl" env:"GUEST_CART_TTL,default=604800"`
+	// ISO 4217 code of the currency all prices are stored in
+	Currency      string                     `json:"currency" env:"CURRENCY,default=USD"`
+	ExchangeRates ExchangeRatesConfiguration `json:"exchangeRates" env:",prefix=EXCHANGE_RATES_"`
+	// seconds between checks for scheduled price changes that are due, 0 disables them
+	PriceScheduleInterval uint `json:"priceScheduleInterval" env:"PRICE_SCHEDULE_INTERVAL,default=60"`
+	// percentage of each marketplace sale the store keeps, unless the seller has their own rate
+	CommissionPercentage float32 `json:"commissionPercentage" env:"COMMISSION_PERCENTAGE,default=10"`
+}
+
+// ExchangeRatesConfiguration is used to load the exchange rates from a feed
+type ExchangeRatesConfiguration struct {
+	// path of a local file or http(s) url of a feed, rates are only managed manually if empty
+	Source string `json:"source" env:"SOURCE"`
+	// seconds between loading the rates again, 0 only loads them on startup
+	RefreshInterval uint `json:"refreshInterval" env:"REFRESH_INTERVAL,default=0"`
+}
+
+// ShippingConfiguration is used to estimate the shipping cost of a cart
+type ShippingConfiguration struct {
+	BaseCost    model.Money `json:"baseCost" env:"BASE_COST,default=5"`
+	PerCardCost model.Money `json:"perCardCost" env:"PER_CARD_COST,default=0"`
+	// carts costing at least this much after discounts ship for free, 0 disables free shipping
+	FreeFrom model.Money `json:"freeFrom" env:"FREE_FROM,default=100"`
 }
 
 type CardsDbConfiguration struct {
 	PageSize uint `json:"pageSize" env:"PAGE_SIZE"`
 }
 
+type UsersDbConfiguration struct {
+	PageSize uint `json:"pageSize" env:"PAGE_SIZE,default=30"`
+}
+
 type DbConfiguration struct {
 	ConnectionUri string               `json:"connectionUri" env:"CONNECTION_URI"`
 	DbName        string               `json:"dbName" env:"NAME"`
 	Cards         CardsDbConfiguration `json:"cards" env:",prefix=CARDS_"`
+	Users         UsersDbConfiguration `json:"users" env:",prefix=USERS_"`
 }
 
 type CacheConfiguration struct {
 	ConnectionUri string `json:"connectionUri" env:"CONNECTION_URI"`
 }
 
+// all durations are in seconds
+type LockoutConfiguration struct {
+	MaxUsernameFailures uint `json:"maxUsernameFailures" env:"MAX_USERNAME_FAILURES,default=5"`
+	MaxIpFailures       uint `json:"maxIpFailures" env:"MAX_IP_FAILURES,default=20"`
+	FailureWindow       uint `json:"failureWindow" env:"FAILURE_WINDOW,default=900"`
+	BaseLockout         uint `json:"baseLockout" env:"BASE_LOCKOUT,default=30"`
+	MaxLockout          uint `json:"maxLockout" env:"MAX_LOCKOUT,default=3600"`
+}
+
+type AuthConfiguration struct {
+	RequireAdminTwoFactor bool                 `json:"requireAdminTwoFactor" env:"REQUIRE_ADMIN_TWO_FACTOR"`
+	RecoveryCodeCount     uint                 `json:"recoveryCodeCount" env:"RECOVERY_CODE_COUNT,default=10"`
+	Lockout               LockoutConfiguration `json:"lockout" env:",prefix=LOCKOUT_"`
+	// space separated IPs or CIDRs of the reverse proxies whose X-Forwarded-For header is trusted,
+	// the connecting address is used as the client's IP if empty
+	TrustedProxies string `json:"trustedProxies" env:"TRUSTED_PROXIES"`
+}
+
+type OidcConfiguration struct {
+	Enabled      bool   `json:"enabled" env:"ENABLED"`
+	Issuer       string `json:"issuer" env:"ISSUER"`
+	ClientId     string `json:"clientId" env:"CLIENT_ID"`
+	ClientSecret string `json:"clientSecret" env:"CLIENT_SECRET"`
+	RedirectUrl  string `json:"redirectUrl" env:"REDIRECT_URL"`
+	Scopes       string `json:"scopes" env:"SCOPES,default=openid email profile"`
+}
+
+// all durations are in seconds
+type AccountConfiguration struct {
+	EmailTokenTtl         uint `json:"emailTokenTtl" env:"EMAIL_TOKEN_TTL,default=86400"`
+	PasswordResetTokenTtl uint `json:"passwordResetTokenTtl" env:"PASSWORD_RESET_TOKEN_TTL,default=86400"`
+}
+
 type Configuration struct {
-	Host       string             `json:"host" env:"HOST"`
-	Port       string             `json:"port" env:"PORT,required"`
-	Db         DbConfiguration    `json:"db" env:",prefix=DB_"`
-	Cache      CacheConfiguration `json:"cache" env:",prefix=CACHE_"`
-	QueryCache CacheConfiguration `json:"queryCache" env:",prefix=QUERY_CACHE_"`
-	Store      StoreConfiguration `json:"store" env:",prefix=STORE_"`
-	AuthKey    string             `json:"authKey" env:"AUTH_KEY"`
-	JwtRealm   string             `json:"jwtRealm" env:"JWT_REALM"`
+	Host       string               `json:"host" env:"HOST"`
+	Port       string               `json:"port" env:"PORT,required"`
+	Db         DbConfiguration      `json:"db" env:",prefix=DB_"`
+	Cache      CacheConfiguration   `json:"cache" env:",prefix=CACHE_"`
+	QueryCache CacheConfiguration   `json:"queryCache" env:",prefix=QUERY_CACHE_"`
+	Store      StoreConfiguration   `json:"store" env:",prefix=STORE_"`
+	AuthKey    string               `json:"authKey" env:"AUTH_KEY"`
+	JwtRealm   string               `json:"jwtRealm" env:"JWT_REALM"`
+	Auth       AuthConfiguration    `json:"auth" env:",prefix=AUTH_"`
+	Oidc       OidcConfiguration    `json:"oidc" env:",prefix=OIDC_"`
+	Account    AccountConfiguration `json:"account" env:",prefix=ACCOUNT_"`
 }
 
 func ReadConfig(path string) (*Configuration, error) {
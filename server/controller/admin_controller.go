@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/query"
+	"store.api/service"
+)
+
+type AdminController struct {
+	authService service.AuthService
+	userService service.UserService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *AdminController) ConfigureApi(r *gin.RouterGroup) {
+	con.group = r.Group("/admin")
+	con.group.Use(con.auth)
+	{
+		con.group.GET("/failed-logins", con.FailedLogins)
+		con.group.GET("/users", con.QueryUsers)
+		con.group.POST("/users/:id/unlock", con.Unlock)
+		con.group.POST("/users/:id/verify", con.Verify)
+		con.group.POST("/users/:id/suspend", con.Suspend)
+		con.group.POST("/users/:id/unsuspend", con.Unsuspend)
+		con.group.POST("/users/:id/promote", con.Promote)
+		con.group.POST("/users/:id/demote", con.Demote)
+		con.group.POST("/users/:id/password-reset", con.ForcePasswordReset)
+	}
+
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		Build()
+}
+
+func (con *AdminController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewAdminController(authService service.AuthService, userService service.UserService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *AdminController {
+	return &AdminController{
+		authService:   authService,
+		userService:   userService,
+		auth:          auth,
+		claimExtractF: claimExtractF,
+	}
+}
+
+// FailedLogins			godoc
+// @Summary				Fetch failed logins
+// @Description			Fetches the most recent failed login attempts, optionally filtered by username
+// @Param				Authorization header string false "Authenticator"
+// @Param				username query string false "Username"
+// @Tags				Admin
+// @Success				200 {object} dto.GetFailedLogin[]
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/admin/failed-logins [get]
+func (con *AdminController) FailedLogins(c *gin.Context) {
+	var result []*dto.GetFailedLogin = con.authService.FailedLogins(c.Query("username"))
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Unlock				godoc
+// @Summary				Unlock user
+// @Description			Clears the failed login counters and lockout of a user
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Tags				Admin
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/unlock [post]
+func (con *AdminController) Unlock(c *gin.Context) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid user id", p), true)
+		return
+	}
+
+	err = con.authService.UnlockUser(uint(id))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusNotFound, userNotFound(uint(id)), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// QueryUsers			godoc
+// @Summary				Fetch users
+// @Description			Fetches a page of users, optionally filtered by a username or email search
+// @Param				Authorization header string false "Authenticator"
+// @Param				query query query.UserQuery false "User query"
+// @Tags				Admin
+// @Success				200 {object} service.UserQueryResult
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/admin/users [get]
+func (con *AdminController) QueryUsers(c *gin.Context) {
+	var query query.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil || query.Page == 0 {
+		AbortWithError(c, http.StatusBadRequest, errors.New("invalid user query"), true)
+		return
+	}
+	query.Search = strings.TrimSpace(query.Search)
+
+	result := con.userService.Query(&query)
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Verify				godoc
+// @Summary				Verify user
+// @Description			Marks the user as verified
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Tags				Admin
+// @Success				200 {object} dto.GetUser
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/verify [post]
+func (con *AdminController) Verify(c *gin.Context) {
+	con.userAction(c, func(adminId uint, userId uint) (*dto.GetUser, error) {
+		return con.userService.Verify(userId)
+	})
+}
+
+// Suspend				godoc
+// @Summary				Suspend user
+// @Description			Suspends the user until the given time, a suspension without an end is a ban; suspended users can't log in or access the API
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Param				suspension body dto.Suspension true "Suspension details"
+// @Tags				Admin
+// @Success				200 {object} dto.GetUser
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/suspend [post]
+func (con *AdminController) Suspend(c *gin.Context) {
+	var suspension dto.Suspension
+	if err := c.BindJSON(&suspension); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	con.userAction(c, func(adminId uint, userId uint) (*dto.GetUser, error) {
+		return con.userService.Suspend(adminId, userId, &suspension)
+	})
+}
+
+// Unsuspend			godoc
+// @Summary				Unsuspend user
+// @Description			Lifts the user's suspension or ban
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Tags				Admin
+// @Success				200 {object} dto.GetUser
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/unsuspend [post]
+func (con *AdminController) Unsuspend(c *gin.Context) {
+	con.userAction(c, func(adminId uint, userId uint) (*dto.GetUser, error) {
+		return con.userService.Unsuspend(userId)
+	})
+}
+
+// Promote				godoc
+// @Summary				Promote user
+// @Description			Makes the user an admin
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Tags				Admin
+// @Success				200 {object} dto.GetUser
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/promote [post]
+func (con *AdminController) Promote(c *gin.Context) {
+	con.userAction(c, func(adminId uint, userId uint) (*dto.GetUser, error) {
+		return con.userService.SetAdmin(adminId, userId, true)
+	})
+}
+
+// Demote				godoc
+// @Summary				Demote user
+// @Description			Removes the user's admin rights, admins can't demote themselves
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Tags				Admin
+// @Success				200 {object} dto.GetUser
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/demote [post]
+func (con *AdminController) Demote(c *gin.Context) {
+	con.userAction(c, func(adminId uint, userId uint) (*dto.GetUser, error) {
+		return con.userService.SetAdmin(adminId, userId, false)
+	})
+}
+
+// ForcePasswordReset	godoc
+// @Summary				Force password reset
+// @Description			Blocks password logins for the user and emails them a password reset token
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "User ID"
+// @Tags				Admin
+// @Success				200 {object} dto.GetUser
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/admin/users/{id}/password-reset [post]
+func (con *AdminController) ForcePasswordReset(c *gin.Context) {
+	con.userAction(c, func(adminId uint, userId uint) (*dto.GetUser, error) {
+		return con.userService.ForcePasswordReset(userId)
+	})
+}
+
+// userAction applies the action to the user from the path and responds with the updated user
+func (con *AdminController) userAction(c *gin.Context, action func(adminId uint, userId uint) (*dto.GetUser, error)) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	adminId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid user id", p), true)
+		return
+	}
+
+	result, err := action(uint(adminId), uint(id))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusNotFound, userNotFound(uint(id)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
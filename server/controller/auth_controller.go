@@ -1,31 +1,70 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"store.api/auth"
 	"store.api/dto"
+	"store.api/model"
 	"store.api/service"
 )
 
 type AuthController struct {
-	authService  service.AuthService
-	loginHandler gin.HandlerFunc
-	group        *gin.RouterGroup
+	authService   service.AuthService
+	oidcService   service.OidcService
+	oidcState     *auth.OidcStateCookie
+	loginHandler  gin.HandlerFunc
+	issueToken    func(*gin.Context, interface{})
+	auth          gin.HandlerFunc
+	claimExtractF func(string, *gin.Context) (string, error)
+
+	group       *gin.RouterGroup
+	authChecker auth.AuthorizationChecker
 }
 
-func (con AuthController) ConfigureApi(r *gin.RouterGroup) {
+func (con *AuthController) ConfigureApi(r *gin.RouterGroup) {
 	con.group = r.Group("/auth")
 	{
 		con.group.POST("/register", con.Register)
 		con.group.POST("/login", con.Login)
+		con.group.POST("/password-reset", con.ResetPassword)
+		con.group.GET("/oidc/login", con.OidcLogin)
+		con.group.GET("/oidc/callback", con.OidcCallback)
+		con.group.POST("/oidc/2fa", con.OidcTwoFactor)
+	}
+
+	twoFactor := con.group.Group("/2fa")
+	twoFactor.Use(con.auth)
+	{
+		twoFactor.POST("/enroll", con.EnrollTwoFactor)
+		twoFactor.POST("/confirm", con.ConfirmTwoFactor)
+		twoFactor.POST("/disable", con.DisableTwoFactor)
 	}
+
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(twoFactor.BasePath() + "*").
+		ForAnyMethod().
+		PermitAll().
+		Build()
+}
+
+func (con *AuthController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
 }
 
-func NewAuthController(authService service.AuthService, loginHandler gin.HandlerFunc) *AuthController {
+func NewAuthController(authService service.AuthService, oidcService service.OidcService, oidcState *auth.OidcStateCookie, loginHandler gin.HandlerFunc, issueToken func(*gin.Context, interface{}), auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *AuthController {
 	return &AuthController{
-		authService:  authService,
-		loginHandler: loginHandler,
+		authService:   authService,
+		oidcService:   oidcService,
+		oidcState:     oidcState,
+		loginHandler:  loginHandler,
+		issueToken:    issueToken,
+		auth:          auth,
+		claimExtractF: claimExtractF,
 	}
 }
 
@@ -57,13 +96,279 @@ func (con *AuthController) Register(c *gin.Context) {
 
 // UserLogin			godoc
 // @Summary				Logs in the user
-// @Description			Checks the user data and returns a jwt token on correct Login
+// @Description			Checks the user data and returns a jwt token on correct Login, users with 2FA enabled must also provide a TOTP or recovery code
 // @Param				details body dto.LoginDetails true "Login details"
 // @Tags				Auth
 // @Success				200
 // @Failure				400 {object} string
 // @Failure				401 {object} string
 // @Router				/auth/login [post]
-func (con AuthController) Login(c *gin.Context) {
+func (con *AuthController) Login(c *gin.Context) {
 	con.loginHandler(c)
 }
+
+// ResetPassword		godoc
+// @Summary				Reset password
+// @Description			Sets a new password using the reset token sent by email
+// @Param				details body dto.PasswordReset true "Reset token and new password"
+// @Tags				Auth
+// @Success				200
+// @Failure				400 {object} string
+// @Router				/auth/password-reset [post]
+func (con *AuthController) ResetPassword(c *gin.Context) {
+	var reset dto.PasswordReset
+	if err := c.BindJSON(&reset); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, false)
+		return
+	}
+
+	err := con.authService.ResetPassword(&reset)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// OidcLogin			godoc
+// @Summary				Start OIDC login
+// @Description			Redirects the user to the identity provider's authorization endpoint, the login is tied to the browser with a cookie
+// @Tags				Auth
+// @Success				302
+// @Failure				404 {object} string
+// @Router				/auth/oidc/login [get]
+func (con *AuthController) OidcLogin(c *gin.Context) {
+	url, state, err := con.oidcService.AuthorizationUrl()
+	if err != nil {
+		if err == service.ErrOidcDisabled {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadGateway, err, false)
+		return
+	}
+
+	con.oidcState.Write(c, state)
+	c.Redirect(http.StatusFound, url)
+}
+
+// OidcCallback			godoc
+// @Summary				Finish OIDC login
+// @Description			Exchanges the authorization code, links or creates the user and returns a jwt token, users with 2FA enabled must then post the state and their code to /auth/oidc/2fa
+// @Param				code query string true "Authorization code"
+// @Param				state query string true "Login state"
+// @Tags				Auth
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/auth/oidc/callback [get]
+func (con *AuthController) OidcCallback(c *gin.Context) {
+	if errMessage := c.Query("error"); len(errMessage) > 0 {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("identity provider returned %s", errMessage), true)
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if len(code) == 0 || len(state) == 0 {
+		AbortWithError(c, http.StatusBadRequest, errors.New("missing code or state"), true)
+		return
+	}
+	if !con.oidcState.Matches(c, state) {
+		AbortWithError(c, http.StatusBadRequest, errors.New("the OIDC login was started in another browser"), true)
+		return
+	}
+
+	user, err := con.oidcService.Callback(code, state)
+	if err != nil {
+		if err == service.ErrOidcDisabled {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrOidcInvalidState {
+			AbortWithError(c, http.StatusBadRequest, err, true)
+			return
+		}
+		if err == service.ErrTwoFactorRequired {
+			AbortWithError(c, http.StatusUnauthorized, err, true)
+			return
+		}
+		if err == service.ErrUserSuspended || err == service.ErrPasswordResetRequired {
+			AbortWithError(c, http.StatusForbidden, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusUnauthorized, errors.New("failed to authenticate with the identity provider"), true)
+		return
+	}
+
+	con.oidcState.Clear(c)
+	con.issueToken(c, user)
+}
+
+// OidcTwoFactor		godoc
+// @Summary				Finish OIDC login with 2FA
+// @Description			Checks the TOTP or recovery code of a user whose OIDC login required 2FA and returns a jwt token, the state can only be used once
+// @Param				details body dto.OidcTwoFactor true "Login state and code"
+// @Tags				Auth
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/auth/oidc/2fa [post]
+func (con *AuthController) OidcTwoFactor(c *gin.Context) {
+	var details dto.OidcTwoFactor
+	if err := c.BindJSON(&details); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, false)
+		return
+	}
+
+	if !con.oidcState.Matches(c, details.State) {
+		AbortWithError(c, http.StatusBadRequest, errors.New("the OIDC login was started in another browser"), true)
+		return
+	}
+
+	user, err := con.oidcService.CompleteTwoFactor(&details)
+	if err != nil {
+		if err == service.ErrOidcDisabled {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrOidcInvalidState {
+			AbortWithError(c, http.StatusBadRequest, err, true)
+			return
+		}
+		if err == service.ErrInvalidTwoFactorCode {
+			AbortWithError(c, http.StatusUnauthorized, err, true)
+			return
+		}
+		if err == service.ErrUserSuspended || err == service.ErrPasswordResetRequired {
+			AbortWithError(c, http.StatusForbidden, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, err, false)
+		return
+	}
+
+	con.oidcState.Clear(c)
+	con.issueToken(c, user)
+}
+
+// EnrollTwoFactor		godoc
+// @Summary				Start 2FA enrollment
+// @Description			Generates a new TOTP secret and recovery codes for the user, 2FA is enabled only after confirming a code
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Auth
+// @Success				200 {object} dto.TwoFactorEnrollment
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Router				/auth/2fa/enroll [post]
+func (con *AuthController) EnrollTwoFactor(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	result, err := con.authService.EnrollTwoFactor(uint(userId))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// ConfirmTwoFactor		godoc
+// @Summary				Confirm 2FA enrollment
+// @Description			Enables 2FA for the user after checking a code generated from the enrolled secret
+// @Param				Authorization header string false "Authenticator"
+// @Param				code body dto.TwoFactorCode true "TOTP code"
+// @Tags				Auth
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Router				/auth/2fa/confirm [post]
+func (con *AuthController) ConfirmTwoFactor(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var code dto.TwoFactorCode
+	if err := c.BindJSON(&code); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	err = con.authService.ConfirmTwoFactor(uint(userId), &code)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DisableTwoFactor		godoc
+// @Summary				Disable 2FA
+// @Description			Disables 2FA for the user, requires a TOTP or recovery code
+// @Param				Authorization header string false "Authenticator"
+// @Param				code body dto.TwoFactorCode true "TOTP or recovery code"
+// @Tags				Auth
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Router				/auth/2fa/disable [post]
+func (con *AuthController) DisableTwoFactor(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var code dto.TwoFactorCode
+	if err := c.BindJSON(&code); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	err = con.authService.DisableTwoFactor(uint(userId), &code)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -4,18 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"store.api/auth"
 	"store.api/config"
 	"store.api/dto"
 	"store.api/model"
 	"store.api/query"
 	"store.api/service"
-
-	urlquery "github.com/google/go-querystring/query"
 )
 
 type CardController struct {
@@ -41,6 +41,12 @@ func (con *CardController) ConfigureApi(r *gin.RouterGroup) {
 		con.group.PATCH("/:id", con.Update)
 		con.group.PATCH("/price/:id", con.UpdatePrice)
 		con.group.PATCH("/stocked/:id", con.UpdateInStockAmount)
+		con.group.PATCH("/price", con.UpdatePrices)
+		con.group.PATCH("/price/scale", con.ScalePrices)
+		con.group.PATCH("/stocked", con.UpdateInStockAmounts)
+		con.group.GET("/stocked/:id/movements", con.StockMovements)
+		con.group.GET("/low-stock", con.LowStock)
+		con.group.PATCH("/expansions/:id/threshold", con.UpdateExpansionThreshold)
 	}
 
 	path := con.group.BasePath() + "*"
@@ -58,6 +64,16 @@ func (con *CardController) ConfigureApi(r *gin.RouterGroup) {
 		Permit(func(user *model.User) bool {
 			return user.IsAdmin && user.Verified
 		}).
+		ForPath(con.group.BasePath() + "/stocked/*").
+		ForMethod("GET").
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		ForPath(con.group.BasePath() + "/low-stock").
+		ForMethod("GET").
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
 		Build()
 }
 
@@ -123,6 +139,7 @@ func (con *CardController) Create(c *gin.Context) {
 // @Summary				Fetch card by id
 // @Description			Fetches a card by it's id
 // @Param				id path int true "Card ID"
+// @Param				currency query string false "Currency of the price, the store's currency if empty"
 // @Tags				Card
 // @Success				200 {object} dto.GetCard
 // @Failure				400 {object} string
@@ -136,12 +153,16 @@ func (con *CardController) ById(c *gin.Context) {
 		return
 	}
 
-	card, err := con.cardService.GetById(uint(id))
+	card, err := con.cardService.GetById(uint(id), c.Query("currency"))
 	if err != nil {
 		if err == service.ErrCardNotFound {
 			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %v", id), true)
 			return
 		}
+		if err == service.ErrUnknownCurrency {
+			AbortWithError(c, http.StatusBadRequest, err, true)
+			return
+		}
 		panic(err)
 	}
 
@@ -168,21 +189,20 @@ func (con *CardController) Query(c *gin.Context) {
 		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("too many keywords (limit: %d)", con.config.Store.QueryKeywordLimit), true)
 		return
 	}
-	vals, err := urlquery.Values(query)
+	query.Raw = query.Encode()
+
+	result, err := con.cardService.Query(&query)
 	if err != nil {
-		// * should never happen
-		panic(err)
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
 	}
-	query.Raw = vals.Encode()
-
-	result := con.cardService.Query(&query)
 
 	c.IndentedJSON(http.StatusOK, result)
 }
 
 // UpdateCard			godoc
 // @Summary				Update card
-// @Description			Updates an existing card
+// @Description			Updates an existing card, a changed stock is recorded in the card's stock ledger as a correction
 // @Param				Authorization header string false "Authenticator"
 // @Param				id path int true "Card ID"
 // @Param				card body dto.PostCard true "new card data"
@@ -207,7 +227,19 @@ func (con *CardController) Update(c *gin.Context) {
 		return
 	}
 
-	card, err := con.cardService.Update(&newData, uint(id))
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	card, err := con.cardService.Update(&newData, uint(id), uint(userId))
 	if err != nil {
 		if err == service.ErrCardNotFound {
 			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %v", id), true)
@@ -262,7 +294,7 @@ func (con *CardController) UpdatePrice(c *gin.Context) {
 
 // UpdateInStockAmount	godoc
 // @Summary				Update card stocked amount
-// @Description			Updates the amount of cards stocked
+// @Description			Updates the amount of cards stocked, the change is recorded in the card's stock movements as a correction unless another kind is given
 // @Param				Authorization header string false "Authenticator"
 // @Param				id path int true "Card ID"
 // @Param				price body dto.StockedAmountUpdate true "new card stock amount"
@@ -287,20 +319,228 @@ func (con *CardController) UpdateInStockAmount(c *gin.Context) {
 		return
 	}
 
-	card, err := con.cardService.UpdateInStockAmount(uint(id), &newAmount)
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	card, err := con.cardService.UpdateInStockAmount(uint(id), &newAmount, uint(userId))
 	if err != nil {
 		if err == service.ErrCardNotFound {
 			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %v", id), true)
 			return
 		}
-		panic(err)
-		// AbortWithError(c, http.StatusBadRequest, err, true)
-		// return
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
 	}
 
 	c.IndentedJSON(http.StatusOK, card)
 }
 
+// UpdatePrices		godoc
+// @Summary				Update card prices in bulk
+// @Description			Sets the prices of many cards in one transaction, none are changed if any of the cards doesn't exist
+// @Param				Authorization header string false "Authenticator"
+// @Param				prices body dto.BulkPriceUpdate true "new card prices"
+// @Tags				Card
+// @Success				200 {object} dto.GetCard[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/card/price [patch]
+func (con *CardController) UpdatePrices(c *gin.Context) {
+	var update dto.BulkPriceUpdate
+	if err := c.BindJSON(&update); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	cards, err := con.cardService.UpdatePrices(&update)
+	if err != nil {
+		abortBulkUpdate(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, cards)
+}
+
+// ScalePrices			godoc
+// @Summary				Change card prices by a percentage
+// @Description			Changes the prices of all cards matching the filter by a percentage in one transaction, an empty filter has to be confirmed with all. Prices in the filter are in the store's currency, none are changed if any card would cost 0
+// @Param				Authorization header string false "Authenticator"
+// @Param				scale body dto.BulkPriceScale true "card filter and percentage"
+// @Tags				Card
+// @Success				200 {object} dto.GetCard[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/card/price/scale [patch]
+func (con *CardController) ScalePrices(c *gin.Context) {
+	var scale dto.BulkPriceScale
+	if err := c.BindJSON(&scale); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	values, err := url.ParseQuery(scale.Filter)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, errors.New("invalid card filter"), true)
+		return
+	}
+	var filter query.CardQuery
+	if err := binding.MapFormWithTag(&filter, values, "form"); err != nil {
+		AbortWithError(c, http.StatusBadRequest, errors.New("invalid card filter"), true)
+		return
+	}
+	filter.Keywords = strings.Join(strings.Fields(filter.Keywords), " ")
+
+	cards, err := con.cardService.ScalePrices(&filter, &scale)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, cards)
+}
+
+// UpdateInStockAmounts	godoc
+// @Summary				Update card stocked amounts in bulk
+// @Description			Sets or adjusts the stocked amounts of many cards in one transaction, none are changed if any of the cards doesn't exist or would go out of stock below 0. Every change is recorded in the card's stock movements
+// @Param				Authorization header string false "Authenticator"
+// @Param				stock body dto.BulkStockUpdate true "new card stock amounts"
+// @Tags				Card
+// @Success				200 {object} dto.GetCard[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/card/stocked [patch]
+func (con *CardController) UpdateInStockAmounts(c *gin.Context) {
+	var update dto.BulkStockUpdate
+	if err := c.BindJSON(&update); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	cards, err := con.cardService.UpdateInStockAmounts(&update, uint(userId))
+	if err != nil {
+		abortBulkUpdate(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, cards)
+}
+
+// StockMovements		godoc
+// @Summary				Fetch card stock movements
+// @Description			Fetches the card's stock ledger, newest first. Every change of its stocked amount is a movement with the resulting balance
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Card ID"
+// @Tags				Card
+// @Success				200 {object} dto.GetStockMovement[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/card/stocked/{id}/movements [get]
+func (con *CardController) StockMovements(c *gin.Context) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid card id", p), true)
+		return
+	}
+
+	movements, err := con.cardService.StockMovements(uint(id))
+	if err != nil {
+		AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %v", id), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, movements)
+}
+
+// LowStock			godoc
+// @Summary				Fetch low stock report
+// @Description			Fetches the cards whose stock is at or below their low stock threshold, or their expansion's default one, the emptiest first
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Card
+// @Success				200 {object} dto.GetLowStockCard[]
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/card/low-stock [get]
+func (con *CardController) LowStock(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, con.cardService.LowStock())
+}
+
+// UpdateExpansionThreshold	godoc
+// @Summary				Update expansion low stock threshold
+// @Description			Sets the default low stock threshold of the expansion's cards, cards with their own threshold keep it. Admins are mailed when a card's stock falls to its threshold
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path string true "Expansion ID"
+// @Param				threshold body dto.LowStockThresholdUpdate true "new threshold"
+// @Tags				Card
+// @Success				200 {object} model.Expansion
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/card/expansions/{id}/threshold [patch]
+func (con *CardController) UpdateExpansionThreshold(c *gin.Context) {
+	id := c.Param("id")
+
+	var update dto.LowStockThresholdUpdate
+	if err := c.BindJSON(&update); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	expansion, err := con.cardService.UpdateExpansionThreshold(id, &update)
+	if err != nil {
+		if err == service.ErrExpansionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no expansion with id %s", id), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, expansion)
+}
+
+// abortBulkUpdate aborts with 404 for missing cards, 409 for stock that would go negative and 400 otherwise
+func abortBulkUpdate(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrCardNotFound) {
+		AbortWithError(c, http.StatusNotFound, err, true)
+		return
+	}
+	if errors.Is(err, service.ErrInsufficientStock) {
+		AbortWithError(c, http.StatusConflict, err, true)
+		return
+	}
+	AbortWithError(c, http.StatusBadRequest, err, true)
+}
+
 // Languages			godoc
 // @Summary				Get all languages
 // @Description			Fetches all available languages
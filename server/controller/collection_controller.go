@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 	"store.api/auth"
 	"store.api/dto"
 	"store.api/model"
+	"store.api/query"
 	"store.api/service"
 )
 
@@ -27,12 +29,33 @@ func (con *CollectionController) ConfigureApi(r *gin.RouterGroup) {
 	{
 		con.group.GET("/all", con.All)
 		con.group.GET("/:id", con.ById)
+		con.group.GET("/:id/value", con.Value)
+		con.group.GET("/:id/export", con.Export)
+		con.group.GET("/:id/diff/:otherId", con.Diff)
+		con.group.GET("/:id/needs/:otherId", con.Needs)
 		con.group.POST("", con.Create)
 		con.group.POST("/:collectionId", con.EditSlot)
+		con.group.POST("/:collectionId/batch", con.EditSlots)
+		con.group.POST("/:collectionId/import", con.Import)
+		con.group.POST("/:collectionId/to-cart", con.ToCart)
+		con.group.POST("/:collectionId/diff", con.DiffDecklist)
+		con.group.POST("/:collectionId/needs", con.NeedsDecklist)
+		con.group.POST("/:collectionId/copy", con.Copy)
+		con.group.POST("/:collectionId/merge", con.Merge)
+		con.group.POST("/folder", con.CreateFolder)
+		con.group.PATCH("/folder/:id", con.UpdateFolder)
+		con.group.DELETE("/folder/:id", con.DeleteFolder)
 		con.group.DELETE("/:id", con.Delete)
 		con.group.PATCH("/:id", con.UpdateInfo)
 	}
 
+	// shared collections are visible without logging in
+	public := r.Group("/collection")
+	{
+		public.GET("/shared/:token", con.Shared)
+		public.GET("/public/:username", con.Public)
+	}
+
 	con.authChecker = auth.NewAuthorizationCheckerBuilder().
 		ForPath(con.group.BasePath() + "*").
 		ForMethod("*").
@@ -54,11 +77,14 @@ func NewCollectionController(collectionService service.CollectionService, auth g
 
 // All					godoc
 // @Summary				Fetch all collections
-// @Description			Fetches all the user's collections
+// @Description			Fetches the user's folders and collections as a tree, starting from the given folder or the top level. With a tag only collections with that tag and the folders that contain them are included
 // @Param				Authorization header string false "Authenticator"
+// @Param				query query query.CollectionQuery false "Collection query"
 // @Tags				Collection
-// @Success				200 {object} dto.GetCollection[]
+// @Success				200 {object} dto.CollectionTree
+// @Failure				400 {object} string
 // @Failure				401 {object} string
+// @Failure				404 {object} string
 // @Router				/collection/all [get]
 func (con *CollectionController) All(c *gin.Context) {
 	rawId, err := con.claimExtractF(auth.IDKey, c)
@@ -72,7 +98,20 @@ func (con *CollectionController) All(c *gin.Context) {
 		return
 	}
 
-	collections := con.collectionService.GetAll(uint(userId))
+	var query query.CollectionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		AbortWithError(c, http.StatusBadRequest, errors.New("invalid collection query"), true)
+		return
+	}
+
+	collections, err := con.collectionService.GetAll(uint(userId), &query)
+	if err != nil {
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no folder with id %d", *query.Folder), true)
+			return
+		}
+		panic(err)
+	}
 
 	c.IndentedJSON(http.StatusOK, collections)
 }
@@ -111,6 +150,10 @@ func (con *CollectionController) Create(c *gin.Context) {
 			c.AbortWithStatus(http.StatusForbidden)
 			return
 		}
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
 		if err == service.ErrUserNotFound {
 			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
 			return
@@ -181,6 +224,69 @@ func (con *CollectionController) EditSlot(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, collection)
 }
 
+// EditSlots			godoc
+// @Summary				Edit many collection slots
+// @Description			Adds, removes or alters many collection slots at once. Either all changes are applied or none are
+// @Param				Authorization header string false "Authenticator"
+// @Param				collectionId path int true "Collection ID"
+// @Param				collectionSlots body dto.PostCollectionSlots true "collection slot changes"
+// @Tags				Collection
+// @Success				200 {object} dto.GetCollection
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/batch [post]
+func (con *CollectionController) EditSlots(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("collectionId")
+	collectionId, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	var newSlots dto.PostCollectionSlots
+	if err := c.BindJSON(&newSlots); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	collection, err := con.collectionService.EditSlots(&newSlots, uint(collectionId), uint(userId))
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", collectionId), true)
+			return
+		}
+		if errors.Is(err, service.ErrCardNotFound) {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, collection)
+}
+
 // ById					godoc
 // @Summary				Fetch collection by id
 // @Description			Fetches a collection by it's id
@@ -301,6 +407,10 @@ func (con *CollectionController) UpdateInfo(c *gin.Context) {
 
 	collection, err := con.collectionService.UpdateInfo(&newData, uint(id), uint(userId))
 	if err != nil {
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
 		if err == service.ErrNotVerified {
 			c.AbortWithStatus(http.StatusForbidden)
 			return
@@ -319,3 +429,695 @@ func (con *CollectionController) UpdateInfo(c *gin.Context) {
 
 	c.IndentedJSON(http.StatusOK, collection)
 }
+
+// Value				godoc
+// @Summary				Fetch collection value
+// @Description			Sums the current price of the collection's cards, breaks it down by expansion, type and language and reports the change over the last 7 and 30 days
+// @Param				id path int true "Collection ID"
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Collection
+// @Success				200 {object} dto.CollectionValue
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{id}/value [get]
+func (con *CollectionController) Value(c *gin.Context) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	value, err := con.collectionService.Value(uint(id), uint(userId))
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, value)
+}
+
+// Import				godoc
+// @Summary				Import decklist
+// @Description			Adds the cards of a decklist (text, arena or mtgo .dek) to the collection. Lines are resolved by english card name, expansion and collector number, lines that can't be resolved are reported and skipped
+// @Param				collectionId path int true "Collection ID"
+// @Param				Authorization header string false "Authenticator"
+// @Param				decklist body dto.DecklistImport true "decklist"
+// @Tags				Collection
+// @Success				200 {object} dto.DecklistImportResult
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/import [post]
+func (con *CollectionController) Import(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("collectionId")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	var list dto.DecklistImport
+	if err := c.BindJSON(&list); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.collectionService.Import(uint(id), uint(userId), &list)
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Export				godoc
+// @Summary				Export decklist
+// @Description			Writes the collection out as a decklist file
+// @Param				id path int true "Collection ID"
+// @Param				format query string false "text, arena or mtgo, defaults to text"
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Collection
+// @Produce				plain
+// @Produce				xml
+// @Success				200 {object} string
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{id}/export [get]
+func (con *CollectionController) Export(c *gin.Context) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	format := c.DefaultQuery("format", "text")
+	export, err := con.collectionService.Export(uint(id), uint(userId), format)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		if err == service.ErrUnknownDecklistFormat {
+			AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a known decklist format", format), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+	c.Data(http.StatusOK, export.ContentType, export.Data)
+}
+
+// ToCart				godoc
+// @Summary				Add collection to cart
+// @Description			Adds all the collection's cards to the cart, capped by the stock. Cards that couldn't be fully added are reported, with substitute set the missing amount is made up with the cheapest in-stock printings of the same card
+// @Param				collectionId path int true "Collection ID"
+// @Param				substitute query bool false "substitute missing cards with other printings"
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Collection
+// @Success				200 {object} dto.CollectionToCart
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/to-cart [post]
+func (con *CollectionController) ToCart(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("collectionId")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	rawSubstitute := c.DefaultQuery("substitute", "false")
+	substitute, err := strconv.ParseBool(rawSubstitute)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid substitute flag", rawSubstitute), true)
+		return
+	}
+
+	result, err := con.collectionService.ToCart(uint(id), uint(userId), substitute)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Diff				godoc
+// @Summary				Compare collections
+// @Description			Lists the printings whose amounts differ between the collection and another of the user's collections or a public collection, grouped by card
+// @Param				id path int true "Collection ID"
+// @Param				otherId path int true "ID of the collection to compare to"
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Collection
+// @Success				200 {object} dto.CollectionDiff
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{id}/diff/{otherId} [get]
+func (con *CollectionController) Diff(c *gin.Context) {
+	id, otherId, userId, ok := con.comparedIds(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.collectionService.Diff(id, otherId, userId)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DiffDecklist			godoc
+// @Summary				Compare collection to decklist
+// @Description			Lists the printings whose amounts differ between the collection and a decklist, grouped by card. Lines that can't be resolved are reported and skipped
+// @Param				collectionId path int true "Collection ID"
+// @Param				Authorization header string false "Authenticator"
+// @Param				decklist body dto.DecklistImport true "decklist"
+// @Tags				Collection
+// @Success				200 {object} dto.CollectionDiff
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/diff [post]
+func (con *CollectionController) DiffDecklist(c *gin.Context) {
+	id, userId, list, ok := con.comparedDecklist(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.collectionService.DiffDecklist(id, userId, list)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Needs				godoc
+// @Summary				Price missing cards
+// @Description			Lists the cards the collection is missing to match another of the user's collections or a public collection, priced from the cheapest printings in stock
+// @Param				id path int true "Collection ID"
+// @Param				otherId path int true "ID of the collection to compare to"
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Collection
+// @Success				200 {object} dto.CollectionNeeds
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{id}/needs/{otherId} [get]
+func (con *CollectionController) Needs(c *gin.Context) {
+	id, otherId, userId, ok := con.comparedIds(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.collectionService.Needs(id, otherId, userId)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// NeedsDecklist		godoc
+// @Summary				Price cards missing for decklist
+// @Description			Lists the cards the collection is missing to build a decklist, priced from the cheapest printings in stock. Lines that can't be resolved are reported and skipped
+// @Param				collectionId path int true "Collection ID"
+// @Param				Authorization header string false "Authenticator"
+// @Param				decklist body dto.DecklistImport true "decklist"
+// @Tags				Collection
+// @Success				200 {object} dto.CollectionNeeds
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/needs [post]
+func (con *CollectionController) NeedsDecklist(c *gin.Context) {
+	id, userId, list, ok := con.comparedDecklist(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.collectionService.NeedsDecklist(id, userId, list)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// comparedIds reads the ids of the compared collections and the user, aborting if any is invalid
+func (con *CollectionController) comparedIds(c *gin.Context) (id uint, otherId uint, userId uint, ok bool) {
+	p := c.Param("id")
+	rawCollectionId, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+	p = c.Param("otherId")
+	rawOtherId, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	rawUserId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	return uint(rawCollectionId), uint(rawOtherId), uint(rawUserId), true
+}
+
+// comparedDecklist reads the collection id, the user and the decklist it's compared to, aborting if any is invalid
+func (con *CollectionController) comparedDecklist(c *gin.Context) (id uint, userId uint, list *dto.DecklistImport, ok bool) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	rawUserId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("collectionId")
+	rawCollectionId, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	list = &dto.DecklistImport{}
+	if err := c.BindJSON(list); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	return uint(rawCollectionId), uint(rawUserId), list, true
+}
+
+// Copy					godoc
+// @Summary				Copy collection
+// @Description			Creates a private copy of one of the user's collections or a public collection. Copies of the user's own collections keep their folder and tags
+// @Param				Authorization header string false "Authenticator"
+// @Param				collectionId path int true "Collection ID"
+// @Param				copy body dto.CopyCollection true "copy options"
+// @Tags				Collection
+// @Success				201 {object} dto.GetCollection
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/copy [post]
+func (con *CollectionController) Copy(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("collectionId")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	var options dto.CopyCollection
+	if err := c.BindJSON(&options); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	collection, err := con.collectionService.Copy(uint(id), uint(userId), &options)
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no collection with id %d", id), true)
+			return
+		}
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, collection)
+}
+
+// Merge				godoc
+// @Summary				Merge collections
+// @Description			Adds the cards of another of the user's collections to the collection, summing the amounts of the same card. The merged collection can be deleted afterwards
+// @Param				Authorization header string false "Authenticator"
+// @Param				collectionId path int true "Collection ID"
+// @Param				merge body dto.MergeCollections true "merged collection"
+// @Tags				Collection
+// @Success				200 {object} dto.GetCollection
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/{collectionId}/merge [post]
+func (con *CollectionController) Merge(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("collectionId")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid collection id", p), true)
+		return
+	}
+
+	var merge dto.MergeCollections
+	if err := c.BindJSON(&merge); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	collection, err := con.collectionService.Merge(uint(id), uint(userId), &merge)
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, collection)
+}
+
+// CreateFolder			godoc
+// @Summary				Create collection folder
+// @Description			Creates a folder for organising collections, folders can be nested
+// @Param				Authorization header string false "Authenticator"
+// @Param				folder body dto.PostCollectionFolder true "new folder data"
+// @Tags				Collection
+// @Success				201 {object} dto.GetCollectionFolder
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/folder [post]
+func (con *CollectionController) CreateFolder(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var folder dto.PostCollectionFolder
+	if err := c.BindJSON(&folder); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.collectionService.CreateFolder(uint(userId), &folder)
+	if err != nil {
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// UpdateFolder			godoc
+// @Summary				Rename or move collection folder
+// @Description			Renames the folder and moves it under another folder, or to the top level if no parent is given
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Folder ID"
+// @Param				folder body dto.PostCollectionFolder true "new folder data"
+// @Tags				Collection
+// @Success				200 {object} dto.GetCollectionFolder
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/folder/{id} [patch]
+func (con *CollectionController) UpdateFolder(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid folder id", p), true)
+		return
+	}
+
+	var folder dto.PostCollectionFolder
+	if err := c.BindJSON(&folder); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.collectionService.UpdateFolder(uint(id), uint(userId), &folder)
+	if err != nil {
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DeleteFolder			godoc
+// @Summary				Delete collection folder
+// @Description			Deletes the folder, its subfolders and collections are moved to its parent
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Folder ID"
+// @Tags				Collection
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/collection/folder/{id} [delete]
+func (con *CollectionController) DeleteFolder(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid folder id", p), true)
+		return
+	}
+
+	err = con.collectionService.DeleteFolder(uint(id), uint(userId))
+	if err != nil {
+		if err == service.ErrFolderNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no folder with id %d", id), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Shared				godoc
+// @Summary				Fetch shared collection
+// @Description			Fetches an unlisted or public collection by it's share token
+// @Param				token path string true "Share token"
+// @Tags				Collection
+// @Success				200 {object} dto.GetSharedCollection
+// @Failure				404 {object} string
+// @Router				/collection/shared/{token} [get]
+func (con *CollectionController) Shared(c *gin.Context) {
+	token := c.Param("token")
+
+	collection, err := con.collectionService.GetShared(token)
+	if err != nil {
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, errors.New("no shared collection with this link"), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, collection)
+}
+
+// Public				godoc
+// @Summary				Fetch public collections
+// @Description			Fetches all public collections of a user
+// @Param				username path string true "Username"
+// @Tags				Collection
+// @Success				200 {object} dto.GetSharedCollection[]
+// @Failure				404 {object} string
+// @Router				/collection/public/{username} [get]
+func (con *CollectionController) Public(c *gin.Context) {
+	username := c.Param("username")
+
+	collections, err := con.collectionService.GetPublic(username)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no user with username %s", username), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, collections)
+}
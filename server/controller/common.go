@@ -1,7 +1,24 @@
 package controller
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"store.api/dto"
+	"store.api/service"
+)
 
 func userNotFound(id uint) error {
 	return fmt.Errorf("no user with id %d", id)
 }
+
+// respondWithCart converts the cart to the currency of the request's currency parameter
+func respondWithCart(c *gin.Context, cartService service.CartService, cart *dto.GetCart) {
+	result, err := cartService.Convert(cart, c.Query("currency"))
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, result)
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+type CurrencyController struct {
+	currencyService service.CurrencyService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *CurrencyController) ConfigureApi(r *gin.RouterGroup) {
+	r.GET("/currency", con.Rates)
+	con.group = r.Group("/currency")
+	{
+		con.group.Use(con.auth)
+		con.group.PUT("/:currency", con.SetRate)
+		con.group.DELETE("/:currency", con.DeleteRate)
+		con.group.POST("/load", con.Load)
+	}
+
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		Build()
+}
+
+func (con *CurrencyController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewCurrencyController(currencyService service.CurrencyService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *CurrencyController {
+	return &CurrencyController{
+		currencyService: currencyService,
+		auth:            auth,
+		claimExtractF:   claimExtractF,
+	}
+}
+
+// ExchangeRates		godoc
+// @Summary				Fetch exchange rates
+// @Description			Fetches the store's currency and the currencies prices can be converted to
+// @Tags				Currency
+// @Success				200 {object} dto.GetCurrencies
+// @Router				/currency [get]
+func (con *CurrencyController) Rates(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, con.currencyService.Rates())
+}
+
+// SetExchangeRate		godoc
+// @Summary				Set exchange rate
+// @Description			Creates or replaces the exchange rate of a currency, in units of the currency per unit of the store's currency
+// @Param				Authorization header string false "Authenticator"
+// @Param				currency path string true "ISO 4217 currency code"
+// @Param				rate body dto.PostExchangeRate true "exchange rate"
+// @Tags				Currency
+// @Success				200 {object} dto.GetExchangeRate
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/currency/{currency} [put]
+func (con *CurrencyController) SetRate(c *gin.Context) {
+	var rate dto.PostExchangeRate
+	if err := c.BindJSON(&rate); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.currencyService.SetRate(c.Param("currency"), &rate)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DeleteExchangeRate	godoc
+// @Summary				Delete exchange rate
+// @Description			Deletes the exchange rate, prices can't be converted to the currency anymore
+// @Param				Authorization header string false "Authenticator"
+// @Param				currency path string true "ISO 4217 currency code"
+// @Tags				Currency
+// @Success				200
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/currency/{currency} [delete]
+func (con *CurrencyController) DeleteRate(c *gin.Context) {
+	currency := c.Param("currency")
+	err := con.currencyService.DeleteRate(currency)
+	if err != nil {
+		if err == service.ErrUnknownCurrency {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no exchange rate for %s", currency), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// LoadExchangeRates	godoc
+// @Summary				Load exchange rates
+// @Description			Updates the exchange rates from the configured file or feed, rates missing from it are kept
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Currency
+// @Success				200 {object} dto.GetCurrencies
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				409 {object} string
+// @Failure				502 {object} string
+// @Router				/currency/load [post]
+func (con *CurrencyController) Load(c *gin.Context) {
+	result, err := con.currencyService.Load()
+	if err != nil {
+		if err == service.ErrNoExchangeRateSource {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadGateway, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/security"
+	"store.api/service"
+)
+
+// GuestCartController lets users that haven't logged in fill a cart, it is merged into their own cart on login
+type GuestCartController struct {
+	cartService service.CartService
+	guestCart   *auth.GuestCartCookie
+
+	group *gin.RouterGroup
+}
+
+func (con *GuestCartController) ConfigureApi(r *gin.RouterGroup) {
+	con.group = r.Group("/guest/cart")
+	{
+		con.group.GET("", con.Get)
+		con.group.POST("", con.EditSlot)
+		con.group.POST("/batch", con.EditSlots)
+	}
+}
+
+func NewGuestCartController(cartService service.CartService, guestCart *auth.GuestCartCookie) *GuestCartController {
+	return &GuestCartController{
+		cartService: cartService,
+		guestCart:   guestCart,
+	}
+}
+
+// GetGuestCart			godoc
+// @Summary				Fetch guest cart
+// @Description			Fetches the cart of a user that hasn't logged in, identified by the guest cart cookie
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Cart
+// @Success				200 {object} dto.GetCart
+// @Router				/guest/cart [get]
+func (con *GuestCartController) Get(c *gin.Context) {
+	guestId, ok := con.guestCart.Read(c)
+	if !ok {
+		respondWithCart(c, con.cartService, dto.NewGetCart(&model.Cart{}))
+		return
+	}
+
+	respondWithCart(c, con.cartService, con.cartService.GetGuest(guestId))
+}
+
+// EditGuestCartSlot	godoc
+// @Summary				Add, remove or alter guest cart slot
+// @Description			Adds, removes or alters a slot of the guest cart, starts a new guest cart if there is none
+// @Param				cartSlot body dto.PostCartSlot true "new cart slot data"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Cart
+// @Success				200 {object} dto.GetCart
+// @Failure				400 {object} string
+// @Failure				404 {object} string
+// @Router				/guest/cart [post]
+func (con *GuestCartController) EditSlot(c *gin.Context) {
+	var newCartSlot dto.PostCartSlot
+	if err := c.BindJSON(&newCartSlot); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	guestId, err := con.guestId(c)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err, false)
+		return
+	}
+
+	result, err := con.cartService.EditGuestSlot(guestId, &newCartSlot)
+	if err != nil {
+		if errors.Is(err, service.ErrCardNotFound) {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	con.guestCart.Write(c, guestId)
+	respondWithCart(c, con.cartService, result)
+}
+
+// EditGuestCartSlots	godoc
+// @Summary				Edit many guest cart slots
+// @Description			Adds, removes or alters many slots of the guest cart at once. Either all changes are applied or none are
+// @Param				cartSlots body dto.PostCartSlots true "cart slot changes"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Cart
+// @Success				200 {object} dto.GetCart
+// @Failure				400 {object} string
+// @Failure				404 {object} string
+// @Router				/guest/cart/batch [post]
+func (con *GuestCartController) EditSlots(c *gin.Context) {
+	var newCartSlots dto.PostCartSlots
+	if err := c.BindJSON(&newCartSlots); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	guestId, err := con.guestId(c)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, err, false)
+		return
+	}
+
+	result, err := con.cartService.EditGuestSlots(guestId, &newCartSlots)
+	if err != nil {
+		if errors.Is(err, service.ErrCardNotFound) {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	con.guestCart.Write(c, guestId)
+	respondWithCart(c, con.cartService, result)
+}
+
+// guestId returns the guest id from the cookie or a new one if the cookie is missing or invalid
+func (con *GuestCartController) guestId(c *gin.Context) (string, error) {
+	guestId, ok := con.guestCart.Read(c)
+	if ok {
+		return guestId, nil
+	}
+	return security.RandomToken()
+}
@@ -0,0 +1,350 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+type ListingController struct {
+	listingService service.ListingService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *ListingController) ConfigureApi(r *gin.RouterGroup) {
+	// offers are visible without logging in
+	r.GET("/listing/card/:cardId", con.Offers)
+	r.GET("/listing/:id", con.ById)
+
+	con.group = r.Group("/listing")
+	con.group.Use(con.auth)
+	{
+		con.group.GET("/mine", con.Mine)
+		con.group.POST("", con.Create)
+		con.group.PATCH("/:id", con.Update)
+		con.group.DELETE("/:id", con.Delete)
+		con.group.POST("/:id/buy", con.Buy)
+	}
+
+	// sellers have to be verified, which is checked when listing
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		PermitAll().
+		Build()
+}
+
+func (con *ListingController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewListingController(listingService service.ListingService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *ListingController {
+	return &ListingController{
+		listingService: listingService,
+		auth:           auth,
+		claimExtractF:  claimExtractF,
+	}
+}
+
+// CardOffers			godoc
+// @Summary				Fetch card offers
+// @Description			Fetches the store's own offer of a card and the marketplace listings of it, the cheapest listing first
+// @Param				cardId path int true "Card ID"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Listing
+// @Success				200 {object} dto.CardOffers
+// @Failure				400 {object} string
+// @Failure				404 {object} string
+// @Router				/listing/card/{cardId} [get]
+func (con *ListingController) Offers(c *gin.Context) {
+	p := c.Param("cardId")
+	cardId, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid card id", p), true)
+		return
+	}
+
+	offers, err := con.listingService.Offers(uint(cardId), c.Query("currency"))
+	if err != nil {
+		if err == service.ErrCardNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %v", cardId), true)
+			return
+		}
+		if err == service.ErrUnknownCurrency {
+			AbortWithError(c, http.StatusBadRequest, err, true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, offers)
+}
+
+// ListingById			godoc
+// @Summary				Fetch listing
+// @Description			Fetches a marketplace listing, including ones without copies left
+// @Param				id path int true "Listing ID"
+// @Param				currency query string false "Currency of the price, the store's currency if empty"
+// @Tags				Listing
+// @Success				200 {object} dto.GetListing
+// @Failure				400 {object} string
+// @Failure				404 {object} string
+// @Router				/listing/{id} [get]
+func (con *ListingController) ById(c *gin.Context) {
+	id, ok := listingId(c)
+	if !ok {
+		return
+	}
+
+	listing, err := con.listingService.ById(id, c.Query("currency"))
+	if err != nil {
+		if err == service.ErrListingNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no listing with id %d", id), true)
+			return
+		}
+		if err == service.ErrUnknownCurrency {
+			AbortWithError(c, http.StatusBadRequest, err, true)
+			return
+		}
+		panic(err)
+	}
+
+	c.IndentedJSON(http.StatusOK, listing)
+}
+
+// MyListings			godoc
+// @Summary				Fetch own listings
+// @Description			Fetches all of the user's marketplace listings, including ones without copies left
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Listing
+// @Success				200 {object} dto.GetListing[]
+// @Failure				401 {object} string
+// @Router				/listing/mine [get]
+func (con *ListingController) Mine(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.listingService.BySeller(uint(userId)))
+}
+
+// CreateListing		godoc
+// @Summary				Create listing
+// @Description			Lists copies of a catalogue printing on the marketplace, only verified users can sell
+// @Param				Authorization header string false "Authenticator"
+// @Param				listing body dto.PostListing true "listing"
+// @Tags				Listing
+// @Success				201 {object} dto.GetListing
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/listing [post]
+func (con *ListingController) Create(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var newListing dto.PostListing
+	if err := c.BindJSON(&newListing); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	listing, err := con.listingService.Create(&newListing, uint(userId))
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrCardNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %d", newListing.CardId), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, listing)
+}
+
+// UpdateListing		godoc
+// @Summary				Update listing
+// @Description			Changes the price, condition, amount or comment of one of the user's listings, omitted fields are left unchanged
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Listing ID"
+// @Param				listing body dto.PatchListing true "listing changes"
+// @Tags				Listing
+// @Success				200 {object} dto.GetListing
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/listing/{id} [patch]
+func (con *ListingController) Update(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	id, ok := listingId(c)
+	if !ok {
+		return
+	}
+
+	var patch dto.PatchListing
+	if err := c.BindJSON(&patch); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	listing, err := con.listingService.Update(id, &patch, uint(userId))
+	if err != nil {
+		if err == service.ErrListingNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no listing with id %d", id), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, listing)
+}
+
+// DeleteListing		godoc
+// @Summary				Delete listing
+// @Description			Deletes one of the user's listings
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Listing ID"
+// @Tags				Listing
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/listing/{id} [delete]
+func (con *ListingController) Delete(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	id, ok := listingId(c)
+	if !ok {
+		return
+	}
+
+	err = con.listingService.Delete(id, uint(userId))
+	if err != nil {
+		if err == service.ErrListingNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no listing with id %d", id), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// BuyListing			godoc
+// @Summary				Buy listing
+// @Description			Orders copies of another seller's listing at its current price, only verified users can buy
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Listing ID"
+// @Param				purchase body dto.PostListingPurchase true "purchase"
+// @Tags				Listing
+// @Success				201 {object} dto.GetOrder
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/listing/{id}/buy [post]
+func (con *ListingController) Buy(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	id, ok := listingId(c)
+	if !ok {
+		return
+	}
+
+	var purchase dto.PostListingPurchase
+	if err := c.BindJSON(&purchase); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	order, err := con.listingService.Buy(id, &purchase, uint(userId))
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrListingNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no listing with id %d", id), true)
+			return
+		}
+		if err == service.ErrListingUnavailable {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, order)
+}
+
+// listingId parses the listing id path parameter, aborting with 400 if it's invalid
+func listingId(c *gin.Context) (uint, bool) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid listing id", p), true)
+		return 0, false
+	}
+	return uint(id), true
+}
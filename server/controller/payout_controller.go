@@ -0,0 +1,320 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+type PayoutController struct {
+	payoutService service.PayoutService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *PayoutController) ConfigureApi(r *gin.RouterGroup) {
+	con.group = r.Group("/payout")
+	con.group.Use(con.auth)
+	{
+		con.group.GET("", con.Mine)
+		con.group.GET("/statement", con.MyStatement)
+		con.group.GET("/commission", con.MyCommission)
+		con.group.POST("", con.Pay)
+		con.group.GET("/seller/:sellerId", con.SellerPayouts)
+		con.group.GET("/seller/:sellerId/statement", con.SellerStatement)
+		con.group.GET("/seller/:sellerId/commission", con.SellerCommission)
+		con.group.PUT("/seller/:sellerId/commission", con.UpdateCommission)
+	}
+
+	// sellers see their own statements, admins pay them out and see everyone's
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		PermitAll().
+		ForPath(con.group.BasePath()).
+		ForMethod("POST").
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		ForPath(con.group.BasePath() + "/seller/*").
+		ForAnyMethod().
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		Build()
+}
+
+func (con *PayoutController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewPayoutController(payoutService service.PayoutService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *PayoutController {
+	return &PayoutController{
+		payoutService: payoutService,
+		auth:          auth,
+		claimExtractF: claimExtractF,
+	}
+}
+
+// MyPayouts			godoc
+// @Summary				Fetch own payouts
+// @Description			Fetches the payouts of the user's marketplace sales, newest first
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Payout
+// @Success				200 {object} dto.GetPayout[]
+// @Failure				401 {object} string
+// @Router				/payout [get]
+func (con *PayoutController) Mine(c *gin.Context) {
+	userId, ok := con.userId(c)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.payoutService.Payouts(userId))
+}
+
+// MyStatement			godoc
+// @Summary				Fetch own payout statement
+// @Description			Sums up the user's marketplace sales of a period, by default from the first of the current month to today
+// @Param				Authorization header string false "Authenticator"
+// @Param				period query dto.Period false "Period"
+// @Tags				Payout
+// @Success				200 {object} dto.PayoutStatement
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Router				/payout/statement [get]
+func (con *PayoutController) MyStatement(c *gin.Context) {
+	userId, ok := con.userId(c)
+	if !ok {
+		return
+	}
+
+	con.statement(c, userId)
+}
+
+// MyCommission			godoc
+// @Summary				Fetch own commission
+// @Description			Fetches the percentage of the user's marketplace sales the store keeps
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Payout
+// @Success				200 {object} dto.GetCommission
+// @Failure				401 {object} string
+// @Router				/payout/commission [get]
+func (con *PayoutController) MyCommission(c *gin.Context) {
+	userId, ok := con.userId(c)
+	if !ok {
+		return
+	}
+
+	commission, err := con.payoutService.Commission(userId)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, userNotFound(userId), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, commission)
+}
+
+// Pay					godoc
+// @Summary				Mark payout made
+// @Description			Records that the seller was paid the net amount of their unpaid sales in the period, only possible if the earlier payouts covering the period add up to the sales they paid
+// @Param				Authorization header string false "Authenticator"
+// @Param				payout body dto.PostPayout true "payout"
+// @Tags				Payout
+// @Success				201 {object} dto.GetPayout
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/payout [post]
+func (con *PayoutController) Pay(c *gin.Context) {
+	adminId, ok := con.userId(c)
+	if !ok {
+		return
+	}
+
+	var payout dto.PostPayout
+	if err := c.BindJSON(&payout); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.payoutService.Pay(&payout, adminId)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusNotFound, userNotFound(payout.SellerId), true)
+			return
+		}
+		if err == service.ErrNothingToPay || err == service.ErrSalesUnreconciled {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// SellerPayouts		godoc
+// @Summary				Fetch seller payouts
+// @Description			Fetches the payouts of a seller's marketplace sales, newest first
+// @Param				Authorization header string false "Authenticator"
+// @Param				sellerId path int true "Seller ID"
+// @Tags				Payout
+// @Success				200 {object} dto.GetPayout[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/payout/seller/{sellerId} [get]
+func (con *PayoutController) SellerPayouts(c *gin.Context) {
+	sellerId, ok := sellerId(c)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.payoutService.Payouts(sellerId))
+}
+
+// SellerStatement		godoc
+// @Summary				Fetch seller payout statement
+// @Description			Sums up a seller's marketplace sales of a period, by default from the first of the current month to today
+// @Param				Authorization header string false "Authenticator"
+// @Param				sellerId path int true "Seller ID"
+// @Param				period query dto.Period false "Period"
+// @Tags				Payout
+// @Success				200 {object} dto.PayoutStatement
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/payout/seller/{sellerId}/statement [get]
+func (con *PayoutController) SellerStatement(c *gin.Context) {
+	sellerId, ok := sellerId(c)
+	if !ok {
+		return
+	}
+
+	con.statement(c, sellerId)
+}
+
+// SellerCommission		godoc
+// @Summary				Fetch seller commission
+// @Description			Fetches the percentage of a seller's marketplace sales the store keeps
+// @Param				Authorization header string false "Authenticator"
+// @Param				sellerId path int true "Seller ID"
+// @Tags				Payout
+// @Success				200 {object} dto.GetCommission
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/payout/seller/{sellerId}/commission [get]
+func (con *PayoutController) SellerCommission(c *gin.Context) {
+	sellerId, ok := sellerId(c)
+	if !ok {
+		return
+	}
+
+	commission, err := con.payoutService.Commission(sellerId)
+	if err != nil {
+		AbortWithError(c, http.StatusNotFound, userNotFound(sellerId), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, commission)
+}
+
+// UpdateCommission		godoc
+// @Summary				Update seller commission
+// @Description			Sets the percentage of a seller's future marketplace sales the store keeps, null resets it to the store's
+// @Param				Authorization header string false "Authenticator"
+// @Param				sellerId path int true "Seller ID"
+// @Param				commission body dto.CommissionUpdate true "commission"
+// @Tags				Payout
+// @Success				200 {object} dto.GetCommission
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/payout/seller/{sellerId}/commission [put]
+func (con *PayoutController) UpdateCommission(c *gin.Context) {
+	sellerId, ok := sellerId(c)
+	if !ok {
+		return
+	}
+
+	var update dto.CommissionUpdate
+	if err := c.BindJSON(&update); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	commission, err := con.payoutService.UpdateCommission(sellerId, &update)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusNotFound, userNotFound(sellerId), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, commission)
+}
+
+func (con *PayoutController) statement(c *gin.Context, sellerId uint) {
+	var period dto.Period
+	if err := c.ShouldBindQuery(&period); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	statement, err := con.payoutService.Statement(sellerId, &period)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusNotFound, userNotFound(sellerId), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, statement)
+}
+
+// userId extracts the logged in user's id, aborting with 401 if it's invalid
+func (con *PayoutController) userId(c *gin.Context) (uint, bool) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return 0, false
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return 0, false
+	}
+	return uint(userId), true
+}
+
+// sellerId parses the seller id path parameter, aborting with 400 if it's invalid
+func sellerId(c *gin.Context) (uint, bool) {
+	p := c.Param("sellerId")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid seller id", p), true)
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+type PriceScheduleController struct {
+	priceScheduleService service.PriceScheduleService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *PriceScheduleController) ConfigureApi(r *gin.RouterGroup) {
+	con.group = r.Group("/price-schedule")
+	con.group.Use(con.auth)
+	{
+		con.group.GET("", con.Pending)
+		con.group.GET("/:id", con.ById)
+		con.group.POST("", con.Schedule)
+		con.group.DELETE("/:id", con.Cancel)
+	}
+
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		Build()
+}
+
+func (con *PriceScheduleController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewPriceScheduleController(priceScheduleService service.PriceScheduleService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *PriceScheduleController {
+	return &PriceScheduleController{
+		priceScheduleService: priceScheduleService,
+		auth:                 auth,
+		claimExtractF:        claimExtractF,
+	}
+}
+
+// PendingPriceChanges	godoc
+// @Summary				Fetch scheduled price changes
+// @Description			Fetches the price changes that are pending or in an active sales window, by their start
+// @Param				Authorization header string false "Authenticator"
+// @Param				cardId query int false "only the changes of this card"
+// @Tags				PriceSchedule
+// @Success				200 {object} dto.GetScheduledPrice[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/price-schedule [get]
+func (con *PriceScheduleController) Pending(c *gin.Context) {
+	p := c.DefaultQuery("cardId", "0")
+	cardId, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid card id", p), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.priceScheduleService.Pending(uint(cardId)))
+}
+
+// ScheduledPriceById	godoc
+// @Summary				Fetch scheduled price change
+// @Description			Fetches a scheduled price change, including done ones
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Scheduled price change ID"
+// @Tags				PriceSchedule
+// @Success				200 {object} dto.GetScheduledPrice
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/price-schedule/{id} [get]
+func (con *PriceScheduleController) ById(c *gin.Context) {
+	id, ok := scheduledPriceId(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.priceScheduleService.ById(id)
+	if err != nil {
+		AbortWithError(c, http.StatusNotFound, fmt.Errorf("no scheduled price change with id %d", id), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// SchedulePrice		godoc
+// @Summary				Schedule price change
+// @Description			Schedules a card's price change. With an end it's a sales window, the card's previous price is restored when it ends
+// @Param				Authorization header string false "Authenticator"
+// @Param				change body dto.PostScheduledPrice true "price change"
+// @Tags				PriceSchedule
+// @Success				201 {object} dto.GetScheduledPrice
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/price-schedule [post]
+func (con *PriceScheduleController) Schedule(c *gin.Context) {
+	var newChange dto.PostScheduledPrice
+	if err := c.BindJSON(&newChange); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.priceScheduleService.Schedule(&newChange)
+	if err != nil {
+		if err == service.ErrCardNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no card with id %d", newChange.CardId), true)
+			return
+		}
+		if err == service.ErrScheduledPriceOverlap {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// CancelPriceChange	godoc
+// @Summary				Cancel scheduled price change
+// @Description			Cancels a pending price change, an active sales window is ended right away by restoring the card's previous price
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Scheduled price change ID"
+// @Tags				PriceSchedule
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/price-schedule/{id} [delete]
+func (con *PriceScheduleController) Cancel(c *gin.Context) {
+	id, ok := scheduledPriceId(c)
+	if !ok {
+		return
+	}
+
+	err := con.priceScheduleService.Cancel(id)
+	if err != nil {
+		if err == service.ErrScheduledPriceNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no scheduled price change with id %d", id), true)
+			return
+		}
+		if err == service.ErrScheduledPriceDone || err == service.ErrScheduledPriceApplied {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// scheduledPriceId parses the scheduled price change id path parameter, aborting with 400 if it's invalid
+func scheduledPriceId(c *gin.Context) (uint, bool) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid scheduled price change id", p), true)
+		return 0, false
+	}
+	return uint(id), true
+}
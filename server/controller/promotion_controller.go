@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+type PromotionController struct {
+	promotionService service.PromotionService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *PromotionController) ConfigureApi(r *gin.RouterGroup) {
+	con.group = r.Group("/promotion")
+	con.group.Use(con.auth)
+	{
+		con.group.GET("", con.All)
+		con.group.GET("/:id", con.ById)
+		con.group.POST("", con.Create)
+		con.group.PATCH("/:id", con.Update)
+		con.group.DELETE("/:id", con.Delete)
+		con.group.GET("/:id/redemptions", con.Redemptions)
+	}
+
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		Permit(func(user *model.User) bool {
+			return user.IsAdmin && user.Verified
+		}).
+		Build()
+}
+
+func (con *PromotionController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewPromotionController(promotionService service.PromotionService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *PromotionController {
+	return &PromotionController{
+		promotionService: promotionService,
+		auth:             auth,
+		claimExtractF:    claimExtractF,
+	}
+}
+
+// AllPromotions		godoc
+// @Summary				Fetch promotions
+// @Description			Fetches all promotions with their redemption counts, newest first
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Promotion
+// @Success				200 {object} dto.GetPromotion[]
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/promotion [get]
+func (con *PromotionController) All(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, con.promotionService.All())
+}
+
+// PromotionById		godoc
+// @Summary				Fetch promotion
+// @Description			Fetches a promotion with its redemption count
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Promotion ID"
+// @Tags				Promotion
+// @Success				200 {object} dto.GetPromotion
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/promotion/{id} [get]
+func (con *PromotionController) ById(c *gin.Context) {
+	id, ok := promotionId(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.promotionService.ById(id)
+	if err != nil {
+		AbortWithError(c, http.StatusNotFound, fmt.Errorf("no promotion with id %d", id), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// CreatePromotion		godoc
+// @Summary				Create promotion
+// @Description			Creates a coupon, or an automatic promotion if no code is given
+// @Param				Authorization header string false "Authenticator"
+// @Param				promotion body dto.PostPromotion true "promotion data"
+// @Tags				Promotion
+// @Success				201 {object} dto.GetPromotion
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/promotion [post]
+func (con *PromotionController) Create(c *gin.Context) {
+	var newPromotion dto.PostPromotion
+	if err := c.BindJSON(&newPromotion); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.promotionService.Create(&newPromotion)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// UpdatePromotion		godoc
+// @Summary				Update promotion
+// @Description			Replaces the promotion's data, past redemptions are kept
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Promotion ID"
+// @Param				promotion body dto.PostPromotion true "promotion data"
+// @Tags				Promotion
+// @Success				200 {object} dto.GetPromotion
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/promotion/{id} [patch]
+func (con *PromotionController) Update(c *gin.Context) {
+	id, ok := promotionId(c)
+	if !ok {
+		return
+	}
+
+	var newPromotion dto.PostPromotion
+	if err := c.BindJSON(&newPromotion); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.promotionService.Update(id, &newPromotion)
+	if err != nil {
+		if err == service.ErrPromotionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no promotion with id %d", id), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DeletePromotion		godoc
+// @Summary				Delete promotion
+// @Description			Deletes the promotion, its redemptions are kept for auditing
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Promotion ID"
+// @Tags				Promotion
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/promotion/{id} [delete]
+func (con *PromotionController) Delete(c *gin.Context) {
+	id, ok := promotionId(c)
+	if !ok {
+		return
+	}
+
+	err := con.promotionService.Delete(id)
+	if err != nil {
+		if err == service.ErrPromotionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no promotion with id %d", id), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// PromotionRedemptions	godoc
+// @Summary				Fetch promotion redemptions
+// @Description			Fetches the orders the promotion was redeemed in, newest first
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Promotion ID"
+// @Tags				Promotion
+// @Success				200 {object} dto.GetPromotionRedemption[]
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Router				/promotion/{id}/redemptions [get]
+func (con *PromotionController) Redemptions(c *gin.Context) {
+	id, ok := promotionId(c)
+	if !ok {
+		return
+	}
+
+	result, err := con.promotionService.Redemptions(id)
+	if err != nil {
+		AbortWithError(c, http.StatusNotFound, fmt.Errorf("no promotion with id %d", id), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// promotionId parses the promotion id path parameter, aborting with 400 if it's invalid
+func promotionId(c *gin.Context) (uint, bool) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid promotion id", p), true)
+		return 0, false
+	}
+	return uint(id), true
+}
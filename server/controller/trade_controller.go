@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"store.api/auth"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+type TradeController struct {
+	tradeService service.TradeService
+
+	group         *gin.RouterGroup
+	auth          gin.HandlerFunc
+	authChecker   auth.AuthorizationChecker
+	claimExtractF func(string, *gin.Context) (string, error)
+}
+
+func (con *TradeController) ConfigureApi(r *gin.RouterGroup) {
+	con.group = r.Group("/trade")
+	con.group.Use(con.auth)
+	{
+		con.group.GET("", con.Mine)
+		con.group.GET("/:id", con.ById)
+		con.group.POST("", con.Propose)
+		con.group.POST("/:id/counter", con.Counter)
+		con.group.POST("/:id/accept", con.Accept)
+		con.group.POST("/:id/decline", con.Decline)
+		con.group.POST("/:id/cancel", con.Cancel)
+	}
+
+	// users only see their own trades, which is checked by the service
+	con.authChecker = auth.NewAuthorizationCheckerBuilder().
+		ForPath(con.group.BasePath() + "*").
+		ForAnyMethod().
+		PermitAll().
+		Build()
+}
+
+func (con *TradeController) Check(c *gin.Context, user *model.User) (authorized bool, matches bool) {
+	return con.authChecker.Check(c, user)
+}
+
+func NewTradeController(tradeService service.TradeService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *TradeController {
+	return &TradeController{
+		tradeService:  tradeService,
+		auth:          auth,
+		claimExtractF: claimExtractF,
+	}
+}
+
+// MyTrades				godoc
+// @Summary				Fetch own trades
+// @Description			Fetches the trades the user proposed or received, newest first
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Trade
+// @Success				200 {object} dto.GetTrade[]
+// @Failure				401 {object} string
+// @Router				/trade [get]
+func (con *TradeController) Mine(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.tradeService.Mine(uint(userId)))
+}
+
+// TradeById			godoc
+// @Summary				Fetch trade
+// @Description			Fetches a trade the user proposed or received
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Trade ID"
+// @Tags				Trade
+// @Success				200 {object} dto.GetTrade
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/trade/{id} [get]
+func (con *TradeController) ById(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	id, ok := tradeId(c)
+	if !ok {
+		return
+	}
+
+	trade, err := con.tradeService.ById(id, uint(userId))
+	if err != nil {
+		abortWithTradeError(c, id, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, trade)
+}
+
+// ProposeTrade			godoc
+// @Summary				Propose trade
+// @Description			Proposes to trade cards of one of the user's collections for cards of another user's public collection, only verified users can trade
+// @Param				Authorization header string false "Authenticator"
+// @Param				trade body dto.PostTrade true "trade"
+// @Tags				Trade
+// @Success				201 {object} dto.GetTrade
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/trade [post]
+func (con *TradeController) Propose(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var newTrade dto.PostTrade
+	if err := c.BindJSON(&newTrade); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	trade, err := con.tradeService.Propose(&newTrade, uint(userId))
+	if err != nil {
+		if err == service.ErrNotVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if err == service.ErrCollectionNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrTradeUnavailable {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, trade)
+}
+
+// CounterTrade			godoc
+// @Summary				Counter trade
+// @Description			Answers a received trade with a counter-offer, which closes it and proposes a new trade between the same collections in the other direction
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Trade ID"
+// @Param				trade body dto.PostCounterTrade true "counter-offer"
+// @Tags				Trade
+// @Success				201 {object} dto.GetTrade
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/trade/{id}/counter [post]
+func (con *TradeController) Counter(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	id, ok := tradeId(c)
+	if !ok {
+		return
+	}
+
+	var counter dto.PostCounterTrade
+	if err := c.BindJSON(&counter); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	trade, err := con.tradeService.Counter(id, &counter, uint(userId))
+	if err != nil {
+		abortWithTradeError(c, id, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, trade)
+}
+
+// AcceptTrade			godoc
+// @Summary				Accept trade
+// @Description			Accepts a received trade, exchanging the cards between the collections all at once
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Trade ID"
+// @Tags				Trade
+// @Success				200 {object} dto.GetTrade
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/trade/{id}/accept [post]
+func (con *TradeController) Accept(c *gin.Context) {
+	con.answer(c, con.tradeService.Accept)
+}
+
+// DeclineTrade			godoc
+// @Summary				Decline trade
+// @Description			Declines a received trade
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Trade ID"
+// @Tags				Trade
+// @Success				200 {object} dto.GetTrade
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/trade/{id}/decline [post]
+func (con *TradeController) Decline(c *gin.Context) {
+	con.answer(c, con.tradeService.Decline)
+}
+
+// CancelTrade			godoc
+// @Summary				Cancel trade
+// @Description			Withdraws a trade the user proposed
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Trade ID"
+// @Tags				Trade
+// @Success				200 {object} dto.GetTrade
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/trade/{id}/cancel [post]
+func (con *TradeController) Cancel(c *gin.Context) {
+	con.answer(c, con.tradeService.Cancel)
+}
+
+// answer closes the trade of the path with the given service action
+func (con *TradeController) answer(c *gin.Context, action func(id uint, userId uint) (*dto.GetTrade, error)) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	id, ok := tradeId(c)
+	if !ok {
+		return
+	}
+
+	trade, err := action(id, uint(userId))
+	if err != nil {
+		abortWithTradeError(c, id, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, trade)
+}
+
+// tradeId parses the trade id path parameter, aborting with 400 if it's invalid
+func tradeId(c *gin.Context) (uint, bool) {
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid trade id", p), true)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// abortWithTradeError maps the errors of acting on an existing trade
+func abortWithTradeError(c *gin.Context, id uint, err error) {
+	if err == service.ErrTradeNotFound {
+		AbortWithError(c, http.StatusNotFound, fmt.Errorf("no trade with id %d", id), true)
+		return
+	}
+	if err == service.ErrNotVerified {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	if err == service.ErrTradeForbidden {
+		AbortWithError(c, http.StatusForbidden, err, true)
+		return
+	}
+	if err == service.ErrTradeClosed || err == service.ErrTradeUnavailable {
+		AbortWithError(c, http.StatusConflict, err, true)
+		return
+	}
+	AbortWithError(c, http.StatusBadRequest, err, true)
+}
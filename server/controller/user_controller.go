@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"store.api/auth"
@@ -13,8 +15,10 @@ import (
 )
 
 type UserController struct {
-	userService service.UserService
-	cartService service.CartService
+	userService     service.UserService
+	cartService     service.CartService
+	wishlistService service.WishlistService
+	orderService    service.OrderService
 
 	group         *gin.RouterGroup
 	auth          gin.HandlerFunc
@@ -27,6 +31,11 @@ func (con *UserController) ConfigureApi(r *gin.RouterGroup) {
 	con.group.Use(con.auth)
 	{
 		con.group.GET("", con.GetInfo)
+		con.group.PATCH("/profile", con.UpdateProfile)
+		con.group.POST("/email", con.RequestEmailChange)
+		con.group.POST("/email/verify", con.VerifyEmail)
+		con.group.GET("/export", con.Export)
+		con.group.DELETE("", con.Delete)
 		con.group.GET("/login-test", func(ctx *gin.Context) {
 			ctx.IndentedJSON(http.StatusOK, gin.H{
 				"message": "hello:)",
@@ -37,6 +46,24 @@ func (con *UserController) ConfigureApi(r *gin.RouterGroup) {
 		{
 			cart.GET("", con.GetCart)
 			cart.POST("", con.EditCartSlot)
+			cart.POST("/batch", con.EditCartSlots)
+			cart.POST("/promotion", con.ApplyPromotion)
+			cart.DELETE("/promotion", con.RemovePromotion)
+			cart.POST("/checkout", con.Checkout)
+		}
+
+		orders := con.group.Group("/orders")
+		{
+			orders.GET("", con.GetOrders)
+			orders.GET("/:id", con.GetOrder)
+		}
+
+		wishlist := con.group.Group("/wishlist")
+		{
+			wishlist.GET("", con.GetWishlist)
+			wishlist.POST("", con.AddToWishlist)
+			wishlist.PATCH("/:id", con.UpdateWishlist)
+			wishlist.DELETE("/:id", con.RemoveFromWishlist)
 		}
 	}
 
@@ -51,12 +78,14 @@ func (con *UserController) Check(c *gin.Context, user *model.User) (authorized b
 	return con.authChecker.Check(c, user)
 }
 
-func NewUserController(userService service.UserService, cartService service.CartService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *UserController {
+func NewUserController(userService service.UserService, cartService service.CartService, wishlistService service.WishlistService, orderService service.OrderService, auth gin.HandlerFunc, claimExtractF func(string, *gin.Context) (string, error)) *UserController {
 	return &UserController{
-		userService:   userService,
-		cartService:   cartService,
-		auth:          auth,
-		claimExtractF: claimExtractF,
+		userService:     userService,
+		cartService:     cartService,
+		wishlistService: wishlistService,
+		orderService:    orderService,
+		auth:            auth,
+		claimExtractF:   claimExtractF,
 	}
 }
 
@@ -64,6 +93,7 @@ func NewUserController(userService service.UserService, cartService service.Cart
 // @Summary				Fetch cart
 // @Description			Fetches the user's cart
 // @Param				Authorization header string false "Authenticator"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
 // @Tags				Cart
 // @Success				200 {object} dto.GetCart
 // @Failure				401 {object} string
@@ -89,7 +119,7 @@ func (con *UserController) GetCart(c *gin.Context) {
 		panic(err)
 	}
 
-	c.IndentedJSON(http.StatusOK, cart)
+	respondWithCart(c, con.cartService, cart)
 }
 
 // EditCartSlot			godoc
@@ -97,6 +127,7 @@ func (con *UserController) GetCart(c *gin.Context) {
 // @Description			Adds, removes or alters a cart slot
 // @Param				Authorization header string false "Authenticator"
 // @Param				collectionSlot body dto.PostCollectionSlot true "new cart slot data"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
 // @Tags				Collection
 // @Success				200 {object} dto.GetCollection
 // @Failure				400 {object} string
@@ -133,9 +164,404 @@ func (con *UserController) EditCartSlot(c *gin.Context) {
 		return
 	}
 
+	respondWithCart(c, con.cartService, result)
+}
+
+// EditCartSlots		godoc
+// @Summary				Edit many cart slots
+// @Description			Adds, removes or alters many cart slots at once. Either all changes are applied or none are
+// @Param				Authorization header string false "Authenticator"
+// @Param				cartSlots body dto.PostCartSlots true "cart slot changes"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Collection
+// @Success				200 {object} dto.GetCart
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/user/cart/batch [post]
+func (con *UserController) EditCartSlots(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var newCartSlots dto.PostCartSlots
+	if err := c.BindJSON(&newCartSlots); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.cartService.EditSlots(uint(userId), &newCartSlots)
+	if err != nil {
+		if errors.Is(err, service.ErrCardNotFound) {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	respondWithCart(c, con.cartService, result)
+}
+
+// ApplyPromotion		godoc
+// @Summary				Apply coupon
+// @Description			Sets the coupon of the user's cart, replacing the previous one
+// @Param				Authorization header string false "Authenticator"
+// @Param				code body dto.PromotionCode true "coupon code"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Cart
+// @Success				200 {object} dto.GetCart
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/user/cart/promotion [post]
+func (con *UserController) ApplyPromotion(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var code dto.PromotionCode
+	if err := c.BindJSON(&code); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.cartService.ApplyPromotion(uint(userId), &code)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		if err == service.ErrPromotionNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no promotion with code %s", code.Code), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	respondWithCart(c, con.cartService, result)
+}
+
+// RemovePromotion		godoc
+// @Summary				Remove coupon
+// @Description			Removes the coupon from the user's cart, automatic promotions still apply
+// @Param				Authorization header string false "Authenticator"
+// @Param				currency query string false "Currency of the prices, the store's currency if empty"
+// @Tags				Cart
+// @Success				200 {object} dto.GetCart
+// @Failure				401 {object} string
+// @Router				/user/cart/promotion [delete]
+func (con *UserController) RemovePromotion(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	result, err := con.cartService.RemovePromotion(uint(userId))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		panic(err)
+	}
+
+	respondWithCart(c, con.cartService, result)
+}
+
+// Checkout				godoc
+// @Summary				Check out cart
+// @Description			Orders the cart's cards at their current prices and promotions, takes them out of stock and empties the cart
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Cart
+// @Success				201 {object} dto.GetOrder
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Failure				409 {object} string
+// @Router				/user/cart/checkout [post]
+func (con *UserController) Checkout(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	result, err := con.cartService.Checkout(uint(userId))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		if err == service.ErrNotVerified {
+			AbortWithError(c, http.StatusForbidden, err, true)
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientStock) || err == service.ErrPromotionLimitReached {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// GetOrders			godoc
+// @Summary				Fetch orders
+// @Description			Fetches the user's orders, newest first
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Order
+// @Success				200 {object} dto.GetOrder[]
+// @Failure				401 {object} string
+// @Router				/user/orders [get]
+func (con *UserController) GetOrders(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.orderService.All(uint(userId)))
+}
+
+// GetOrder				godoc
+// @Summary				Fetch order
+// @Description			Fetches one of the user's orders
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Order ID"
+// @Tags				Order
+// @Success				200 {object} dto.GetOrder
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/user/orders/{id} [get]
+func (con *UserController) GetOrder(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid order id", p), true)
+		return
+	}
+
+	result, err := con.orderService.ById(uint(userId), uint(id))
+	if err != nil {
+		AbortWithError(c, http.StatusNotFound, fmt.Errorf("no order with id %d", id), true)
+		return
+	}
+
 	c.IndentedJSON(http.StatusOK, result)
 }
 
+// GetWishlist			godoc
+// @Summary				Fetch wishlist
+// @Description			Fetches the cards on the user's wishlist
+// @Param				Authorization header string false "Authenticator"
+// @Tags				Wishlist
+// @Success				200 {object} dto.GetWishlist[]
+// @Failure				401 {object} string
+// @Router				/user/wishlist [get]
+func (con *UserController) GetWishlist(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, con.wishlistService.All(uint(userId)))
+}
+
+// AddToWishlist		godoc
+// @Summary				Add card to wishlist
+// @Description			Adds a card printing or any printing of a card key to the wishlist, the user is mailed when it comes back in stock or its price drops to the max price
+// @Param				Authorization header string false "Authenticator"
+// @Param				wishlist body dto.PostWishlist true "wished card"
+// @Tags				Wishlist
+// @Success				201 {object} dto.GetWishlist
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Failure				409 {object} string
+// @Router				/user/wishlist [post]
+func (con *UserController) AddToWishlist(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var wishlist dto.PostWishlist
+	if err := c.BindJSON(&wishlist); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.wishlistService.Add(uint(userId), &wishlist)
+	if err != nil {
+		if err == service.ErrCardNotFound || err == service.ErrCardKeyNotFound {
+			AbortWithError(c, http.StatusNotFound, err, true)
+			return
+		}
+		if err == service.ErrAlreadyWished {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// UpdateWishlist		godoc
+// @Summary				Update wishlist entry
+// @Description			Changes the max price or min condition of a wishlist entry
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Wishlist ID"
+// @Param				wishlist body dto.PatchWishlist true "changed fields"
+// @Tags				Wishlist
+// @Success				200 {object} dto.GetWishlist
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/user/wishlist/{id} [patch]
+func (con *UserController) UpdateWishlist(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid wishlist id", p), true)
+		return
+	}
+
+	var patch dto.PatchWishlist
+	if err := c.BindJSON(&patch); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.wishlistService.Update(uint(userId), uint(id), &patch)
+	if err != nil {
+		if err == service.ErrWishlistNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no wishlist with id %d", id), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// RemoveFromWishlist	godoc
+// @Summary				Remove card from wishlist
+// @Description			Removes an entry from the wishlist
+// @Param				Authorization header string false "Authenticator"
+// @Param				id path int true "Wishlist ID"
+// @Tags				Wishlist
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				404 {object} string
+// @Router				/user/wishlist/{id} [delete]
+func (con *UserController) RemoveFromWishlist(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	p := c.Param("id")
+	id, err := strconv.ParseUint(p, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, fmt.Errorf("%s is not a valid wishlist id", p), true)
+		return
+	}
+
+	err = con.wishlistService.Delete(uint(userId), uint(id))
+	if err != nil {
+		if err == service.ErrWishlistNotFound {
+			AbortWithError(c, http.StatusNotFound, fmt.Errorf("no wishlist with id %d", id), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
 // GetInfo				godoc
 // @Summary				Get user info
 // @Description			Gets the user's private information
@@ -168,3 +594,222 @@ func (con *UserController) GetInfo(c *gin.Context) {
 
 	c.IndentedJSON(http.StatusOK, data)
 }
+
+// UpdateProfile		godoc
+// @Summary				Update profile
+// @Description			Updates the user's display name, preferred currency and preferred language, omitted fields are left unchanged
+// @Param				Authorization header string false "Authenticator"
+// @Param				profile body dto.PatchProfile true "Profile changes"
+// @Tags				User
+// @Success				200 {object} dto.PrivateUserInfo
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Router				/user/profile [patch]
+func (con *UserController) UpdateProfile(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var profile dto.PatchProfile
+	if err := c.BindJSON(&profile); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.userService.UpdateProfile(uint(userId), &profile)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// RequestEmailChange	godoc
+// @Summary				Change email
+// @Description			Sends a verification token to the new email, the email is changed once the token is verified
+// @Param				Authorization header string false "Authenticator"
+// @Param				email body dto.EmailChange true "New email"
+// @Tags				User
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				409 {object} string
+// @Router				/user/email [post]
+func (con *UserController) RequestEmailChange(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var change dto.EmailChange
+	if err := c.BindJSON(&change); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	err = con.userService.RequestEmailChange(uint(userId), &change)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		if err == service.ErrEmailTaken {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// VerifyEmail			godoc
+// @Summary				Verify email
+// @Description			Checks the verification token and replaces the user's email with the pending one
+// @Param				Authorization header string false "Authenticator"
+// @Param				token body dto.EmailVerification true "Verification token"
+// @Tags				User
+// @Success				200 {object} dto.PrivateUserInfo
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				409 {object} string
+// @Router				/user/email/verify [post]
+func (con *UserController) VerifyEmail(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var verification dto.EmailVerification
+	if err := c.BindJSON(&verification); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	result, err := con.userService.VerifyEmail(uint(userId), &verification)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		if err == service.ErrEmailTaken {
+			AbortWithError(c, http.StatusConflict, err, true)
+			return
+		}
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Export				godoc
+// @Summary				Export user data
+// @Description			Fetches all of the user's data: profile, collections and cart
+// @Param				Authorization header string false "Authenticator"
+// @Tags				User
+// @Success				200 {object} dto.UserExport
+// @Failure				401 {object} string
+// @Router				/user/export [get]
+func (con *UserController) Export(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	result, err := con.userService.Export(uint(userId))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=export.json")
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// Delete				godoc
+// @Summary				Delete account
+// @Description			Removes the user's collections and cart and anonymises the account, the password is required for users that have one, users created through OIDC must have logged in within the last five minutes
+// @Param				Authorization header string false "Authenticator"
+// @Param				details body dto.AccountDeletion true "Account deletion confirmation"
+// @Tags				User
+// @Success				200
+// @Failure				400 {object} string
+// @Failure				401 {object} string
+// @Failure				403 {object} string
+// @Router				/user [delete]
+func (con *UserController) Delete(c *gin.Context) {
+	rawId, err := con.claimExtractF(auth.IDKey, c)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, err, true)
+		return
+	}
+	userId, err := strconv.ParseUint(rawId, 10, 32)
+	if err != nil {
+		AbortWithError(c, http.StatusUnauthorized, fmt.Errorf("%s is an invalid user id", rawId), true)
+		return
+	}
+
+	var deletion dto.AccountDeletion
+	if err := c.BindJSON(&deletion); err != nil {
+		AbortWithError(c, http.StatusBadRequest, err, true)
+		return
+	}
+
+	// tokens issued before login times were recorded count as old logins
+	var loggedInAt time.Time
+	if rawLoginTime, err := con.claimExtractF(auth.LoginTimeKey, c); err == nil {
+		if loginTime, err := strconv.ParseInt(rawLoginTime, 10, 64); err == nil {
+			loggedInAt = time.Unix(loginTime, 0)
+		}
+	}
+
+	err = con.userService.Delete(uint(userId), &deletion, loggedInAt)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			AbortWithError(c, http.StatusUnauthorized, userNotFound(uint(userId)), true)
+			return
+		}
+		if err == service.ErrIncorrectPassword || err == service.ErrLoginTooOld {
+			AbortWithError(c, http.StatusForbidden, err, true)
+			return
+		}
+		panic(err)
+	}
+
+	c.Status(http.StatusOK)
+}
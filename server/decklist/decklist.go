@@ -0,0 +1,83 @@
+package decklist
+
+import "errors"
+
+type Format string
+
+const (
+	// one card per line: `4 Lightning Bolt (M11) 149`, expansion and collector number are optional
+	FormatText Format = "text"
+	// MTG Arena export, same lines as the text format grouped under Deck/Sideboard/Commander headers
+	FormatArena Format = "arena"
+	// MTGO .dek xml file
+	FormatMtgo Format = "mtgo"
+)
+
+var ErrUnknownFormat = errors.New("unknown decklist format")
+
+func ParseFormat(raw string) (Format, error) {
+	switch f := Format(raw); f {
+	case FormatText, FormatArena, FormatMtgo:
+		return f, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+func (f Format) ContentType() string {
+	if f == FormatMtgo {
+		return "application/xml"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func (f Format) Extension() string {
+	if f == FormatMtgo {
+		return "dek"
+	}
+	return "txt"
+}
+
+type Entry struct {
+	// line of the entry in the decklist, for .dek files the index of the card element
+	Line int
+	// the entry as written in the decklist
+	Text            string
+	Amount          uint
+	Name            string
+	Expansion       string
+	CollectorNumber string
+	Sideboard       bool
+}
+
+// InvalidLine is a line of the decklist that couldn't be read as a card entry
+type InvalidLine struct {
+	Line   int
+	Text   string
+	Reason string
+}
+
+// Parse reads the entries of a decklist, lines that aren't card entries are returned separately
+// so the rest of the decklist can still be imported. An error is returned only if the decklist
+// can't be read at all
+func Parse(format Format, data []byte) ([]*Entry, []*InvalidLine, error) {
+	switch format {
+	case FormatText, FormatArena:
+		entries, invalid := parseText(string(data))
+		return entries, invalid, nil
+	case FormatMtgo:
+		return parseDek(data)
+	}
+	return nil, nil, ErrUnknownFormat
+}
+
+func Write(format Format, entries []*Entry) ([]byte, error) {
+	switch format {
+	case FormatText:
+		return writeText(entries, false), nil
+	case FormatArena:
+		return writeText(entries, true), nil
+	case FormatMtgo:
+		return writeDek(entries)
+	}
+	return nil, ErrUnknownFormat
+}
@@ -0,0 +1,84 @@
+package decklist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type dekDeck struct {
+	XMLName              xml.Name  `xml:"Deck"`
+	NetDeckID            int       `xml:"NetDeckID"`
+	PreconstructedDeckID int       `xml:"PreconstructedDeckID"`
+	Cards                []dekCard `xml:"Cards"`
+}
+
+type dekCard struct {
+	CatID     string `xml:"CatID,attr,omitempty"`
+	Quantity  string `xml:"Quantity,attr"`
+	Sideboard bool   `xml:"Sideboard,attr"`
+	Name      string `xml:"Name,attr"`
+}
+
+// .dek files only identify cards by name and the MTGO catalog id, so entries have no expansion
+func parseDek(data []byte) ([]*Entry, []*InvalidLine, error) {
+	var deck dekDeck
+	err := xml.Unmarshal(data, &deck)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid .dek file: %w", err)
+	}
+
+	entries := []*Entry{}
+	invalid := []*InvalidLine{}
+	for i, card := range deck.Cards {
+		text := fmt.Sprintf(`%s x%s`, card.Name, card.Quantity)
+
+		var amount uint
+		_, err := fmt.Sscan(card.Quantity, &amount)
+		if err != nil || amount == 0 {
+			invalid = append(invalid, &InvalidLine{
+				Line:   i + 1,
+				Text:   text,
+				Reason: fmt.Sprintf("%s is not a valid amount", card.Quantity),
+			})
+			continue
+		}
+		name := strings.TrimSpace(card.Name)
+		if len(name) == 0 {
+			invalid = append(invalid, &InvalidLine{
+				Line:   i + 1,
+				Text:   text,
+				Reason: "missing card name",
+			})
+			continue
+		}
+
+		entries = append(entries, &Entry{
+			Line:      i + 1,
+			Text:      text,
+			Amount:    amount,
+			Name:      name,
+			Sideboard: card.Sideboard,
+		})
+	}
+	return entries, invalid, nil
+}
+
+func writeDek(entries []*Entry) ([]byte, error) {
+	deck := dekDeck{
+		Cards: make([]dekCard, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		deck.Cards = append(deck.Cards, dekCard{
+			Quantity:  fmt.Sprint(entry.Amount),
+			Sideboard: entry.Sideboard,
+			Name:      entry.Name,
+		})
+	}
+
+	result, err := xml.MarshalIndent(deck, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), result...), nil
+}
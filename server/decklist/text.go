@@ -0,0 +1,113 @@
+package decklist
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// `4 Lightning Bolt`, `4x Lightning Bolt`, `4 Lightning Bolt (M11)`, `4 Lightning Bolt (M11) 149`
+var textLine = regexp.MustCompile(`^(\d+)x?\s+(.+?)(?:\s+\(([^()\s]+)\)(?:\s+(\S+))?)?$`)
+
+func parseText(data string) ([]*Entry, []*InvalidLine) {
+	entries := []*Entry{}
+	invalid := []*InvalidLine{}
+
+	sideboard := false
+	about := false
+	for i, raw := range strings.Split(data, "\n") {
+		line := strings.TrimSpace(raw)
+		if len(line) == 0 || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSuffix(line, ":")) {
+		case "deck", "commander", "companion":
+			sideboard = false
+			about = false
+			continue
+		case "sideboard", "maybeboard":
+			sideboard = true
+			about = false
+			continue
+		case "about":
+			about = true
+			continue
+		}
+		// the about section of arena exports only holds the deck name
+		if about {
+			continue
+		}
+
+		match := textLine.FindStringSubmatch(line)
+		if match == nil {
+			invalid = append(invalid, &InvalidLine{
+				Line:   i + 1,
+				Text:   line,
+				Reason: "expected an amount followed by a card name",
+			})
+			continue
+		}
+		amount, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || amount == 0 {
+			invalid = append(invalid, &InvalidLine{
+				Line:   i + 1,
+				Text:   line,
+				Reason: fmt.Sprintf("%s is not a valid amount", match[1]),
+			})
+			continue
+		}
+
+		entries = append(entries, &Entry{
+			Line:            i + 1,
+			Text:            line,
+			Amount:          uint(amount),
+			Name:            match[2],
+			Expansion:       match[3],
+			CollectorNumber: match[4],
+			Sideboard:       sideboard,
+		})
+	}
+	return entries, invalid
+}
+
+func writeText(entries []*Entry, headers bool) []byte {
+	var result bytes.Buffer
+	if headers {
+		result.WriteString("Deck\n")
+	}
+
+	var sideboard []*Entry
+	for _, entry := range entries {
+		if entry.Sideboard {
+			sideboard = append(sideboard, entry)
+			continue
+		}
+		writeTextLine(&result, entry)
+	}
+
+	if len(sideboard) > 0 {
+		if headers {
+			result.WriteString("\nSideboard\n")
+		} else {
+			result.WriteString("\nSIDEBOARD:\n")
+		}
+		for _, entry := range sideboard {
+			writeTextLine(&result, entry)
+		}
+	}
+	return result.Bytes()
+}
+
+func writeTextLine(b *bytes.Buffer, entry *Entry) {
+	fmt.Fprintf(b, "%d %s", entry.Amount, entry.Name)
+	if len(entry.Expansion) > 0 {
+		fmt.Fprintf(b, " (%s)", entry.Expansion)
+		if len(entry.CollectorNumber) > 0 {
+			fmt.Fprintf(b, " %s", entry.CollectorNumber)
+		}
+	}
+	b.WriteString("\n")
+}
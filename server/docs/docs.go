@@ -15,32 +15,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Checks the user data and returns a jwt token on correct Login",
+        "/admin/failed-logins": {
+            "get": {
+                "description": "Fetches the most recent failed login attempts, optionally filtered by username",
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Logs in the user",
+                "summary": "Fetch failed logins",
                 "parameters": [
                     {
-                        "description": "Login details",
-                        "name": "details",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginDetails"
-                        }
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.GetFailedLogin"
                         }
                     },
                     "401": {
@@ -48,34 +48,9 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Checks the user data and adds it to the repo",
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Registers the user",
-                "parameters": [
-                    {
-                        "description": "Register details",
-                        "name": "details",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RegisterDetails"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -83,53 +58,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/card": {
+        "/admin/users": {
             "get": {
-                "description": "Fetches all cards that match the query",
+                "description": "Fetches a page of users, optionally filtered by a username or email search",
                 "tags": [
-                    "Card"
+                    "Admin"
                 ],
-                "summary": "Fetch card by query",
+                "summary": "Fetch users",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "expansion",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "foilOnly",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "name": "inStockOnly",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "key",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "lang",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "name": "maxPrice",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "name": "minPrice",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "name",
-                        "in": "query"
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
                     },
                     {
                         "type": "integer",
@@ -138,17 +79,12 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "name": "raw",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "t",
+                        "name": "search",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "name": "type",
+                        "type": "boolean",
+                        "name": "suspendedOnly",
                         "in": "query"
                     }
                 ],
@@ -156,7 +92,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service.CardQueryResult"
+                            "$ref": "#/definitions/service.UserQueryResult"
                         }
                     },
                     "400": {
@@ -164,15 +100,29 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/demote": {
             "post": {
-                "description": "Creates a new card",
+                "description": "Removes the user's admin rights, admins can't demote themselves",
                 "tags": [
-                    "Card"
+                    "Admin"
                 ],
-                "summary": "Create new card",
+                "summary": "Demote user",
                 "parameters": [
                     {
                         "type": "string",
@@ -181,20 +131,18 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "new card data",
-                        "name": "card",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PostCard"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCard"
+                            "$ref": "#/definitions/dto.GetUser"
                         }
                     },
                     "400": {
@@ -214,68 +162,23 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/card/expansions": {
-            "get": {
-                "description": "Fetches all available expansions",
-                "tags": [
-                    "Expansions"
-                ],
-                "summary": "Get all expansions",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Expansion"
-                        }
-                    }
-                }
-            }
-        },
-        "/card/keys": {
-            "get": {
-                "description": "Fetches all card keys",
-                "tags": [
-                    "CardKeys"
-                ],
-                "summary": "Get all card keys",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.CardKey"
-                        }
-                    }
-                }
-            }
-        },
-        "/card/languages": {
-            "get": {
-                "description": "Fetches all available languages",
-                "tags": [
-                    "Language"
-                ],
-                "summary": "Get all languages",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.Language"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/card/price/{id}": {
-            "patch": {
-                "description": "Updates an existing card's price",
+        "/admin/users/{id}/password-reset": {
+            "post": {
+                "description": "Blocks password logins for the user and emails them a password reset token",
                 "tags": [
-                    "Card"
+                    "Admin"
                 ],
-                "summary": "Update card price",
+                "summary": "Force password reset",
                 "parameters": [
                     {
                         "type": "string",
@@ -285,26 +188,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Card ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "new card price",
-                        "name": "price",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PriceUpdate"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCard"
+                            "$ref": "#/definitions/dto.GetUser"
                         }
                     },
                     "400": {
@@ -334,13 +228,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/card/stocked/{id}": {
-            "patch": {
-                "description": "Updates the amount of cards stocked",
+        "/admin/users/{id}/promote": {
+            "post": {
+                "description": "Makes the user an admin",
                 "tags": [
-                    "Card"
+                    "Admin"
                 ],
-                "summary": "Update card stocked amount",
+                "summary": "Promote user",
                 "parameters": [
                     {
                         "type": "string",
@@ -350,26 +244,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Card ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "new card stock amount",
-                        "name": "price",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.StockedAmountUpdate"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCard"
+                            "$ref": "#/definitions/dto.GetUser"
                         }
                     },
                     "400": {
@@ -399,27 +284,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/card/{id}": {
-            "get": {
-                "description": "Fetches a card by it's id",
+        "/admin/users/{id}/suspend": {
+            "post": {
+                "description": "Suspends the user until the given time, a suspension without an end is a ban; suspended users can't log in or access the API",
                 "tags": [
-                    "Card"
+                    "Admin"
                 ],
-                "summary": "Fetch card by id",
+                "summary": "Suspend user",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
                     {
                         "type": "integer",
-                        "description": "Card ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Suspension details",
+                        "name": "suspension",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Suspension"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCard"
+                            "$ref": "#/definitions/dto.GetUser"
                         }
                     },
                     "400": {
@@ -428,20 +328,34 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Updates an existing card",
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/unlock": {
+            "post": {
+                "description": "Clears the failed login counters and lockout of a user",
                 "tags": [
-                    "Card"
+                    "Admin"
                 ],
-                "summary": "Update card",
+                "summary": "Unlock user",
                 "parameters": [
                     {
                         "type": "string",
@@ -451,27 +365,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Card ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "new card data",
-                        "name": "card",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PostCard"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.GetCard"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -500,13 +402,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/collection": {
+        "/admin/users/{id}/unsuspend": {
             "post": {
-                "description": "Creates a new card collection",
+                "description": "Lifts the user's suspension or ban",
                 "tags": [
-                    "Collection"
+                    "Admin"
                 ],
-                "summary": "Create new collection",
+                "summary": "Unsuspend user",
                 "parameters": [
                     {
                         "type": "string",
@@ -515,20 +417,18 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "new collection data",
-                        "name": "collection",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PostCollection"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCollection"
+                            "$ref": "#/definitions/dto.GetUser"
                         }
                     },
                     "400": {
@@ -542,30 +442,55 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/collection/all": {
-            "get": {
-                "description": "Fetches all the user's collections",
+        "/admin/users/{id}/verify": {
+            "post": {
+                "description": "Marks the user as verified",
                 "tags": [
-                    "Collection"
+                    "Admin"
                 ],
-                "summary": "Fetch all collections",
+                "summary": "Verify user",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Authenticator",
                         "name": "Authorization",
                         "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCollection"
+                            "$ref": "#/definitions/dto.GetUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "401": {
@@ -573,17 +498,29 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/collection/{collectionId}": {
+        "/auth/2fa/confirm": {
             "post": {
-                "description": "Adds, removes or alters a collection slot in an existing collection",
+                "description": "Enables 2FA for the user after checking a code generated from the enrolled secret",
                 "tags": [
-                    "Collection"
+                    "Auth"
                 ],
-                "summary": "Add, remove or alter collection slot",
+                "summary": "Confirm 2FA enrollment",
                 "parameters": [
                     {
                         "type": "string",
@@ -592,28 +529,18 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "description": "Collection ID",
-                        "name": "collectionId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "new collection slot data",
-                        "name": "collectionSlot",
+                        "description": "TOTP code",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.PostCollectionSlot"
+                            "$ref": "#/definitions/dto.TwoFactorCode"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/dto.GetCollection"
-                        }
+                    "200": {
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -626,44 +553,37 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
             }
         },
-        "/collection/{id}": {
-            "get": {
-                "description": "Fetches a collection by it's id",
+        "/auth/2fa/disable": {
+            "post": {
+                "description": "Disables 2FA for the user, requires a TOTP or recovery code",
                 "tags": [
-                    "Collection"
+                    "Auth"
                 ],
-                "summary": "Fetch collection by id",
+                "summary": "Disable 2FA",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Collection ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Authenticator",
                         "name": "Authorization",
                         "in": "header"
+                    },
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCode"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.GetCollection"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -676,29 +596,18 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a collection by it's id",
+            }
+        },
+        "/auth/2fa/enroll": {
+            "post": {
+                "description": "Generates a new TOTP secret and recovery codes for the user, 2FA is enabled only after confirming a code",
                 "tags": [
-                    "Collection"
+                    "Auth"
                 ],
-                "summary": "Delete collection",
+                "summary": "Start 2FA enrollment",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Collection ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Authenticator",
@@ -708,7 +617,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorEnrollment"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -721,42 +633,25 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
-            },
-            "patch": {
-                "description": "Deletes a collection's info by it's id",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Checks the user data and returns a jwt token on correct Login, users with 2FA enabled must also provide a TOTP or recovery code",
                 "tags": [
-                    "Collection"
+                    "Auth"
                 ],
-                "summary": "Update collection info",
+                "summary": "Logs in the user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Collection ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authenticator",
-                        "name": "Authorization",
-                        "in": "header"
-                    },
-                    {
-                        "description": "new collection data",
-                        "name": "collection",
+                        "description": "Login details",
+                        "name": "details",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.PostCollection"
+                            "$ref": "#/definitions/dto.LoginDetails"
                         }
                     }
                 ],
@@ -775,36 +670,42 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
             }
         },
-        "/user": {
-            "get": {
-                "description": "Gets the user's private information",
+        "/auth/oidc/2fa": {
+            "post": {
+                "description": "Checks the TOTP or recovery code of a user whose OIDC login required 2FA and returns a jwt token, the state can only be used once",
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Get user info",
+                "summary": "Finish OIDC login with 2FA",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Authenticator",
-                        "name": "Authorization",
-                        "in": "header"
+                        "description": "Login state and code",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OidcTwoFactor"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.PrivateUserInfo"
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "403": {
@@ -812,30 +713,47 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/user/cart": {
+        "/auth/oidc/callback": {
             "get": {
-                "description": "Fetches the user's cart",
+                "description": "Exchanges the authorization code, links or creates the user and returns a jwt token, users with 2FA enabled must then post the state and their code to /auth/oidc/2fa",
                 "tags": [
-                    "Cart"
+                    "Auth"
                 ],
-                "summary": "Fetch cart",
+                "summary": "Finish OIDC login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Authenticator",
-                        "name": "Authorization",
-                        "in": "header"
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCart"
+                            "type": "string"
                         }
                     },
                     "401": {
@@ -843,15 +761,200 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects the user to the identity provider's authorization endpoint, the login is tied to the browser with a cookie",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Start OIDC login",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset": {
+            "post": {
+                "description": "Sets a new password using the reset token sent by email",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordReset"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Checks the user data and adds it to the repo",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registers the user",
+                "parameters": [
+                    {
+                        "description": "Register details",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card": {
+            "get": {
+                "description": "Fetches all cards that match the query",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Fetch card by query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "currency of the price filters and the returned prices, empty for the store's currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "expansion",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "foilOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "inStockOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "name": "maxPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "name": "minPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "raw",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "t",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.CardQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Adds, removes or alters a cart slot",
+                "description": "Creates a new card",
                 "tags": [
-                    "Collection"
+                    "Card"
                 ],
-                "summary": "Add, remove or alter cart slot",
+                "summary": "Create new card",
                 "parameters": [
                     {
                         "type": "string",
@@ -860,20 +963,20 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "new cart slot data",
-                        "name": "collectionSlot",
+                        "description": "new card data",
+                        "name": "card",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.PostCollectionSlot"
+                            "$ref": "#/definitions/dto.PostCard"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetCollection"
+                            "$ref": "#/definitions/dto.GetCard"
                         }
                     },
                     "400": {
@@ -888,182 +991,6550 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.GetCard": {
-            "type": "object",
-            "properties": {
-                "cardType": {
-                    "$ref": "#/definitions/model.CardType"
-                },
-                "expansion": {
-                    "type": "string"
+        },
+        "/card/expansions": {
+            "get": {
+                "description": "Fetches all available expansions",
+                "tags": [
+                    "Expansions"
+                ],
+                "summary": "Get all expansions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Expansion"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/expansions/{id}/threshold": {
+            "patch": {
+                "description": "Sets the default low stock threshold of the expansion's cards, cards with their own threshold keep it. Admins are mailed when a card's stock falls to its threshold",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Update expansion low stock threshold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expansion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new threshold",
+                        "name": "threshold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LowStockThresholdUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Expansion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/keys": {
+            "get": {
+                "description": "Fetches all card keys",
+                "tags": [
+                    "CardKeys"
+                ],
+                "summary": "Get all card keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CardKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/languages": {
+            "get": {
+                "description": "Fetches all available languages",
+                "tags": [
+                    "Language"
+                ],
+                "summary": "Get all languages",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Language"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/low-stock": {
+            "get": {
+                "description": "Fetches the cards whose stock is at or below their low stock threshold, or their expansion's default one, the emptiest first",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Fetch low stock report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLowStockCard"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/price": {
+            "patch": {
+                "description": "Sets the prices of many cards in one transaction, none are changed if any of the cards doesn't exist",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Update card prices in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new card prices",
+                        "name": "prices",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkPriceUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/price/scale": {
+            "patch": {
+                "description": "Changes the prices of all cards matching the filter by a percentage in one transaction, an empty filter has to be confirmed with all. Prices in the filter are in the store's currency, none are changed if any card would cost 0",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Change card prices by a percentage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "card filter and percentage",
+                        "name": "scale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkPriceScale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/price/{id}": {
+            "patch": {
+                "description": "Updates an existing card's price",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Update card price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new card price",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PriceUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/stocked": {
+            "patch": {
+                "description": "Sets or adjusts the stocked amounts of many cards in one transaction, none are changed if any of the cards doesn't exist or would go out of stock below 0. Every change is recorded in the card's stock movements",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Update card stocked amounts in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new card stock amounts",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkStockUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/stocked/{id}": {
+            "patch": {
+                "description": "Updates the amount of cards stocked, the change is recorded in the card's stock movements as a correction unless another kind is given",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Update card stocked amount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new card stock amount",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StockedAmountUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/stocked/{id}/movements": {
+            "get": {
+                "description": "Fetches the card's stock ledger, newest first. Every change of its stocked amount is a movement with the resulting balance",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Fetch card stock movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetStockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/card/{id}": {
+            "get": {
+                "description": "Fetches a card by it's id",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Fetch card by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the price, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates an existing card, a changed stock is recorded in the card's stock ledger as a correction",
+                "tags": [
+                    "Card"
+                ],
+                "summary": "Update card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Card ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new card data",
+                        "name": "card",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCard"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection": {
+            "post": {
+                "description": "Creates a new card collection",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Create new collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new collection data",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/all": {
+            "get": {
+                "description": "Fetches the user's folders and collections as a tree, starting from the given folder or the top level. With a tag only collections with that tag and the folders that contain them are included",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Fetch all collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "folder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionTree"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/folder": {
+            "post": {
+                "description": "Creates a folder for organising collections, folders can be nested",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Create collection folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new folder data",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollectionFolder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollectionFolder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/folder/{id}": {
+            "delete": {
+                "description": "Deletes the folder, its subfolders and collections are moved to its parent",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Delete collection folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Renames the folder and moves it under another folder, or to the top level if no parent is given",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Rename or move collection folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Folder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new folder data",
+                        "name": "folder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollectionFolder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollectionFolder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/public/{username}": {
+            "get": {
+                "description": "Fetches all public collections of a user",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Fetch public collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetSharedCollection"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/shared/{token}": {
+            "get": {
+                "description": "Fetches an unlisted or public collection by it's share token",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Fetch shared collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetSharedCollection"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}": {
+            "post": {
+                "description": "Adds, removes or alters a collection slot in an existing collection",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Add, remove or alter collection slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new collection slot data",
+                        "name": "collectionSlot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollectionSlot"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/batch": {
+            "post": {
+                "description": "Adds, removes or alters many collection slots at once. Either all changes are applied or none are",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Edit many collection slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "collection slot changes",
+                        "name": "collectionSlots",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollectionSlots"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/copy": {
+            "post": {
+                "description": "Creates a private copy of one of the user's collections or a public collection. Copies of the user's own collections keep their folder and tags",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Copy collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "copy options",
+                        "name": "copy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CopyCollection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/diff": {
+            "post": {
+                "description": "Lists the printings whose amounts differ between the collection and a decklist, grouped by card. Lines that can't be resolved are reported and skipped",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Compare collection to decklist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "decklist",
+                        "name": "decklist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DecklistImport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/import": {
+            "post": {
+                "description": "Adds the cards of a decklist (text, arena or mtgo .dek) to the collection. Lines are resolved by english card name, expansion and collector number, lines that can't be resolved are reported and skipped",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Import decklist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "decklist",
+                        "name": "decklist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DecklistImport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DecklistImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/merge": {
+            "post": {
+                "description": "Adds the cards of another of the user's collections to the collection, summing the amounts of the same card. The merged collection can be deleted afterwards",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Merge collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "merged collection",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergeCollections"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/needs": {
+            "post": {
+                "description": "Lists the cards the collection is missing to build a decklist, priced from the cheapest printings in stock. Lines that can't be resolved are reported and skipped",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Price cards missing for decklist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "decklist",
+                        "name": "decklist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DecklistImport"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionNeeds"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{collectionId}/to-cart": {
+            "post": {
+                "description": "Adds all the collection's cards to the cart, capped by the stock. Cards that couldn't be fully added are reported, with substitute set the missing amount is made up with the cheapest in-stock printings of the same card",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Add collection to cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collectionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "substitute missing cards with other printings",
+                        "name": "substitute",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionToCart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{id}": {
+            "get": {
+                "description": "Fetches a collection by it's id",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Fetch collection by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a collection by it's id",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Delete collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Deletes a collection's info by it's id",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Update collection info",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new collection data",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{id}/diff/{otherId}": {
+            "get": {
+                "description": "Lists the printings whose amounts differ between the collection and another of the user's collections or a public collection, grouped by card",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Compare collections",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the collection to compare to",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{id}/export": {
+            "get": {
+                "description": "Writes the collection out as a decklist file",
+                "produces": [
+                    "text/plain",
+                    "text/xml"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Export decklist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "text, arena or mtgo, defaults to text",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{id}/needs/{otherId}": {
+            "get": {
+                "description": "Lists the cards the collection is missing to match another of the user's collections or a public collection, priced from the cheapest printings in stock",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Price missing cards",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the collection to compare to",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionNeeds"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/collection/{id}/value": {
+            "get": {
+                "description": "Sums the current price of the collection's cards, breaks it down by expansion, type and language and reports the change over the last 7 and 30 days",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Fetch collection value",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionValue"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/currency": {
+            "get": {
+                "description": "Fetches the store's currency and the currencies prices can be converted to",
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Fetch exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCurrencies"
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/load": {
+            "post": {
+                "description": "Updates the exchange rates from the configured file or feed, rates missing from it are kept",
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Load exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCurrencies"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/currency/{currency}": {
+            "put": {
+                "description": "Creates or replaces the exchange rate of a currency, in units of the currency per unit of the store's currency",
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Set exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "exchange rate",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostExchangeRate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetExchangeRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the exchange rate, prices can't be converted to the currency anymore",
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Delete exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/cart": {
+            "get": {
+                "description": "Fetches the cart of a user that hasn't logged in, identified by the guest cart cookie",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Fetch guest cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds, removes or alters a slot of the guest cart, starts a new guest cart if there is none",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Add, remove or alter guest cart slot",
+                "parameters": [
+                    {
+                        "description": "new cart slot data",
+                        "name": "cartSlot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCartSlot"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/guest/cart/batch": {
+            "post": {
+                "description": "Adds, removes or alters many slots of the guest cart at once. Either all changes are applied or none are",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Edit many guest cart slots",
+                "parameters": [
+                    {
+                        "description": "cart slot changes",
+                        "name": "cartSlots",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCartSlots"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/listing": {
+            "post": {
+                "description": "Lists copies of a catalogue printing on the marketplace, only verified users can sell",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Create listing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "listing",
+                        "name": "listing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostListing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetListing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/listing/card/{cardId}": {
+            "get": {
+                "description": "Fetches the store's own offer of a card and the marketplace listings of it, the cheapest listing first",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Fetch card offers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Card ID",
+                        "name": "cardId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CardOffers"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/listing/mine": {
+            "get": {
+                "description": "Fetches all of the user's marketplace listings, including ones without copies left",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Fetch own listings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetListing"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/listing/{id}": {
+            "get": {
+                "description": "Fetches a marketplace listing, including ones without copies left",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Fetch listing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the price, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetListing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes one of the user's listings",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Delete listing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes the price, condition, amount or comment of one of the user's listings, omitted fields are left unchanged",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Update listing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "listing changes",
+                        "name": "listing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchListing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetListing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/listing/{id}/buy": {
+            "post": {
+                "description": "Orders copies of another seller's listing at its current price, only verified users can buy",
+                "tags": [
+                    "Listing"
+                ],
+                "summary": "Buy listing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Listing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "purchase",
+                        "name": "purchase",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostListingPurchase"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payout": {
+            "get": {
+                "description": "Fetches the payouts of the user's marketplace sales, newest first",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Fetch own payouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPayout"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Records that the seller was paid the net amount of their unpaid sales in the period, only possible if the earlier payouts covering the period add up to the sales they paid",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Mark payout made",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "payout",
+                        "name": "payout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostPayout"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPayout"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payout/commission": {
+            "get": {
+                "description": "Fetches the percentage of the user's marketplace sales the store keeps",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Fetch own commission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCommission"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payout/seller/{sellerId}": {
+            "get": {
+                "description": "Fetches the payouts of a seller's marketplace sales, newest first",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Fetch seller payouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "sellerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPayout"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payout/seller/{sellerId}/commission": {
+            "get": {
+                "description": "Fetches the percentage of a seller's marketplace sales the store keeps",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Fetch seller commission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "sellerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCommission"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets the percentage of a seller's future marketplace sales the store keeps, null resets it to the store's",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Update seller commission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "sellerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "commission",
+                        "name": "commission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommissionUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCommission"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payout/seller/{sellerId}/statement": {
+            "get": {
+                "description": "Sums up a seller's marketplace sales of a period, by default from the first of the current month to today",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Fetch seller payout statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seller ID",
+                        "name": "sellerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayoutStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payout/statement": {
+            "get": {
+                "description": "Sums up the user's marketplace sales of a period, by default from the first of the current month to today",
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Fetch own payout statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayoutStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-schedule": {
+            "get": {
+                "description": "Fetches the price changes that are pending or in an active sales window, by their start",
+                "tags": [
+                    "PriceSchedule"
+                ],
+                "summary": "Fetch scheduled price changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only the changes of this card",
+                        "name": "cardId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetScheduledPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedules a card's price change. With an end it's a sales window, the card's previous price is restored when it ends",
+                "tags": [
+                    "PriceSchedule"
+                ],
+                "summary": "Schedule price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "price change",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostScheduledPrice"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetScheduledPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-schedule/{id}": {
+            "get": {
+                "description": "Fetches a scheduled price change, including done ones",
+                "tags": [
+                    "PriceSchedule"
+                ],
+                "summary": "Fetch scheduled price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Scheduled price change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetScheduledPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancels a pending price change, an active sales window is ended right away by restoring the card's previous price",
+                "tags": [
+                    "PriceSchedule"
+                ],
+                "summary": "Cancel scheduled price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Scheduled price change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion": {
+            "get": {
+                "description": "Fetches all promotions with their redemption counts, newest first",
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Fetch promotions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPromotion"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a coupon, or an automatic promotion if no code is given",
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Create promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "promotion data",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostPromotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPromotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/{id}": {
+            "get": {
+                "description": "Fetches a promotion with its redemption count",
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Fetch promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPromotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the promotion, its redemptions are kept for auditing",
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Delete promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Replaces the promotion's data, past redemptions are kept",
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Update promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "promotion data",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostPromotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPromotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/{id}/redemptions": {
+            "get": {
+                "description": "Fetches the orders the promotion was redeemed in, newest first",
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Fetch promotion redemptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPromotionRedemption"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trade": {
+            "get": {
+                "description": "Fetches the trades the user proposed or received, newest first",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Fetch own trades",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Proposes to trade cards of one of the user's collections for cards of another user's public collection, only verified users can trade",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Propose trade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "trade",
+                        "name": "trade",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostTrade"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trade/{id}": {
+            "get": {
+                "description": "Fetches a trade the user proposed or received",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Fetch trade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trade ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trade/{id}/accept": {
+            "post": {
+                "description": "Accepts a received trade, exchanging the cards between the collections all at once",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Accept trade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trade ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trade/{id}/cancel": {
+            "post": {
+                "description": "Withdraws a trade the user proposed",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Cancel trade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trade ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trade/{id}/counter": {
+            "post": {
+                "description": "Answers a received trade with a counter-offer, which closes it and proposes a new trade between the same collections in the other direction",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Counter trade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trade ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "counter-offer",
+                        "name": "trade",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCounterTrade"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trade/{id}/decline": {
+            "post": {
+                "description": "Declines a received trade",
+                "tags": [
+                    "Trade"
+                ],
+                "summary": "Decline trade",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trade ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTrade"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "description": "Gets the user's private information",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrivateUserInfo"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the user's collections and cart and anonymises the account, the password is required for users that have one, users created through OIDC must have logged in within the last five minutes",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Account deletion confirmation",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AccountDeletion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/cart": {
+            "get": {
+                "description": "Fetches the user's cart",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Fetch cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds, removes or alters a cart slot",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Add, remove or alter cart slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new cart slot data",
+                        "name": "collectionSlot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCollectionSlot"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/cart/batch": {
+            "post": {
+                "description": "Adds, removes or alters many cart slots at once. Either all changes are applied or none are",
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Edit many cart slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "cart slot changes",
+                        "name": "cartSlots",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostCartSlots"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/cart/checkout": {
+            "post": {
+                "description": "Orders the cart's cards at their current prices and promotions, takes them out of stock and empties the cart",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Check out cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/cart/promotion": {
+            "post": {
+                "description": "Sets the coupon of the user's cart, replacing the previous one",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Apply coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "coupon code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PromotionCode"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the coupon from the user's cart, automatic promotions still apply",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Remove coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the prices, the store's currency if empty",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCart"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/email": {
+            "post": {
+                "description": "Sends a verification token to the new email, the email is changed once the token is verified",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.EmailChange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/email/verify": {
+            "post": {
+                "description": "Checks the verification token and replaces the user's email with the pending one",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.EmailVerification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrivateUserInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "description": "Fetches all of the user's data: profile, collections and cart",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Export user data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/orders": {
+            "get": {
+                "description": "Fetches the user's orders, newest first",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Fetch orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrder"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/orders/{id}": {
+            "get": {
+                "description": "Fetches one of the user's orders",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Fetch order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/profile": {
+            "patch": {
+                "description": "Updates the user's display name, preferred currency and preferred language, omitted fields are left unchanged",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profile changes",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrivateUserInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/wishlist": {
+            "get": {
+                "description": "Fetches the cards on the user's wishlist",
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Fetch wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetWishlist"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a card printing or any printing of a card key to the wishlist, the user is mailed when it comes back in stock or its price drops to the max price",
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Add card to wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "wished card",
+                        "name": "wishlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PostWishlist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetWishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/wishlist/{id}": {
+            "delete": {
+                "description": "Removes an entry from the wishlist",
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Remove card from wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes the max price or min condition of a wishlist entry",
+                "tags": [
+                    "Wishlist"
+                ],
+                "summary": "Update wishlist entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authenticator",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "changed fields",
+                        "name": "wishlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchWishlist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetWishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AccountDeletion": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AppliedPromotion": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.BulkPriceEntry": {
+            "type": "object",
+            "required": [
+                "cardId"
+            ],
+            "properties": {
+                "cardId": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.BulkPriceScale": {
+            "type": "object",
+            "required": [
+                "percentage"
+            ],
+            "properties": {
+                "all": {
+                    "description": "has to be set to change the prices of all cards with an empty filter",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "description": "card query parameters like those of GET /card, e.g. \"expansion=LEA\u0026minPrice=10\", prices are in the store's currency",
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.BulkPriceUpdate": {
+            "type": "object",
+            "required": [
+                "prices"
+            ],
+            "properties": {
+                "prices": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.BulkPriceEntry"
+                    }
+                }
+            }
+        },
+        "dto.BulkStockEntry": {
+            "type": "object",
+            "required": [
+                "cardId"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "relative": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.BulkStockUpdate": {
+            "type": "object",
+            "required": [
+                "stock"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "restock",
+                        "sale",
+                        "return",
+                        "correction",
+                        "reservation"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "stock": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.BulkStockEntry"
+                    }
+                }
+            }
+        },
+        "dto.CardOffers": {
+            "type": "object",
+            "properties": {
+                "card": {
+                    "description": "the store's price and stock",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.GetCard"
+                        }
+                    ]
+                },
+                "cheapest": {
+                    "description": "cheapest listing, nil without listings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.GetListing"
+                        }
+                    ]
+                },
+                "listings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetListing"
+                    }
+                },
+                "storeIsCheapest": {
+                    "description": "the store has the card in stock for at most the cheapest listing's price",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CartShortfall": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "description": "amount that is still missing after substitution",
+                    "type": "integer"
+                },
+                "requested": {
+                    "type": "integer"
+                },
+                "substitutes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CartSubstitute"
+                    }
+                }
+            }
+        },
+        "dto.CartSubstitute": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CollectionDiff": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DiffEntry"
+                    }
+                },
+                "collectionId": {
+                    "type": "integer"
+                },
+                "unresolved": {
+                    "description": "decklist lines that couldn't be resolved, only set when comparing to a decklist",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UnresolvedLine"
+                    }
+                }
+            }
+        },
+        "dto.CollectionFolderNode": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetCollection"
+                    }
+                },
+                "folders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CollectionFolderNode"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CollectionNeeds": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NeededCard"
+                    }
+                },
+                "collectionId": {
+                    "type": "integer"
+                },
+                "totalCost": {
+                    "type": "number"
+                },
+                "unresolved": {
+                    "description": "decklist lines that couldn't be resolved, only set when comparing to a decklist",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UnresolvedLine"
+                    }
+                }
+            }
+        },
+        "dto.CollectionToCart": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "cart": {
+                    "$ref": "#/definitions/dto.GetCart"
+                },
+                "shortfalls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CartShortfall"
+                    }
+                }
+            }
+        },
+        "dto.CollectionTree": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetCollection"
+                    }
+                },
+                "folderId": {
+                    "type": "integer"
+                },
+                "folders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CollectionFolderNode"
+                    }
+                }
+            }
+        },
+        "dto.CollectionValue": {
+            "type": "object",
+            "properties": {
+                "byExpansion": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ValueBreakdown"
+                    }
+                },
+                "byLanguage": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ValueBreakdown"
+                    }
+                },
+                "byType": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ValueBreakdown"
+                    }
+                },
+                "cards": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ValueChange"
+                    }
+                },
+                "collectionId": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CommissionUpdate": {
+            "type": "object",
+            "properties": {
+                "percentage": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.CopyCollection": {
+            "type": "object",
+            "properties": {
+                "folderId": {
+                    "description": "defaults to the folder of the copied collection if it's the user's",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "defaults to the name of the copied collection",
+                    "type": "string",
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.DecklistImport": {
+            "type": "object",
+            "required": [
+                "decklist",
+                "format"
+            ],
+            "properties": {
+                "decklist": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "arena",
+                        "mtgo"
+                    ]
+                }
+            }
+        },
+        "dto.DecklistImportResult": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "$ref": "#/definitions/dto.GetCollection"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "unresolved": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UnresolvedLine"
+                    }
+                }
+            }
+        },
+        "dto.DiffEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "cardKeyId": {
+                    "type": "string"
+                },
+                "delta": {
+                    "description": "OtherAmount - Amount",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "otherAmount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.EmailChange": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EmailVerification": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetCard": {
+            "type": "object",
+            "properties": {
+                "cardType": {
+                    "$ref": "#/definitions/model.CardType"
+                },
+                "collectorNumber": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expansion": {
+                    "type": "string"
+                },
+                "expansionName": {
+                    "type": "string"
+                },
+                "foiling": {
+                    "$ref": "#/definitions/model.Foiling"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "inStockAmount": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "language": {
+                    "$ref": "#/definitions/model.Language"
+                },
+                "lowStockThreshold": {
+                    "description": "only the card's own threshold, not the expansion's default",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "offers": {
+                    "description": "marketplace listings of the card, nil without any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.OfferSummary"
+                        }
+                    ]
+                },
+                "price": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetCart": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetCartSlot"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "promotionCode": {
+                    "description": "coupon entered by the user, it's only listed in the promotions while it applies",
+                    "type": "string"
+                },
+                "promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AppliedPromotion"
+                    }
+                },
+                "shipping": {
+                    "description": "estimated from the store's shipping rates",
+                    "type": "number"
+                },
+                "subtotal": {
+                    "description": "sum of the line totals, archived cards aren't included",
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.GetCartSlot": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "archived": {
+                    "description": "set when the card was removed from the store, archived lines aren't priced",
+                    "type": "boolean"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "exceedsStock": {
+                    "description": "set when the amount is more than the store has in stock",
+                    "type": "boolean"
+                },
+                "inStockAmount": {
+                    "type": "integer"
+                },
+                "lineTotal": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.GetCollection": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetCollectionSlot"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "folderId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "shareToken": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetCollectionFolder": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetCollectionSlot": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetCommission": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "the seller doesn't have their own rate",
+                    "type": "boolean"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "sellerId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetCurrencies": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetExchangeRate"
+                    }
+                },
+                "storeCurrency": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetExchangeRate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetFailedLogin": {
+            "type": "object",
+            "properties": {
+                "clientIp": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetListing": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "seller": {
+                    "type": "string"
+                },
+                "sellerId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetLowStockCard": {
+            "type": "object",
+            "properties": {
+                "card": {
+                    "$ref": "#/definitions/dto.GetCard"
+                },
+                "threshold": {
+                    "description": "the card's own threshold or its expansion's default",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetOrder": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetOrderLine"
+                    }
+                },
+                "promotionCode": {
+                    "type": "string"
+                },
+                "shipping": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.GetOrderLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "lineTotal": {
+                    "type": "number"
+                },
+                "listingId": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sellerId": {
+                    "type": "integer"
+                },
+                "unitPrice": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.GetPayout": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "commission": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "paidById": {
+                    "type": "integer"
+                },
+                "periodEnd": {
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "sales": {
+                    "type": "integer"
+                },
+                "sellerId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetPromotion": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "buyAmount": {
+                    "type": "integer"
+                },
+                "cardTypeId": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "expansionId": {
+                    "type": "string"
+                },
+                "freeAmount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "maxRedemptions": {
+                    "type": "integer"
+                },
+                "maxRedemptionsPerUser": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "redemptions": {
+                    "type": "integer"
+                },
+                "startsAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetPromotionRedemption": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "redeemedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetScheduledPrice": {
+            "type": "object",
+            "properties": {
+                "cardId": {
+                    "type": "integer"
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "previousPrice": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetSellerSale": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "commission": {
+                    "type": "number"
+                },
+                "commissionPercentage": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "listingId": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "orderLineId": {
+                    "type": "integer"
+                },
+                "payoutId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetSharedCollection": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetCollectionSlot"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "ownerDisplayName": {
+                    "type": "string"
+                },
+                "shareToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetStockMovement": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetTrade": {
+            "type": "object",
+            "properties": {
+                "counterOfId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "offered": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetTradeCard"
+                    }
+                },
+                "proposerCollectionId": {
+                    "type": "integer"
+                },
+                "proposerId": {
+                    "type": "integer"
+                },
+                "recipientCollectionId": {
+                    "type": "integer"
+                },
+                "recipientId": {
+                    "type": "integer"
+                },
+                "requested": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetTradeCard"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetTradeCard": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetUser": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isAdmin": {
+                    "type": "boolean"
+                },
+                "passwordResetRequired": {
+                    "type": "boolean"
+                },
+                "suspended": {
+                    "type": "boolean"
+                },
+                "suspendedUntil": {
+                    "type": "string"
+                },
+                "suspensionReason": {
+                    "type": "string"
+                },
+                "twoFactorEnabled": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetWishlist": {
+            "type": "object",
+            "properties": {
+                "cardId": {
+                    "type": "integer"
+                },
+                "cardKeyId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "maxPrice": {
+                    "type": "number"
+                },
+                "minCondition": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginDetails": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LowStockThresholdUpdate": {
+            "type": "object",
+            "properties": {
+                "threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.MergeCollections": {
+            "type": "object",
+            "required": [
+                "sourceId"
+            ],
+            "properties": {
+                "deleteSource": {
+                    "type": "boolean"
+                },
+                "sourceId": {
+                    "description": "collection whose cards are added to the merged into collection",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NeededCard": {
+            "type": "object",
+            "properties": {
+                "cardKeyId": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "needed": {
+                    "type": "integer"
+                },
+                "printings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NeededPrinting"
+                    }
+                },
+                "unavailable": {
+                    "description": "amount that isn't in stock",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NeededPrinting": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.OfferSummary": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cheapestListingId": {
+                    "type": "integer"
+                },
+                "cheapestPrice": {
+                    "type": "number"
+                },
+                "listings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.OidcTwoFactor": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PasswordReset": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PatchListing": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "M",
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ]
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.PatchProfile": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "preferredCurrency": {
+                    "type": "string"
+                },
+                "preferredLanguageId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PatchWishlist": {
+            "type": "object",
+            "properties": {
+                "maxPrice": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "minCondition": {
+                    "type": "string",
+                    "enum": [
+                        "",
+                        "M",
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ]
+                }
+            }
+        },
+        "dto.PayoutStatement": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "paid": {
+                    "type": "number"
+                },
+                "payouts": {
+                    "type": "integer"
+                },
+                "reconciled": {
+                    "type": "boolean"
+                },
+                "sales": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetSellerSale"
+                    }
+                },
+                "sellerId": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "unpaid": {
+                    "type": "number"
+                },
+                "unreconciledPayouts": {
+                    "description": "ids of the payouts that don't add up",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.PostCard": {
+            "type": "object",
+            "required": [
+                "expansion",
+                "key",
+                "language",
+                "name",
+                "price",
+                "text",
+                "type"
+            ],
+            "properties": {
+                "collectorNumber": {
+                    "description": "CollectorNumber is the number of the card within its expansion",
+                    "type": "string"
+                },
+                "condition": {
+                    "description": "Condition defaults to near mint",
+                    "type": "string",
+                    "enum": [
+                        "M",
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ]
+                },
+                "expansion": {
+                    "type": "string"
+                },
+                "foiling": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "inStockAmount": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "lowStockThreshold": {
+                    "description": "LowStockThreshold overrides the expansion's default low stock threshold",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PostCartSlot": {
+            "type": "object",
+            "required": [
+                "amount",
+                "cardId"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "change of the amount, bounded so line totals can't overflow",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": -1000
+                },
+                "cardId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PostCartSlots": {
+            "type": "object",
+            "required": [
+                "slots"
+            ],
+            "properties": {
+                "slots": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.PostCartSlot"
+                    }
+                }
+            }
+        },
+        "dto.PostCollection": {
+            "type": "object",
+            "required": [
+                "name",
+                "tags"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "folderId": {
+                    "description": "nil for collections that aren't in a folder",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 3
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "visibility": {
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "dto.PostCollectionFolder": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "parentId": {
+                    "description": "nil for a top level folder",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PostCollectionSlot": {
+            "type": "object",
+            "required": [
+                "amount",
+                "cardId"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PostCollectionSlots": {
+            "type": "object",
+            "required": [
+                "slots"
+            ],
+            "properties": {
+                "slots": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.PostCollectionSlot"
+                    }
+                }
+            }
+        },
+        "dto.PostCounterTrade": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "offered": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/dto.TradeCard"
+                    }
+                },
+                "requested": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/dto.TradeCard"
+                    }
+                }
+            }
+        },
+        "dto.PostExchangeRate": {
+            "type": "object",
+            "required": [
+                "rate"
+            ],
+            "properties": {
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.PostListing": {
+            "type": "object",
+            "required": [
+                "amount",
+                "cardId",
+                "condition",
+                "price"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cardId": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "M",
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ]
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.PostListingPurchase": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PostPayout": {
+            "type": "object",
+            "required": [
+                "from",
+                "sellerId",
+                "to"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "sellerId": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PostPromotion": {
+            "type": "object",
+            "required": [
+                "description",
+                "kind"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "buyAmount": {
+                    "type": "integer"
+                },
+                "cardTypeId": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 256
                 },
-                "expansionName": {
+                "endsAt": {
                     "type": "string"
                 },
-                "foiling": {
-                    "$ref": "#/definitions/model.Foiling"
+                "expansionId": {
+                    "type": "string",
+                    "minLength": 1
                 },
-                "id": {
+                "freeAmount": {
                     "type": "integer"
                 },
-                "imageUrl": {
-                    "type": "string"
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed",
+                        "buyXGetY"
+                    ]
                 },
-                "inStockAmount": {
+                "maxRedemptions": {
                     "type": "integer"
                 },
-                "key": {
-                    "type": "string"
-                },
-                "language": {
-                    "$ref": "#/definitions/model.Language"
-                },
-                "name": {
-                    "type": "string"
+                "maxRedemptionsPerUser": {
+                    "type": "integer"
                 },
-                "price": {
-                    "type": "number"
+                "percentage": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
                 },
-                "text": {
+                "startsAt": {
                     "type": "string"
                 }
             }
         },
-        "dto.GetCart": {
-            "type": "object",
-            "properties": {
-                "cards": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.GetCartSlot"
-                    }
-                }
-            }
-        },
-        "dto.GetCartSlot": {
+        "dto.PostScheduledPrice": {
             "type": "object",
+            "required": [
+                "cardId",
+                "startsAt"
+            ],
             "properties": {
-                "amount": {
-                    "type": "integer"
-                },
                 "cardId": {
                     "type": "integer"
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "startsAt": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GetCollection": {
+        "dto.PostTrade": {
             "type": "object",
+            "required": [
+                "collectionId",
+                "recipientCollectionId"
+            ],
             "properties": {
-                "cards": {
+                "collectionId": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 512
+                },
+                "offered": {
                     "type": "array",
+                    "maxItems": 100,
                     "items": {
-                        "$ref": "#/definitions/dto.GetCollectionSlot"
+                        "$ref": "#/definitions/dto.TradeCard"
                     }
                 },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
+                "recipientCollectionId": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "requested": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/dto.TradeCard"
+                    }
                 }
             }
         },
-        "dto.GetCollectionSlot": {
+        "dto.PostWishlist": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer"
-                },
                 "cardId": {
                     "type": "integer"
+                },
+                "cardKeyId": {
+                    "type": "string"
+                },
+                "maxPrice": {
+                    "type": "number"
+                },
+                "minCondition": {
+                    "type": "string",
+                    "enum": [
+                        "M",
+                        "NM",
+                        "LP",
+                        "MP",
+                        "HP",
+                        "DMG"
+                    ]
                 }
             }
         },
-        "dto.LoginDetails": {
+        "dto.PriceUpdate": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
+                "newPrice": {
+                    "type": "number"
                 }
             }
         },
-        "dto.PostCard": {
+        "dto.PrivateUserInfo": {
             "type": "object",
-            "required": [
-                "expansion",
-                "key",
-                "language",
-                "name",
-                "price",
-                "text",
-                "type"
-            ],
             "properties": {
-                "expansion": {
+                "displayName": {
                     "type": "string"
                 },
-                "foiling": {
+                "email": {
                     "type": "string"
                 },
-                "imageUrl": {
+                "id": {
                     "type": "string"
                 },
-                "inStockAmount": {
-                    "type": "integer"
+                "isAdmin": {
+                    "type": "boolean"
                 },
-                "key": {
+                "pendingEmail": {
                     "type": "string"
                 },
-                "language": {
+                "preferredCurrency": {
                     "type": "string"
                 },
-                "name": {
+                "preferredLanguageId": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "twoFactorEnabled": {
+                    "type": "boolean"
                 },
-                "text": {
+                "username": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string"
+                "verified": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.PostCollection": {
+        "dto.PromotionCode": {
             "type": "object",
             "required": [
-                "name"
+                "code"
             ],
             "properties": {
-                "description": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "dto.RegisterDetails": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "name": {
+                "password": {
                     "type": "string",
-                    "minLength": 3
+                    "maxLength": 20,
+                    "minLength": 8
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 4
                 }
             }
         },
-        "dto.PostCollectionSlot": {
+        "dto.StockedAmountUpdate": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "restock",
+                        "sale",
+                        "return",
+                        "correction",
+                        "reservation"
+                    ]
+                },
+                "newAmount": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 256
+                }
+            }
+        },
+        "dto.Suspension": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TradeCard": {
             "type": "object",
             "required": [
                 "amount",
@@ -1078,65 +7549,118 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.PriceUpdate": {
+        "dto.TwoFactorCode": {
             "type": "object",
+            "required": [
+                "code"
+            ],
             "properties": {
-                "newPrice": {
-                    "type": "number"
+                "code": {
+                    "type": "string"
                 }
             }
         },
-        "dto.PrivateUserInfo": {
+        "dto.TwoFactorEnrollment": {
             "type": "object",
             "properties": {
-                "id": {
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
                     "type": "string"
                 },
-                "isAdmin": {
-                    "type": "boolean"
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UnresolvedLine": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
                 },
-                "username": {
+                "reason": {
                     "type": "string"
                 },
-                "verified": {
-                    "type": "boolean"
+                "text": {
+                    "type": "string"
                 }
             }
         },
-        "dto.RegisterDetails": {
+        "dto.UserExport": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
+                "cart": {
+                    "$ref": "#/definitions/dto.GetCart"
+                },
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetCollection"
+                    }
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetOrder"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/dto.PrivateUserInfo"
+                },
+                "wishlist": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetWishlist"
+                    }
+                }
+            }
+        },
+        "dto.ValueBreakdown": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 8
+                "name": {
+                    "type": "string"
                 },
-                "username": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 4
+                "value": {
+                    "type": "number"
                 }
             }
         },
-        "dto.StockedAmountUpdate": {
+        "dto.ValueChange": {
             "type": "object",
             "properties": {
-                "newAmount": {
+                "change": {
+                    "type": "number"
+                },
+                "changePercent": {
+                    "description": "nil when the collection had no value at the start of the period",
+                    "type": "number"
+                },
+                "days": {
                     "type": "integer"
+                },
+                "previousValue": {
+                    "type": "number"
                 }
             }
         },
         "model.CardKey": {
             "type": "object",
             "properties": {
+                "engName": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 }
@@ -1162,6 +7686,10 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "lowStockThreshold": {
+                    "description": "default low stock threshold of the expansion's cards, nil doesn't watch them",
+                    "type": "integer"
+                },
                 "shortName": {
                     "type": "string"
                 }
@@ -1208,6 +7736,23 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "service.UserQueryResult": {
+            "type": "object",
+            "properties": {
+                "perPage": {
+                    "type": "integer"
+                },
+                "totalUsers": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetUser"
+                    }
+                }
+            }
         }
     }
 }`
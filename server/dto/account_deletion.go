@@ -0,0 +1,6 @@
+package dto
+
+// password is required for users that have one, users created through OIDC confirm with a recent login instead
+type AccountDeletion struct {
+	Password string `json:"password"`
+}
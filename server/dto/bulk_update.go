@@ -0,0 +1,35 @@
+package dto
+
+import "store.api/model"
+
+type BulkPriceEntry struct {
+	CardId uint        `json:"cardId" validate:"required"`
+	Price  model.Money `json:"price" validate:"gt=0"`
+}
+
+type BulkPriceUpdate struct {
+	Prices []BulkPriceEntry `json:"prices" validate:"required,min=1,max=1000,dive"`
+}
+
+// BulkPriceScale changes the prices of all cards matching the filter by a percentage, rounded to the cent
+type BulkPriceScale struct {
+	// card query parameters like those of GET /card, e.g. "expansion=LEA&minPrice=10", prices are in the store's currency
+	Filter string `json:"filter"`
+	// has to be set to change the prices of all cards with an empty filter
+	All        bool    `json:"all"`
+	Percentage float64 `json:"percentage" validate:"required,gt=-100"`
+}
+
+// BulkStockEntry sets the card's stock to Amount, or adds Amount to it if Relative
+type BulkStockEntry struct {
+	CardId   uint `json:"cardId" validate:"required"`
+	Amount   int  `json:"amount"`
+	Relative bool `json:"relative"`
+}
+
+// BulkStockUpdate changes the stock of many cards, the changes are recorded in the stock ledger as corrections unless another kind is given
+type BulkStockUpdate struct {
+	Stock  []BulkStockEntry `json:"stock" validate:"required,min=1,max=1000,dive"`
+	Kind   string           `json:"kind" validate:"omitempty,oneof=restock sale return correction reservation"`
+	Reason string           `json:"reason" validate:"lte=256"`
+}
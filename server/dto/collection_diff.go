@@ -0,0 +1,47 @@
+package dto
+
+import "store.api/model"
+
+// DiffEntry is a printing whose amount differs between the collection and what it's compared to,
+// the card key groups the printings of the same card
+type DiffEntry struct {
+	CardId      uint   `json:"cardId"`
+	CardKeyId   string `json:"cardKeyId"`
+	Name        string `json:"name"`
+	Amount      uint   `json:"amount"`
+	OtherAmount uint   `json:"otherAmount"`
+	// OtherAmount - Amount
+	Delta int `json:"delta"`
+}
+
+type CollectionDiff struct {
+	CollectionId uint         `json:"collectionId"`
+	Cards        []*DiffEntry `json:"cards"`
+	// decklist lines that couldn't be resolved, only set when comparing to a decklist
+	Unresolved []*UnresolvedLine `json:"unresolved,omitempty"`
+}
+
+type NeededPrinting struct {
+	CardId uint        `json:"cardId"`
+	Amount uint        `json:"amount"`
+	Price  model.Money `json:"price"`
+}
+
+// NeededCard is a card missing from the collection, priced from the cheapest printings in stock
+type NeededCard struct {
+	CardKeyId string            `json:"cardKeyId"`
+	Name      string            `json:"name"`
+	Needed    uint              `json:"needed"`
+	Printings []*NeededPrinting `json:"printings"`
+	Cost      model.Money       `json:"cost"`
+	// amount that isn't in stock
+	Unavailable uint `json:"unavailable"`
+}
+
+type CollectionNeeds struct {
+	CollectionId uint          `json:"collectionId"`
+	Cards        []*NeededCard `json:"cards"`
+	TotalCost    model.Money   `json:"totalCost"`
+	// decklist lines that couldn't be resolved, only set when comparing to a decklist
+	Unresolved []*UnresolvedLine `json:"unresolved,omitempty"`
+}
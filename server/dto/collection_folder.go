@@ -0,0 +1,50 @@
+package dto
+
+import "store.api/model"
+
+type PostCollectionFolder struct {
+	Name string `json:"name" validate:"required,lte=64"`
+	// nil for a top level folder
+	ParentId *uint `json:"parentId"`
+}
+
+type GetCollectionFolder struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	ParentId *uint  `json:"parentId"`
+}
+
+func NewGetCollectionFolder(folder *model.CollectionFolder) *GetCollectionFolder {
+	return &GetCollectionFolder{
+		ID:       folder.ID,
+		Name:     folder.Name,
+		ParentId: folder.ParentID,
+	}
+}
+
+type CollectionFolderNode struct {
+	ID          uint                    `json:"id"`
+	Name        string                  `json:"name"`
+	Folders     []*CollectionFolderNode `json:"folders"`
+	Collections []*GetCollection        `json:"collections"`
+}
+
+// CollectionTree holds the user's folders and collections, starting either from the top level or from a folder
+type CollectionTree struct {
+	FolderId    *uint                   `json:"folderId"`
+	Folders     []*CollectionFolderNode `json:"folders"`
+	Collections []*GetCollection        `json:"collections"`
+}
+
+type CopyCollection struct {
+	// defaults to the name of the copied collection
+	Name string `json:"name" validate:"omitempty,gte=3"`
+	// defaults to the folder of the copied collection if it's the user's
+	FolderId *uint `json:"folderId"`
+}
+
+type MergeCollections struct {
+	// collection whose cards are added to the merged into collection
+	SourceId     uint `json:"sourceId" validate:"required"`
+	DeleteSource bool `json:"deleteSource"`
+}
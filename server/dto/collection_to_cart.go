@@ -0,0 +1,26 @@
+package dto
+
+import "store.api/model"
+
+// CartSubstitute is another printing of the same card added in place of the missing amount
+type CartSubstitute struct {
+	CardId uint        `json:"cardId"`
+	Amount uint        `json:"amount"`
+	Price  model.Money `json:"price"`
+}
+
+// CartShortfall is a collection slot that couldn't be fully added to the cart from its own printing
+type CartShortfall struct {
+	CardId      uint              `json:"cardId"`
+	Requested   uint              `json:"requested"`
+	Added       uint              `json:"added"`
+	Substitutes []*CartSubstitute `json:"substitutes"`
+	// amount that is still missing after substitution
+	Missing uint `json:"missing"`
+}
+
+type CollectionToCart struct {
+	Cart       *GetCart         `json:"cart"`
+	Added      uint             `json:"added"`
+	Shortfalls []*CartShortfall `json:"shortfalls"`
+}
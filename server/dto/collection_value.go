@@ -0,0 +1,28 @@
+package dto
+
+import "store.api/model"
+
+type ValueBreakdown struct {
+	Id    string      `json:"id"`
+	Name  string      `json:"name"`
+	Cards uint        `json:"cards"`
+	Value model.Money `json:"value"`
+}
+
+type ValueChange struct {
+	Days          uint        `json:"days"`
+	PreviousValue model.Money `json:"previousValue"`
+	Change        model.Money `json:"change"`
+	// nil when the collection had no value at the start of the period
+	ChangePercent *float64 `json:"changePercent"`
+}
+
+type CollectionValue struct {
+	CollectionId uint              `json:"collectionId"`
+	Cards        uint              `json:"cards"`
+	Value        model.Money       `json:"value"`
+	ByExpansion  []*ValueBreakdown `json:"byExpansion"`
+	ByType       []*ValueBreakdown `json:"byType"`
+	ByLanguage   []*ValueBreakdown `json:"byLanguage"`
+	Changes      []*ValueChange    `json:"changes"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+type DecklistImport struct {
+	Format   string `json:"format" validate:"required,oneof=text arena mtgo"`
+	Decklist string `json:"decklist" validate:"required"`
+}
+
+// UnresolvedLine is a decklist line that wasn't imported
+type UnresolvedLine struct {
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+type DecklistImportResult struct {
+	Collection *GetCollection    `json:"collection"`
+	Imported   uint              `json:"imported"`
+	Unresolved []*UnresolvedLine `json:"unresolved"`
+}
+
+// DecklistExport is a collection written out as a decklist file
+type DecklistExport struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
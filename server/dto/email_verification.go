@@ -0,0 +1,5 @@
+package dto
+
+type EmailVerification struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+// GetCurrencies lists the currencies prices can be converted to, rates are per unit of the store's currency
+type GetCurrencies struct {
+	StoreCurrency string             `json:"storeCurrency"`
+	Rates         []*GetExchangeRate `json:"rates"`
+}
+
+type GetExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type PostExchangeRate struct {
+	Rate float64 `json:"rate" validate:"required,gt=0"`
+}
+
+// ExchangeRateFeed is the format of the exchange rate source, rates are per unit of the base currency
+type ExchangeRateFeed struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func NewGetExchangeRate(r *model.ExchangeRate) *GetExchangeRate {
+	return &GetExchangeRate{
+		Currency:  r.Currency,
+		Rate:      r.Rate,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
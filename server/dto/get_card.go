@@ -3,33 +3,44 @@ package dto
 import "store.api/model"
 
 type GetCard struct {
-	ID            uint           `json:"id"`
-	Name          string         `json:"name"`
-	Text          string         `json:"text"`
-	ImageUrl      string         `json:"imageUrl"`
-	Price         float32        `json:"price"`
-	Type          model.CardType `json:"cardType"`
-	Language      model.Language `json:"language"`
-	Foiling       model.Foiling  `json:"foiling"`
-	Key           string         `json:"key"`
-	Expansion     string         `json:"expansion"`
-	ExpansionName string         `json:"expansionName"`
-	InStockAmount uint           `json:"inStockAmount"`
+	ID              uint           `json:"id"`
+	Name            string         `json:"name"`
+	Text            string         `json:"text"`
+	ImageUrl        string         `json:"imageUrl"`
+	Price           model.Money    `json:"price"`
+	Currency        string         `json:"currency"`
+	Type            model.CardType `json:"cardType"`
+	Language        model.Language `json:"language"`
+	Foiling         model.Foiling  `json:"foiling"`
+	Key             string         `json:"key"`
+	Expansion       string         `json:"expansion"`
+	ExpansionName   string         `json:"expansionName"`
+	InStockAmount   uint           `json:"inStockAmount"`
+	CollectorNumber string         `json:"collectorNumber"`
+	Condition       string         `json:"condition"`
+	// only the card's own threshold, not the expansion's default
+	LowStockThreshold *uint `json:"lowStockThreshold"`
+	// marketplace listings of the card, nil without any
+	Offers *OfferSummary `json:"offers,omitempty"`
 }
 
 func NewGetCard(c *model.Card) *GetCard {
 	return &GetCard{
-		ID:            c.ID,
-		Name:          c.Name,
-		Text:          c.Text,
-		ImageUrl:      c.ImageUrl,
-		Price:         c.Price,
-		Type:          c.CardType,
-		Language:      c.Language,
-		Key:           c.CardKeyID,
-		Expansion:     c.Expansion.ShortName,
-		ExpansionName: c.Expansion.FullName,
-		InStockAmount: c.InStockAmount,
-		Foiling:       c.Foiling,
+		ID:              c.ID,
+		Name:            c.Name,
+		Text:            c.Text,
+		ImageUrl:        c.ImageUrl,
+		Price:           c.Price,
+		Type:            c.CardType,
+		Language:        c.Language,
+		Key:             c.CardKeyID,
+		Expansion:       c.Expansion.ShortName,
+		ExpansionName:   c.Expansion.FullName,
+		InStockAmount:   c.InStockAmount,
+		Foiling:         c.Foiling,
+		CollectorNumber: c.CollectorNumber,
+		Condition:       string(c.Condition),
+
+		LowStockThreshold: c.LowStockThreshold,
 	}
 }
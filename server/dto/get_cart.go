@@ -7,8 +7,26 @@ import (
 
 type GetCart struct {
 	Cards []*GetCartSlot `json:"cards"`
+	// sum of the line totals, archived cards aren't included
+	Subtotal   model.Money         `json:"subtotal"`
+	Promotions []*AppliedPromotion `json:"promotions"`
+	// coupon entered by the user, it's only listed in the promotions while it applies
+	PromotionCode *string     `json:"promotionCode"`
+	Discount      model.Money `json:"discount"`
+	// estimated from the store's shipping rates
+	Shipping model.Money `json:"shipping"`
+	Total    model.Money `json:"total"`
+	Currency string      `json:"currency"`
 }
 
+// AppliedPromotion is a promotion discounting the cart, automatic promotions have no code
+type AppliedPromotion struct {
+	Code        string      `json:"code"`
+	Description string      `json:"description"`
+	Discount    model.Money `json:"discount"`
+}
+
+// NewGetCart maps the cart's slots, prices are left for the cart pricer to fill in
 func NewGetCart(cart *model.Cart) *GetCart {
 	return &GetCart{
 		Cards: utility.MapSlice(
@@ -17,5 +35,7 @@ func NewGetCart(cart *model.Cart) *GetCart {
 				return NewGetCartSlot(&c)
 			},
 		),
+		Promotions:    []*AppliedPromotion{},
+		PromotionCode: cart.PromotionCode,
 	}
 }
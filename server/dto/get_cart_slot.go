@@ -3,8 +3,16 @@ package dto
 import "store.api/model"
 
 type GetCartSlot struct {
-	Amount uint `gorm:"not null" json:"amount"`
-	CardId uint `gorm:"not null" json:"cardId"`
+	Amount    uint        `gorm:"not null" json:"amount"`
+	CardId    uint        `gorm:"not null" json:"cardId"`
+	Name      string      `json:"name"`
+	Price     model.Money `json:"price"`
+	LineTotal model.Money `json:"lineTotal"`
+	// set when the amount is more than the store has in stock
+	ExceedsStock  bool `json:"exceedsStock"`
+	InStockAmount uint `json:"inStockAmount"`
+	// set when the card was removed from the store, archived lines aren't priced
+	Archived bool `json:"archived"`
 }
 
 func NewGetCartSlot(slot *model.CartSlot) *GetCartSlot {
@@ -9,21 +9,63 @@ type GetCollection struct {
 	ID          uint                 `json:"id"`
 	Name        string               `json:"name"`
 	Description string               `json:"description"`
+	Visibility  string               `json:"visibility"`
+	ShareToken  string               `json:"shareToken,omitempty"`
 	Cards       []*GetCollectionSlot `json:"cards"`
+	FolderId    *uint                `json:"folderId"`
+	Tags        []string             `json:"tags"`
 	OwnerId     uint                 `json:"-"`
 }
 
 func NewGetCollection(col *model.Collection) *GetCollection {
-	return &GetCollection{
+	result := &GetCollection{
 		ID:          col.ID,
 		Name:        col.Name,
 		Description: col.Description,
+		Visibility:  string(col.Visibility),
 		Cards: utility.MapSlice(
 			col.Cards,
 			func(c model.CollectionSlot) *GetCollectionSlot {
 				return NewGetCollectionSlot(&c)
 			},
 		),
-		OwnerId: col.OwnerID,
+		FolderId: col.FolderID,
+		Tags:     col.Tags,
+		OwnerId:  col.OwnerID,
 	}
+	if col.ShareToken != nil {
+		result.ShareToken = *col.ShareToken
+	}
+	return result
+}
+
+// GetSharedCollection is a collection as seen by users other than the owner
+type GetSharedCollection struct {
+	ID               uint                 `json:"id"`
+	Name             string               `json:"name"`
+	Description      string               `json:"description"`
+	Owner            string               `json:"owner"`
+	OwnerDisplayName string               `json:"ownerDisplayName"`
+	ShareToken       string               `json:"shareToken"`
+	Cards            []*GetCollectionSlot `json:"cards"`
+}
+
+func NewGetSharedCollection(col *model.Collection, owner *model.User) *GetSharedCollection {
+	result := &GetSharedCollection{
+		ID:               col.ID,
+		Name:             col.Name,
+		Description:      col.Description,
+		Owner:            owner.Username,
+		OwnerDisplayName: owner.DisplayName,
+		Cards: utility.MapSlice(
+			col.Cards,
+			func(c model.CollectionSlot) *GetCollectionSlot {
+				return NewGetCollectionSlot(&c)
+			},
+		),
+	}
+	if col.ShareToken != nil {
+		result.ShareToken = *col.ShareToken
+	}
+	return result
 }
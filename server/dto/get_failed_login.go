@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+type GetFailedLogin struct {
+	Username string    `json:"username"`
+	ClientIp string    `json:"clientIp"`
+	Reason   string    `json:"reason"`
+	Time     time.Time `json:"time"`
+}
+
+func NewGetFailedLogin(failed *model.FailedLogin) *GetFailedLogin {
+	return &GetFailedLogin{
+		Username: failed.Username,
+		ClientIp: failed.ClientIp,
+		Reason:   failed.Reason,
+		Time:     failed.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+// GetUser is the user as seen by admins
+type GetUser struct {
+	Id               uint       `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	IsAdmin          bool       `json:"isAdmin"`
+	Verified         bool       `json:"verified"`
+	TwoFactorEnabled bool       `json:"twoFactorEnabled"`
+	Suspended        bool       `json:"suspended"`
+	SuspendedUntil   *time.Time `json:"suspendedUntil"`
+	SuspensionReason string     `json:"suspensionReason"`
+	PasswordReset    bool       `json:"passwordResetRequired"`
+	Created          time.Time  `json:"created"`
+}
+
+func NewGetUser(user *model.User) *GetUser {
+	return &GetUser{
+		Id:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		IsAdmin:          user.IsAdmin,
+		Verified:         user.Verified,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		Suspended:        user.Suspended,
+		SuspendedUntil:   user.SuspendedUntil,
+		SuspensionReason: user.SuspensionReason,
+		PasswordReset:    user.PasswordResetRequired,
+		Created:          user.CreatedAt,
+	}
+}
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+type PostListing struct {
+	CardId    uint        `json:"cardId" validate:"required"`
+	Price     model.Money `json:"price" validate:"required,gt=0"`
+	Condition string      `json:"condition" validate:"required,oneof=M NM LP MP HP DMG"`
+	Amount    uint        `json:"amount" validate:"required,gt=0"`
+	Comment   string      `json:"comment" validate:"lte=512"`
+}
+
+func (l *PostListing) ToListing(sellerId uint) *model.Listing {
+	return &model.Listing{
+		SellerID:  sellerId,
+		CardID:    l.CardId,
+		Price:     l.Price,
+		Condition: model.CardCondition(l.Condition),
+		Amount:    l.Amount,
+		Comment:   l.Comment,
+	}
+}
+
+// omitted fields are left unchanged, an amount of 0 stops offering the listing without deleting it
+type PatchListing struct {
+	Price     *model.Money `json:"price" validate:"omitempty,gt=0"`
+	Condition *string      `json:"condition" validate:"omitempty,oneof=M NM LP MP HP DMG"`
+	Amount    *uint        `json:"amount"`
+	Comment   *string      `json:"comment" validate:"omitempty,lte=512"`
+}
+
+// PostListingPurchase buys copies of a listing, the seller is paid through the store
+type PostListingPurchase struct {
+	Amount uint `json:"amount" validate:"required,gt=0"`
+}
+
+type GetListing struct {
+	Id        uint        `json:"id"`
+	CardId    uint        `json:"cardId"`
+	SellerId  uint        `json:"sellerId"`
+	Seller    string      `json:"seller"`
+	Price     model.Money `json:"price"`
+	Currency  string      `json:"currency"`
+	Condition string      `json:"condition"`
+	Amount    uint        `json:"amount"`
+	Comment   string      `json:"comment"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+func NewGetListing(l *model.Listing) *GetListing {
+	return &GetListing{
+		Id:        l.ID,
+		CardId:    l.CardID,
+		SellerId:  l.SellerID,
+		Seller:    l.Seller.Username,
+		Price:     l.Price,
+		Condition: string(l.Condition),
+		Amount:    l.Amount,
+		Comment:   l.Comment,
+		CreatedAt: l.CreatedAt,
+	}
+}
+
+// CardOffers are the store's own offer of a card and the marketplace listings of it
+type CardOffers struct {
+	// the store's price and stock
+	Card     *GetCard      `json:"card"`
+	Listings []*GetListing `json:"listings"`
+	// cheapest listing, nil without listings
+	Cheapest *GetListing `json:"cheapest"`
+	// the store has the card in stock for at most the cheapest listing's price
+	StoreIsCheapest bool `json:"storeIsCheapest"`
+}
+
+// OfferSummary sums up the marketplace listings of a card in card queries
+type OfferSummary struct {
+	Listings          uint        `json:"listings"`
+	Amount            uint        `json:"amount"`
+	CheapestListingId uint        `json:"cheapestListingId"`
+	CheapestPrice     model.Money `json:"cheapestPrice"`
+}
@@ -3,4 +3,7 @@ package dto
 type LoginDetails struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Code     string `json:"code"`
+
+	ClientIp string `json:"-"`
 }
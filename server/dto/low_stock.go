@@ -0,0 +1,12 @@
+package dto
+
+// LowStockThresholdUpdate sets an expansion's default low stock threshold, null stops watching its cards
+type LowStockThresholdUpdate struct {
+	Threshold *uint `json:"threshold"`
+}
+
+type GetLowStockCard struct {
+	Card *GetCard `json:"card"`
+	// the card's own threshold or its expansion's default
+	Threshold uint `json:"threshold"`
+}
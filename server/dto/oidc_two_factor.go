@@ -0,0 +1,6 @@
+package dto
+
+type OidcTwoFactor struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
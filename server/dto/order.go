@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+	"store.api/utility"
+)
+
+type GetOrder struct {
+	Id            uint            `json:"id"`
+	Lines         []*GetOrderLine `json:"lines"`
+	Subtotal      model.Money     `json:"subtotal"`
+	Discount      model.Money     `json:"discount"`
+	Shipping      model.Money     `json:"shipping"`
+	Total         model.Money     `json:"total"`
+	PromotionCode *string         `json:"promotionCode"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+type GetOrderLine struct {
+	CardId    uint        `json:"cardId"`
+	Name      string      `json:"name"`
+	UnitPrice model.Money `json:"unitPrice"`
+	Amount    uint        `json:"amount"`
+	LineTotal model.Money `json:"lineTotal"`
+	ListingId *uint       `json:"listingId"`
+	SellerId  *uint       `json:"sellerId"`
+}
+
+func NewGetOrder(o *model.Order) *GetOrder {
+	return &GetOrder{
+		Id: o.ID,
+		Lines: utility.MapSlice(
+			o.Lines,
+			func(l model.OrderLine) *GetOrderLine {
+				return &GetOrderLine{
+					CardId:    l.CardID,
+					Name:      l.Name,
+					UnitPrice: l.UnitPrice,
+					Amount:    l.Amount,
+					LineTotal: l.LineTotal,
+					ListingId: l.ListingID,
+					SellerId:  l.SellerID,
+				}
+			},
+		),
+		Subtotal:      o.Subtotal,
+		Discount:      o.Discount,
+		Shipping:      o.Shipping,
+		Total:         o.Total,
+		PromotionCode: o.PromotionCode,
+		CreatedAt:     o.CreatedAt,
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+type PasswordReset struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,gte=8,lte=20"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// omitted fields are left unchanged, an empty preferred language clears it
+type PatchProfile struct {
+	DisplayName         *string `json:"displayName" validate:"omitempty,lte=32"`
+	PreferredCurrency   *string `json:"preferredCurrency" validate:"omitempty,iso4217"`
+	PreferredLanguageId *string `json:"preferredLanguageId"`
+}
@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+// Period selects the days from its start up to and including its end. It starts on the first of the current
+// month and ends today if omitted
+type Period struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type PostPayout struct {
+	SellerId  uint   `json:"sellerId" validate:"required"`
+	From      string `json:"from" validate:"required,datetime=2006-01-02"`
+	To        string `json:"to" validate:"required,datetime=2006-01-02"`
+	Reference string `json:"reference" validate:"lte=128"`
+}
+
+type GetSellerSale struct {
+	Id                   uint        `json:"id"`
+	OrderId              uint        `json:"orderId"`
+	OrderLineId          uint        `json:"orderLineId"`
+	ListingId            *uint       `json:"listingId"`
+	CardId               uint        `json:"cardId"`
+	Name                 string      `json:"name"`
+	Amount               uint        `json:"amount"`
+	Gross                model.Money `json:"gross"`
+	CommissionPercentage float32     `json:"commissionPercentage"`
+	Commission           model.Money `json:"commission"`
+	Net                  model.Money `json:"net"`
+	PayoutId             *uint       `json:"payoutId"`
+	CreatedAt            time.Time   `json:"createdAt"`
+}
+
+func NewGetSellerSale(s *model.SellerSale) *GetSellerSale {
+	return &GetSellerSale{
+		Id:                   s.ID,
+		OrderId:              s.OrderLine.OrderID,
+		OrderLineId:          s.OrderLineID,
+		ListingId:            s.OrderLine.ListingID,
+		CardId:               s.OrderLine.CardID,
+		Name:                 s.OrderLine.Name,
+		Amount:               s.OrderLine.Amount,
+		Gross:                s.Gross,
+		CommissionPercentage: s.CommissionPercentage,
+		Commission:           s.Commission,
+		Net:                  s.Net,
+		PayoutId:             s.PayoutID,
+		CreatedAt:            s.CreatedAt,
+	}
+}
+
+// PayoutStatement sums up a seller's sales of a period. It's reconciled if every payout covering part of the
+// period adds up to the sales it paid, its amount to their net and its amount and commission to their gross
+type PayoutStatement struct {
+	SellerId   uint             `json:"sellerId"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Sales      []*GetSellerSale `json:"sales"`
+	Gross      model.Money      `json:"gross"`
+	Commission model.Money      `json:"commission"`
+	Net        model.Money      `json:"net"`
+	Paid       model.Money      `json:"paid"`
+	Unpaid     model.Money      `json:"unpaid"`
+	Payouts    uint             `json:"payouts"`
+	// ids of the payouts that don't add up
+	UnreconciledPayouts []uint `json:"unreconciledPayouts"`
+	Reconciled          bool   `json:"reconciled"`
+}
+
+type GetPayout struct {
+	Id          uint        `json:"id"`
+	SellerId    uint        `json:"sellerId"`
+	PeriodStart time.Time   `json:"periodStart"`
+	PeriodEnd   time.Time   `json:"periodEnd"`
+	Amount      model.Money `json:"amount"`
+	Commission  model.Money `json:"commission"`
+	Sales       uint        `json:"sales"`
+	Reference   string      `json:"reference"`
+	PaidById    uint        `json:"paidById"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+func NewGetPayout(p *model.Payout) *GetPayout {
+	return &GetPayout{
+		Id:          p.ID,
+		SellerId:    p.SellerID,
+		PeriodStart: p.PeriodStart,
+		PeriodEnd:   p.PeriodEnd,
+		Amount:      p.Amount,
+		Commission:  p.Commission,
+		Sales:       p.Sales,
+		Reference:   p.Reference,
+		PaidById:    p.PaidByID,
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+type GetCommission struct {
+	SellerId   uint    `json:"sellerId"`
+	Percentage float32 `json:"percentage"`
+	// the seller doesn't have their own rate
+	Default bool `json:"default"`
+}
+
+// CommissionUpdate sets the seller's own commission percentage, null resets it to the store's
+type CommissionUpdate struct {
+	Percentage *float32 `json:"percentage" validate:"omitnil,gte=0,lte=100"`
+}
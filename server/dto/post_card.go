@@ -3,16 +3,22 @@ package dto
 import "store.api/model"
 
 type PostCard struct {
-	Name          string  `json:"name" validate:"required"`
-	Text          string  `json:"text" validate:"required"`
-	ImageUrl      string  `json:"imageUrl"`
-	Price         float32 `json:"price" validate:"required,gt=0"`
-	Type          string  `json:"type" validate:"required"`
-	Language      string  `json:"language" validate:"required"`
-	Key           string  `json:"key" validate:"required"`
-	Expansion     string  `json:"expansion" validate:"required"`
-	InStockAmount uint    `json:"inStockAmount"`
-	Foiling       string  `json:"foiling"`
+	Name          string      `json:"name" validate:"required"`
+	Text          string      `json:"text" validate:"required"`
+	ImageUrl      string      `json:"imageUrl"`
+	Price         model.Money `json:"price" validate:"required,gt=0"`
+	Type          string      `json:"type" validate:"required"`
+	Language      string      `json:"language" validate:"required"`
+	Key           string      `json:"key" validate:"required"`
+	Expansion     string      `json:"expansion" validate:"required"`
+	InStockAmount uint        `json:"inStockAmount"`
+	Foiling       string      `json:"foiling"`
+	// CollectorNumber is the number of the card within its expansion
+	CollectorNumber string `json:"collectorNumber"`
+	// Condition defaults to near mint
+	Condition string `json:"condition" validate:"omitempty,oneof=M NM LP MP HP DMG"`
+	// LowStockThreshold overrides the expansion's default low stock threshold
+	LowStockThreshold *uint `json:"lowStockThreshold"`
 }
 
 func (c PostCard) ToCard() *model.Card {
@@ -20,16 +26,24 @@ func (c PostCard) ToCard() *model.Card {
 	if len(c.Foiling) > 0 {
 		foiling = &c.Foiling
 	}
+	condition := model.ConditionNearMint
+	if len(c.Condition) > 0 {
+		condition = model.CardCondition(c.Condition)
+	}
 	return &model.Card{
-		Name:          c.Name,
-		Text:          c.Text,
-		ImageUrl:      c.ImageUrl,
-		Price:         c.Price,
-		CardTypeID:    c.Type,
-		LanguageID:    c.Language,
-		CardKeyID:     c.Key,
-		ExpansionID:   c.Expansion,
-		InStockAmount: c.InStockAmount,
-		FoilingID:     foiling,
+		Name:            c.Name,
+		Text:            c.Text,
+		ImageUrl:        c.ImageUrl,
+		Price:           c.Price,
+		CardTypeID:      c.Type,
+		LanguageID:      c.Language,
+		CardKeyID:       c.Key,
+		ExpansionID:     c.Expansion,
+		InStockAmount:   c.InStockAmount,
+		FoilingID:       foiling,
+		CollectorNumber: c.CollectorNumber,
+		Condition:       condition,
+
+		LowStockThreshold: c.LowStockThreshold,
 	}
 }
@@ -8,7 +8,8 @@ import (
 
 type PostCartSlot struct {
 	CardId uint `json:"cardId" validate:"required"`
-	Amount int  `json:"amount" validate:"required"`
+	// change of the amount, bounded so line totals can't overflow
+	Amount int `json:"amount" validate:"required,gte=-1000,lte=1000"`
 }
 
 func (s *PostCartSlot) ToCartSlot() (*model.CartSlot, error) {
@@ -20,3 +21,8 @@ func (s *PostCartSlot) ToCartSlot() (*model.CartSlot, error) {
 		CardID: s.CardId,
 	}, nil
 }
+
+// PostCartSlots edits many slots at once, amounts of the same card are summed
+type PostCartSlots struct {
+	Slots []*PostCartSlot `json:"slots" validate:"required,min=1,max=500,dive"`
+}
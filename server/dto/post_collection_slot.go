@@ -20,3 +20,8 @@ func (c *PostCollectionSlot) ToCollectionSlot() (*model.CollectionSlot, error) {
 		CardID: c.CardId,
 	}, nil
 }
+
+// PostCollectionSlots edits many slots at once, amounts of the same card are summed
+type PostCollectionSlots struct {
+	Slots []*PostCollectionSlot `json:"slots" validate:"required,min=1,max=500,dive"`
+}
@@ -1,16 +1,45 @@
 package dto
 
-import "store.api/model"
+import (
+	"slices"
+	"strings"
+
+	"store.api/model"
+)
 
 type PostCollection struct {
 	Name        string `json:"name" validate:"required,gte=3"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=private unlisted public"`
+	// nil for collections that aren't in a folder
+	FolderId *uint    `json:"folderId"`
+	Tags     []string `json:"tags" validate:"omitempty,max=20,dive,required,lte=32"`
 }
 
 func (c *PostCollection) ToCollection() *model.Collection {
+	visibility := model.VisibilityPrivate
+	if len(c.Visibility) > 0 {
+		visibility = model.CollectionVisibility(c.Visibility)
+	}
 	return &model.Collection{
 		Name:        c.Name,
 		Description: c.Description,
+		Visibility:  visibility,
 		Cards:       []model.CollectionSlot{},
+		FolderID:    c.FolderId,
+		Tags:        NormalizeTags(c.Tags),
+	}
+}
+
+// NormalizeTags lowercases and trims the tags, dropping empty and repeated ones
+func NormalizeTags(tags []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) == 0 || slices.Contains(result, tag) {
+			continue
+		}
+		result = append(result, tag)
 	}
+	return result
 }
@@ -1,5 +1,7 @@
 package dto
 
+import "store.api/model"
+
 type PriceUpdate struct {
-	NewPrice float32 `json:"newPrice"`
+	NewPrice model.Money `json:"newPrice"`
 }
@@ -11,6 +11,14 @@ type PrivateUserInfo struct {
 	Username string `json:"username"`
 	IsAdmin  bool   `json:"isAdmin"`
 	Verified bool   `json:"verified"`
+
+	Email               string  `json:"email"`
+	PendingEmail        string  `json:"pendingEmail"`
+	DisplayName         string  `json:"displayName"`
+	PreferredCurrency   string  `json:"preferredCurrency"`
+	PreferredLanguageId *string `json:"preferredLanguageId"`
+
+	TwoFactorEnabled bool `json:"twoFactorEnabled"`
 }
 
 func NewPrivateUserInfo(user *model.User) *PrivateUserInfo {
@@ -19,6 +27,14 @@ func NewPrivateUserInfo(user *model.User) *PrivateUserInfo {
 		Username: user.Username,
 		IsAdmin:  user.IsAdmin,
 		Verified: user.Verified,
+
+		Email:               user.Email,
+		PendingEmail:        user.PendingEmail,
+		DisplayName:         user.DisplayName,
+		PreferredCurrency:   user.PreferredCurrency,
+		PreferredLanguageId: user.PreferredLanguageID,
+
+		TwoFactorEnabled: user.TwoFactorEnabled,
 	}
 
 	return &result
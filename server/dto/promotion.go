@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"store.api/model"
+)
+
+// PostPromotion creates or replaces a promotion, promotions without a code are applied automatically.
+// Percentage is required for percentage promotions, amount for fixed ones and buy and free amounts for buy X get Y ones
+type PostPromotion struct {
+	Code                  *string     `json:"code" validate:"omitempty,min=3,max=32,alphanum"`
+	Description           string      `json:"description" validate:"required,lte=256"`
+	Kind                  string      `json:"kind" validate:"required,oneof=percentage fixed buyXGetY"`
+	Percentage            float32     `json:"percentage" validate:"required_if=Kind percentage,gte=0,lte=100"`
+	Amount                model.Money `json:"amount" validate:"required_if=Kind fixed,gte=0"`
+	BuyAmount             uint        `json:"buyAmount" validate:"required_if=Kind buyXGetY"`
+	FreeAmount            uint        `json:"freeAmount" validate:"required_if=Kind buyXGetY"`
+	ExpansionId           *string     `json:"expansionId" validate:"omitempty,min=1"`
+	CardTypeId            *string     `json:"cardTypeId" validate:"omitempty,min=1"`
+	StartsAt              *time.Time  `json:"startsAt"`
+	EndsAt                *time.Time  `json:"endsAt"`
+	MaxRedemptions        uint        `json:"maxRedemptions"`
+	MaxRedemptionsPerUser uint        `json:"maxRedemptionsPerUser"`
+	Active                bool        `json:"active"`
+}
+
+// ToPromotion copies the promotion data, codes are stored upper case
+func (p *PostPromotion) ToPromotion() *model.Promotion {
+	var code *string
+	if p.Code != nil {
+		upper := strings.ToUpper(*p.Code)
+		code = &upper
+	}
+	return &model.Promotion{
+		Code:                  code,
+		Description:           p.Description,
+		Kind:                  model.PromotionKind(p.Kind),
+		Percentage:            p.Percentage,
+		Amount:                p.Amount,
+		BuyAmount:             p.BuyAmount,
+		FreeAmount:            p.FreeAmount,
+		ExpansionID:           p.ExpansionId,
+		CardTypeID:            p.CardTypeId,
+		StartsAt:              p.StartsAt,
+		EndsAt:                p.EndsAt,
+		MaxRedemptions:        p.MaxRedemptions,
+		MaxRedemptionsPerUser: p.MaxRedemptionsPerUser,
+		Active:                p.Active,
+	}
+}
+
+type GetPromotion struct {
+	Id                    uint        `json:"id"`
+	Code                  *string     `json:"code"`
+	Description           string      `json:"description"`
+	Kind                  string      `json:"kind"`
+	Percentage            float32     `json:"percentage"`
+	Amount                model.Money `json:"amount"`
+	BuyAmount             uint        `json:"buyAmount"`
+	FreeAmount            uint        `json:"freeAmount"`
+	ExpansionId           *string     `json:"expansionId"`
+	CardTypeId            *string     `json:"cardTypeId"`
+	StartsAt              *time.Time  `json:"startsAt"`
+	EndsAt                *time.Time  `json:"endsAt"`
+	MaxRedemptions        uint        `json:"maxRedemptions"`
+	MaxRedemptionsPerUser uint        `json:"maxRedemptionsPerUser"`
+	Active                bool        `json:"active"`
+	Redemptions           int64       `json:"redemptions"`
+}
+
+func NewGetPromotion(p *model.Promotion, redemptions int64) *GetPromotion {
+	return &GetPromotion{
+		Id:                    p.ID,
+		Code:                  p.Code,
+		Description:           p.Description,
+		Kind:                  string(p.Kind),
+		Percentage:            p.Percentage,
+		Amount:                p.Amount,
+		BuyAmount:             p.BuyAmount,
+		FreeAmount:            p.FreeAmount,
+		ExpansionId:           p.ExpansionID,
+		CardTypeId:            p.CardTypeID,
+		StartsAt:              p.StartsAt,
+		EndsAt:                p.EndsAt,
+		MaxRedemptions:        p.MaxRedemptions,
+		MaxRedemptionsPerUser: p.MaxRedemptionsPerUser,
+		Active:                p.Active,
+		Redemptions:           redemptions,
+	}
+}
+
+type GetPromotionRedemption struct {
+	Id         uint        `json:"id"`
+	UserId     uint        `json:"userId"`
+	OrderId    uint        `json:"orderId"`
+	Discount   model.Money `json:"discount"`
+	RedeemedAt time.Time   `json:"redeemedAt"`
+}
+
+func NewGetPromotionRedemption(r *model.PromotionRedemption) *GetPromotionRedemption {
+	return &GetPromotionRedemption{
+		Id:         r.ID,
+		UserId:     r.UserID,
+		OrderId:    r.OrderID,
+		Discount:   r.Discount,
+		RedeemedAt: r.CreatedAt,
+	}
+}
+
+// PromotionCode is a coupon entered by the user
+type PromotionCode struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+// PostScheduledPrice schedules a price change, with an end it's a sales window after which the previous price is restored
+type PostScheduledPrice struct {
+	CardId   uint        `json:"cardId" validate:"required"`
+	Price    model.Money `json:"price" validate:"gt=0"`
+	StartsAt time.Time   `json:"startsAt" validate:"required"`
+	EndsAt   *time.Time  `json:"endsAt"`
+}
+
+type GetScheduledPrice struct {
+	Id            uint         `json:"id"`
+	CardId        uint         `json:"cardId"`
+	Price         model.Money  `json:"price"`
+	StartsAt      time.Time    `json:"startsAt"`
+	EndsAt        *time.Time   `json:"endsAt"`
+	Status        string       `json:"status"`
+	PreviousPrice *model.Money `json:"previousPrice"`
+}
+
+func NewGetScheduledPrice(s *model.ScheduledPriceChange) *GetScheduledPrice {
+	return &GetScheduledPrice{
+		Id:            s.ID,
+		CardId:        s.CardID,
+		Price:         s.Price,
+		StartsAt:      s.StartsAt,
+		EndsAt:        s.EndsAt,
+		Status:        string(s.Status),
+		PreviousPrice: s.PreviousPrice,
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+type GetStockMovement struct {
+	Id        uint      `json:"id"`
+	CardId    uint      `json:"cardId"`
+	Kind      string    `json:"kind"`
+	Delta     int       `json:"delta"`
+	Balance   uint      `json:"balance"`
+	ActorId   *uint     `json:"actorId"`
+	Reason    string    `json:"reason"`
+	OrderId   *uint     `json:"orderId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewGetStockMovement(m *model.StockMovement) *GetStockMovement {
+	return &GetStockMovement{
+		Id:        m.ID,
+		CardId:    m.CardID,
+		Kind:      string(m.Kind),
+		Delta:     m.Delta,
+		Balance:   m.Balance,
+		ActorId:   m.ActorID,
+		Reason:    m.Reason,
+		OrderId:   m.OrderID,
+		CreatedAt: m.CreatedAt,
+	}
+}
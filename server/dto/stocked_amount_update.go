@@ -1,5 +1,8 @@
 package dto
 
+// StockedAmountUpdate sets the card's stock, the change is recorded in its stock ledger as a correction unless another kind is given
 type StockedAmountUpdate struct {
-	NewAmount uint `json:"newAmount"`
+	NewAmount uint   `json:"newAmount"`
+	Kind      string `json:"kind" validate:"omitempty,oneof=restock sale return correction reservation"`
+	Reason    string `json:"reason" validate:"lte=256"`
 }
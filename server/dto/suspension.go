@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+// a suspension without an end date is a ban
+type Suspension struct {
+	Reason string     `json:"reason" validate:"required"`
+	Until  *time.Time `json:"until"`
+}
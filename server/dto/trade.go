@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+type TradeCard struct {
+	CardId uint `json:"cardId" validate:"required"`
+	Amount uint `json:"amount" validate:"required,gt=0"`
+}
+
+// PostTrade proposes to give the offered cards of one of the user's collections
+// for the requested cards of another user's public collection
+type PostTrade struct {
+	CollectionId          uint        `json:"collectionId" validate:"required"`
+	RecipientCollectionId uint        `json:"recipientCollectionId" validate:"required"`
+	Offered               []TradeCard `json:"offered" validate:"max=100,dive"`
+	Requested             []TradeCard `json:"requested" validate:"max=100,dive"`
+	Message               string      `json:"message" validate:"lte=512"`
+}
+
+// PostCounterTrade replaces a received trade, the collections stay the same with the roles swapped
+type PostCounterTrade struct {
+	Offered   []TradeCard `json:"offered" validate:"max=100,dive"`
+	Requested []TradeCard `json:"requested" validate:"max=100,dive"`
+	Message   string      `json:"message" validate:"lte=512"`
+}
+
+type GetTradeCard struct {
+	CardId uint   `json:"cardId"`
+	Name   string `json:"name"`
+	Amount uint   `json:"amount"`
+}
+
+type GetTrade struct {
+	Id                    uint            `json:"id"`
+	Status                string          `json:"status"`
+	ProposerId            uint            `json:"proposerId"`
+	ProposerCollectionId  uint            `json:"proposerCollectionId"`
+	RecipientId           uint            `json:"recipientId"`
+	RecipientCollectionId uint            `json:"recipientCollectionId"`
+	CounterOfId           *uint           `json:"counterOfId"`
+	Message               string          `json:"message"`
+	Offered               []*GetTradeCard `json:"offered"`
+	Requested             []*GetTradeCard `json:"requested"`
+	CreatedAt             time.Time       `json:"createdAt"`
+	UpdatedAt             time.Time       `json:"updatedAt"`
+}
+
+func NewGetTrade(t *model.Trade) *GetTrade {
+	result := &GetTrade{
+		Id:                    t.ID,
+		Status:                string(t.Status),
+		ProposerId:            t.ProposerID,
+		ProposerCollectionId:  t.ProposerCollectionID,
+		RecipientId:           t.RecipientID,
+		RecipientCollectionId: t.RecipientCollectionID,
+		CounterOfId:           t.CounterOfID,
+		Message:               t.Message,
+		Offered:               []*GetTradeCard{},
+		Requested:             []*GetTradeCard{},
+		CreatedAt:             t.CreatedAt,
+		UpdatedAt:             t.UpdatedAt,
+	}
+	for _, line := range t.Lines {
+		card := &GetTradeCard{
+			CardId: line.CardID,
+			Name:   line.Card.Name,
+			Amount: line.Amount,
+		}
+		if line.Offered {
+			result.Offered = append(result.Offered, card)
+		} else {
+			result.Requested = append(result.Requested, card)
+		}
+	}
+	return result
+}
@@ -0,0 +1,5 @@
+package dto
+
+type TwoFactorCode struct {
+	Code string `json:"code" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type TwoFactorEnrollment struct {
+	Secret        string   `json:"secret"`
+	Uri           string   `json:"uri"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type UserExport struct {
+	Profile     *PrivateUserInfo `json:"profile"`
+	Collections []*GetCollection `json:"collections"`
+	Cart        *GetCart         `json:"cart"`
+	Wishlist    []*GetWishlist   `json:"wishlist"`
+	Orders      []*GetOrder      `json:"orders"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "store.api/model"
+
+// either a card or a card key is wished for, a card key matches any printing of the card
+type PostWishlist struct {
+	CardId       *uint        `json:"cardId" validate:"required_without=CardKeyId,excluded_with=CardKeyId"`
+	CardKeyId    *string      `json:"cardKeyId" validate:"required_without=CardId,excluded_with=CardId"`
+	MaxPrice     *model.Money `json:"maxPrice" validate:"omitempty,gt=0"`
+	MinCondition string       `json:"minCondition" validate:"omitempty,oneof=M NM LP MP HP DMG"`
+}
+
+func (w *PostWishlist) ToWishlist(userId uint) *model.Wishlist {
+	return &model.Wishlist{
+		UserID:       userId,
+		CardID:       w.CardId,
+		CardKeyID:    w.CardKeyId,
+		MaxPrice:     w.MaxPrice,
+		MinCondition: model.CardCondition(w.MinCondition),
+	}
+}
+
+// omitted fields are left unchanged, a max price of 0 removes the price limit
+// and an empty min condition accepts any condition
+type PatchWishlist struct {
+	MaxPrice     *model.Money `json:"maxPrice" validate:"omitempty,gte=0"`
+	MinCondition *string      `json:"minCondition" validate:"omitempty,oneof='' M NM LP MP HP DMG"`
+}
+
+type GetWishlist struct {
+	ID           uint         `json:"id"`
+	CardId       *uint        `json:"cardId"`
+	CardKeyId    *string      `json:"cardKeyId"`
+	MaxPrice     *model.Money `json:"maxPrice"`
+	MinCondition string       `json:"minCondition"`
+}
+
+func NewGetWishlist(w *model.Wishlist) *GetWishlist {
+	return &GetWishlist{
+		ID:           w.ID,
+		CardId:       w.CardID,
+		CardKeyId:    w.CardKeyID,
+		MaxPrice:     w.MaxPrice,
+		MinCondition: string(w.MinCondition),
+	}
+}
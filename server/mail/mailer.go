@@ -0,0 +1,20 @@
+package mail
+
+import "log"
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// LogMailer writes the mail to the log instead of sending it, used until an SMTP relay is configured
+type LogMailer struct {
+}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(to string, subject string, body string) error {
+	log.Printf("mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
@@ -5,13 +5,22 @@ import "gorm.io/gorm"
 type Card struct {
 	gorm.Model
 
-	Name          string  `gorm:"not null" json:"name"`
-	Text          string  `gorm:"not null,type:text" json:"text"`
-	ImageUrl      string  `gorm:"" json:"imageUrl"`
-	Price         float32 `gorm:"not null" json:"price"`
-	InStockAmount uint    `gorm:"not null"`
+	Name          string `gorm:"not null" json:"name"`
+	Text          string `gorm:"not null,type:text" json:"text"`
+	ImageUrl      string `gorm:"" json:"imageUrl"`
+	Price         Money  `gorm:"not null" json:"price"`
+	InStockAmount uint   `gorm:"not null"`
 
-	CardKeyID string `gorm:"not null" json:"cardKeyId"`
+	CardKeyID string  `gorm:"not null" json:"cardKeyId"`
+	CardKey   CardKey `json:"cardKey"`
+
+	// number of the card within its expansion, not all expansions have them
+	CollectorNumber string `gorm:"" json:"collectorNumber"`
+
+	Condition CardCondition `gorm:"not null;default:NM" json:"condition"`
+
+	// admins are alerted when the stock falls to it, nil uses the expansion's default
+	LowStockThreshold *uint `gorm:"" json:"lowStockThreshold"`
 
 	PosterID uint `gorm:"not null" json:"posterId"`
 	Poster   User `json:"-"`
@@ -28,3 +37,11 @@ type Card struct {
 	FoilingID *string `gorm:"" json:"foilingId"`
 	Foiling   Foiling `json:"foiling"`
 }
+
+// StockThreshold returns the card's low stock threshold, falling back to the expansion's default
+func (c *Card) StockThreshold(expansion *Expansion) *uint {
+	if c.LowStockThreshold != nil || expansion == nil {
+		return c.LowStockThreshold
+	}
+	return expansion.LowStockThreshold
+}
@@ -0,0 +1,27 @@
+package model
+
+type CardCondition string
+
+const (
+	ConditionMint             CardCondition = "M"
+	ConditionNearMint         CardCondition = "NM"
+	ConditionLightlyPlayed    CardCondition = "LP"
+	ConditionModeratelyPlayed CardCondition = "MP"
+	ConditionHeavilyPlayed    CardCondition = "HP"
+	ConditionDamaged          CardCondition = "DMG"
+)
+
+// from the worst to the best condition, an unknown condition ranks below all of them
+var conditionRanks = map[CardCondition]int{
+	ConditionDamaged:          1,
+	ConditionHeavilyPlayed:    2,
+	ConditionModeratelyPlayed: 3,
+	ConditionLightlyPlayed:    4,
+	ConditionNearMint:         5,
+	ConditionMint:             6,
+}
+
+// AtLeast checks if the condition is as good as or better than min, an empty min accepts any condition
+func (c CardCondition) AtLeast(min CardCondition) bool {
+	return conditionRanks[c] >= conditionRanks[min]
+}
@@ -7,4 +7,7 @@ type Cart struct {
 
 	UserID uint       `gorm:"not null" json:"userId"`
 	Cards  []CartSlot `json:"cards"`
+
+	// coupon the user entered, applied when the cart is priced
+	PromotionCode *string `gorm:"" json:"promotionCode"`
 }
@@ -1,6 +1,20 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type CollectionVisibility string
+
+const (
+	// only the owner can see the collection
+	VisibilityPrivate CollectionVisibility = "private"
+	// anyone with the share link can see the collection
+	VisibilityUnlisted CollectionVisibility = "unlisted"
+	// the collection is listed on the owner's public page and can be shared by link
+	VisibilityPublic CollectionVisibility = "public"
+)
 
 type Collection struct {
 	gorm.Model
@@ -8,7 +22,15 @@ type Collection struct {
 	Name        string `gorm:"not null" json:"name"`
 	Description string `gorm:"type:text" json:"description"`
 
+	Visibility CollectionVisibility `gorm:"not null;default:private" json:"visibility"`
+	ShareToken *string              `gorm:"uniqueIndex" json:"shareToken"`
+
 	Cards []CollectionSlot `json:"cards"`
 
+	// nil for collections that aren't in a folder
+	FolderID *uint             `json:"folderId"`
+	Folder   *CollectionFolder `json:"-"`
+	Tags     pq.StringArray    `gorm:"type:text[]" json:"tags"`
+
 	OwnerID uint `gorm:"not null" json:"ownerId"`
 }
@@ -0,0 +1,16 @@
+package model
+
+import "gorm.io/gorm"
+
+// CollectionFolder groups a user's collections, folders can be nested
+type CollectionFolder struct {
+	gorm.Model
+
+	Name string `gorm:"not null" json:"name"`
+
+	// nil for top level folders
+	ParentID *uint             `json:"parentId"`
+	Parent   *CollectionFolder `json:"-"`
+
+	OwnerID uint `gorm:"not null;index" json:"ownerId"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// ExchangeRate is the amount of the currency one unit of the store's currency is worth
+type ExchangeRate struct {
+	Currency  string    `gorm:"primaryKey;size:3" json:"currency"`
+	Rate      float64   `gorm:"not null" json:"rate"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
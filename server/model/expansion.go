@@ -5,4 +5,7 @@ type Expansion struct {
 
 	ShortName string `gorm:"not null" json:"shortName"`
 	FullName  string `gorm:"not null" json:"fullName"`
+
+	// default low stock threshold of the expansion's cards, nil doesn't watch them
+	LowStockThreshold *uint `gorm:"" json:"lowStockThreshold"`
 }
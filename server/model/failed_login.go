@@ -0,0 +1,11 @@
+package model
+
+import "gorm.io/gorm"
+
+type FailedLogin struct {
+	gorm.Model
+
+	Username string `gorm:"not null;index"`
+	ClientIp string `gorm:"not null"`
+	Reason   string `gorm:"not null"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "gorm.io/gorm"
+
+// Listing is a user's offer to sell copies of a catalogue printing on the marketplace,
+// next to the store's own stock of it
+type Listing struct {
+	gorm.Model
+
+	SellerID uint `gorm:"not null;index" json:"sellerId"`
+	Seller   User `json:"-"`
+
+	CardID uint `gorm:"not null;index" json:"cardId"`
+	Card   Card `json:"-"`
+
+	Price     Money         `gorm:"not null" json:"price"`
+	Condition CardCondition `gorm:"not null;default:NM" json:"condition"`
+	// listings without copies left stay with the seller but aren't offered
+	Amount  uint   `gorm:"not null" json:"amount"`
+	Comment string `gorm:"not null;default:''" json:"comment"`
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in cents, the hundredth part of the currency's unit. It is read and
+// written as a decimal number so the API keeps using plain prices like 12.5
+type Money int64
+
+var ErrInvalidMoney = errors.New("amounts can have at most two decimal places")
+
+var hundred = big.NewRat(100, 1)
+
+// ParseMoney parses a decimal amount, amounts with more than two decimal places are rejected instead of rounded
+func ParseMoney(s string) (Money, error) {
+	amount, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("%q is not an amount", s)
+	}
+	cents := amount.Mul(amount, hundred)
+	if !cents.IsInt() {
+		return 0, ErrInvalidMoney
+	}
+	if !cents.Num().IsInt64() {
+		return 0, fmt.Errorf("%q is too large", s)
+	}
+	return Money(cents.Num().Int64()), nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Times multiplies the amount by a count of items
+func (m Money) Times(n uint) Money {
+	return m * Money(n)
+}
+
+// Scale multiplies the amount by a factor like a percentage or an exchange rate, rounding to the nearest cent
+func (m Money) Scale(factor float64) Money {
+	return Money(math.Round(float64(m) * factor))
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalText reads amounts from the environment configuration
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := ParseMoney(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam reads amounts from query parameters
+func (m *Money) UnmarshalParam(param string) error {
+	return m.UnmarshalText([]byte(param))
+}
@@ -0,0 +1,10 @@
+package model
+
+// OidcFlow holds the secrets of an authorization request that is still waiting for the provider's callback
+type OidcFlow struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+
+	// set once the provider authenticated a user who still has to enter their two-factor code
+	UserID uint `json:"userId,omitempty"`
+}
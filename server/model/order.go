@@ -0,0 +1,17 @@
+package model
+
+import "gorm.io/gorm"
+
+// Order is a checked out cart, the prices are the ones at checkout
+type Order struct {
+	gorm.Model
+
+	UserID uint        `gorm:"not null;index" json:"userId"`
+	Lines  []OrderLine `json:"lines"`
+
+	Subtotal      Money   `gorm:"not null" json:"subtotal"`
+	Discount      Money   `gorm:"not null" json:"discount"`
+	Shipping      Money   `gorm:"not null" json:"shipping"`
+	Total         Money   `gorm:"not null" json:"total"`
+	PromotionCode *string `gorm:"" json:"promotionCode"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "gorm.io/gorm"
+
+type OrderLine struct {
+	gorm.Model
+
+	OrderID uint `gorm:"not null;index" json:"orderId"`
+
+	CardID uint `gorm:"not null;index" json:"cardId"`
+	// the card's name at checkout, cards can be renamed or deleted later
+	Name      string `gorm:"not null" json:"name"`
+	UnitPrice Money  `gorm:"not null" json:"unitPrice"`
+	Amount    uint   `gorm:"not null" json:"amount"`
+	LineTotal Money  `gorm:"not null" json:"lineTotal"`
+
+	// marketplace sales are bought from a seller's listing instead of the store's stock
+	ListingID *uint `gorm:"" json:"listingId"`
+	SellerID  *uint `gorm:"index" json:"sellerId"`
+}
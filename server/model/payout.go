@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Payout records that an admin paid a seller the net amount of their unpaid sales in a period
+type Payout struct {
+	gorm.Model
+
+	SellerID uint `gorm:"not null;index" json:"sellerId"`
+	// the period covers the sales ordered from its start up to, excluding, its end
+	PeriodStart time.Time `gorm:"not null" json:"periodStart"`
+	PeriodEnd   time.Time `gorm:"not null" json:"periodEnd"`
+
+	Amount Money `gorm:"not null" json:"amount"`
+	// commission the store kept from the paid sales
+	Commission Money `gorm:"not null;default:0" json:"commission"`
+	Sales      uint  `gorm:"not null" json:"sales"`
+	// e.g. the bank transfer's reference
+	Reference string `gorm:"not null;default:''" json:"reference"`
+	PaidByID  uint   `gorm:"not null" json:"paidById"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "gorm.io/gorm"
+
+type PriceChange struct {
+	gorm.Model
+
+	CardID   uint  `gorm:"not null;index"`
+	OldPrice Money `gorm:"not null"`
+	NewPrice Money `gorm:"not null"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PromotionKind string
+
+const (
+	// percentage off the covered cards
+	PromotionPercentage PromotionKind = "percentage"
+	// fixed amount off the covered cards, never more than they cost
+	PromotionFixed PromotionKind = "fixed"
+	// for every BuyAmount covered cards, the next FreeAmount cheaper ones are free
+	PromotionBuyXGetY PromotionKind = "buyXGetY"
+)
+
+// Promotion is a discount on the cart. Promotions with a code are coupons the user has to enter,
+// the ones without are applied automatically
+type Promotion struct {
+	gorm.Model
+
+	Code        *string       `gorm:"uniqueIndex:idx_promotions_code,where:deleted_at IS NULL" json:"code"`
+	Description string        `gorm:"not null" json:"description"`
+	Kind        PromotionKind `gorm:"not null" json:"kind"`
+
+	Percentage float32 `gorm:"" json:"percentage"`
+	Amount     Money   `gorm:"" json:"amount"`
+	BuyAmount  uint    `gorm:"" json:"buyAmount"`
+	FreeAmount uint    `gorm:"" json:"freeAmount"`
+
+	// nil covers cards of any expansion or card type
+	ExpansionID *string `gorm:"" json:"expansionId"`
+	CardTypeID  *string `gorm:"" json:"cardTypeId"`
+
+	// nil leaves the window open on that side
+	StartsAt *time.Time `gorm:"" json:"startsAt"`
+	EndsAt   *time.Time `gorm:"" json:"endsAt"`
+
+	// 0 doesn't limit the redemptions
+	MaxRedemptions        uint `gorm:"not null;default:0" json:"maxRedemptions"`
+	MaxRedemptionsPerUser uint `gorm:"not null;default:0" json:"maxRedemptionsPerUser"`
+
+	Active bool `gorm:"not null" json:"active"`
+}
+
+// Running checks if the promotion is active and within its validity window, redemption limits aren't checked
+func (p *Promotion) Running(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Covers checks if the card is discounted by the promotion
+func (p *Promotion) Covers(card *Card) bool {
+	if p.ExpansionID != nil && *p.ExpansionID != card.ExpansionID {
+		return false
+	}
+	if p.CardTypeID != nil && *p.CardTypeID != card.CardTypeID {
+		return false
+	}
+	return true
+}
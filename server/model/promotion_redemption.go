@@ -0,0 +1,13 @@
+package model
+
+import "gorm.io/gorm"
+
+// PromotionRedemption records a promotion used in an order, they are kept when the promotion is deleted
+type PromotionRedemption struct {
+	gorm.Model
+
+	PromotionID uint  `gorm:"not null;index" json:"promotionId"`
+	UserID      uint  `gorm:"not null;index" json:"userId"`
+	OrderID     uint  `gorm:"not null;index" json:"orderId"`
+	Discount    Money `gorm:"not null" json:"discount"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "gorm.io/gorm"
+
+type RecoveryCode struct {
+	gorm.Model
+
+	CodeHash string `gorm:"not null"`
+	Used     bool   `gorm:"not null"`
+
+	UserID uint `gorm:"not null"`
+}
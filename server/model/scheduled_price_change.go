@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ScheduledPriceStatus string
+
+const (
+	// waiting for StartsAt
+	ScheduledPricePending ScheduledPriceStatus = "pending"
+	// price applied, waiting for EndsAt to restore the previous one
+	ScheduledPriceActive ScheduledPriceStatus = "active"
+	// applied and restored if it had an end, or skipped because its window had already passed
+	ScheduledPriceDone ScheduledPriceStatus = "done"
+	// cancelled before it was applied
+	ScheduledPriceCancelled ScheduledPriceStatus = "cancelled"
+)
+
+// ScheduledPriceChange sets the card's price at StartsAt. With an EndsAt it's a sales window,
+// the price the card had right before the change is restored when it ends
+type ScheduledPriceChange struct {
+	gorm.Model
+
+	CardID uint  `gorm:"not null;index"`
+	Price  Money `gorm:"not null"`
+
+	StartsAt time.Time  `gorm:"not null;index"`
+	EndsAt   *time.Time `gorm:"index"`
+
+	Status ScheduledPriceStatus `gorm:"not null;default:pending;index"`
+	// price the card had when the change was applied
+	PreviousPrice *Money `gorm:""`
+}
+
+// Overlaps checks if the change happens within the other one's sales window or the other way around
+func (s *ScheduledPriceChange) Overlaps(other *ScheduledPriceChange) bool {
+	return s.contains(other.StartsAt) || other.contains(s.StartsAt)
+}
+
+func (s *ScheduledPriceChange) contains(t time.Time) bool {
+	if s.EndsAt == nil {
+		return t.Equal(s.StartsAt)
+	}
+	return !t.Before(s.StartsAt) && t.Before(*s.EndsAt)
+}
@@ -0,0 +1,30 @@
+package model
+
+import "gorm.io/gorm"
+
+// SellerSale is the seller's share of a marketplace order line, the store keeps the commission
+// at the seller's rate at the time of the sale and pays out the rest
+type SellerSale struct {
+	gorm.Model
+
+	SellerID    uint      `gorm:"not null;index" json:"sellerId"`
+	OrderLineID uint      `gorm:"not null;uniqueIndex" json:"orderLineId"`
+	OrderLine   OrderLine `json:"-"`
+
+	// the order line's total
+	Gross                Money   `gorm:"not null" json:"gross"`
+	CommissionPercentage float32 `gorm:"not null" json:"commissionPercentage"`
+	Commission           Money   `gorm:"not null" json:"commission"`
+	Net                  Money   `gorm:"not null" json:"net"`
+
+	// nil until the net amount is paid out
+	PayoutID *uint `gorm:"index" json:"payoutId"`
+}
+
+// SellerCommission overrides the store's commission percentage for a seller
+type SellerCommission struct {
+	gorm.Model
+
+	SellerID   uint    `gorm:"not null;uniqueIndex" json:"sellerId"`
+	Percentage float32 `gorm:"not null" json:"percentage"`
+}
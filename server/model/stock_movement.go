@@ -0,0 +1,45 @@
+package model
+
+import "gorm.io/gorm"
+
+type StockMovementKind string
+
+const (
+	// cards added to the stock
+	StockRestock StockMovementKind = "restock"
+	// cards taken out of the stock by an order
+	StockSale StockMovementKind = "sale"
+	// cards of an order put back into the stock
+	StockReturn StockMovementKind = "return"
+	// stock set to a counted amount, or changed for any other reason
+	StockCorrection StockMovementKind = "correction"
+	// cards held back from the stock, or released if the delta is positive
+	StockReservation StockMovementKind = "reservation"
+)
+
+// Allows checks the direction of a change against the kind, restocks and returns add cards and sales take them
+func (k StockMovementKind) Allows(delta int) bool {
+	switch k {
+	case StockRestock, StockReturn:
+		return delta > 0
+	case StockSale:
+		return delta < 0
+	}
+	return true
+}
+
+// StockMovement is an entry of a card's stock ledger, every change of the card's InStockAmount records one
+type StockMovement struct {
+	gorm.Model
+
+	CardID uint              `gorm:"not null;index" json:"cardId"`
+	Kind   StockMovementKind `gorm:"not null" json:"kind"`
+	Delta  int               `gorm:"not null" json:"delta"`
+	// the card's stock after the movement
+	Balance uint `gorm:"not null" json:"balance"`
+
+	// nil for movements made by the store itself
+	ActorID *uint  `gorm:"" json:"actorId"`
+	Reason  string `gorm:"not null" json:"reason"`
+	OrderID *uint  `gorm:"index" json:"orderId"`
+}
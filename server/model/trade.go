@@ -0,0 +1,53 @@
+package model
+
+import "gorm.io/gorm"
+
+type TradeStatus string
+
+const (
+	// waiting for the recipient to accept, decline or counter it
+	TradeProposed TradeStatus = "proposed"
+	// the cards were exchanged
+	TradeAccepted TradeStatus = "accepted"
+	TradeDeclined TradeStatus = "declined"
+	// withdrawn by the proposer
+	TradeCancelled TradeStatus = "cancelled"
+	// replaced by the recipient's counter-offer
+	TradeCountered TradeStatus = "countered"
+)
+
+// Trade is a proposal to exchange cards between two users' collections. The proposer gives
+// the offered cards from their collection for the requested cards of the recipient's collection
+type Trade struct {
+	gorm.Model
+
+	ProposerID           uint `gorm:"not null;index" json:"proposerId"`
+	ProposerCollectionID uint `gorm:"not null" json:"proposerCollectionId"`
+	RecipientID          uint `gorm:"not null;index" json:"recipientId"`
+	// public unless the trade is a counter-offer
+	RecipientCollectionID uint `gorm:"not null" json:"recipientCollectionId"`
+
+	Status  TradeStatus `gorm:"not null;default:proposed;index" json:"status"`
+	Message string      `gorm:"not null;default:''" json:"message"`
+	// the trade this one is a counter-offer to, with the roles swapped
+	CounterOfID *uint `gorm:"" json:"counterOfId"`
+
+	Lines []TradeLine `json:"lines"`
+}
+
+// Open checks if the trade can still be accepted, declined, countered or cancelled
+func (t *Trade) Open() bool {
+	return t.Status == TradeProposed
+}
+
+type TradeLine struct {
+	gorm.Model
+
+	TradeID uint `gorm:"not null;index" json:"tradeId"`
+
+	CardID uint `gorm:"not null" json:"cardId"`
+	Card   Card `json:"-"`
+	Amount uint `gorm:"not null" json:"amount"`
+	// given by the proposer, otherwise requested from the recipient
+	Offered bool `gorm:"not null" json:"offered"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
@@ -12,5 +16,38 @@ type User struct {
 
 	Verified bool `gorm:"not null"`
 
+	DisplayName         string  `gorm:""`
+	PreferredCurrency   string  `gorm:""`
+	PreferredLanguageID *string `gorm:""`
+
+	PendingEmail        string     `gorm:""`
+	EmailTokenHash      string     `gorm:""`
+	EmailTokenExpiresAt *time.Time `gorm:""`
+
+	TwoFactorSecret  string `gorm:""`
+	TwoFactorEnabled bool   `gorm:"not null;default:false"`
+	// the time step of the last accepted TOTP code, codes of it and earlier steps can't be used again
+	TotpLastStep  uint64 `gorm:"not null;default:0"`
+	RecoveryCodes []RecoveryCode
+
+	OidcIssuer  *string `gorm:"uniqueIndex:idx_user_oidc"`
+	OidcSubject *string `gorm:"uniqueIndex:idx_user_oidc"`
+
+	Suspended        bool       `gorm:"not null;default:false"`
+	SuspendedUntil   *time.Time `gorm:""`
+	SuspensionReason string     `gorm:""`
+
+	PasswordResetRequired  bool       `gorm:"not null;default:false"`
+	PasswordResetTokenHash string     `gorm:"index"`
+	PasswordResetExpiresAt *time.Time `gorm:""`
+
 	Cart Cart
 }
+
+// IsSuspended reports whether the user is suspended at the given time, a suspension without an end is a ban
+func (u *User) IsSuspended(t time.Time) bool {
+	if !u.Suspended {
+		return false
+	}
+	return u.SuspendedUntil == nil || t.Before(*u.SuspendedUntil)
+}
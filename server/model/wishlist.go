@@ -0,0 +1,31 @@
+package model
+
+import "gorm.io/gorm"
+
+// Wishlist is a card the user is looking for, either a specific printing or any printing of a card key
+type Wishlist struct {
+	gorm.Model
+
+	UserID uint `gorm:"not null;index" json:"userId"`
+
+	CardID    *uint   `gorm:"index" json:"cardId"`
+	CardKeyID *string `gorm:"index" json:"cardKeyId"`
+
+	// nil accepts any price
+	MaxPrice     *Money        `gorm:"" json:"maxPrice"`
+	MinCondition CardCondition `gorm:"" json:"minCondition"`
+}
+
+// Matches checks if the card is one the user is looking for at the price and condition they want
+func (w *Wishlist) Matches(card *Card) bool {
+	if w.CardID != nil && *w.CardID != card.ID {
+		return false
+	}
+	if w.CardKeyID != nil && *w.CardKeyID != card.CardKeyID {
+		return false
+	}
+	if w.MaxPrice != nil && card.Price > *w.MaxPrice {
+		return false
+	}
+	return card.Condition.AtLeast(w.MinCondition)
+}
@@ -1,17 +1,41 @@
 package query
 
+import (
+	urlquery "github.com/google/go-querystring/query"
+	"store.api/model"
+)
+
 type CardQuery struct {
-	Raw string
+	Raw string `url:"-"`
+
+	Name     string      `form:"name" url:"name"`
+	Type     string      `form:"type" url:"type"`
+	Language string      `form:"lang" url:"lang"`
+	Key      string      `form:"key" url:"key"`
+	MinPrice model.Money `form:"minPrice,default=-1" url:"minPrice"`
+	MaxPrice model.Money `form:"maxPrice,default=-1" url:"maxPrice"`
+	// currency of the price filters and the returned prices, empty for the store's currency
+	Currency    string `form:"currency" url:"currency"`
+	Page        uint   `form:"page,default=1" url:"page"`
+	Keywords    string `form:"t" url:"keywords"`
+	Expansion   string `form:"expansion" url:"expansion"`
+	InStockOnly bool   `form:"inStockOnly,default=false"`
+	FoilOnly    bool   `form:"foilOnly,default=false"`
+}
+
+// Filters checks if the query narrows down the cards, the currency and paging don't
+func (q *CardQuery) Filters() bool {
+	return q.Name != "" || q.Type != "" || q.Language != "" || q.Key != "" ||
+		q.MinPrice >= 0 || q.MaxPrice >= 0 || q.Keywords != "" || q.Expansion != "" ||
+		q.InStockOnly || q.FoilOnly
+}
 
-	Name        string  `form:"name" url:"name"`
-	Type        string  `form:"type" url:"type"`
-	Language    string  `form:"lang" url:"lang"`
-	Key         string  `form:"key" url:"key"`
-	MinPrice    float32 `form:"minPrice,default=-1" url:"minPrice"`
-	MaxPrice    float32 `form:"maxPrice,default=-1" url:"maxPrice"`
-	Page        uint    `form:"page,default=1" url:"page"`
-	Keywords    string  `form:"t" url:"keywords"`
-	Expansion   string  `form:"expansion" url:"expansion"`
-	InStockOnly bool    `form:"inStockOnly,default=false"`
-	FoilOnly    bool    `form:"foilOnly,default=false"`
+// Encode encodes the query as the key its results are cached by
+func (q *CardQuery) Encode() string {
+	vals, err := urlquery.Values(q)
+	if err != nil {
+		// * should never happen
+		panic(err)
+	}
+	return vals.Encode()
 }
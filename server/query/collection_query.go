@@ -0,0 +1,6 @@
+package query
+
+type CollectionQuery struct {
+	Folder *uint  `form:"folder"`
+	Tag    string `form:"tag"`
+}
@@ -0,0 +1,7 @@
+package query
+
+type UserQuery struct {
+	Search        string `form:"search"`
+	Page          uint   `form:"page,default=1"`
+	SuspendedOnly bool   `form:"suspendedOnly,default=false"`
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"store.api/cache"
 	"store.api/config"
 	"store.api/model"
@@ -16,6 +19,7 @@ type CardDbRepository struct {
 	config     *config.Configuration
 	cardCache  cache.CardCache
 	queryCache cache.CardQueryCache
+	observers  []CardObserver
 }
 
 func errCreatedAndFailedToFindCard(id uint) error {
@@ -31,9 +35,33 @@ func NewCardDbRepository(db *gorm.DB, config *config.Configuration, cardCache ca
 	}
 }
 
+// Observe registers an observer that is notified of stock and price changes
+func (r *CardDbRepository) Observe(observer CardObserver) {
+	r.observers = append(r.observers, observer)
+}
+
+func (r *CardDbRepository) notifyStockChanged(card *model.Card, oldAmount uint) {
+	if card.InStockAmount == oldAmount {
+		return
+	}
+	for _, observer := range r.observers {
+		observer.StockChanged(card, oldAmount)
+	}
+}
+
+func (r *CardDbRepository) notifyPriceChanged(card *model.Card, oldPrice model.Money) {
+	if card.Price == oldPrice {
+		return
+	}
+	for _, observer := range r.observers {
+		observer.PriceChanged(card, oldPrice)
+	}
+}
+
 func (r *CardDbRepository) applyPreloads(db *gorm.DB) *gorm.DB {
 	return db.
 		Preload("CardType").
+		Preload("CardKey").
 		Preload("Foiling").
 		Preload("Expansion").
 		Preload("Language")
@@ -54,8 +82,17 @@ func (r *CardDbRepository) dbFindById(id uint) *model.Card {
 }
 
 func (r *CardDbRepository) Save(card *model.Card) error {
-	create := r.db.Create(card)
-	err := create.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(card).Error
+		if err != nil {
+			return err
+		}
+		return recordStockMovement(tx, card.ID, 0, card.InStockAmount, model.StockMovement{
+			Kind:    model.StockRestock,
+			ActorID: &card.PosterID,
+			Reason:  "initial stock",
+		})
+	})
 	if err != nil {
 		return err
 	}
@@ -128,9 +165,29 @@ func (r *CardDbRepository) Count() int64 {
 	return result
 }
 
-func (r *CardDbRepository) Update(card *model.Card) error {
-	update := r.db.Save(card)
-	err := update.Error
+func (r *CardDbRepository) Update(card *model.Card, movement model.StockMovement) error {
+	var old model.Card
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("price", "in_stock_amount").
+			First(&old, card.ID).
+			Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		err = tx.Save(card).Error
+		if err != nil {
+			return err
+		}
+
+		err = recordPriceChange(tx, card.ID, old.Price, card.Price)
+		if err != nil {
+			return err
+		}
+		return recordStockMovement(tx, card.ID, old.InStockAmount, card.InStockAmount, movement)
+	})
 	if err != nil {
 		return err
 	}
@@ -143,19 +200,41 @@ func (r *CardDbRepository) Update(card *model.Card) error {
 
 	r.queryCache.ForgetAll()
 
+	r.notifyPriceChanged(result, old.Price)
+	r.notifyStockChanged(result, old.InStockAmount)
 	return nil
 }
 
-func (r *CardDbRepository) UpdatePrice(id uint, price float32) (*model.Card, error) {
-	c := &model.Card{}
-	c.ID = id
-	update := r.db.
-		Model(c).
-		Update("price", price)
-	if update.Error != nil {
-		return nil, update.Error
+func (r *CardDbRepository) UpdatePrice(id uint, price model.Money) (*model.Card, error) {
+	found := true
+	var old model.Card
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		find := tx.Select("id", "price").Find(&old, id)
+		if find.Error != nil {
+			return find.Error
+		}
+		if find.RowsAffected == 0 {
+			found = false
+			return nil
+		}
+
+		// updating through old would overwrite the old price
+		c := &model.Card{}
+		c.ID = id
+		err := tx.
+			Model(c).
+			Update("price", price).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return recordPriceChange(tx, id, old.Price, price)
+	})
+	if err != nil {
+		return nil, err
 	}
-	if update.RowsAffected == 0 {
+	if !found {
 		return nil, nil
 	}
 
@@ -163,19 +242,238 @@ func (r *CardDbRepository) UpdatePrice(id uint, price float32) (*model.Card, err
 	r.cardCache.Remember(result)
 
 	r.queryCache.ForgetAll()
+
+	r.notifyPriceChanged(result, old.Price)
 	return result, nil
 }
 
-func (r *CardDbRepository) UpdateInStockAmount(id uint, price uint) (*model.Card, error) {
-	c := &model.Card{}
-	c.ID = id
-	update := r.db.
-		Model(c).
-		Update("in_stock_amount", price)
-	if update.Error != nil {
-		return nil, update.Error
+// recordPriceChange stores the change in the card's price history, unchanged prices are skipped
+func recordPriceChange(tx *gorm.DB, cardId uint, oldPrice model.Money, newPrice model.Money) error {
+	if oldPrice == newPrice {
+		return nil
+	}
+	return tx.Create(&model.PriceChange{
+		CardID:   cardId,
+		OldPrice: oldPrice,
+		NewPrice: newPrice,
+	}).Error
+}
+
+func (r *CardDbRepository) UpdatePrices(prices map[uint]model.Money) ([]*model.Card, error) {
+	ids := make([]uint, 0, len(prices))
+	for id := range prices {
+		ids = append(ids, id)
+	}
+
+	oldPrices := map[uint]model.Money{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var cards []*model.Card
+		err := lockCards(tx, ids).
+			Select("id", "price").
+			Find(&cards).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(cards) != len(ids) {
+			return missingCards(ids, cards)
+		}
+
+		for _, card := range cards {
+			oldPrices[card.ID] = card.Price
+		}
+		return setPrices(tx, oldPrices, prices)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.refreshPrices(oldPrices), nil
+}
+
+func (r *CardDbRepository) ScalePrices(q *query.CardQuery, factor float64) ([]*model.Card, error) {
+	oldPrices := map[uint]model.Money{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var cards []*model.Card
+		// the keyword filters join other tables, only the cards are locked
+		err := r.applyQuery(q, tx.Model(&model.Card{})).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+			Select("cards.id", "cards.price").
+			Order("cards.id").
+			Find(&cards).
+			Error
+		if err != nil {
+			return err
+		}
+
+		newPrices := make(map[uint]model.Money, len(cards))
+		for _, card := range cards {
+			oldPrices[card.ID] = card.Price
+			newPrices[card.ID] = card.Price.Scale(factor)
+			if newPrices[card.ID] <= 0 {
+				return fmt.Errorf("%w: card %d would cost %v", ErrNonPositivePrice, card.ID, newPrices[card.ID])
+			}
+		}
+		return setPrices(tx, oldPrices, newPrices)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.refreshPrices(oldPrices), nil
+}
+
+// setPrices updates the prices that changed and records them in the price history
+func setPrices(tx *gorm.DB, oldPrices map[uint]model.Money, newPrices map[uint]model.Money) error {
+	for id, price := range newPrices {
+		if oldPrices[id] == price {
+			continue
+		}
+
+		c := &model.Card{}
+		c.ID = id
+		err := tx.
+			Model(c).
+			Update("price", price).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = recordPriceChange(tx, id, oldPrices[id], price)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockCards selects the cards for update, in the order of their ids so that concurrent bulk updates can't deadlock
+func lockCards(tx *gorm.DB, ids []uint) *gorm.DB {
+	return tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id")
+}
+
+// missingCards reports which of the ids weren't found
+func missingCards(ids []uint, found []*model.Card) error {
+	existing := make(map[uint]bool, len(found))
+	for _, card := range found {
+		existing[card.ID] = true
+	}
+	var missing []uint
+	for _, id := range ids {
+		if !existing[id] {
+			missing = append(missing, id)
+		}
+	}
+	slices.Sort(missing)
+	return &CardsNotFoundError{Ids: missing}
+}
+
+// PricesAt returns the prices the cards had at the given time: the old price of the first change
+// after that time, or the current price if the price didn't change since
+func (r *CardDbRepository) PricesAt(ids []uint, at time.Time) map[uint]model.Money {
+	result := make(map[uint]model.Money)
+	if len(ids) == 0 {
+		return result
+	}
+
+	var changes []*model.PriceChange
+	err := r.db.
+		Raw("SELECT DISTINCT ON (card_id) card_id, old_price FROM price_changes WHERE card_id IN ? AND created_at > ? AND deleted_at IS NULL ORDER BY card_id, created_at", ids, at).
+		Scan(&changes).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	for _, change := range changes {
+		result[change.CardID] = change.OldPrice
+	}
+
+	var cards []*model.Card
+	err = r.db.
+		Select("id", "price").
+		Where("id IN ?", ids).
+		Find(&cards).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	for _, card := range cards {
+		if _, ok := result[card.ID]; !ok {
+			result[card.ID] = card.Price
+		}
+	}
+
+	return result
+}
+
+// FindByKeyName returns all printings of the card with the given english name, non-foil printings first
+func (r *CardDbRepository) FindByKeyName(name string) []*model.Card {
+	var result []*model.Card
+	err := r.applyPreloads(r.db).
+		Joins("JOIN card_keys ON cards.card_key_id = card_keys.id").
+		Where("LOWER(card_keys.eng_name) = ?", strings.ToLower(name)).
+		Order("cards.foiling_id IS NOT NULL").
+		Order("cards.id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
 	}
-	if update.RowsAffected == 0 {
+	return result
+}
+
+// FindInStockByKeyId returns the printings of a card key that are in stock, from the cheapest
+func (r *CardDbRepository) FindInStockByKeyId(keyId string) []*model.Card {
+	var result []*model.Card
+	err := r.applyPreloads(r.db).
+		Where("card_key_id=?", keyId).
+		Where("in_stock_amount > 0").
+		Order("price").
+		Order("id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *CardDbRepository) UpdateInStockAmount(id uint, amount uint, movement model.StockMovement) (*model.Card, error) {
+	found := true
+	var old model.Card
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		find := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "in_stock_amount").
+			Find(&old, id)
+		if find.Error != nil {
+			return find.Error
+		}
+		if find.RowsAffected == 0 {
+			found = false
+			return nil
+		}
+
+		c := &model.Card{}
+		c.ID = id
+		err := tx.
+			Model(c).
+			Update("in_stock_amount", amount).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return recordStockMovement(tx, id, old.InStockAmount, amount, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
 		return nil, nil
 	}
 
@@ -183,9 +481,164 @@ func (r *CardDbRepository) UpdateInStockAmount(id uint, price uint) (*model.Card
 	r.cardCache.Remember(result)
 
 	r.queryCache.ForgetAll()
+
+	r.notifyStockChanged(result, old.InStockAmount)
 	return result, nil
 }
 
+func (r *CardDbRepository) AdjustStock(adjustments []StockAdjustment, movement model.StockMovement) ([]*model.Card, error) {
+	ids := make([]uint, 0, len(adjustments))
+	for _, adjustment := range adjustments {
+		ids = append(ids, adjustment.CardID)
+	}
+
+	oldAmounts := map[uint]uint{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var cards []*model.Card
+		err := lockCards(tx, ids).
+			Select("id", "in_stock_amount").
+			Find(&cards).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(cards) != len(ids) {
+			return missingCards(ids, cards)
+		}
+
+		for _, card := range cards {
+			oldAmounts[card.ID] = card.InStockAmount
+		}
+		for _, adjustment := range adjustments {
+			amount := adjustment.Amount
+			if adjustment.Relative {
+				amount += int(oldAmounts[adjustment.CardID])
+			}
+			if amount < 0 {
+				return &StockShortageError{CardId: adjustment.CardID, InStock: oldAmounts[adjustment.CardID]}
+			}
+
+			c := &model.Card{}
+			c.ID = adjustment.CardID
+			err := tx.
+				Model(c).
+				Update("in_stock_amount", amount).
+				Error
+			if err != nil {
+				return err
+			}
+
+			err = recordStockMovement(tx, adjustment.CardID, oldAmounts[adjustment.CardID], uint(amount), movement)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.refreshStock(oldAmounts), nil
+}
+
+func (r *CardDbRepository) StockMovements(cardId uint) []*model.StockMovement {
+	var result []*model.StockMovement
+	err := r.db.
+		Where("card_id=?", cardId).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *CardDbRepository) FindLowStock() []*model.Card {
+	var result []*model.Card
+	err := r.applyPreloads(r.db).
+		Select("cards.*").
+		Joins("JOIN expansions ON cards.expansion_id = expansions.id").
+		Where("cards.in_stock_amount <= COALESCE(cards.low_stock_threshold, expansions.low_stock_threshold)").
+		Order("cards.in_stock_amount").
+		Order("cards.id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// recordStockMovement stores the change in the card's stock ledger, unchanged amounts are skipped.
+// The movement only needs its kind, actor, reason and order, the rest is filled in
+func recordStockMovement(tx *gorm.DB, cardId uint, oldAmount uint, newAmount uint, movement model.StockMovement) error {
+	if oldAmount == newAmount {
+		return nil
+	}
+	movement.CardID = cardId
+	movement.Delta = int(newAmount) - int(oldAmount)
+	if !movement.Kind.Allows(movement.Delta) {
+		return fmt.Errorf("%w: %s of %d for card %d", ErrStockMovementKind, movement.Kind, movement.Delta, cardId)
+	}
+	movement.Balance = newAmount
+	return tx.Create(&movement).Error
+}
+
+// refreshStock recaches cards whose stock was changed outside of the repository and notifies the observers,
+// oldAmounts maps the card ids to their stock before the change
+func (r *CardDbRepository) refreshStock(oldAmounts map[uint]uint) []*model.Card {
+	ids := make([]uint, 0, len(oldAmounts))
+	for id := range oldAmounts {
+		ids = append(ids, id)
+	}
+	refreshed := r.refreshCards(ids)
+
+	for _, card := range refreshed {
+		r.notifyStockChanged(card, oldAmounts[card.ID])
+	}
+	return refreshed
+}
+
+// refreshPrices recaches cards whose prices were changed in bulk and notifies the observers,
+// oldPrices maps the card ids to their price before the change
+func (r *CardDbRepository) refreshPrices(oldPrices map[uint]model.Money) []*model.Card {
+	ids := make([]uint, 0, len(oldPrices))
+	for id := range oldPrices {
+		ids = append(ids, id)
+	}
+	refreshed := r.refreshCards(ids)
+
+	for _, card := range refreshed {
+		r.notifyPriceChanged(card, oldPrices[card.ID])
+	}
+	return refreshed
+}
+
+// refreshCards fetches and recaches the cards in one query and forgets the cached queries, ordered by id
+func (r *CardDbRepository) refreshCards(ids []uint) []*model.Card {
+	if len(ids) == 0 {
+		return []*model.Card{}
+	}
+
+	var result []*model.Card
+	err := r.applyPreloads(r.db).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	for _, card := range result {
+		r.cardCache.Remember(card)
+	}
+	r.queryCache.ForgetAll()
+	return result
+}
+
 func (repo *CardDbRepository) applyQuery(q *query.CardQuery, d *gorm.DB) *gorm.DB {
 	result := d.Where("LOWER(name) like ?", "%"+strings.ToLower(q.Name)+"%")
 	if len(q.Type) > 0 {
@@ -194,10 +647,10 @@ func (repo *CardDbRepository) applyQuery(q *query.CardQuery, d *gorm.DB) *gorm.D
 	if len(q.Language) > 0 {
 		result = result.Where("language_id=?", q.Language)
 	}
-	if q.MaxPrice != -1 {
+	if q.MaxPrice >= 0 {
 		result = result.Where("price < ?", q.MaxPrice)
 	}
-	if q.MinPrice != -1 {
+	if q.MinPrice >= 0 {
 		result = result.Where("price > ?", q.MinPrice)
 	}
 	if len(q.Key) > 0 {
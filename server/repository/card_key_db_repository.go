@@ -27,3 +27,17 @@ func (repo *CardKeyDbRepository) All() []*model.CardKey {
 	}
 	return result
 }
+
+func (repo *CardKeyDbRepository) FindById(id string) *model.CardKey {
+	var result model.CardKey
+	find := repo.db.
+		Where("id=?", id).
+		First(&result)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
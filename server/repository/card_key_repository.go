@@ -4,4 +4,5 @@ import "store.api/model"
 
 type CardKeyRepository interface {
 	All() []*model.CardKey
+	FindById(id string) *model.CardKey
 }
@@ -0,0 +1,9 @@
+package repository
+
+import "store.api/model"
+
+// CardObserver is notified after a card's stock or price has been changed and the change is committed
+type CardObserver interface {
+	StockChanged(card *model.Card, oldAmount uint)
+	PriceChanged(card *model.Card, oldPrice model.Money)
+}
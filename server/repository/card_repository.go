@@ -1,15 +1,75 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"store.api/model"
 	"store.api/query"
 )
 
+var (
+	ErrStockMovementKind = errors.New("the stock movement's kind doesn't match the direction of the change")
+	ErrNonPositivePrice  = errors.New("prices have to stay above 0")
+)
+
+// CardsNotFoundError lists the cards of a bulk update that don't exist
+type CardsNotFoundError struct {
+	Ids []uint
+}
+
+func (e *CardsNotFoundError) Error() string {
+	return fmt.Sprintf("cards not found: %v", e.Ids)
+}
+
+// StockShortageError is the ErrInsufficientStock of a bulk stock adjustment, naming the card that would go negative
+type StockShortageError struct {
+	CardId  uint
+	InStock uint
+}
+
+func (e *StockShortageError) Error() string {
+	return fmt.Sprintf("%v: card %d has %d", ErrInsufficientStock, e.CardId, e.InStock)
+}
+
+func (e *StockShortageError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+// StockAdjustment sets a card's stock to Amount, or adds Amount to it if Relative
+type StockAdjustment struct {
+	CardID   uint
+	Amount   int
+	Relative bool
+}
+
 type CardRepository interface {
 	Save(*model.Card) error
 	FindById(id uint) *model.Card
-	Update(*model.Card) error
-	UpdatePrice(id uint, price float32) (*model.Card, error)
-	UpdateInStockAmount(id uint, amount uint) (*model.Card, error)
+	// Update saves the card, a change of its stock is recorded in the stock ledger with the movement's kind, actor and reason
+	Update(card *model.Card, movement model.StockMovement) error
+	UpdatePrice(id uint, price model.Money) (*model.Card, error)
+	// UpdateInStockAmount sets the card's stock and records the change in its stock ledger,
+	// the movement only needs its kind, actor and reason. Fails with ErrStockMovementKind if the kind doesn't fit the change
+	UpdateInStockAmount(id uint, amount uint, movement model.StockMovement) (*model.Card, error)
+	// UpdatePrices sets the prices of many cards in one transaction and refreshes the caches once. Fails with
+	// CardsNotFoundError if any of the cards doesn't exist, nothing is changed then
+	UpdatePrices(prices map[uint]model.Money) ([]*model.Card, error)
+	// ScalePrices multiplies the prices of all cards matching the query's filters by the factor in one transaction,
+	// the query's paging is ignored. Fails with ErrNonPositivePrice if any card would cost 0, nothing is changed then
+	ScalePrices(query *query.CardQuery, factor float64) ([]*model.Card, error)
+	// AdjustStock applies the adjustments in one transaction and refreshes the caches once, each change is recorded
+	// in the stock ledger with the movement's kind, actor and reason. Fails with CardsNotFoundError, ErrStockMovementKind,
+	// or StockShortageError if an adjustment would leave a card with negative stock
+	AdjustStock(adjustments []StockAdjustment, movement model.StockMovement) ([]*model.Card, error)
+	// StockMovements returns the card's stock ledger, newest first
+	StockMovements(cardId uint) []*model.StockMovement
+	// FindLowStock returns the cards whose stock is at or below their own threshold or their expansion's default,
+	// the emptiest first
+	FindLowStock() []*model.Card
 	Query(query *query.CardQuery) ([]*model.Card, int64)
+	PricesAt(ids []uint, at time.Time) map[uint]model.Money
+	FindByKeyName(name string) []*model.Card
+	FindInStockByKeyId(keyId string) []*model.Card
 }
@@ -101,3 +101,76 @@ func (r *CartDbRepository) DeleteSlot(slot *model.CartSlot) error {
 	r.cache.Remember(updated)
 	return nil
 }
+
+func (r *CartDbRepository) UpdateSlots(cartId uint, saved []*model.CartSlot, deleted []*model.CartSlot) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, slot := range saved {
+			err := tx.Save(slot).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, slot := range deleted {
+			err := tx.Delete(slot).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	updated := r.dbFindById(cartId)
+	r.cache.Remember(updated)
+	return nil
+}
+
+func (r *CartDbRepository) UpdatePromotionCode(cartId uint, code *string) error {
+	c := &model.Cart{}
+	c.ID = cartId
+	err := r.db.
+		Model(c).
+		Update("promotion_code", code).
+		Error
+	if err != nil {
+		return err
+	}
+	r.refresh(cartId)
+	return nil
+}
+
+// refresh recaches a cart that was changed outside of the repository
+func (r *CartDbRepository) refresh(cartId uint) {
+	updated := r.dbFindById(cartId)
+	if updated == nil {
+		return
+	}
+	r.cache.Remember(updated)
+}
+
+func (r *CartDbRepository) DeleteByUserId(userId uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteCart(tx, userId)
+	})
+	if err != nil {
+		return err
+	}
+	r.cache.Forget(userId)
+	return nil
+}
+
+func deleteCart(tx *gorm.DB, userId uint) error {
+	err := tx.
+		Where("cart_id IN (?)", tx.Model(&model.Cart{}).Select("id").Where("user_id=?", userId)).
+		Delete(&model.CartSlot{}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return tx.
+		Where("user_id=?", userId).
+		Delete(&model.Cart{}).
+		Error
+}
@@ -8,4 +8,9 @@ type CartRepository interface {
 	Update(*model.Cart) error
 	UpdateSlot(slot *model.CartSlot) error
 	DeleteSlot(slot *model.CartSlot) error
+	// UpdateSlots saves and deletes the slots of a cart in a single transaction
+	UpdateSlots(cartId uint, saved []*model.CartSlot, deleted []*model.CartSlot) error
+	// UpdatePromotionCode sets the cart's coupon, nil removes it
+	UpdatePromotionCode(cartId uint, code *string) error
+	DeleteByUserId(userId uint) error
 }
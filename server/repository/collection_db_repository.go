@@ -75,6 +75,34 @@ func (repo *CollectionDbRepository) FindById(id uint) *model.Collection {
 	return result
 }
 
+func (repo *CollectionDbRepository) FindByShareToken(token string) *model.Collection {
+	var result model.Collection
+	find := repo.db.
+		Select("id").
+		Where("share_token=?", token).
+		Find(&result)
+
+	if find.Error != nil {
+		panic(find.Error)
+	}
+	if find.RowsAffected == 0 {
+		return nil
+	}
+	return repo.FindById(result.ID)
+}
+
+func (repo *CollectionDbRepository) FindPublicByOwnerId(ownerId uint) []*model.Collection {
+	var result []*model.Collection
+	find := repo.db.
+		Where("owner_id=? AND visibility=?", ownerId, model.VisibilityPublic).
+		Find(&result)
+
+	if find.Error != nil {
+		panic(find.Error)
+	}
+	return result
+}
+
 func (repo *CollectionDbRepository) Update(collection *model.Collection) error {
 	update := repo.db.Save(collection)
 	if update.Error != nil {
@@ -108,6 +136,40 @@ func (repo *CollectionDbRepository) DeleteSlot(slot *model.CollectionSlot) error
 	return nil
 }
 
+func (repo *CollectionDbRepository) UpdateSlots(collectionId uint, saved []*model.CollectionSlot, deleted []*model.CollectionSlot) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, slot := range saved {
+			err := tx.Save(slot).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, slot := range deleted {
+			err := tx.Delete(slot).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	updated := repo.dbFindById(collectionId)
+	repo.cache.Remember(updated)
+	return nil
+}
+
+// refresh recaches a collection whose slots were changed outside of the repository
+func (repo *CollectionDbRepository) refresh(id uint) {
+	updated := repo.dbFindById(id)
+	if updated == nil {
+		repo.cache.Forget(id)
+		return
+	}
+	repo.cache.Remember(updated)
+}
+
 func (repo *CollectionDbRepository) Delete(id uint) error {
 	delete := repo.db.Delete(&model.Collection{}, id)
 	if delete.Error != nil {
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"store.api/cache"
+	"store.api/config"
+	"store.api/model"
+)
+
+type CollectionFolderDbRepository struct {
+	db              *gorm.DB
+	config          *config.Configuration
+	collectionCache cache.CollectionCache
+}
+
+func NewCollectionFolderDbRepository(db *gorm.DB, config *config.Configuration, collectionCache cache.CollectionCache) *CollectionFolderDbRepository {
+	return &CollectionFolderDbRepository{
+		db:              db,
+		config:          config,
+		collectionCache: collectionCache,
+	}
+}
+
+func (r *CollectionFolderDbRepository) FindByOwnerId(ownerId uint) []*model.CollectionFolder {
+	var result []*model.CollectionFolder
+	err := r.db.
+		Where("owner_id=?", ownerId).
+		Order("name").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *CollectionFolderDbRepository) FindById(id uint) *model.CollectionFolder {
+	var result model.CollectionFolder
+	find := r.db.First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *CollectionFolderDbRepository) Save(folder *model.CollectionFolder) error {
+	return r.db.Create(folder).Error
+}
+
+func (r *CollectionFolderDbRepository) Update(folder *model.CollectionFolder) error {
+	return r.db.Save(folder).Error
+}
+
+func (r *CollectionFolderDbRepository) Delete(folder *model.CollectionFolder) error {
+	var moved []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&model.CollectionFolder{}).
+			Where("parent_id=?", folder.ID).
+			Update("parent_id", folder.ParentID).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Model(&model.Collection{}).
+			Where("folder_id=?", folder.ID).
+			Pluck("id", &moved).
+			Error
+		if err != nil {
+			return err
+		}
+		err = tx.
+			Model(&model.Collection{}).
+			Where("folder_id=?", folder.ID).
+			Update("folder_id", folder.ParentID).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(folder).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// cached collections still point to the deleted folder
+	for _, id := range moved {
+		r.collectionCache.Forget(id)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "store.api/model"
+
+type CollectionFolderRepository interface {
+	FindByOwnerId(ownerId uint) []*model.CollectionFolder
+	FindById(id uint) *model.CollectionFolder
+	Save(*model.CollectionFolder) error
+	Update(*model.CollectionFolder) error
+	// Delete removes the folder, its subfolders and collections are moved to its parent
+	Delete(*model.CollectionFolder) error
+}
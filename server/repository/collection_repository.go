@@ -6,8 +6,12 @@ type CollectionRepository interface {
 	FindByOwnerId(ownerId uint) []*model.Collection
 	Save(*model.Collection) error
 	FindById(id uint) *model.Collection
+	FindByShareToken(token string) *model.Collection
+	FindPublicByOwnerId(ownerId uint) []*model.Collection
 	Update(*model.Collection) error
 	UpdateSlot(slot *model.CollectionSlot) error
 	DeleteSlot(slot *model.CollectionSlot) error
+	// UpdateSlots saves and deletes the slots of a collection in a single transaction
+	UpdateSlots(collectionId uint, saved []*model.CollectionSlot, deleted []*model.CollectionSlot) error
 	Delete(id uint) error
 }
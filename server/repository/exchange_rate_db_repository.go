@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"store.api/config"
+	"store.api/model"
+)
+
+type ExchangeRateDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewExchangeRateDbRepository(db *gorm.DB, config *config.Configuration) *ExchangeRateDbRepository {
+	return &ExchangeRateDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+func (r *ExchangeRateDbRepository) FindAll() []*model.ExchangeRate {
+	var result []*model.ExchangeRate
+	err := r.db.
+		Order("currency").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *ExchangeRateDbRepository) FindByCurrency(currency string) *model.ExchangeRate {
+	var result model.ExchangeRate
+	find := r.db.
+		Where("currency=?", currency).
+		First(&result)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *ExchangeRateDbRepository) Save(rates ...*model.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	err := r.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "currency"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+		}).
+		Create(&rates).
+		Error
+	return err
+}
+
+func (r *ExchangeRateDbRepository) Delete(currency string) error {
+	err := r.db.
+		Where("currency=?", currency).
+		Delete(&model.ExchangeRate{}).
+		Error
+	return err
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "store.api/model"
+
+type ExchangeRateRepository interface {
+	FindAll() []*model.ExchangeRate
+	FindByCurrency(currency string) *model.ExchangeRate
+	// Save creates or replaces the rates of the currencies
+	Save(rates ...*model.ExchangeRate) error
+	Delete(currency string) error
+}
@@ -36,3 +36,23 @@ func (repo *ExpansionDbRepository) All() []*model.Expansion {
 	repo.cache.Remember(result)
 	return result
 }
+
+func (repo *ExpansionDbRepository) UpdateLowStockThreshold(id string, threshold *uint) (*model.Expansion, error) {
+	update := repo.db.
+		Model(&model.Expansion{ID: id}).
+		Update("low_stock_threshold", threshold)
+	if update.Error != nil {
+		return nil, update.Error
+	}
+	if update.RowsAffected == 0 {
+		return nil, nil
+	}
+	repo.cache.Forget()
+
+	var result model.Expansion
+	err := repo.db.First(&result, "id=?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -4,4 +4,6 @@ import "store.api/model"
 
 type ExpansionRepository interface {
 	All() []*model.Expansion
+	// UpdateLowStockThreshold sets the expansion's default low stock threshold, returns nil if there's no such expansion
+	UpdateLowStockThreshold(id string, threshold *uint) (*model.Expansion, error)
 }
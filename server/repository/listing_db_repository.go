@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"store.api/config"
+	"store.api/model"
+)
+
+type ListingDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewListingDbRepository(db *gorm.DB, config *config.Configuration) *ListingDbRepository {
+	return &ListingDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+func (r *ListingDbRepository) FindById(id uint) *model.Listing {
+	var result model.Listing
+	find := r.db.
+		Preload("Seller").
+		First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *ListingDbRepository) FindBySeller(sellerId uint) []*model.Listing {
+	var result []*model.Listing
+	err := r.db.
+		Preload("Seller").
+		Where("seller_id=?", sellerId).
+		Order("created_at").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *ListingDbRepository) FindOffers(cardId uint) []*model.Listing {
+	var result []*model.Listing
+	err := r.db.
+		Preload("Seller").
+		Where("card_id=? AND amount > 0", cardId).
+		Order("price").
+		Order("created_at").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *ListingDbRepository) Summaries(cardIds []uint) map[uint]*OfferSummary {
+	result := map[uint]*OfferSummary{}
+	if len(cardIds) == 0 {
+		return result
+	}
+
+	var summaries []*OfferSummary
+	err := r.db.
+		Model(&model.Listing{}).
+		Select("card_id, COUNT(*) AS listings, SUM(amount) AS amount").
+		Where("card_id IN ? AND amount > 0", cardIds).
+		Group("card_id").
+		Scan(&summaries).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	for _, summary := range summaries {
+		result[summary.CardID] = summary
+	}
+
+	var cheapest []*model.Listing
+	err = r.db.
+		Select("DISTINCT ON (card_id) id, card_id, price").
+		Where("card_id IN ? AND amount > 0", cardIds).
+		Order("card_id").
+		Order("price").
+		Order("created_at").
+		Find(&cheapest).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	for _, listing := range cheapest {
+		if summary, ok := result[listing.CardID]; ok {
+			summary.CheapestID = listing.ID
+			summary.CheapestPrice = listing.Price
+		}
+	}
+	return result
+}
+
+func (r *ListingDbRepository) Save(listing *model.Listing) error {
+	return r.db.Omit("Seller", "Card").Create(listing).Error
+}
+
+func (r *ListingDbRepository) Update(listing *model.Listing, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.
+		Model(listing).
+		Select(fields).
+		Updates(listing).
+		Error
+}
+
+func (r *ListingDbRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Listing{}, id).Error
+}
+
+func (r *ListingDbRepository) Sell(listing *model.Listing, order *model.Order, sale *model.SellerSale) error {
+	line := order.Lines[0]
+	var left uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Listing
+		find := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "price", "amount").
+			Find(&current, listing.ID)
+		if find.Error != nil {
+			return find.Error
+		}
+		if find.RowsAffected == 0 || current.Price != listing.Price || current.Amount < line.Amount {
+			return ErrListingUnavailable
+		}
+
+		left = current.Amount - line.Amount
+		err := tx.
+			Model(&current).
+			Update("amount", left).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(order).Error
+		if err != nil {
+			return err
+		}
+		sale.OrderLineID = order.Lines[0].ID
+		return tx.Omit("OrderLine").Create(sale).Error
+	})
+	if err != nil {
+		return err
+	}
+	listing.Amount = left
+	return nil
+}
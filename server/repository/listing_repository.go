@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+
+	"store.api/model"
+)
+
+var (
+	ErrListingUnavailable = errors.New("listing was changed or doesn't have enough copies left")
+)
+
+// OfferSummary sums up the marketplace offers of a card
+type OfferSummary struct {
+	CardID uint
+	// number of listings with copies left
+	Listings uint
+	// copies left over all listings
+	Amount uint
+	// cheapest listing, the oldest one if several have the same price
+	CheapestID    uint
+	CheapestPrice model.Money
+}
+
+type ListingRepository interface {
+	FindById(id uint) *model.Listing
+	FindBySeller(sellerId uint) []*model.Listing
+	// FindOffers returns the card's listings with copies left, the cheapest first
+	FindOffers(cardId uint) []*model.Listing
+	// Summaries returns the offer summaries of the cards by their ids, cards without offers are left out
+	Summaries(cardIds []uint) map[uint]*OfferSummary
+	Save(*model.Listing) error
+	// Update saves only the given fields of the listing, so copies sold meanwhile aren't overwritten by the others
+	Update(listing *model.Listing, fields ...string) error
+	Delete(id uint) error
+	// Sell takes the order's copies from the listing and stores the order and the sale of its line, all in one
+	// transaction. Fails with ErrListingUnavailable if the listing's price changed or its copies were sold meanwhile
+	Sell(listing *model.Listing, order *model.Order, sale *model.SellerSale) error
+}
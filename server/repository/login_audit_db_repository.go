@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"store.api/config"
+	"store.api/model"
+)
+
+type LoginAuditDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewLoginAuditDbRepository(db *gorm.DB, config *config.Configuration) *LoginAuditDbRepository {
+	return &LoginAuditDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+func (r *LoginAuditDbRepository) Save(failed *model.FailedLogin) error {
+	return r.db.Create(failed).Error
+}
+
+func (r *LoginAuditDbRepository) FindRecent(username string, limit uint) []*model.FailedLogin {
+	var result []*model.FailedLogin
+	db := r.db
+	if len(username) > 0 {
+		db = db.Where("username=?", username)
+	}
+	err := db.
+		Order("created_at desc").
+		Limit(int(limit)).
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "store.api/model"
+
+type LoginAuditRepository interface {
+	Save(*model.FailedLogin) error
+	FindRecent(username string, limit uint) []*model.FailedLogin
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"store.api/config"
+	"store.api/model"
+)
+
+type OrderDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+	// checkouts change cards and carts, their caches are refreshed through their repositories
+	cardRepo *CardDbRepository
+	cartRepo *CartDbRepository
+}
+
+func NewOrderDbRepository(db *gorm.DB, config *config.Configuration, cardRepo *CardDbRepository, cartRepo *CartDbRepository) *OrderDbRepository {
+	return &OrderDbRepository{
+		db:       db,
+		config:   config,
+		cardRepo: cardRepo,
+		cartRepo: cartRepo,
+	}
+}
+
+func (r *OrderDbRepository) Create(order *model.Order, cartId uint, redemptions []*model.PromotionRedemption) error {
+	// cards are locked in the order of their ids so that concurrent checkouts can't deadlock
+	lines := slices.Clone(order.Lines)
+	slices.SortFunc(lines, func(a, b model.OrderLine) int {
+		return cmp.Compare(a.CardID, b.CardID)
+	})
+
+	oldAmounts := map[uint]uint{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, line := range lines {
+			var card model.Card
+			find := tx.
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "in_stock_amount").
+				Find(&card, line.CardID)
+			if find.Error != nil {
+				return find.Error
+			}
+			if find.RowsAffected == 0 || card.InStockAmount < line.Amount {
+				return ErrInsufficientStock
+			}
+			oldAmounts[card.ID] = card.InStockAmount
+
+			c := &model.Card{}
+			c.ID = card.ID
+			err := tx.
+				Model(c).
+				Update("in_stock_amount", card.InStockAmount-line.Amount).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err := tx.Create(order).Error
+		if err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			err := recordStockMovement(tx, line.CardID, oldAmounts[line.CardID], oldAmounts[line.CardID]-line.Amount, model.StockMovement{
+				Kind:    model.StockSale,
+				ActorID: &order.UserID,
+				Reason:  "checkout",
+				OrderID: &order.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, redemption := range redemptions {
+			err := checkRedemptionLimits(tx, redemption)
+			if err != nil {
+				return err
+			}
+			redemption.OrderID = order.ID
+			err = tx.Create(redemption).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.
+			Where("cart_id=?", cartId).
+			Delete(&model.CartSlot{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		c := &model.Cart{}
+		c.ID = cartId
+		return tx.
+			Model(c).
+			Update("promotion_code", nil).
+			Error
+	})
+	if err != nil {
+		return err
+	}
+
+	r.cardRepo.refreshStock(oldAmounts)
+	r.cartRepo.refresh(cartId)
+	return nil
+}
+
+// checkRedemptionLimits locks the promotion so that concurrent checkouts can't both take its last redemption
+func checkRedemptionLimits(tx *gorm.DB, redemption *model.PromotionRedemption) error {
+	var promotion model.Promotion
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&promotion, redemption.PromotionID).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrPromotionExhausted
+		}
+		return err
+	}
+
+	if promotion.MaxRedemptions > 0 {
+		var count int64
+		err = tx.
+			Model(&model.PromotionRedemption{}).
+			Where("promotion_id=?", promotion.ID).
+			Count(&count).
+			Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(promotion.MaxRedemptions) {
+			return ErrPromotionExhausted
+		}
+	}
+
+	if promotion.MaxRedemptionsPerUser > 0 {
+		var count int64
+		err = tx.
+			Model(&model.PromotionRedemption{}).
+			Where("promotion_id=?", promotion.ID).
+			Where("user_id=?", redemption.UserID).
+			Count(&count).
+			Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(promotion.MaxRedemptionsPerUser) {
+			return ErrPromotionExhausted
+		}
+	}
+	return nil
+}
+
+func (r *OrderDbRepository) FindByUserId(userId uint) []*model.Order {
+	var result []*model.Order
+	err := r.db.
+		Preload("Lines").
+		Where("user_id=?", userId).
+		Order("created_at DESC").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *OrderDbRepository) FindById(id uint) *model.Order {
+	var result model.Order
+	find := r.db.
+		Preload("Lines").
+		First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
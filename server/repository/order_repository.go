@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"errors"
+
+	"store.api/model"
+)
+
+var (
+	ErrInsufficientStock  = errors.New("not enough cards in stock")
+	ErrPromotionExhausted = errors.New("promotion has reached its redemption limit")
+)
+
+type OrderRepository interface {
+	// Create stores the order, takes its cards out of stock, records the redemptions and empties the cart,
+	// all in one transaction. Fails with ErrInsufficientStock or ErrPromotionExhausted if another order got there first
+	Create(order *model.Order, cartId uint, redemptions []*model.PromotionRedemption) error
+	FindByUserId(userId uint) []*model.Order
+	FindById(id uint) *model.Order
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"store.api/config"
+	"store.api/model"
+)
+
+type PayoutDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewPayoutDbRepository(db *gorm.DB, config *config.Configuration) *PayoutDbRepository {
+	return &PayoutDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+// periodSales selects the seller's sales by the time their order was placed, the sale is recorded in the same
+// transaction but a few moments later
+func periodSales(db *gorm.DB, sellerId uint, from time.Time, to time.Time) *gorm.DB {
+	return db.
+		Model(&model.SellerSale{}).
+		Joins("JOIN order_lines ON order_lines.id = seller_sales.order_line_id").
+		Joins("JOIN orders ON orders.id = order_lines.order_id").
+		Where("seller_sales.seller_id=?", sellerId).
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to)
+}
+
+func (r *PayoutDbRepository) FindSales(sellerId uint, from time.Time, to time.Time) []*model.SellerSale {
+	var result []*model.SellerSale
+	err := periodSales(r.db, sellerId, from, to).
+		Preload("OrderLine").
+		Order("orders.created_at").
+		Order("seller_sales.id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *PayoutDbRepository) Reconcile(sellerId uint, from time.Time, to time.Time) *SalesReconciliation {
+	var payouts []struct {
+		ID         uint
+		Amount     model.Money
+		Commission model.Money
+		Sales      uint
+		PaidSales  uint
+		PaidGross  model.Money
+		PaidNet    model.Money
+	}
+	// sales only count for the payout of the seller they were made by
+	err := r.db.
+		Model(&model.Payout{}).
+		Joins("LEFT JOIN seller_sales ON seller_sales.payout_id = payouts.id AND seller_sales.seller_id = payouts.seller_id AND seller_sales.deleted_at IS NULL").
+		Where("payouts.seller_id=?", sellerId).
+		Where("payouts.period_start < ? AND payouts.period_end > ?", to, from).
+		Group("payouts.id").
+		Order("payouts.id").
+		Select("payouts.id, payouts.amount, payouts.commission, payouts.sales, " +
+			"COUNT(seller_sales.id) AS paid_sales, " +
+			"COALESCE(SUM(seller_sales.gross), 0) AS paid_gross, " +
+			"COALESCE(SUM(seller_sales.net), 0) AS paid_net").
+		Scan(&payouts).
+		Error
+	if err != nil {
+		panic(err)
+	}
+
+	result := &SalesReconciliation{
+		Payouts:    uint(len(payouts)),
+		Mismatched: []uint{},
+	}
+	for _, p := range payouts {
+		if p.PaidSales != p.Sales || p.PaidNet != p.Amount || p.PaidGross != p.Amount+p.Commission {
+			result.Mismatched = append(result.Mismatched, p.ID)
+		}
+	}
+	return result
+}
+
+func (r *PayoutDbRepository) FindBySeller(sellerId uint) []*model.Payout {
+	var result []*model.Payout
+	err := r.db.
+		Where("seller_id=?", sellerId).
+		Order("created_at DESC").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *PayoutDbRepository) FindCommission(sellerId uint) *model.SellerCommission {
+	var result model.SellerCommission
+	find := r.db.
+		Where("seller_id=?", sellerId).
+		First(&result)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *PayoutDbRepository) SaveCommission(commission *model.SellerCommission) error {
+	return r.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "seller_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"percentage", "updated_at"}),
+		}).
+		Create(commission).
+		Error
+}
+
+func (r *PayoutDbRepository) DeleteCommission(sellerId uint) error {
+	return r.db.
+		Unscoped().
+		Where("seller_id=?", sellerId).
+		Delete(&model.SellerCommission{}).
+		Error
+}
+
+func (r *PayoutDbRepository) Pay(payout *model.Payout) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// the unpaid sales are locked so that concurrent payouts can't pay them twice
+		var unpaid []*model.SellerSale
+		err := periodSales(tx, payout.SellerID, payout.PeriodStart, payout.PeriodEnd).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "seller_sales"}}).
+			Where("seller_sales.payout_id IS NULL").
+			Select("seller_sales.id", "seller_sales.net", "seller_sales.commission").
+			Find(&unpaid).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(unpaid) == 0 {
+			return ErrNothingToPay
+		}
+
+		ids := make([]uint, 0, len(unpaid))
+		payout.Amount = 0
+		payout.Commission = 0
+		for _, sale := range unpaid {
+			ids = append(ids, sale.ID)
+			payout.Amount += sale.Net
+			payout.Commission += sale.Commission
+		}
+		payout.Sales = uint(len(unpaid))
+
+		err = tx.Create(payout).Error
+		if err != nil {
+			return err
+		}
+		return tx.
+			Model(&model.SellerSale{}).
+			Where("id IN ?", ids).
+			Update("payout_id", payout.ID).
+			Error
+	})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"store.api/model"
+)
+
+var (
+	ErrNothingToPay = errors.New("no unpaid sales in the period")
+)
+
+// SalesReconciliation compares the seller's payouts that cover part of a period with the sales they paid
+type SalesReconciliation struct {
+	Payouts uint
+	// payouts whose amount and commission don't add up to the net and gross of their sales
+	Mismatched []uint
+}
+
+type PayoutRepository interface {
+	// FindSales returns the seller's sales of the orders placed in the period, the oldest first
+	FindSales(sellerId uint, from time.Time, to time.Time) []*model.SellerSale
+	// Reconcile checks the seller's payouts that cover part of the period against the sales they paid, whenever
+	// those were ordered
+	Reconcile(sellerId uint, from time.Time, to time.Time) *SalesReconciliation
+	// FindBySeller returns the seller's payouts, the newest first
+	FindBySeller(sellerId uint) []*model.Payout
+	FindCommission(sellerId uint) *model.SellerCommission
+	// SaveCommission creates or replaces the seller's commission
+	SaveCommission(*model.SellerCommission) error
+	DeleteCommission(sellerId uint) error
+	// Pay marks the seller's unpaid sales in the payout's period as paid by it and stores it with their count,
+	// net total and commission, all in one transaction. Fails with ErrNothingToPay if there are none
+	Pay(payout *model.Payout) error
+}
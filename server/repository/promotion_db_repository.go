@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"store.api/config"
+	"store.api/model"
+)
+
+type PromotionDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewPromotionDbRepository(db *gorm.DB, config *config.Configuration) *PromotionDbRepository {
+	return &PromotionDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+func (r *PromotionDbRepository) FindAll() []*model.Promotion {
+	var result []*model.Promotion
+	err := r.db.
+		Order("created_at DESC").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *PromotionDbRepository) FindById(id uint) *model.Promotion {
+	var result model.Promotion
+	find := r.db.First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *PromotionDbRepository) FindByCode(code string) *model.Promotion {
+	var result model.Promotion
+	find := r.db.
+		Where("UPPER(code)=?", strings.ToUpper(code)).
+		First(&result)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *PromotionDbRepository) FindAutomatic() []*model.Promotion {
+	var result []*model.Promotion
+	err := r.db.
+		Where("code IS NULL").
+		Where("active").
+		Order("id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *PromotionDbRepository) Save(promotion *model.Promotion) error {
+	return r.db.Create(promotion).Error
+}
+
+func (r *PromotionDbRepository) Update(promotion *model.Promotion) error {
+	return r.db.Save(promotion).Error
+}
+
+func (r *PromotionDbRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Promotion{}, id).Error
+}
+
+func (r *PromotionDbRepository) CountRedemptions(promotionId uint) int64 {
+	var result int64
+	err := r.db.
+		Model(&model.PromotionRedemption{}).
+		Where("promotion_id=?", promotionId).
+		Count(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *PromotionDbRepository) CountUserRedemptions(promotionId uint, userId uint) int64 {
+	var result int64
+	err := r.db.
+		Model(&model.PromotionRedemption{}).
+		Where("promotion_id=?", promotionId).
+		Where("user_id=?", userId).
+		Count(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *PromotionDbRepository) FindRedemptions(promotionId uint) []*model.PromotionRedemption {
+	var result []*model.PromotionRedemption
+	err := r.db.
+		Where("promotion_id=?", promotionId).
+		Order("created_at DESC").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
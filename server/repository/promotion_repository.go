@@ -0,0 +1,18 @@
+package repository
+
+import "store.api/model"
+
+type PromotionRepository interface {
+	FindAll() []*model.Promotion
+	FindById(id uint) *model.Promotion
+	// FindByCode ignores the case of the code
+	FindByCode(code string) *model.Promotion
+	// FindAutomatic returns the active promotions without a code, their validity windows aren't checked
+	FindAutomatic() []*model.Promotion
+	Save(*model.Promotion) error
+	Update(*model.Promotion) error
+	Delete(id uint) error
+	CountRedemptions(promotionId uint) int64
+	CountUserRedemptions(promotionId uint, userId uint) int64
+	FindRedemptions(promotionId uint) []*model.PromotionRedemption
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"store.api/config"
+	"store.api/model"
+)
+
+type ScheduledPriceDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewScheduledPriceDbRepository(db *gorm.DB, config *config.Configuration) *ScheduledPriceDbRepository {
+	return &ScheduledPriceDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+func (r *ScheduledPriceDbRepository) FindById(id uint) *model.ScheduledPriceChange {
+	var result model.ScheduledPriceChange
+	find := r.db.First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *ScheduledPriceDbRepository) FindPending(cardId uint) []*model.ScheduledPriceChange {
+	var result []*model.ScheduledPriceChange
+	db := r.db.
+		Where("status IN ?", []model.ScheduledPriceStatus{model.ScheduledPricePending, model.ScheduledPriceActive})
+	if cardId != 0 {
+		db = db.Where("card_id=?", cardId)
+	}
+	err := db.
+		Order("starts_at, id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *ScheduledPriceDbRepository) FindDue(now time.Time) []*model.ScheduledPriceChange {
+	var result []*model.ScheduledPriceChange
+	err := r.db.
+		Where("status=?", model.ScheduledPricePending).
+		Where("starts_at<=?", now).
+		Order("starts_at, id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *ScheduledPriceDbRepository) FindEnded(now time.Time) []*model.ScheduledPriceChange {
+	var result []*model.ScheduledPriceChange
+	err := r.db.
+		Where("status=?", model.ScheduledPriceActive).
+		Where("ends_at<=?", now).
+		Order("ends_at, id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *ScheduledPriceDbRepository) Save(change *model.ScheduledPriceChange) error {
+	return r.db.Create(change).Error
+}
+
+func (r *ScheduledPriceDbRepository) Update(change *model.ScheduledPriceChange) error {
+	return r.db.Save(change).Error
+}
+
+func (r *ScheduledPriceDbRepository) Transition(change *model.ScheduledPriceChange, from model.ScheduledPriceStatus) (bool, error) {
+	update := r.db.
+		Model(&model.ScheduledPriceChange{}).
+		Where("id=? AND status=?", change.ID, from).
+		Updates(map[string]interface{}{
+			"status":         change.Status,
+			"previous_price": change.PreviousPrice,
+		})
+	if update.Error != nil {
+		return false, update.Error
+	}
+	return update.RowsAffected == 1, nil
+}
+
+func (r *ScheduledPriceDbRepository) Delete(id uint) error {
+	return r.db.Delete(&model.ScheduledPriceChange{}, id).Error
+}
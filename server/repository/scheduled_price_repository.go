@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"store.api/model"
+)
+
+type ScheduledPriceRepository interface {
+	FindById(id uint) *model.ScheduledPriceChange
+	// FindPending returns the pending and active changes ordered by their start, of all cards if cardId is 0
+	FindPending(cardId uint) []*model.ScheduledPriceChange
+	// FindDue returns the pending changes starting at or before now
+	FindDue(now time.Time) []*model.ScheduledPriceChange
+	// FindEnded returns the active changes whose window ended at or before now
+	FindEnded(now time.Time) []*model.ScheduledPriceChange
+	Save(*model.ScheduledPriceChange) error
+	Update(*model.ScheduledPriceChange) error
+	// Transition stores the change's status and previous price if its stored status is still from,
+	// returns false if another run already moved it on
+	Transition(change *model.ScheduledPriceChange, from model.ScheduledPriceStatus) (bool, error)
+	Delete(id uint) error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"store.api/config"
+	"store.api/model"
+)
+
+type TradeDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+	// accepted trades change collections, their caches are refreshed through their repository
+	collectionRepo *CollectionDbRepository
+}
+
+func NewTradeDbRepository(db *gorm.DB, config *config.Configuration, collectionRepo *CollectionDbRepository) *TradeDbRepository {
+	return &TradeDbRepository{
+		db:             db,
+		config:         config,
+		collectionRepo: collectionRepo,
+	}
+}
+
+func (r *TradeDbRepository) FindById(id uint) *model.Trade {
+	var result model.Trade
+	find := r.db.
+		Preload("Lines.Card").
+		First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *TradeDbRepository) FindByUserId(userId uint) []*model.Trade {
+	var result []*model.Trade
+	err := r.db.
+		Preload("Lines.Card").
+		Where("proposer_id=? OR recipient_id=?", userId, userId).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *TradeDbRepository) Save(trade *model.Trade) error {
+	return r.db.Omit("Lines.Card").Create(trade).Error
+}
+
+func (r *TradeDbRepository) Counter(trade *model.Trade, counter *model.Trade) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := closeTrade(tx, trade, model.TradeCountered)
+		if err != nil {
+			return err
+		}
+		counter.CounterOfID = &trade.ID
+		return tx.Omit("Lines.Card").Create(counter).Error
+	})
+	if err != nil {
+		return err
+	}
+	trade.Status = model.TradeCountered
+	return nil
+}
+
+func (r *TradeDbRepository) Close(trade *model.Trade, status model.TradeStatus) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return closeTrade(tx, trade, status)
+	})
+	if err != nil {
+		return err
+	}
+	trade.Status = status
+	return nil
+}
+
+// slotKey identifies the slot of a card in a collection
+type slotKey struct {
+	collectionId uint
+	cardId       uint
+}
+
+func (r *TradeDbRepository) Accept(trade *model.Trade) error {
+	collectionIds := []uint{trade.ProposerCollectionID, trade.RecipientCollectionID}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := closeTrade(tx, trade, model.TradeAccepted)
+		if err != nil {
+			return err
+		}
+
+		// collections are locked in the order of their ids so that concurrent trades can't deadlock
+		var collections []*model.Collection
+		err = tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id IN ?", collectionIds).
+			Order("id").
+			Find(&collections).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(collections) != len(collectionIds) {
+			return ErrTradeUnavailable
+		}
+
+		// the slots are locked as well, they're also changed without locking their collection
+		var existing []*model.CollectionSlot
+		err = tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("collection_id IN ?", collectionIds).
+			Order("id").
+			Find(&existing).
+			Error
+		if err != nil {
+			return err
+		}
+		slots := map[slotKey]*model.CollectionSlot{}
+		for _, slot := range existing {
+			slots[slotKey{slot.CollectionID, slot.CardID}] = slot
+		}
+
+		for _, line := range trade.Lines {
+			from, to := trade.RecipientCollectionID, trade.ProposerCollectionID
+			if line.Offered {
+				from, to = to, from
+			}
+
+			source := slots[slotKey{from, line.CardID}]
+			if source == nil || source.Amount < line.Amount {
+				return ErrTradeUnavailable
+			}
+			source.Amount -= line.Amount
+
+			target := slots[slotKey{to, line.CardID}]
+			if target == nil {
+				target = &model.CollectionSlot{CollectionID: to, CardID: line.CardID}
+				slots[slotKey{to, line.CardID}] = target
+			}
+			target.Amount += line.Amount
+		}
+
+		for _, slot := range slots {
+			if slot.ID == 0 && slot.Amount == 0 {
+				continue
+			}
+			if slot.Amount == 0 {
+				err = tx.Delete(slot).Error
+			} else {
+				err = tx.Omit("Card").Save(slot).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	trade.Status = model.TradeAccepted
+
+	for _, id := range collectionIds {
+		r.collectionRepo.refresh(id)
+	}
+	return nil
+}
+
+// closeTrade locks the trade and sets its status if it's still open
+func closeTrade(tx *gorm.DB, trade *model.Trade, status model.TradeStatus) error {
+	var current model.Trade
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		First(&current, trade.ID).
+		Error
+	if err != nil {
+		return err
+	}
+	if !current.Open() {
+		return ErrTradeClosed
+	}
+
+	return tx.
+		Model(&current).
+		Update("status", status).
+		Error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"errors"
+
+	"store.api/model"
+)
+
+var (
+	ErrTradeUnavailable = errors.New("the collections no longer have the traded cards")
+	ErrTradeClosed      = errors.New("trade is no longer open")
+)
+
+type TradeRepository interface {
+	FindById(id uint) *model.Trade
+	// FindByUserId returns the trades the user proposed or received, newest first
+	FindByUserId(userId uint) []*model.Trade
+	Save(*model.Trade) error
+	// Counter replaces the open trade with the counter-offer in one transaction, fails with ErrTradeClosed
+	// if it was closed meanwhile
+	Counter(trade *model.Trade, counter *model.Trade) error
+	// Close sets the status of the open trade, fails with ErrTradeClosed if it was closed meanwhile
+	Close(trade *model.Trade, status model.TradeStatus) error
+	// Accept moves the traded cards between the collections and accepts the trade in one transaction. Fails with
+	// ErrTradeClosed, or ErrTradeUnavailable if a collection doesn't have enough copies anymore, nothing is changed then
+	Accept(trade *model.Trade) error
+}
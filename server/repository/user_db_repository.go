@@ -1,20 +1,29 @@
 package repository
 
 import (
+	"fmt"
+
 	"gorm.io/gorm"
 	"store.api/config"
 	"store.api/model"
+	"store.api/query"
 )
 
 type UserDbRepository struct {
 	db     *gorm.DB
 	config *config.Configuration
+
+	collectionRepo *CollectionDbRepository
+	cartRepo       *CartDbRepository
 }
 
-func NewUserDbRepository(db *gorm.DB, config *config.Configuration) *UserDbRepository {
+func NewUserDbRepository(db *gorm.DB, config *config.Configuration, collectionRepo *CollectionDbRepository, cartRepo *CartDbRepository) *UserDbRepository {
 	return &UserDbRepository{
 		db:     db,
 		config: config,
+
+		collectionRepo: collectionRepo,
+		cartRepo:       cartRepo,
 	}
 }
 
@@ -40,7 +49,8 @@ func (r *UserDbRepository) FindByUsername(username string) *model.User {
 
 func (r *UserDbRepository) FindByEmail(email string) *model.User {
 	var result model.User
-	find := r.db.Where("email=?", email).Find(&result)
+	// unverified accounts may share the email of a verified one, the verified one is the owner
+	find := r.db.Where("email=?", email).Order("verified desc").Limit(1).Find(&result)
 	err := find.Error
 	if err != nil {
 		panic(err)
@@ -64,3 +74,248 @@ func (r *UserDbRepository) FindById(id uint) *model.User {
 	}
 	return &result
 }
+
+func (r *UserDbRepository) FindByOidcSubject(issuer string, subject string) *model.User {
+	var result model.User
+	find := r.db.Where("oidc_issuer=? AND oidc_subject=?", issuer, subject).Find(&result)
+	err := find.Error
+	if err != nil {
+		panic(err)
+	}
+
+	if find.RowsAffected == 0 {
+		return nil
+	}
+
+	return &result
+}
+
+func (r *UserDbRepository) FindByPasswordResetToken(tokenHash string) *model.User {
+	var result model.User
+	find := r.db.Where("password_reset_token_hash=?", tokenHash).Find(&result)
+	err := find.Error
+	if err != nil {
+		panic(err)
+	}
+
+	if find.RowsAffected == 0 {
+		return nil
+	}
+
+	return &result
+}
+
+func (r *UserDbRepository) Query(query *query.UserQuery) ([]*model.User, int64) {
+	var result []*model.User
+
+	db := r.db.Model(&model.User{})
+	if len(query.Search) > 0 {
+		pattern := "%" + query.Search + "%"
+		db = db.Where("username ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if query.SuspendedOnly {
+		db = db.Where("suspended=?", true)
+	}
+
+	var count int64
+	err := db.Count(&count).Error
+	if err != nil {
+		panic(err)
+	}
+
+	pageSize := int(r.config.Db.Users.PageSize)
+	offset := (int(query.Page) - 1) * pageSize
+
+	err = db.
+		Order("id").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&result).Error
+	if err != nil {
+		panic(err)
+	}
+
+	return result, count
+}
+
+func (r *UserDbRepository) FindAdmins() []*model.User {
+	var result []*model.User
+	err := r.db.
+		Where("is_admin=? AND verified=?", true, true).
+		Order("id").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *UserDbRepository) Update(user *model.User) error {
+	return r.db.Omit("Cart", "RecoveryCodes").Save(user).Error
+}
+
+func (r *UserDbRepository) ReplaceRecoveryCodes(userId uint, codes []*model.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Unscoped().
+			Where("user_id=?", userId).
+			Delete(&model.RecoveryCode{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		for _, code := range codes {
+			code.UserID = userId
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(codes).Error
+	})
+}
+
+func (r *UserDbRepository) UseRecoveryCode(userId uint, codeHash string) (bool, error) {
+	update := r.db.
+		Model(&model.RecoveryCode{}).
+		Where("user_id=? AND code_hash=? AND used=?", userId, codeHash, false).
+		Update("used", true)
+	if update.Error != nil {
+		return false, update.Error
+	}
+	return update.RowsAffected > 0, nil
+}
+
+func (r *UserDbRepository) UseTotpStep(userId uint, step uint64) (bool, error) {
+	update := r.db.
+		Model(&model.User{}).
+		Where("id=? AND totp_last_step<?", userId, step).
+		Update("totp_last_step", step)
+	if update.Error != nil {
+		return false, update.Error
+	}
+	return update.RowsAffected > 0, nil
+}
+
+// Anonymise deletes the user's collections and cart, removes the personal data of the user and soft-deletes it,
+// all or nothing. The row is kept so that records referencing the user stay valid
+func (r *UserDbRepository) Anonymise(user *model.User) error {
+	var collectionIds []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&model.Collection{}).
+			Where("owner_id=?", user.ID).
+			Pluck("id", &collectionIds).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(collectionIds) > 0 {
+			err = tx.Delete(&model.Collection{}, collectionIds).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = deleteCart(tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Unscoped().
+			Where("user_id=?", user.ID).
+			Delete(&model.RecoveryCode{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Where("username=?", user.Username).
+			Delete(&model.FailedLogin{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Unscoped().
+			Where("user_id=?", user.ID).
+			Delete(&model.Wishlist{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Where("owner_id=?", user.ID).
+			Delete(&model.CollectionFolder{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// the listings stay for the sales that reference them, but nothing is offered anymore
+		err = tx.
+			Model(&model.Listing{}).
+			Where("seller_id=?", user.ID).
+			Update("amount", 0).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// open trades can't be completed without the user
+		err = tx.
+			Model(&model.Trade{}).
+			Where("proposer_id=? AND status=?", user.ID, model.TradeProposed).
+			Update("status", model.TradeCancelled).
+			Error
+		if err != nil {
+			return err
+		}
+		err = tx.
+			Model(&model.Trade{}).
+			Where("recipient_id=? AND status=?", user.ID, model.TradeProposed).
+			Update("status", model.TradeDeclined).
+			Error
+		if err != nil {
+			return err
+		}
+
+		user.Username = fmt.Sprintf("deleted-%d", user.ID)
+		user.PasswordHash = ""
+		user.Email = ""
+		user.Verified = false
+		user.DisplayName = ""
+		user.PreferredCurrency = ""
+		user.PreferredLanguageID = nil
+		user.PendingEmail = ""
+		user.EmailTokenHash = ""
+		user.EmailTokenExpiresAt = nil
+		user.PasswordResetRequired = false
+		user.PasswordResetTokenHash = ""
+		user.PasswordResetExpiresAt = nil
+		user.TwoFactorSecret = ""
+		user.TwoFactorEnabled = false
+		user.OidcIssuer = nil
+		user.OidcSubject = nil
+		err = tx.Omit("Cart", "RecoveryCodes").Save(user).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range collectionIds {
+		r.collectionRepo.cache.Forget(id)
+	}
+	r.cartRepo.cache.Forget(user.ID)
+	return nil
+}
@@ -1,10 +1,24 @@
 package repository
 
-import "store.api/model"
+import (
+	"store.api/model"
+	"store.api/query"
+)
 
 type UserRepository interface {
 	Save(*model.User) error
 	FindByUsername(username string) *model.User
 	FindByEmail(email string) *model.User
 	FindById(id uint) *model.User
+	FindByOidcSubject(issuer string, subject string) *model.User
+	FindByPasswordResetToken(tokenHash string) *model.User
+	Query(query *query.UserQuery) ([]*model.User, int64)
+	// FindAdmins returns the verified admins, including suspended ones
+	FindAdmins() []*model.User
+	Update(*model.User) error
+	ReplaceRecoveryCodes(userId uint, codes []*model.RecoveryCode) error
+	UseRecoveryCode(userId uint, codeHash string) (bool, error)
+	// UseTotpStep records the TOTP time step as used, returns false if it or a later one was used already
+	UseTotpStep(userId uint, step uint64) (bool, error)
+	Anonymise(*model.User) error
 }
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"store.api/config"
+	"store.api/model"
+)
+
+type WishlistDbRepository struct {
+	db     *gorm.DB
+	config *config.Configuration
+}
+
+func NewWishlistDbRepository(db *gorm.DB, config *config.Configuration) *WishlistDbRepository {
+	return &WishlistDbRepository{
+		db:     db,
+		config: config,
+	}
+}
+
+func (r *WishlistDbRepository) FindByUserId(userId uint) []*model.Wishlist {
+	var result []*model.Wishlist
+	err := r.db.
+		Where("user_id=?", userId).
+		Order("created_at").
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *WishlistDbRepository) FindById(id uint) *model.Wishlist {
+	var result model.Wishlist
+	find := r.db.First(&result, id)
+	if find.Error != nil {
+		if find.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		panic(find.Error)
+	}
+	return &result
+}
+
+func (r *WishlistDbRepository) FindForCard(card *model.Card) []*model.Wishlist {
+	var result []*model.Wishlist
+	err := r.db.
+		Where("card_id=?", card.ID).
+		Or("card_key_id=?", card.CardKeyID).
+		Find(&result).
+		Error
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (r *WishlistDbRepository) Save(wishlist *model.Wishlist) error {
+	return r.db.Create(wishlist).Error
+}
+
+func (r *WishlistDbRepository) Update(wishlist *model.Wishlist) error {
+	return r.db.Save(wishlist).Error
+}
+
+func (r *WishlistDbRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Wishlist{}, id).Error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "store.api/model"
+
+type WishlistRepository interface {
+	FindByUserId(userId uint) []*model.Wishlist
+	FindById(id uint) *model.Wishlist
+	// FindForCard returns the wishlists of the card's printing or its card key
+	FindForCard(card *model.Card) []*model.Wishlist
+	Save(*model.Wishlist) error
+	Update(*model.Wishlist) error
+	Delete(id uint) error
+}
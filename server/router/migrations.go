@@ -0,0 +1,74 @@
+package router
+
+import (
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"store.api/model"
+)
+
+// moneyColumns held floating point amounts before amounts were stored in cents
+var moneyColumns = map[string][]string{
+	"cards":                 {"price"},
+	"price_changes":         {"old_price", "new_price"},
+	"wishlists":             {"max_price"},
+	"promotions":            {"amount"},
+	"promotion_redemptions": {"discount"},
+	"orders":                {"subtotal", "discount", "shipping", "total"},
+	"order_lines":           {"unit_price", "line_total"},
+}
+
+// migrateMoney converts the floating point amounts of an existing database to cents. It has to run
+// before the auto migration, which would change the column types without scaling the amounts
+func migrateMoney(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		for table, columns := range moneyColumns {
+			if !migrator.HasTable(table) {
+				continue
+			}
+			types, err := migrator.ColumnTypes(table)
+			if err != nil {
+				return err
+			}
+			for _, column := range types {
+				if !slices.Contains(columns, column.Name()) || !isFloatColumn(column.DatabaseTypeName()) {
+					continue
+				}
+				err := tx.Exec(
+					"ALTER TABLE ? ALTER COLUMN ? TYPE bigint USING ROUND(? * 100)",
+					clause.Table{Name: table},
+					clause.Column{Name: column.Name()},
+					clause.Column{Name: column.Name()},
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// migrateOpeningStock records the stock of cards stocked before the stock ledger existed as an opening
+// balance, so every card's movements add up to its stocked amount. Cards with movements are left as is
+func migrateOpeningStock(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO stock_movements (created_at, updated_at, card_id, kind, delta, balance, reason)
+		SELECT now(), now(), id, ?, in_stock_amount, in_stock_amount, 'opening balance'
+		FROM cards
+		WHERE in_stock_amount > 0 AND deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.card_id = cards.id)`,
+		model.StockCorrection,
+	).Error
+}
+
+func isFloatColumn(databaseType string) bool {
+	switch strings.ToLower(databaseType) {
+	case "float4", "float8", "real", "double precision", "numeric":
+		return true
+	}
+	return false
+}
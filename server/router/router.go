@@ -3,6 +3,7 @@ package router
 import (
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -14,8 +15,10 @@ import (
 	"store.api/cache"
 	"store.api/config"
 	"store.api/controller"
+	"store.api/mail"
 	"store.api/model"
 	"store.api/repository"
+	"store.api/security"
 	"store.api/service"
 	"store.api/utility"
 
@@ -28,6 +31,12 @@ import (
 func CreateRouter(config *config.Configuration) *gin.Engine {
 	result := gin.Default()
 
+	// the login lockout is keyed on the client's IP, so forwarded IPs are only taken from known proxies
+	err := result.SetTrustedProxies(strings.Fields(config.Auth.TrustedProxies))
+	if err != nil {
+		panic(err)
+	}
+
 	result.Use(cors.New(cors.Config{
 		AllowMethods:     []string{"PUT", "PATCH", "POST", "GET", "DELETE"},
 		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Accept-Encoding"},
@@ -63,23 +72,35 @@ func CreateRouter(config *config.Configuration) *gin.Engine {
 	}
 
 	// repositories
-	userRepo := repository.NewUserDbRepository(dbClient, config)
+	cardQueryCache := cache.NewCardQueryValkeyCache(queryCacheClient)
 	cardRepo := repository.NewCardDbRepository(
 		dbClient,
 		config,
 		cache.NewCardValkeyCache(cacheClient),
-		cache.NewCardQueryValkeyCache(queryCacheClient),
+		cardQueryCache,
 	)
+	collectionCache := cache.NewCollectionValkeyCache(cacheClient)
 	collectionRepo := repository.NewCollectionDbRepository(
 		dbClient,
 		config,
-		cache.NewCollectionValkeyCache(cacheClient),
+		collectionCache,
+	)
+	collectionFolderRepo := repository.NewCollectionFolderDbRepository(
+		dbClient,
+		config,
+		collectionCache,
 	)
 	cartRepo := repository.NewCartDbRepository(
 		dbClient,
 		config,
 		cache.NewCartValkeyCache(cacheClient),
 	)
+	userRepo := repository.NewUserDbRepository(
+		dbClient,
+		config,
+		collectionRepo,
+		cartRepo,
+	)
 	langRepo := repository.NewLanguageDbRepository(
 		dbClient,
 		config,
@@ -94,6 +115,59 @@ func CreateRouter(config *config.Configuration) *gin.Engine {
 		dbClient,
 		config,
 	)
+	loginAuditRepo := repository.NewLoginAuditDbRepository(
+		dbClient,
+		config,
+	)
+	wishlistRepo := repository.NewWishlistDbRepository(
+		dbClient,
+		config,
+	)
+	promotionRepo := repository.NewPromotionDbRepository(
+		dbClient,
+		config,
+	)
+	orderRepo := repository.NewOrderDbRepository(
+		dbClient,
+		config,
+		cardRepo,
+		cartRepo,
+	)
+	exchangeRateRepo := repository.NewExchangeRateDbRepository(
+		dbClient,
+		config,
+	)
+	scheduledPriceRepo := repository.NewScheduledPriceDbRepository(
+		dbClient,
+		config,
+	)
+	listingRepo := repository.NewListingDbRepository(
+		dbClient,
+		config,
+	)
+	tradeRepo := repository.NewTradeDbRepository(
+		dbClient,
+		config,
+		collectionRepo,
+	)
+	payoutRepo := repository.NewPayoutDbRepository(
+		dbClient,
+		config,
+	)
+
+	mailer := mail.NewLogMailer()
+
+	// observers
+	cardRepo.Observe(service.NewWishlistNotifier(
+		wishlistRepo,
+		userRepo,
+		mailer,
+	))
+	cardRepo.Observe(service.NewLowStockNotifier(
+		expansionRepo,
+		userRepo,
+		mailer,
+	))
 
 	configRouter(
 		result,
@@ -101,10 +175,24 @@ func CreateRouter(config *config.Configuration) *gin.Engine {
 		userRepo,
 		cardRepo,
 		collectionRepo,
+		collectionFolderRepo,
 		cartRepo,
 		langRepo,
 		expansionRepo,
 		cardKeyRepo,
+		loginAuditRepo,
+		wishlistRepo,
+		promotionRepo,
+		orderRepo,
+		exchangeRateRepo,
+		scheduledPriceRepo,
+		listingRepo,
+		tradeRepo,
+		payoutRepo,
+		cache.NewLoginAttemptValkeyCache(cacheClient),
+		cache.NewOidcFlowValkeyCache(cacheClient),
+		cache.NewGuestCartValkeyCache(cacheClient),
+		mailer,
 	)
 
 	return result
@@ -116,17 +204,38 @@ func configRouter(
 	userRepo repository.UserRepository,
 	cardRepo repository.CardRepository,
 	collectionRepo repository.CollectionRepository,
+	collectionFolderRepo repository.CollectionFolderRepository,
 	cartRepo repository.CartRepository,
 	langRepo repository.LanguageRepository,
 	expansionRepo repository.ExpansionRepository,
 	cardKeyRepo repository.CardKeyRepository,
+	loginAuditRepo repository.LoginAuditRepository,
+	wishlistRepo repository.WishlistRepository,
+	promotionRepo repository.PromotionRepository,
+	orderRepo repository.OrderRepository,
+	exchangeRateRepo repository.ExchangeRateRepository,
+	scheduledPriceRepo repository.ScheduledPriceRepository,
+	listingRepo repository.ListingRepository,
+	tradeRepo repository.TradeRepository,
+	payoutRepo repository.PayoutRepository,
+	loginAttempts cache.LoginAttemptCache,
+	oidcFlows cache.OidcFlowCache,
+	guestCarts cache.GuestCartCache,
+	mailer mail.Mailer,
 ) {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
 	// services
+	currencyConverter := service.NewCurrencyConverter(
+		config,
+		exchangeRateRepo,
+	)
 	authService := service.NewAuthServiceImpl(
+		config,
 		userRepo,
 		cartRepo,
+		loginAuditRepo,
+		loginAttempts,
 		validate,
 	)
 	cardService := service.NewCardServiceImpl(
@@ -136,29 +245,114 @@ func configRouter(
 		langRepo,
 		expansionRepo,
 		cardKeyRepo,
+		listingRepo,
+		currencyConverter,
 		validate,
 	)
+	cartPricer := service.NewCartPricer(
+		config,
+		cardRepo,
+		promotionRepo,
+	)
 	collectionService := service.NewCollectionServiceImpl(
 		collectionRepo,
+		collectionFolderRepo,
 		userRepo,
 		cardRepo,
+		cartRepo,
+		cartPricer,
 		validate,
 	)
 	cartService := service.NewCartServiceImpl(
+		config,
 		cartRepo,
 		userRepo,
 		cardRepo,
+		orderRepo,
+		guestCarts,
+		cartPricer,
+		currencyConverter,
 		validate,
 	)
+	oidcService := service.NewOidcServiceImpl(
+		config,
+		security.NewOidcProvider(
+			config.Oidc.Issuer,
+			config.Oidc.ClientId,
+			config.Oidc.ClientSecret,
+			config.Oidc.RedirectUrl,
+			strings.Fields(config.Oidc.Scopes),
+		),
+		userRepo,
+		cartRepo,
+		oidcFlows,
+	)
 	userService := service.NewUserServiceImpl(
+		config,
 		userRepo,
+		collectionRepo,
+		cartRepo,
+		wishlistRepo,
+		orderRepo,
+		langRepo,
+		mailer,
+		validate,
+	)
+	wishlistService := service.NewWishlistServiceImpl(
+		wishlistRepo,
+		cardRepo,
+		cardKeyRepo,
+		validate,
+	)
+	orderService := service.NewOrderServiceImpl(
+		orderRepo,
+	)
+	promotionService := service.NewPromotionServiceImpl(
+		promotionRepo,
+		validate,
+	)
+	currencyService := service.NewCurrencyServiceImpl(
+		config,
+		exchangeRateRepo,
+		validate,
+	)
+	loadExchangeRates(config, currencyService)
+	priceScheduleService := service.NewPriceScheduleServiceImpl(
+		scheduledPriceRepo,
+		cardRepo,
+		validate,
+	)
+	runPriceSchedule(config, priceScheduleService)
+	listingService := service.NewListingServiceImpl(
+		config,
+		listingRepo,
+		cardRepo,
+		userRepo,
+		payoutRepo,
+		currencyConverter,
+		validate,
+	)
+	tradeService := service.NewTradeServiceImpl(
+		tradeRepo,
+		collectionRepo,
+		userRepo,
+		validate,
+	)
+	payoutService := service.NewPayoutServiceImpl(
+		config,
+		payoutRepo,
+		userRepo,
+		validate,
 	)
 
 	// middleware
+	guestCartCookie := auth.NewGuestCartCookie(config)
 	authentication := auth.NewJwtMiddleware(
 		config,
 		authService,
+		cartService,
 		userRepo,
+		guestCartCookie,
 	)
 
 	// controllers
@@ -171,12 +365,19 @@ func configRouter(
 
 	authController := controller.NewAuthController(
 		authService,
+		oidcService,
+		auth.NewOidcStateCookie(config),
 		authentication.Middle.LoginHandler,
+		authentication.IssueToken,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
 	)
 
 	userController := controller.NewUserController(
 		userService,
 		cartService,
+		wishlistService,
+		orderService,
 		authentication.Middle.MiddlewareFunc(),
 		utility.Extract,
 	)
@@ -187,24 +388,144 @@ func configRouter(
 		utility.Extract,
 	)
 
+	promotionController := controller.NewPromotionController(
+		promotionService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
+	currencyController := controller.NewCurrencyController(
+		currencyService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
+	priceScheduleController := controller.NewPriceScheduleController(
+		priceScheduleService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
+	listingController := controller.NewListingController(
+		listingService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
+	tradeController := controller.NewTradeController(
+		tradeService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
+	payoutController := controller.NewPayoutController(
+		payoutService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
+	guestCartController := controller.NewGuestCartController(
+		cartService,
+		guestCartCookie,
+	)
+
+	adminController := controller.NewAdminController(
+		authService,
+		userService,
+		authentication.Middle.MiddlewareFunc(),
+		utility.Extract,
+	)
+
 	api := router.Group("/api/v1")
 	controllers := []controller.Controller{
 		cardController,
 		authController,
 		userController,
 		collectionController,
+		guestCartController,
+		promotionController,
+		currencyController,
+		priceScheduleController,
+		listingController,
+		tradeController,
+		payoutController,
+		adminController,
 	}
 	for _, c := range controllers {
 		c.ConfigureApi(api)
 	}
 
 	authentication.AuthorizationCheckers = []auth.AuthorizationChecker{
+		authController,
 		cardController,
 		userController,
 		collectionController,
+		promotionController,
+		currencyController,
+		priceScheduleController,
+		listingController,
+		tradeController,
+		payoutController,
+		adminController,
 	}
 }
 
+// loadExchangeRates loads the rates from the configured source and keeps refreshing them in the background,
+// failures are only logged so the last loaded rates stay in use
+func loadExchangeRates(config *config.Configuration, currencyService service.CurrencyService) {
+	if config.Store.ExchangeRates.Source == "" {
+		return
+	}
+	load := func() {
+		// repositories panic on database errors, which would otherwise kill the server from the ticker
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("failed to load exchange rates: %v", r)
+			}
+		}()
+		if _, err := currencyService.Load(); err != nil {
+			log.Printf("failed to load exchange rates: %v", err)
+		}
+	}
+
+	load()
+	if config.Store.ExchangeRates.RefreshInterval == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(config.Store.ExchangeRates.RefreshInterval) * time.Second)
+		for range ticker.C {
+			load()
+		}
+	}()
+}
+
+// runPriceSchedule applies the scheduled price changes in the background, failed changes are logged and retried
+// on the next run
+func runPriceSchedule(config *config.Configuration, priceScheduleService service.PriceScheduleService) {
+	if config.Store.PriceScheduleInterval == 0 {
+		return
+	}
+	run := func(now time.Time) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("failed to run price schedule: %v", r)
+			}
+		}()
+		if err := priceScheduleService.Run(now); err != nil {
+			log.Printf("failed to run price schedule: %v", err)
+		}
+	}
+
+	go func() {
+		run(time.Now())
+		ticker := time.NewTicker(time.Duration(config.Store.PriceScheduleInterval) * time.Second)
+		for now := range ticker.C {
+			run(now)
+		}
+	}()
+}
+
 func dbConnect(config *config.Configuration) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(config.Db.ConnectionUri), &gorm.Config{
 		Logger: logger.New(
@@ -224,24 +545,51 @@ func dbConnect(config *config.Configuration) (*gorm.DB, error) {
 }
 
 func dbConfig(db *gorm.DB) error {
+	err := migrateMoney(db)
+	if err != nil {
+		return err
+	}
 
-	err := db.AutoMigrate(
+	err = db.AutoMigrate(
 		&model.User{},
+		&model.RecoveryCode{},
+		&model.FailedLogin{},
 		&model.CardKey{},
 		&model.Card{},
+		&model.PriceChange{},
 		&model.CardType{},
 		&model.Expansion{},
 		&model.Language{},
 		&model.Foiling{},
+		&model.CollectionFolder{},
 		&model.Collection{},
 		&model.CollectionSlot{},
 		&model.Cart{},
 		&model.CartSlot{},
+		&model.Wishlist{},
+		&model.Promotion{},
+		&model.Order{},
+		&model.OrderLine{},
+		&model.PromotionRedemption{},
+		&model.ExchangeRate{},
+		&model.ScheduledPriceChange{},
+		&model.StockMovement{},
+		&model.Listing{},
+		&model.Trade{},
+		&model.TradeLine{},
+		&model.SellerSale{},
+		&model.SellerCommission{},
+		&model.Payout{},
 	)
 	if err != nil {
 		return err
 	}
 
+	err = migrateOpeningStock(db)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
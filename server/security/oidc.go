@@ -0,0 +1,255 @@
+package security
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrOidcUnknownKey = errors.New("id token is signed with an unknown key")
+	ErrOidcNonce      = errors.New("id token nonce mismatch")
+)
+
+type OidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type OidcClaims struct {
+	jwt.RegisteredClaims
+
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+type oidcJwks struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+type oidcTokenResponse struct {
+	IdToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+// OidcProvider is a minimal OpenID Connect relying party for the authorization code flow with PKCE,
+// the discovery document and signing keys are fetched on first use
+type OidcProvider struct {
+	issuer       string
+	clientId     string
+	clientSecret string
+	redirectUrl  string
+	scopes       []string
+	client       *http.Client
+
+	lock      sync.Mutex
+	discovery *OidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+func NewOidcProvider(issuer string, clientId string, clientSecret string, redirectUrl string, scopes []string) *OidcProvider {
+	return &OidcProvider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		redirectUrl:  redirectUrl,
+		scopes:       scopes,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *OidcProvider) Issuer() string {
+	return p.issuer
+}
+
+func (p *OidcProvider) AuthCodeUrl(state string, nonce string, verifier string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientId)
+	params.Set("redirect_uri", p.redirectUrl)
+	params.Set("scope", strings.Join(p.scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", PkceChallenge(verifier))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange trades the authorization code for tokens and returns the verified id token claims
+func (p *OidcProvider) Exchange(code string, verifier string, nonce string) (*OidcClaims, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectUrl)
+	form.Set("client_id", p.clientId)
+	form.Set("code_verifier", verifier)
+	if len(p.clientSecret) > 0 {
+		form.Set("client_secret", p.clientSecret)
+	}
+
+	resp, err := p.client.PostForm(discovery.TokenEndpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token oidcTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint responded with %d: %s", resp.StatusCode, token.Error)
+	}
+	if len(token.IdToken) == 0 {
+		return nil, errors.New("token endpoint didn't return an id token")
+	}
+
+	return p.verify(token.IdToken, nonce)
+}
+
+func (p *OidcProvider) verify(rawIdToken string, nonce string) (*OidcClaims, error) {
+	var claims OidcClaims
+	_, err := jwt.ParseWithClaims(rawIdToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(kid)
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyIssuer(p.issuer, true) {
+		return nil, fmt.Errorf("id token issuer %s doesn't match %s", claims.Issuer, p.issuer)
+	}
+	if !claims.VerifyAudience(p.clientId, true) {
+		return nil, errors.New("id token wasn't issued for this client")
+	}
+	if claims.Nonce != nonce {
+		return nil, ErrOidcNonce
+	}
+	if len(claims.Subject) == 0 {
+		return nil, errors.New("id token has no subject")
+	}
+
+	return &claims, nil
+}
+
+func (p *OidcProvider) discover() (*OidcDiscovery, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var result OidcDiscovery
+	err := p.getJson(p.issuer+"/.well-known/openid-configuration", &result)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(result.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("discovery document issuer %s doesn't match %s", result.Issuer, p.issuer)
+	}
+
+	p.discovery = &result
+	return p.discovery, nil
+}
+
+// key returns the signing key with the given id, the key set is refetched once
+// when the key is unknown in case the provider rotated its keys
+func (p *OidcProvider) key(kid string) (*rsa.PublicKey, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var jwks oidcJwks
+	err = p.getJson(discovery.JwksUri, &jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		p.keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrOidcUnknownKey
+}
+
+func (p *OidcProvider) getJson(url string, target interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s responded with %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func PkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
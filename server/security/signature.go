@@ -0,0 +1,32 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// SignValue appends an HMAC of the value to it, so that values handed to clients, like cookies, can't be forged
+func SignValue(key string, value string) string {
+	return value + "." + signature(key, value)
+}
+
+// VerifySignedValue returns the value of a string created by SignValue, ok is false if the signature doesn't match
+func VerifySignedValue(key string, signed string) (value string, ok bool) {
+	separator := strings.LastIndex(signed, ".")
+	if separator < 0 {
+		return "", false
+	}
+	value = signed[:separator]
+	if !hmac.Equal([]byte(signed[separator+1:]), []byte(signature(key, value))) {
+		return "", false
+	}
+	return value, true
+}
+
+func signature(key string, value string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpPeriod       = 30
+	totpDigits       = 6
+	totpSecretLength = 20
+	totpSkew         = 1
+
+	recoveryCodeLength = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func GenerateTotpSecret() (string, error) {
+	secret := make([]byte, totpSecretLength)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+func TotpUri(issuer string, account string, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+func TotpCode(secret string, t time.Time) (string, error) {
+	return totpCodeAt(secret, uint64(t.Unix())/totpPeriod)
+}
+
+// MatchTotpCode accepts codes from the current period and one period on either side
+// to tolerate clock drift between the server and the authenticator app. Returns the time step
+// the code belongs to, so that callers can refuse codes that were already used
+func MatchTotpCode(secret string, code string, t time.Time) (step uint64, ok bool) {
+	counter := uint64(t.Unix()) / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		expected, err := totpCodeAt(secret, counter+uint64(i))
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter + uint64(i), true
+		}
+	}
+	return 0, false
+}
+
+func totpCodeAt(secret string, counter uint64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+func GenerateRecoveryCodes(amount int) ([]string, error) {
+	result := make([]string, amount)
+	for i := range result {
+		raw := make([]byte, recoveryCodeLength/2)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(raw)
+		result[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+	}
+	return result, nil
+}
+
+// HashRecoveryCode keys the hash with the auth key, so leaked hashes can't be brute-forced without it
+func HashRecoveryCode(key string, code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return signature(key, normalized)
+}
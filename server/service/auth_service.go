@@ -1,8 +1,29 @@
 package service
 
-import "store.api/dto"
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrTwoFactorRequired       = errors.New("two-factor code required")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrLoginLocked             = errors.New("too many failed login attempts, try again later")
+	ErrUserSuspended           = errors.New("user is suspended")
+	ErrPasswordResetRequired   = errors.New("password reset required, check your email for the reset token")
+	ErrInvalidResetToken       = errors.New("invalid or expired password reset token")
+)
 
 type AuthService interface {
 	Register(*dto.RegisterDetails) error
 	Login(*dto.LoginDetails) (*dto.PrivateUserInfo, error)
+	EnrollTwoFactor(userId uint) (*dto.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(userId uint, code *dto.TwoFactorCode) error
+	DisableTwoFactor(userId uint, code *dto.TwoFactorCode) error
+	UnlockUser(userId uint) error
+	FailedLogins(username string) []*dto.GetFailedLogin
+	ResetPassword(reset *dto.PasswordReset) error
 }
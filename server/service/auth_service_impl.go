@@ -3,28 +3,50 @@ package service
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"store.api/cache"
+	"store.api/config"
 	"store.api/dto"
 	"store.api/model"
 	"store.api/repository"
 	"store.api/security"
+	"store.api/utility"
 )
 
+const failedLoginsLimit = 100
+
 type AuthServiceImpl struct {
-	userRepo repository.UserRepository
-	cartRepo repository.CartRepository
-	validate *validator.Validate
+	config *config.Configuration
+
+	userRepo       repository.UserRepository
+	cartRepo       repository.CartRepository
+	loginAuditRepo repository.LoginAuditRepository
+	attempts       cache.LoginAttemptCache
+	validate       *validator.Validate
 }
 
-func NewAuthServiceImpl(userRepo repository.UserRepository, cartRepo repository.CartRepository, validate *validator.Validate) *AuthServiceImpl {
+func NewAuthServiceImpl(config *config.Configuration, userRepo repository.UserRepository, cartRepo repository.CartRepository, loginAuditRepo repository.LoginAuditRepository, attempts cache.LoginAttemptCache, validate *validator.Validate) *AuthServiceImpl {
 	return &AuthServiceImpl{
-		userRepo: userRepo,
-		cartRepo: cartRepo,
-		validate: validate,
+		config: config,
+
+		userRepo:       userRepo,
+		cartRepo:       cartRepo,
+		loginAuditRepo: loginAuditRepo,
+		attempts:       attempts,
+		validate:       validate,
 	}
 }
 
+func usernameAttemptKey(username string) string {
+	return "user:" + username
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
 func (s *AuthServiceImpl) Register(details *dto.RegisterDetails) error {
 	err := s.validate.Struct(details)
 	if err != nil {
@@ -71,15 +93,260 @@ func (s *AuthServiceImpl) Login(user *dto.LoginDetails) (*dto.PrivateUserInfo, e
 		return nil, err
 	}
 
+	// checked before the password so that locked accounts don't cost a bcrypt comparison
+	if s.isLocked(user) {
+		return nil, ErrLoginLocked
+	}
+
 	existing := s.userRepo.FindByUsername(user.Username)
 
 	if existing == nil {
-		return nil, errors.New("incorrect username or password")
+		return nil, s.recordFailure(user, "unknown username", errors.New("incorrect username or password"))
 	}
 
 	if !security.CheckPasswordHash(user.Password, existing.PasswordHash) {
-		return nil, errors.New("incorrect username or password")
+		return nil, s.recordFailure(user, "incorrect password", errors.New("incorrect username or password"))
+	}
+
+	// only reported after the password check so that the account state isn't leaked
+	if existing.IsSuspended(time.Now()) {
+		return nil, ErrUserSuspended
+	}
+	if existing.PasswordResetRequired {
+		return nil, ErrPasswordResetRequired
 	}
 
+	if existing.TwoFactorEnabled {
+		if len(user.Code) == 0 {
+			return nil, ErrTwoFactorRequired
+		}
+		ok, err := checkTwoFactorCode(s.userRepo, s.config.AuthKey, existing, user.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, s.recordFailure(user, "invalid two-factor code", ErrInvalidTwoFactorCode)
+		}
+	}
+
+	s.attempts.Reset(usernameAttemptKey(user.Username))
+
 	return dto.NewPrivateUserInfo(existing), nil
 }
+
+func (s *AuthServiceImpl) UnlockUser(userId uint) error {
+	user := s.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	s.attempts.Reset(usernameAttemptKey(user.Username))
+	return nil
+}
+
+func (s *AuthServiceImpl) FailedLogins(username string) []*dto.GetFailedLogin {
+	return utility.MapSlice(
+		s.loginAuditRepo.FindRecent(username, failedLoginsLimit),
+		func(f *model.FailedLogin) *dto.GetFailedLogin { return dto.NewGetFailedLogin(f) },
+	)
+}
+
+func (s *AuthServiceImpl) ResetPassword(reset *dto.PasswordReset) error {
+	err := s.validate.Struct(reset)
+	if err != nil {
+		return err
+	}
+
+	user := s.userRepo.FindByPasswordResetToken(security.HashToken(reset.Token))
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+	if user.PasswordResetExpiresAt == nil || time.Now().After(*user.PasswordResetExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user.PasswordHash, err = security.HashPassword(reset.Password)
+	if err != nil {
+		return err
+	}
+	user.PasswordResetRequired = false
+	user.PasswordResetTokenHash = ""
+	user.PasswordResetExpiresAt = nil
+	err = s.userRepo.Update(user)
+	if err != nil {
+		return err
+	}
+
+	s.attempts.Reset(usernameAttemptKey(user.Username))
+	return nil
+}
+
+func (s *AuthServiceImpl) isLocked(details *dto.LoginDetails) bool {
+	if s.attempts.LockedFor(usernameAttemptKey(details.Username)) > 0 {
+		return true
+	}
+	return len(details.ClientIp) > 0 && s.attempts.LockedFor(ipAttemptKey(details.ClientIp)) > 0
+}
+
+// recordFailure counts the failed attempt for both the username and the client ip, locks them out
+// once they go over the configured limits and stores an audit record; loginErr is returned as is
+func (s *AuthServiceImpl) recordFailure(details *dto.LoginDetails, reason string, loginErr error) error {
+	lockout := s.config.Auth.Lockout
+	window := time.Duration(lockout.FailureWindow) * time.Second
+
+	key := usernameAttemptKey(details.Username)
+	s.lockIfExceeded(key, s.attempts.Fail(key, window), lockout.MaxUsernameFailures)
+
+	if len(details.ClientIp) > 0 {
+		key = ipAttemptKey(details.ClientIp)
+		s.lockIfExceeded(key, s.attempts.Fail(key, window), lockout.MaxIpFailures)
+	}
+
+	err := s.loginAuditRepo.Save(&model.FailedLogin{
+		Username: details.Username,
+		ClientIp: details.ClientIp,
+		Reason:   reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return loginErr
+}
+
+// lockIfExceeded doubles the lockout duration for every failure past the limit, a limit of 0 disables the lockout
+func (s *AuthServiceImpl) lockIfExceeded(key string, failures int64, limit uint) {
+	if limit == 0 || failures < int64(limit) {
+		return
+	}
+
+	lockout := s.config.Auth.Lockout
+	max := time.Duration(lockout.MaxLockout) * time.Second
+	duration := time.Duration(lockout.BaseLockout) * time.Second
+
+	for i := int64(limit); i < failures && duration < max; i++ {
+		duration *= 2
+	}
+	if duration > max {
+		duration = max
+	}
+
+	s.attempts.Lock(key, duration)
+}
+
+func (s *AuthServiceImpl) EnrollTwoFactor(userId uint) (*dto.TwoFactorEnrollment, error) {
+	user := s.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := security.GenerateTotpSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := security.GenerateRecoveryCodes(int(s.config.Auth.RecoveryCodeCount))
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]*model.RecoveryCode, len(codes))
+	for i, code := range codes {
+		recoveryCodes[i] = &model.RecoveryCode{
+			CodeHash: security.HashRecoveryCode(s.config.AuthKey, code),
+		}
+	}
+
+	// the secret is stored right away, but isn't used for logging in until the user confirms it
+	user.TwoFactorSecret = secret
+	err = s.userRepo.Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.ReplaceRecoveryCodes(user.ID, recoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrollment{
+		Secret:        secret,
+		Uri:           security.TotpUri(s.config.JwtRealm, user.Username, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *AuthServiceImpl) ConfirmTwoFactor(userId uint, code *dto.TwoFactorCode) error {
+	if err := s.validate.Struct(code); err != nil {
+		return err
+	}
+
+	user := s.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if user.TwoFactorEnabled {
+		return ErrTwoFactorAlreadyEnabled
+	}
+
+	if len(user.TwoFactorSecret) == 0 {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := security.MatchTotpCode(user.TwoFactorSecret, code.Code, time.Now())
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	// the confirming code can't be used to log in right after
+	user.TwoFactorEnabled = true
+	user.TotpLastStep = step
+	return s.userRepo.Update(user)
+}
+
+func (s *AuthServiceImpl) DisableTwoFactor(userId uint, code *dto.TwoFactorCode) error {
+	if err := s.validate.Struct(code); err != nil {
+		return err
+	}
+
+	user := s.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if !user.TwoFactorEnabled {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	ok, err := checkTwoFactorCode(s.userRepo, s.config.AuthKey, user, code.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	err = s.userRepo.Update(user)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.ReplaceRecoveryCodes(user.ID, []*model.RecoveryCode{})
+}
+
+// checkTwoFactorCode accepts either a current TOTP code or an unused recovery code,
+// both are spent on use
+func checkTwoFactorCode(userRepo repository.UserRepository, authKey string, user *model.User, code string) (bool, error) {
+	if step, ok := security.MatchTotpCode(user.TwoFactorSecret, code, time.Now()); ok {
+		return userRepo.UseTotpStep(user.ID, step)
+	}
+
+	return userRepo.UseRecoveryCode(user.ID, security.HashRecoveryCode(authKey, code))
+}
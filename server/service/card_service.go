@@ -9,7 +9,11 @@ import (
 )
 
 var (
-	ErrCardNotFound = errors.New("card not found")
+	ErrCardNotFound       = errors.New("card not found")
+	ErrBulkDuplicateCard  = errors.New("card is listed more than once")
+	ErrExpansionNotFound  = errors.New("expansion not found")
+	ErrScaleWithoutFilter = errors.New("price scale needs a filter, or all to change the prices of every card")
+	ErrScaleCurrency      = errors.New("price scale filters are in the store's currency")
 )
 
 type CardQueryResult struct {
@@ -20,11 +24,28 @@ type CardQueryResult struct {
 
 type CardService interface {
 	Add(*dto.PostCard, uint) (*dto.GetCard, error)
-	GetById(id uint) (*dto.GetCard, error)
-	Query(query *query.CardQuery) *CardQueryResult
-	Update(*dto.PostCard, uint) (*dto.GetCard, error)
+	// GetById converts the price to the currency, an empty currency keeps the store's currency
+	GetById(id uint, currency string) (*dto.GetCard, error)
+	// Query interprets the price filters in the query's currency and converts the prices to it
+	Query(query *query.CardQuery) (*CardQueryResult, error)
+	// Update replaces the card's data, a changed stock is recorded in its stock ledger as a correction under the actor
+	Update(c *dto.PostCard, cardId uint, actorId uint) (*dto.GetCard, error)
 	UpdatePrice(uint, *dto.PriceUpdate) (*dto.GetCard, error)
-	UpdateInStockAmount(uint, *dto.StockedAmountUpdate) (*dto.GetCard, error)
+	// UpdateInStockAmount sets the card's stock, recording the change in its stock ledger under the actor
+	UpdateInStockAmount(id uint, update *dto.StockedAmountUpdate, actorId uint) (*dto.GetCard, error)
+	// UpdatePrices sets the prices of all listed cards or none of them
+	UpdatePrices(*dto.BulkPriceUpdate) ([]*dto.GetCard, error)
+	// ScalePrices changes the prices of the cards matching the filter, the filter's paging is ignored.
+	// An empty filter has to be confirmed with the scale's All
+	ScalePrices(filter *query.CardQuery, scale *dto.BulkPriceScale) ([]*dto.GetCard, error)
+	// UpdateInStockAmounts adjusts the stock of all listed cards or none of them, recording the changes under the actor
+	UpdateInStockAmounts(update *dto.BulkStockUpdate, actorId uint) ([]*dto.GetCard, error)
+	// StockMovements returns the card's stock ledger, newest first
+	StockMovements(id uint) ([]*dto.GetStockMovement, error)
+	// LowStock returns the cards whose stock fell to their low stock threshold, the emptiest first
+	LowStock() []*dto.GetLowStockCard
+	// UpdateExpansionThreshold sets the low stock threshold of the expansion's cards without their own
+	UpdateExpansionThreshold(id string, update *dto.LowStockThresholdUpdate) (*model.Expansion, error)
 	Languages() []*model.Language
 	Expansions() []*model.Expansion
 	Keys() []*model.CardKey
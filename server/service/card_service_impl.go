@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/go-playground/validator/v10"
@@ -20,10 +21,12 @@ type CardServiceImpl struct {
 	langRepo      repository.LanguageRepository
 	expansionRepo repository.ExpansionRepository
 	cardKeyRepo   repository.CardKeyRepository
+	listingRepo   repository.ListingRepository
+	converter     *CurrencyConverter
 	validate      *validator.Validate
 }
 
-func NewCardServiceImpl(config *config.Configuration, cardRepo repository.CardRepository, userRepo repository.UserRepository, langRepo repository.LanguageRepository, expansionRepo repository.ExpansionRepository, cardKeyRepo repository.CardKeyRepository, validate *validator.Validate) *CardServiceImpl {
+func NewCardServiceImpl(config *config.Configuration, cardRepo repository.CardRepository, userRepo repository.UserRepository, langRepo repository.LanguageRepository, expansionRepo repository.ExpansionRepository, cardKeyRepo repository.CardKeyRepository, listingRepo repository.ListingRepository, converter *CurrencyConverter, validate *validator.Validate) *CardServiceImpl {
 	return &CardServiceImpl{
 		config: config,
 
@@ -32,6 +35,8 @@ func NewCardServiceImpl(config *config.Configuration, cardRepo repository.CardRe
 		langRepo:      langRepo,
 		expansionRepo: expansionRepo,
 		cardKeyRepo:   cardKeyRepo,
+		listingRepo:   listingRepo,
+		converter:     converter,
 		validate:      validate,
 	}
 }
@@ -55,35 +60,67 @@ func (s *CardServiceImpl) Add(c *dto.PostCard, posterId uint) (*dto.GetCard, err
 		return nil, err
 	}
 
-	return dto.NewGetCard(card), nil
+	return s.converter.Store().Card(card), nil
 }
 
-func (s *CardServiceImpl) GetById(id uint) (*dto.GetCard, error) {
+func (s *CardServiceImpl) GetById(id uint, currency string) (*dto.GetCard, error) {
+	conversion, err := s.converter.To(currency)
+	if err != nil {
+		return nil, err
+	}
+
 	card := s.cardRepo.FindById(id)
 	if card == nil {
 		return nil, ErrCardNotFound
 	}
-	result := dto.NewGetCard(card)
+	result := conversion.Card(card)
+	if summary, ok := s.listingRepo.Summaries([]uint{card.ID})[card.ID]; ok {
+		result.Offers = conversion.Offers(summary)
+	}
 
 	return result, nil
 }
 
-func (s *CardServiceImpl) Query(query *query.CardQuery) *CardQueryResult {
+func (s *CardServiceImpl) Query(query *query.CardQuery) (*CardQueryResult, error) {
+	conversion, err := s.converter.To(query.Currency)
+	if err != nil {
+		return nil, err
+	}
+	// the repository filters by the stored prices, the results are cached by the converted filters
+	// so that they don't depend on the exchange rate
+	if query.Currency != "" {
+		if query.MinPrice >= 0 {
+			query.MinPrice = conversion.StorePrice(query.MinPrice)
+		}
+		if query.MaxPrice >= 0 {
+			query.MaxPrice = conversion.StorePrice(query.MaxPrice)
+		}
+		query.Currency = ""
+		query.Raw = query.Encode()
+	}
+
 	// TODO move to a more text-search specific service
 	cards, count := s.cardRepo.Query(query)
 
-	mapped := utility.MapSlice(cards, func(c *model.Card) *dto.GetCard {
-		return dto.NewGetCard(c)
-	})
+	mapped := utility.MapSlice(cards, conversion.Card)
+	// listings change independently of the cached query results
+	summaries := s.listingRepo.Summaries(utility.MapSlice(cards, func(card *model.Card) uint {
+		return card.ID
+	}))
+	for _, card := range mapped {
+		if summary, ok := summaries[card.ID]; ok {
+			card.Offers = conversion.Offers(summary)
+		}
+	}
 
 	return &CardQueryResult{
 		Cards:      mapped,
 		TotalCount: count,
 		PerPage:    s.config.Db.Cards.PageSize,
-	}
+	}, nil
 }
 
-func (s *CardServiceImpl) Update(c *dto.PostCard, cardId uint) (*dto.GetCard, error) {
+func (s *CardServiceImpl) Update(c *dto.PostCard, cardId uint, actorId uint) (*dto.GetCard, error) {
 	err := s.validate.Struct(c)
 	if err != nil {
 		return nil, err
@@ -97,17 +134,17 @@ func (s *CardServiceImpl) Update(c *dto.PostCard, cardId uint) (*dto.GetCard, er
 
 	newCard.ID = existing.ID
 	newCard.PosterID = existing.PosterID
-	err = s.cardRepo.Update(newCard)
+	err = s.cardRepo.Update(newCard, stockMovement("", "card updated", actorId))
 	if err != nil {
 		return nil, err
 	}
 
-	return dto.NewGetCard(newCard), nil
+	return s.converter.Store().Card(newCard), nil
 }
 
 func (s *CardServiceImpl) UpdatePrice(id uint, update *dto.PriceUpdate) (*dto.GetCard, error) {
 	if update.NewPrice <= 0 {
-		return nil, fmt.Errorf("card price can't be %f", update.NewPrice)
+		return nil, fmt.Errorf("card price can't be %s", update.NewPrice)
 	}
 	result, err := s.cardRepo.UpdatePrice(id, update.NewPrice)
 	if err != nil {
@@ -116,18 +153,155 @@ func (s *CardServiceImpl) UpdatePrice(id uint, update *dto.PriceUpdate) (*dto.Ge
 	if result == nil {
 		return nil, ErrCardNotFound
 	}
-	return dto.NewGetCard(result), nil
+	return s.converter.Store().Card(result), nil
 }
 
-func (s *CardServiceImpl) UpdateInStockAmount(id uint, update *dto.StockedAmountUpdate) (*dto.GetCard, error) {
-	result, err := s.cardRepo.UpdateInStockAmount(id, update.NewAmount)
+func (s *CardServiceImpl) UpdateInStockAmount(id uint, update *dto.StockedAmountUpdate, actorId uint) (*dto.GetCard, error) {
+	err := s.validate.Struct(update)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.cardRepo.UpdateInStockAmount(id, update.NewAmount, stockMovement(update.Kind, update.Reason, actorId))
 	if err != nil {
 		return nil, err
 	}
 	if result == nil {
 		return nil, ErrCardNotFound
 	}
-	return dto.NewGetCard(result), nil
+	return s.converter.Store().Card(result), nil
+}
+
+func (s *CardServiceImpl) UpdatePrices(update *dto.BulkPriceUpdate) ([]*dto.GetCard, error) {
+	err := s.validate.Struct(update)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[uint]model.Money, len(update.Prices))
+	for _, entry := range update.Prices {
+		if _, ok := prices[entry.CardId]; ok {
+			return nil, fmt.Errorf("%w: %d", ErrBulkDuplicateCard, entry.CardId)
+		}
+		prices[entry.CardId] = entry.Price
+	}
+
+	result, err := s.cardRepo.UpdatePrices(prices)
+	if err != nil {
+		return nil, bulkUpdateError(err)
+	}
+	return utility.MapSlice(result, s.converter.Store().Card), nil
+}
+
+func (s *CardServiceImpl) ScalePrices(filter *query.CardQuery, scale *dto.BulkPriceScale) ([]*dto.GetCard, error) {
+	err := s.validate.Struct(scale)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Currency != "" {
+		return nil, ErrScaleCurrency
+	}
+	if !scale.All && !filter.Filters() {
+		return nil, ErrScaleWithoutFilter
+	}
+
+	result, err := s.cardRepo.ScalePrices(filter, 1+scale.Percentage/100)
+	if err != nil {
+		return nil, err
+	}
+	return utility.MapSlice(result, s.converter.Store().Card), nil
+}
+
+func (s *CardServiceImpl) UpdateInStockAmounts(update *dto.BulkStockUpdate, actorId uint) ([]*dto.GetCard, error) {
+	err := s.validate.Struct(update)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := make([]repository.StockAdjustment, 0, len(update.Stock))
+	listed := make(map[uint]bool, len(update.Stock))
+	for _, entry := range update.Stock {
+		if listed[entry.CardId] {
+			return nil, fmt.Errorf("%w: %d", ErrBulkDuplicateCard, entry.CardId)
+		}
+		listed[entry.CardId] = true
+		if !entry.Relative && entry.Amount < 0 {
+			return nil, fmt.Errorf("card %d can't have %d in stock", entry.CardId, entry.Amount)
+		}
+		adjustments = append(adjustments, repository.StockAdjustment{
+			CardID:   entry.CardId,
+			Amount:   entry.Amount,
+			Relative: entry.Relative,
+		})
+	}
+
+	result, err := s.cardRepo.AdjustStock(adjustments, stockMovement(update.Kind, update.Reason, actorId))
+	if err != nil {
+		return nil, bulkUpdateError(err)
+	}
+	return utility.MapSlice(result, s.converter.Store().Card), nil
+}
+
+func (s *CardServiceImpl) StockMovements(id uint) ([]*dto.GetStockMovement, error) {
+	if s.cardRepo.FindById(id) == nil {
+		return nil, ErrCardNotFound
+	}
+	return utility.MapSlice(
+		s.cardRepo.StockMovements(id),
+		dto.NewGetStockMovement,
+	), nil
+}
+
+func (s *CardServiceImpl) LowStock() []*dto.GetLowStockCard {
+	cards := s.cardRepo.FindLowStock()
+	result := make([]*dto.GetLowStockCard, 0, len(cards))
+	for _, card := range cards {
+		entry := &dto.GetLowStockCard{
+			Card: s.converter.Store().Card(card),
+		}
+		if threshold := card.StockThreshold(&card.Expansion); threshold != nil {
+			entry.Threshold = *threshold
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func (s *CardServiceImpl) UpdateExpansionThreshold(id string, update *dto.LowStockThresholdUpdate) (*model.Expansion, error) {
+	result, err := s.expansionRepo.UpdateLowStockThreshold(id, update.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrExpansionNotFound
+	}
+	return result, nil
+}
+
+// stockMovement describes a manual stock change, corrections are the default kind
+func stockMovement(kind string, reason string, actorId uint) model.StockMovement {
+	result := model.StockMovement{
+		Kind:    model.StockMovementKind(kind),
+		ActorID: &actorId,
+		Reason:  reason,
+	}
+	if kind == "" {
+		result.Kind = model.StockCorrection
+	}
+	return result
+}
+
+// bulkUpdateError translates the repository's errors, keeping which cards failed
+func bulkUpdateError(err error) error {
+	var notFound *repository.CardsNotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %v", ErrCardNotFound, notFound.Ids)
+	}
+	var shortage *repository.StockShortageError
+	if errors.As(err, &shortage) {
+		return fmt.Errorf("%w: card %d has %d", ErrInsufficientStock, shortage.CardId, shortage.InStock)
+	}
+	return err
 }
 
 func (s *CardServiceImpl) Languages() []*model.Language {
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+)
+
+// CartPricer prices the cart's lines with the current card prices, applies the running promotions
+// and estimates the shipping
+type CartPricer struct {
+	config        *config.Configuration
+	cardRepo      repository.CardRepository
+	promotionRepo repository.PromotionRepository
+}
+
+func NewCartPricer(config *config.Configuration, cardRepo repository.CardRepository, promotionRepo repository.PromotionRepository) *CartPricer {
+	return &CartPricer{
+		config:        config,
+		cardRepo:      cardRepo,
+		promotionRepo: promotionRepo,
+	}
+}
+
+type pricedLine struct {
+	card *model.Card
+	line *dto.GetCartSlot
+}
+
+// appliedPromotion is a promotion discounting a cart, it is redeemed when the cart is checked out
+type appliedPromotion struct {
+	promotion *model.Promotion
+	discount  model.Money
+}
+
+func (p *CartPricer) Price(cart *model.Cart) *dto.GetCart {
+	result, _ := p.price(cart, time.Now())
+	return result
+}
+
+func (p *CartPricer) price(cart *model.Cart, now time.Time) (*dto.GetCart, []*appliedPromotion) {
+	result := dto.NewGetCart(cart)
+	result.Currency = p.config.Store.Currency
+
+	var cards uint
+	lines := make([]*pricedLine, 0, len(result.Cards))
+	for _, line := range result.Cards {
+		card := p.cardRepo.FindById(line.CardId)
+		// deleted cards can't be fetched anymore
+		if card == nil {
+			line.Archived = true
+			continue
+		}
+
+		line.Name = card.Name
+		line.Price = card.Price
+		line.InStockAmount = card.InStockAmount
+		line.ExceedsStock = line.Amount > card.InStockAmount
+		line.LineTotal = card.Price.Times(line.Amount)
+
+		result.Subtotal += line.LineTotal
+		cards += line.Amount
+		lines = append(lines, &pricedLine{card: card, line: line})
+	}
+
+	applied := p.applyPromotions(cart, lines, result.Subtotal, now)
+	for _, a := range applied {
+		code := ""
+		if a.promotion.Code != nil {
+			code = *a.promotion.Code
+		}
+		result.Promotions = append(result.Promotions, &dto.AppliedPromotion{
+			Code:        code,
+			Description: a.promotion.Description,
+			Discount:    a.discount,
+		})
+		result.Discount += a.discount
+	}
+
+	result.Shipping = p.shipping(result.Subtotal-result.Discount, cards)
+	result.Total = result.Subtotal - result.Discount + result.Shipping
+	return result, applied
+}
+
+// applyPromotions stacks all running automatic promotions and the cart's coupon,
+// together they never discount more than the subtotal
+func (p *CartPricer) applyPromotions(cart *model.Cart, lines []*pricedLine, subtotal model.Money, now time.Time) []*appliedPromotion {
+	promotions := []*model.Promotion{}
+	for _, promotion := range p.promotionRepo.FindAutomatic() {
+		if p.checkAvailable(promotion, cart.UserID, now) == nil {
+			promotions = append(promotions, promotion)
+		}
+	}
+	if cart.PromotionCode != nil {
+		coupon, err := p.coupon(*cart.PromotionCode, cart.UserID, now)
+		if err == nil {
+			promotions = append(promotions, coupon)
+		}
+	}
+
+	result := []*appliedPromotion{}
+	remaining := subtotal
+	for _, promotion := range promotions {
+		discount := min(promotionDiscount(promotion, lines), remaining)
+		if discount <= 0 {
+			continue
+		}
+		remaining -= discount
+		result = append(result, &appliedPromotion{
+			promotion: promotion,
+			discount:  discount,
+		})
+	}
+	return result
+}
+
+// coupon finds the promotion with the code and checks that the user can redeem it
+func (p *CartPricer) coupon(code string, userId uint, now time.Time) (*model.Promotion, error) {
+	promotion := p.promotionRepo.FindByCode(code)
+	if promotion == nil || promotion.Code == nil {
+		return nil, ErrPromotionNotFound
+	}
+	err := p.checkAvailable(promotion, userId, now)
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// checkAvailable checks the promotion's validity window and redemption limits,
+// guests (user id 0) aren't held to the per user limit until they log in
+func (p *CartPricer) checkAvailable(promotion *model.Promotion, userId uint, now time.Time) error {
+	if !promotion.Running(now) {
+		return ErrPromotionNotRunning
+	}
+	if promotion.MaxRedemptions > 0 && p.promotionRepo.CountRedemptions(promotion.ID) >= int64(promotion.MaxRedemptions) {
+		return ErrPromotionLimitReached
+	}
+	if userId != 0 && promotion.MaxRedemptionsPerUser > 0 && p.promotionRepo.CountUserRedemptions(promotion.ID, userId) >= int64(promotion.MaxRedemptionsPerUser) {
+		return ErrPromotionLimitReached
+	}
+	return nil
+}
+
+// promotionDiscount calculates the discount of the promotion on the lines it covers
+func promotionDiscount(promotion *model.Promotion, lines []*pricedLine) model.Money {
+	var covered model.Money
+	runs := []priceRun{}
+	for _, l := range lines {
+		if !promotion.Covers(l.card) {
+			continue
+		}
+		covered += l.line.LineTotal
+		if promotion.Kind == model.PromotionBuyXGetY {
+			runs = append(runs, priceRun{price: l.card.Price, amount: l.line.Amount})
+		}
+	}
+
+	switch promotion.Kind {
+	case model.PromotionPercentage:
+		return covered.Scale(float64(promotion.Percentage) / 100)
+	case model.PromotionFixed:
+		return min(promotion.Amount, covered)
+	case model.PromotionBuyXGetY:
+		if promotion.BuyAmount == 0 || promotion.FreeAmount == 0 {
+			return 0
+		}
+		return buyXGetYDiscount(promotion.BuyAmount, promotion.FreeAmount, runs)
+	}
+	return 0
+}
+
+// priceRun is an amount of covered cards of the same price
+type priceRun struct {
+	price  model.Money
+	amount uint
+}
+
+// buyXGetYDiscount groups the cards from the most expensive, the cheapest cards of every full group are free.
+// The cards are counted per run instead of one by one, so large amounts cost nothing extra
+func buyXGetYDiscount(buy uint, free uint, runs []priceRun) model.Money {
+	slices.SortFunc(runs, func(a, b priceRun) int {
+		return cmp.Compare(b.price, a.price)
+	})
+
+	group := uint64(buy) + uint64(free)
+	var total uint64
+	for _, run := range runs {
+		total += uint64(run.amount)
+	}
+	grouped := total / group * group
+
+	// freeBefore counts the free cards among the first n cards
+	freeBefore := func(n uint64) uint64 {
+		n = min(n, grouped)
+		rest := n % group
+		return n/group*uint64(free) + rest - min(rest, uint64(buy))
+	}
+
+	var discount model.Money
+	var start uint64
+	for _, run := range runs {
+		end := start + uint64(run.amount)
+		discount += run.price.Times(uint(freeBefore(end) - freeBefore(start)))
+		start = end
+	}
+	return discount
+}
+
+func (p *CartPricer) shipping(cost model.Money, cards uint) model.Money {
+	rates := p.config.Store.Shipping
+	if cards == 0 {
+		return 0
+	}
+	if rates.FreeFrom > 0 && cost >= rates.FreeFrom {
+		return 0
+	}
+	return rates.BaseCost + rates.PerCardCost.Times(cards)
+}
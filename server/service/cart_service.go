@@ -7,10 +7,31 @@ import (
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrPromotionNotFound     = errors.New("promotion not found")
+	ErrPromotionNotRunning   = errors.New("promotion isn't running")
+	ErrPromotionLimitReached = errors.New("promotion has reached its redemption limit")
+	ErrCartEmpty             = errors.New("cart is empty")
+	ErrCartHasArchivedCards  = errors.New("cart contains cards that are no longer sold")
+	ErrInsufficientStock     = errors.New("not enough cards in stock")
 )
 
 type CartService interface {
 	Get(userId uint) (*dto.GetCart, error)
 	EditSlot(userId uint, cartSlot *dto.PostCartSlot) (*dto.GetCart, error)
+	// EditSlots applies all slot changes or none of them
+	EditSlots(userId uint, cartSlots *dto.PostCartSlots) (*dto.GetCart, error)
+	// GetGuest returns an empty cart for guests that haven't added anything yet
+	GetGuest(guestId string) *dto.GetCart
+	EditGuestSlot(guestId string, cartSlot *dto.PostCartSlot) (*dto.GetCart, error)
+	EditGuestSlots(guestId string, cartSlots *dto.PostCartSlots) (*dto.GetCart, error)
+	// ApplyPromotion sets the coupon of the user's cart, it has to be redeemable by the user
+	ApplyPromotion(userId uint, code *dto.PromotionCode) (*dto.GetCart, error)
+	RemovePromotion(userId uint) (*dto.GetCart, error)
+	// Checkout orders the cart at its current prices and promotions and empties it
+	Checkout(userId uint) (*dto.GetOrder, error)
+	// Convert converts the priced cart from the store's currency, an empty currency keeps it as is
+	Convert(cart *dto.GetCart, currency string) (*dto.GetCart, error)
+	// MergeGuest adds the guest's cart to the user's cart and forgets the guest cart
+	MergeGuest(userId uint, guestId string) error
 }
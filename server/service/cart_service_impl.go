@@ -1,24 +1,41 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/go-playground/validator/v10"
+	"store.api/cache"
+	"store.api/config"
 	"store.api/dto"
+	"store.api/model"
 	"store.api/repository"
 )
 
 type CartServiceImpl struct {
-	userRepo repository.UserRepository
-	cartRepo repository.CartRepository
-	cardRepo repository.CardRepository
-	validate *validator.Validate
+	config     *config.Configuration
+	userRepo   repository.UserRepository
+	cartRepo   repository.CartRepository
+	cardRepo   repository.CardRepository
+	orderRepo  repository.OrderRepository
+	guestCarts cache.GuestCartCache
+	pricer     *CartPricer
+	converter  *CurrencyConverter
+	validate   *validator.Validate
 }
 
-func NewCartServiceImpl(cartRepo repository.CartRepository, userRepo repository.UserRepository, cardRepo repository.CardRepository, validate *validator.Validate) *CartServiceImpl {
+func NewCartServiceImpl(config *config.Configuration, cartRepo repository.CartRepository, userRepo repository.UserRepository, cardRepo repository.CardRepository, orderRepo repository.OrderRepository, guestCarts cache.GuestCartCache, pricer *CartPricer, converter *CurrencyConverter, validate *validator.Validate) *CartServiceImpl {
 	return &CartServiceImpl{
-		cartRepo: cartRepo,
-		userRepo: userRepo,
-		cardRepo: cardRepo,
-		validate: validate,
+		config:     config,
+		cartRepo:   cartRepo,
+		userRepo:   userRepo,
+		cardRepo:   cardRepo,
+		orderRepo:  orderRepo,
+		guestCarts: guestCarts,
+		pricer:     pricer,
+		converter:  converter,
+		validate:   validate,
 	}
 }
 
@@ -29,7 +46,7 @@ func (ser *CartServiceImpl) Get(userId uint) (*dto.GetCart, error) {
 	}
 
 	cart := ser.cartRepo.FindSingleByUserId(userId)
-	return dto.NewGetCart(cart), nil
+	return ser.pricer.Price(cart), nil
 }
 
 func (ser *CartServiceImpl) EditSlot(userId uint, newCartSlot *dto.PostCartSlot) (*dto.GetCart, error) {
@@ -83,5 +100,322 @@ func (ser *CartServiceImpl) EditSlot(userId uint, newCartSlot *dto.PostCartSlot)
 	}
 
 	updated := ser.cartRepo.FindSingleByUserId(userId)
-	return dto.NewGetCart(updated), nil
+	return ser.pricer.Price(updated), nil
+}
+
+func (ser *CartServiceImpl) EditSlots(userId uint, newCartSlots *dto.PostCartSlots) (*dto.GetCart, error) {
+	err := ser.validate.Struct(newCartSlots)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	cardIds := make([]uint, 0, len(newCartSlots.Slots))
+	amounts := make([]int, 0, len(newCartSlots.Slots))
+	for _, slot := range newCartSlots.Slots {
+		cardIds = append(cardIds, slot.CardId)
+		amounts = append(amounts, slot.Amount)
+	}
+	order, changes, err := sumSlotChanges(ser.cardRepo, cardIds, amounts)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := ser.cartRepo.FindSingleByUserId(userId)
+
+	existing := map[uint]*model.CartSlot{}
+	for i := range cart.Cards {
+		existing[cart.Cards[i].CardID] = &cart.Cards[i]
+	}
+	saved := []*model.CartSlot{}
+	deleted := []*model.CartSlot{}
+	for _, cardId := range order {
+		change := changes[cardId]
+		if change == 0 {
+			continue
+		}
+
+		slot, ok := existing[cardId]
+		if !ok {
+			newSlot, err := (&dto.PostCartSlot{CardId: cardId, Amount: change}).ToCartSlot()
+			if err != nil {
+				return nil, err
+			}
+			newSlot.CartID = cart.ID
+			saved = append(saved, newSlot)
+			continue
+		}
+
+		amount := int(slot.Amount) + change
+		if amount <= 0 {
+			deleted = append(deleted, slot)
+			continue
+		}
+		slot.Amount = uint(amount)
+		saved = append(saved, slot)
+	}
+
+	err = ser.cartRepo.UpdateSlots(cart.ID, saved, deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := ser.cartRepo.FindSingleByUserId(userId)
+	return ser.pricer.Price(updated), nil
+}
+
+func (ser *CartServiceImpl) ApplyPromotion(userId uint, code *dto.PromotionCode) (*dto.GetCart, error) {
+	err := ser.validate.Struct(code)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	promotion, err := ser.pricer.coupon(code.Code, userId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	cart := ser.cartRepo.FindSingleByUserId(userId)
+	err = ser.cartRepo.UpdatePromotionCode(cart.ID, promotion.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := ser.cartRepo.FindSingleByUserId(userId)
+	return ser.pricer.Price(updated), nil
+}
+
+func (ser *CartServiceImpl) RemovePromotion(userId uint) (*dto.GetCart, error) {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	cart := ser.cartRepo.FindSingleByUserId(userId)
+	err := ser.cartRepo.UpdatePromotionCode(cart.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := ser.cartRepo.FindSingleByUserId(userId)
+	return ser.pricer.Price(updated), nil
+}
+
+func (ser *CartServiceImpl) Checkout(userId uint) (*dto.GetOrder, error) {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.Verified {
+		return nil, ErrNotVerified
+	}
+
+	cart := ser.cartRepo.FindSingleByUserId(userId)
+	priced, applied := ser.pricer.price(cart, time.Now())
+	if len(priced.Cards) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	order := &model.Order{
+		UserID:   userId,
+		Subtotal: priced.Subtotal,
+		Discount: priced.Discount,
+		Shipping: priced.Shipping,
+		Total:    priced.Total,
+	}
+	for _, line := range priced.Cards {
+		if line.Archived {
+			return nil, ErrCartHasArchivedCards
+		}
+		if line.ExceedsStock {
+			return nil, fmt.Errorf("%w: only %d of %s left", ErrInsufficientStock, line.InStockAmount, line.Name)
+		}
+		order.Lines = append(order.Lines, model.OrderLine{
+			CardID:    line.CardId,
+			Name:      line.Name,
+			UnitPrice: line.Price,
+			Amount:    line.Amount,
+			LineTotal: line.LineTotal,
+		})
+	}
+
+	redemptions := make([]*model.PromotionRedemption, 0, len(applied))
+	for _, a := range applied {
+		// the coupon is only recorded on the order if it was applied
+		if a.promotion.Code != nil {
+			order.PromotionCode = a.promotion.Code
+		}
+		redemptions = append(redemptions, &model.PromotionRedemption{
+			PromotionID: a.promotion.ID,
+			UserID:      userId,
+			Discount:    a.discount,
+		})
+	}
+
+	err := ser.orderRepo.Create(order, cart.ID, redemptions)
+	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, ErrInsufficientStock
+		}
+		if errors.Is(err, repository.ErrPromotionExhausted) {
+			return nil, ErrPromotionLimitReached
+		}
+		return nil, err
+	}
+
+	return dto.NewGetOrder(order), nil
+}
+
+func (ser *CartServiceImpl) GetGuest(guestId string) *dto.GetCart {
+	cart := ser.guestCarts.Get(guestId)
+	if cart == nil {
+		cart = &model.Cart{}
+	}
+	return ser.pricer.Price(cart)
+}
+
+func (ser *CartServiceImpl) EditGuestSlot(guestId string, newCartSlot *dto.PostCartSlot) (*dto.GetCart, error) {
+	err := ser.validate.Struct(newCartSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	return ser.EditGuestSlots(guestId, &dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{newCartSlot},
+	})
+}
+
+func (ser *CartServiceImpl) EditGuestSlots(guestId string, newCartSlots *dto.PostCartSlots) (*dto.GetCart, error) {
+	err := ser.validate.Struct(newCartSlots)
+	if err != nil {
+		return nil, err
+	}
+
+	cardIds := make([]uint, 0, len(newCartSlots.Slots))
+	amounts := make([]int, 0, len(newCartSlots.Slots))
+	for _, slot := range newCartSlots.Slots {
+		cardIds = append(cardIds, slot.CardId)
+		amounts = append(amounts, slot.Amount)
+	}
+	order, changes, err := sumSlotChanges(ser.cardRepo, cardIds, amounts)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := ser.guestCarts.Get(guestId)
+	if cart == nil {
+		cart = &model.Cart{}
+	}
+
+	amountByCard := map[uint]int{}
+	for _, slot := range cart.Cards {
+		amountByCard[slot.CardID] = int(slot.Amount)
+	}
+	for _, cardId := range order {
+		change := changes[cardId]
+		if change == 0 {
+			continue
+		}
+
+		amount, ok := amountByCard[cardId]
+		if !ok {
+			newSlot, err := (&dto.PostCartSlot{CardId: cardId, Amount: change}).ToCartSlot()
+			if err != nil {
+				return nil, err
+			}
+			cart.Cards = append(cart.Cards, *newSlot)
+		}
+		amountByCard[cardId] = amount + change
+	}
+
+	// guest carts live only in the cache, so the slots are rebuilt instead of saved one by one
+	slots := make([]model.CartSlot, 0, len(cart.Cards))
+	for _, slot := range cart.Cards {
+		amount := amountByCard[slot.CardID]
+		if amount <= 0 {
+			continue
+		}
+		slot.Amount = uint(amount)
+		slots = append(slots, slot)
+	}
+	cart.Cards = slots
+
+	ser.guestCarts.Remember(guestId, cart, time.Duration(ser.config.Store.GuestCartTtl)*time.Second)
+	return ser.pricer.Price(cart), nil
+}
+
+func (ser *CartServiceImpl) MergeGuest(userId uint, guestId string) error {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	guest := ser.guestCarts.Take(guestId)
+	if guest == nil || len(guest.Cards) == 0 {
+		return nil
+	}
+
+	cart := ser.cartRepo.FindSingleByUserId(userId)
+
+	existing := map[uint]*model.CartSlot{}
+	for i := range cart.Cards {
+		existing[cart.Cards[i].CardID] = &cart.Cards[i]
+	}
+	saved := []*model.CartSlot{}
+	for _, guestSlot := range guest.Cards {
+		// cards can be deleted while they wait in a guest cart
+		if ser.cardRepo.FindById(guestSlot.CardID) == nil {
+			continue
+		}
+
+		slot, ok := existing[guestSlot.CardID]
+		if !ok {
+			slot = &model.CartSlot{
+				CardID: guestSlot.CardID,
+				CartID: cart.ID,
+			}
+			existing[guestSlot.CardID] = slot
+		}
+		slot.Amount += guestSlot.Amount
+		saved = append(saved, slot)
+	}
+	if len(saved) == 0 {
+		return nil
+	}
+
+	return ser.cartRepo.UpdateSlots(cart.ID, saved, nil)
+}
+
+// sumSlotChanges sums the amount changes of every card, returning the cards in the order they first appear.
+// Fails if any of the cards doesn't exist
+func sumSlotChanges(cardRepo repository.CardRepository, cardIds []uint, amounts []int) ([]uint, map[uint]int, error) {
+	order := []uint{}
+	changes := map[uint]int{}
+	for i, cardId := range cardIds {
+		if _, ok := changes[cardId]; !ok {
+			if cardRepo.FindById(cardId) == nil {
+				return nil, nil, fmt.Errorf("%w: no card with id %d", ErrCardNotFound, cardId)
+			}
+			order = append(order, cardId)
+		}
+		changes[cardId] += amounts[i]
+	}
+	return order, changes, nil
+}
+
+func (ser *CartServiceImpl) Convert(cart *dto.GetCart, currency string) (*dto.GetCart, error) {
+	conversion, err := ser.converter.To(currency)
+	if err != nil {
+		return nil, err
+	}
+	return conversion.Cart(cart), nil
 }
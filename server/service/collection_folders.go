@@ -0,0 +1,293 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"store.api/dto"
+	"store.api/model"
+	"store.api/query"
+	"store.api/utility"
+)
+
+func (ser *CollectionServiceImpl) GetAll(userId uint, query *query.CollectionQuery) (*dto.CollectionTree, error) {
+	if query.Folder != nil {
+		_, err := ser.getFolder(*query.Folder, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tag := strings.ToLower(strings.TrimSpace(query.Tag))
+
+	children := map[uint][]*model.CollectionFolder{}
+	var topLevel []*model.CollectionFolder
+	for _, folder := range ser.folderRepo.FindByOwnerId(userId) {
+		if folder.ParentID == nil {
+			topLevel = append(topLevel, folder)
+			continue
+		}
+		children[*folder.ParentID] = append(children[*folder.ParentID], folder)
+	}
+
+	collections := map[uint][]*dto.GetCollection{}
+	var unfiled []*dto.GetCollection
+	for _, col := range ser.colRepo.FindByOwnerId(userId) {
+		if len(tag) > 0 && !slices.Contains(col.Tags, tag) {
+			continue
+		}
+		if col.FolderID == nil {
+			unfiled = append(unfiled, dto.NewGetCollection(col))
+			continue
+		}
+		collections[*col.FolderID] = append(collections[*col.FolderID], dto.NewGetCollection(col))
+	}
+
+	result := &dto.CollectionTree{
+		FolderId:    query.Folder,
+		Folders:     []*dto.CollectionFolderNode{},
+		Collections: []*dto.GetCollection{},
+	}
+	folders := topLevel
+	if query.Folder != nil {
+		folders = children[*query.Folder]
+		unfiled = collections[*query.Folder]
+	}
+	// folders without matching collections only add noise when looking for a tag
+	prune := len(tag) > 0
+	result.Folders = buildFolderNodes(folders, children, collections, prune)
+	if unfiled != nil {
+		result.Collections = unfiled
+	}
+	return result, nil
+}
+
+func buildFolderNodes(folders []*model.CollectionFolder, children map[uint][]*model.CollectionFolder, collections map[uint][]*dto.GetCollection, prune bool) []*dto.CollectionFolderNode {
+	result := []*dto.CollectionFolderNode{}
+	for _, folder := range folders {
+		node := &dto.CollectionFolderNode{
+			ID:          folder.ID,
+			Name:        folder.Name,
+			Folders:     buildFolderNodes(children[folder.ID], children, collections, prune),
+			Collections: collections[folder.ID],
+		}
+		if node.Collections == nil {
+			if prune && len(node.Folders) == 0 {
+				continue
+			}
+			node.Collections = []*dto.GetCollection{}
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+func (ser *CollectionServiceImpl) CreateFolder(userId uint, folder *dto.PostCollectionFolder) (*dto.GetCollectionFolder, error) {
+	err := ser.validate.Struct(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	err = ser.checkFolder(folder.ParentId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.CollectionFolder{
+		Name:     folder.Name,
+		ParentID: folder.ParentId,
+		OwnerID:  userId,
+	}
+	err = ser.folderRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetCollectionFolder(result), nil
+}
+
+func (ser *CollectionServiceImpl) UpdateFolder(id uint, userId uint, folder *dto.PostCollectionFolder) (*dto.GetCollectionFolder, error) {
+	err := ser.validate.Struct(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := ser.getFolder(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// walk up from the new parent, reaching the folder means it would be moved into itself
+	parentId := folder.ParentId
+	for parentId != nil {
+		if *parentId == id {
+			return nil, ErrFolderCycle
+		}
+		parent, err := ser.getFolder(*parentId, userId)
+		if err != nil {
+			return nil, err
+		}
+		parentId = parent.ParentID
+	}
+
+	existing.Name = folder.Name
+	existing.ParentID = folder.ParentId
+	err = ser.folderRepo.Update(existing)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetCollectionFolder(existing), nil
+}
+
+func (ser *CollectionServiceImpl) DeleteFolder(id uint, userId uint) error {
+	folder, err := ser.getFolder(id, userId)
+	if err != nil {
+		return err
+	}
+	return ser.folderRepo.Delete(folder)
+}
+
+func (ser *CollectionServiceImpl) Copy(id uint, userId uint, options *dto.CopyCollection) (*dto.GetCollection, error) {
+	err := ser.validate.Struct(options)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.Verified {
+		return nil, ErrNotVerified
+	}
+
+	source, err := ser.getReadable(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ser.checkFolder(options.FolderId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.Collection{
+		Name:        options.Name,
+		Description: source.Description,
+		Visibility:  model.VisibilityPrivate,
+		Cards: utility.MapSlice(source.Cards, func(s model.CollectionSlot) model.CollectionSlot {
+			return model.CollectionSlot{
+				CardID: s.CardID,
+				Amount: s.Amount,
+			}
+		}),
+		FolderID: options.FolderId,
+		OwnerID:  userId,
+	}
+	if len(result.Name) == 0 {
+		result.Name = source.Name
+	}
+	// folders and tags are the owner's organisation, they aren't carried over from other users
+	if source.OwnerID == userId {
+		if len(options.Name) == 0 {
+			result.Name = fmt.Sprintf("%s (copy)", source.Name)
+		}
+		if result.FolderID == nil {
+			result.FolderID = source.FolderID
+		}
+		result.Tags = slices.Clone(source.Tags)
+	}
+
+	err = ser.colRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetCollection(result), nil
+}
+
+func (ser *CollectionServiceImpl) Merge(id uint, userId uint, merge *dto.MergeCollections) (*dto.GetCollection, error) {
+	err := ser.validate.Struct(merge)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.Verified {
+		return nil, ErrNotVerified
+	}
+
+	if merge.SourceId == id {
+		return nil, ErrMergeIntoSelf
+	}
+
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	source, err := ser.getById(merge.SourceId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[uint]*model.CollectionSlot{}
+	for i := range collection.Cards {
+		existing[collection.Cards[i].CardID] = &collection.Cards[i]
+	}
+	saved := []*model.CollectionSlot{}
+	for _, slot := range source.Cards {
+		if found, ok := existing[slot.CardID]; ok {
+			found.Amount += slot.Amount
+			saved = append(saved, found)
+			continue
+		}
+		newSlot := &model.CollectionSlot{
+			CardID:       slot.CardID,
+			Amount:       slot.Amount,
+			CollectionID: collection.ID,
+		}
+		existing[slot.CardID] = newSlot
+		saved = append(saved, newSlot)
+	}
+
+	err = ser.colRepo.UpdateSlots(collection.ID, saved, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if merge.DeleteSource {
+		err = ser.colRepo.Delete(source.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updated := ser.colRepo.FindById(collection.ID)
+	return dto.NewGetCollection(updated), nil
+}
+
+func (ser *CollectionServiceImpl) getFolder(id uint, userId uint) (*model.CollectionFolder, error) {
+	result := ser.folderRepo.FindById(id)
+	if result == nil || result.OwnerID != userId {
+		return nil, ErrFolderNotFound
+	}
+	return result, nil
+}
+
+// checkFolder checks that the folder is the user's, nil meaning no folder
+func (ser *CollectionServiceImpl) checkFolder(id *uint, userId uint) error {
+	if id == nil {
+		return nil
+	}
+	_, err := ser.getFolder(*id, userId)
+	return err
+}
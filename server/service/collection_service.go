@@ -4,18 +4,49 @@ import (
 	"errors"
 
 	"store.api/dto"
+	"store.api/query"
 )
 
 var (
-	ErrNotVerified        = errors.New("user is not verified")
-	ErrCollectionNotFound = errors.New("collection not found")
+	ErrNotVerified           = errors.New("user is not verified")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrUnknownDecklistFormat = errors.New("unknown decklist format")
+	ErrFolderNotFound        = errors.New("collection folder not found")
+	ErrFolderCycle           = errors.New("folder can't be moved into itself")
+	ErrMergeIntoSelf         = errors.New("collection can't be merged into itself")
 )
 
 type CollectionService interface {
-	GetAll(uint) []*dto.GetCollection
+	// GetAll returns the user's folders and collections as a tree, optionally starting from a folder
+	// and only including collections with a tag
+	GetAll(userId uint, query *query.CollectionQuery) (*dto.CollectionTree, error)
 	Create(*dto.PostCollection, uint) (*dto.GetCollection, error)
 	EditSlot(*dto.PostCollectionSlot, uint, uint) (*dto.GetCollection, error)
+	// EditSlots applies all slot changes or none of them
+	EditSlots(*dto.PostCollectionSlots, uint, uint) (*dto.GetCollection, error)
 	GetById(uint, uint) (*dto.GetCollection, error)
 	Delete(uint, uint) error
 	UpdateInfo(*dto.PostCollection, uint, uint) (*dto.GetCollection, error)
+	GetShared(token string) (*dto.GetSharedCollection, error)
+	GetPublic(username string) ([]*dto.GetSharedCollection, error)
+	Value(id uint, userId uint) (*dto.CollectionValue, error)
+	Import(id uint, userId uint, list *dto.DecklistImport) (*dto.DecklistImportResult, error)
+	Export(id uint, userId uint, format string) (*dto.DecklistExport, error)
+	// ToCart adds the collection's cards to the user's cart, capped by stock. Missing amounts can
+	// be made up with the cheapest in-stock printings of the same card key
+	ToCart(id uint, userId uint, substitute bool) (*dto.CollectionToCart, error)
+	// Diff compares the collection to another of the user's collections or a public collection
+	Diff(id uint, otherId uint, userId uint) (*dto.CollectionDiff, error)
+	DiffDecklist(id uint, userId uint, list *dto.DecklistImport) (*dto.CollectionDiff, error)
+	// Needs prices the cards that the collection is missing to match the other collection
+	Needs(id uint, otherId uint, userId uint) (*dto.CollectionNeeds, error)
+	NeedsDecklist(id uint, userId uint, list *dto.DecklistImport) (*dto.CollectionNeeds, error)
+	// Copy creates a private copy of one of the user's collections or a public collection
+	Copy(id uint, userId uint, options *dto.CopyCollection) (*dto.GetCollection, error)
+	// Merge adds the cards of another of the user's collections to the collection, summing the amounts
+	Merge(id uint, userId uint, merge *dto.MergeCollections) (*dto.GetCollection, error)
+	CreateFolder(userId uint, folder *dto.PostCollectionFolder) (*dto.GetCollectionFolder, error)
+	UpdateFolder(id uint, userId uint, folder *dto.PostCollectionFolder) (*dto.GetCollectionFolder, error)
+	// DeleteFolder removes the folder, its subfolders and collections are moved to its parent
+	DeleteFolder(id uint, userId uint) error
 }
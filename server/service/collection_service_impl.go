@@ -1,36 +1,42 @@
 package service
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/go-playground/validator/v10"
+	"store.api/decklist"
 	"store.api/dto"
 	"store.api/model"
 	"store.api/repository"
+	"store.api/security"
 	"store.api/utility"
 )
 
 type CollectionServiceImpl struct {
-	colRepo  repository.CollectionRepository
-	userRepo repository.UserRepository
-	cardRepo repository.CardRepository
-	validate *validator.Validate
+	colRepo    repository.CollectionRepository
+	folderRepo repository.CollectionFolderRepository
+	userRepo   repository.UserRepository
+	cardRepo   repository.CardRepository
+	cartRepo   repository.CartRepository
+	pricer     *CartPricer
+	validate   *validator.Validate
 }
 
-func NewCollectionServiceImpl(colRepo repository.CollectionRepository, userRepo repository.UserRepository, cardRepo repository.CardRepository, validate *validator.Validate) *CollectionServiceImpl {
+func NewCollectionServiceImpl(colRepo repository.CollectionRepository, folderRepo repository.CollectionFolderRepository, userRepo repository.UserRepository, cardRepo repository.CardRepository, cartRepo repository.CartRepository, pricer *CartPricer, validate *validator.Validate) *CollectionServiceImpl {
 	return &CollectionServiceImpl{
-		colRepo:  colRepo,
-		userRepo: userRepo,
-		cardRepo: cardRepo,
-		validate: validate,
+		colRepo:    colRepo,
+		folderRepo: folderRepo,
+		userRepo:   userRepo,
+		cardRepo:   cardRepo,
+		cartRepo:   cartRepo,
+		pricer:     pricer,
+		validate:   validate,
 	}
 }
 
-func (ser *CollectionServiceImpl) GetAll(userId uint) []*dto.GetCollection {
-	return utility.MapSlice(
-		ser.colRepo.FindByOwnerId(userId),
-		func(c *model.Collection) *dto.GetCollection { return dto.NewGetCollection(c) },
-	)
-}
-
 func (ser *CollectionServiceImpl) Create(col *dto.PostCollection, userId uint) (*dto.GetCollection, error) {
 	err := ser.validate.Struct(col)
 	if err != nil {
@@ -46,8 +52,17 @@ func (ser *CollectionServiceImpl) Create(col *dto.PostCollection, userId uint) (
 		return nil, ErrNotVerified
 	}
 
+	err = ser.checkFolder(col.FolderId, userId)
+	if err != nil {
+		return nil, err
+	}
+
 	result := col.ToCollection()
 	result.OwnerID = userId
+	err = setVisibility(result, result.Visibility)
+	if err != nil {
+		return nil, err
+	}
 
 	err = ser.colRepo.Save(result)
 	if err != nil {
@@ -120,6 +135,78 @@ func (ser *CollectionServiceImpl) EditSlot(newCollectionSlot *dto.PostCollection
 	return dto.NewGetCollection(updated), nil
 }
 
+func (ser *CollectionServiceImpl) EditSlots(newSlots *dto.PostCollectionSlots, colId uint, userId uint) (*dto.GetCollection, error) {
+	err := ser.validate.Struct(newSlots)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.Verified {
+		return nil, ErrNotVerified
+	}
+
+	collection, err := ser.getById(colId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cardIds := make([]uint, 0, len(newSlots.Slots))
+	amounts := make([]int, 0, len(newSlots.Slots))
+	for _, slot := range newSlots.Slots {
+		cardIds = append(cardIds, slot.CardId)
+		amounts = append(amounts, slot.Amount)
+	}
+	order, changes, err := sumSlotChanges(ser.cardRepo, cardIds, amounts)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[uint]*model.CollectionSlot{}
+	for i := range collection.Cards {
+		existing[collection.Cards[i].CardID] = &collection.Cards[i]
+	}
+	saved := []*model.CollectionSlot{}
+	deleted := []*model.CollectionSlot{}
+	for _, cardId := range order {
+		change := changes[cardId]
+		if change == 0 {
+			continue
+		}
+
+		slot, ok := existing[cardId]
+		if !ok {
+			newSlot, err := (&dto.PostCollectionSlot{CardId: cardId, Amount: change}).ToCollectionSlot()
+			if err != nil {
+				return nil, err
+			}
+			newSlot.CollectionID = colId
+			saved = append(saved, newSlot)
+			continue
+		}
+
+		amount := int(slot.Amount) + change
+		if amount <= 0 {
+			deleted = append(deleted, slot)
+			continue
+		}
+		slot.Amount = uint(amount)
+		saved = append(saved, slot)
+	}
+
+	err = ser.colRepo.UpdateSlots(colId, saved, deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := ser.colRepo.FindById(colId)
+	return dto.NewGetCollection(updated), nil
+}
+
 func (ser *CollectionServiceImpl) GetById(id uint, userId uint) (*dto.GetCollection, error) {
 	result, err := ser.getById(id, userId)
 	if err != nil {
@@ -144,7 +231,7 @@ func (ser *CollectionServiceImpl) UpdateInfo(newData *dto.PostCollection, id uin
 	}
 
 	// fetch user
-	user := ser.userRepo.FindById(id)
+	user := ser.userRepo.FindById(userId)
 	if user == nil {
 		return nil, ErrUserNotFound
 	}
@@ -158,11 +245,24 @@ func (ser *CollectionServiceImpl) UpdateInfo(newData *dto.PostCollection, id uin
 		return nil, err
 	}
 
+	err = ser.checkFolder(newData.FolderId, userId)
+	if err != nil {
+		return nil, err
+	}
+
 	// modify collection
 	newCollection := newData.ToCollection()
 	newCollection.ID = existing.ID
 	newCollection.OwnerID = existing.OwnerID
 	newCollection.Cards = existing.Cards
+	newCollection.ShareToken = existing.ShareToken
+	if len(newData.Visibility) == 0 {
+		newCollection.Visibility = existing.Visibility
+	}
+	err = setVisibility(newCollection, newCollection.Visibility)
+	if err != nil {
+		return nil, err
+	}
 
 	err = ser.colRepo.Update(newCollection)
 	if err != nil {
@@ -179,3 +279,719 @@ func (ser *CollectionServiceImpl) getById(id uint, userId uint) (*model.Collecti
 	}
 	return result, nil
 }
+
+func (ser *CollectionServiceImpl) GetShared(token string) (*dto.GetSharedCollection, error) {
+	result := ser.colRepo.FindByShareToken(token)
+	if result == nil || result.Visibility == model.VisibilityPrivate {
+		return nil, ErrCollectionNotFound
+	}
+
+	owner := ser.userRepo.FindById(result.OwnerID)
+	if owner == nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	return dto.NewGetSharedCollection(result, owner), nil
+}
+
+func (ser *CollectionServiceImpl) GetPublic(username string) ([]*dto.GetSharedCollection, error) {
+	owner := ser.userRepo.FindByUsername(username)
+	if owner == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return utility.MapSlice(
+		ser.colRepo.FindPublicByOwnerId(owner.ID),
+		func(c *model.Collection) *dto.GetSharedCollection { return dto.NewGetSharedCollection(c, owner) },
+	), nil
+}
+
+// periods the collection value change is reported for
+var valueChangePeriods = []uint{7, 30}
+
+func (ser *CollectionServiceImpl) Value(id uint, userId uint) (*dto.CollectionValue, error) {
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.CollectionValue{
+		CollectionId: collection.ID,
+		Changes:      []*dto.ValueChange{},
+	}
+	byExpansion := valueBreakdowns{}
+	byType := valueBreakdowns{}
+	byLanguage := valueBreakdowns{}
+
+	cardIds := []uint{}
+	amounts := map[uint]uint{}
+	for _, slot := range collection.Cards {
+		card := ser.cardRepo.FindById(slot.CardID)
+		if card == nil {
+			continue
+		}
+		cardIds = append(cardIds, card.ID)
+		amounts[card.ID] += slot.Amount
+
+		value := card.Price.Times(slot.Amount)
+		result.Cards += slot.Amount
+		result.Value += value
+		byExpansion.add(card.ExpansionID, card.Expansion.FullName, slot.Amount, value)
+		byType.add(card.CardTypeID, card.CardType.LongName, slot.Amount, value)
+		byLanguage.add(card.LanguageID, card.Language.LongName, slot.Amount, value)
+	}
+	result.ByExpansion = byExpansion.sorted()
+	result.ByType = byType.sorted()
+	result.ByLanguage = byLanguage.sorted()
+
+	now := time.Now()
+	for _, days := range valueChangePeriods {
+		prices := ser.cardRepo.PricesAt(cardIds, now.AddDate(0, 0, -int(days)))
+
+		var previous model.Money
+		for cardId, amount := range amounts {
+			previous += prices[cardId].Times(amount)
+		}
+
+		change := &dto.ValueChange{
+			Days:          days,
+			PreviousValue: previous,
+			Change:        result.Value - previous,
+		}
+		if previous > 0 {
+			percent := float64(change.Change) / float64(previous) * 100
+			change.ChangePercent = &percent
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	return result, nil
+}
+
+func (ser *CollectionServiceImpl) Import(id uint, userId uint, list *dto.DecklistImport) (*dto.DecklistImportResult, error) {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.Verified {
+		return nil, ErrNotVerified
+	}
+
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, unresolved, err := ser.resolveDecklist(list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DecklistImportResult{
+		Unresolved: unresolved,
+	}
+
+	// card id -> amount, in the order the cards appear in the decklist
+	cardIds := []uint{}
+	amounts := map[uint]uint{}
+	for _, r := range resolved {
+		if _, ok := amounts[r.card.ID]; !ok {
+			cardIds = append(cardIds, r.card.ID)
+		}
+		amounts[r.card.ID] += r.entry.Amount
+		result.Imported += r.entry.Amount
+	}
+
+	existing := map[uint]*model.CollectionSlot{}
+	for i := range collection.Cards {
+		existing[collection.Cards[i].CardID] = &collection.Cards[i]
+	}
+	saved := []*model.CollectionSlot{}
+	for _, cardId := range cardIds {
+		if found, ok := existing[cardId]; ok {
+			found.Amount += amounts[cardId]
+			saved = append(saved, found)
+			continue
+		}
+		saved = append(saved, &model.CollectionSlot{
+			Amount:       amounts[cardId],
+			CardID:       cardId,
+			CollectionID: collection.ID,
+		})
+	}
+
+	err = ser.colRepo.UpdateSlots(collection.ID, saved, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Collection = dto.NewGetCollection(ser.colRepo.FindById(collection.ID))
+	return result, nil
+}
+
+// resolvedEntry is a decklist entry with the printing it was resolved to
+type resolvedEntry struct {
+	entry *decklist.Entry
+	card  *model.Card
+}
+
+// resolveDecklist parses the decklist and resolves its entries to printings, the lines that couldn't be read
+// or resolved are returned in the order they appear in the decklist
+func (ser *CollectionServiceImpl) resolveDecklist(list *dto.DecklistImport) ([]*resolvedEntry, []*dto.UnresolvedLine, error) {
+	err := ser.validate.Struct(list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	format, err := decklist.ParseFormat(list.Format)
+	if err != nil {
+		return nil, nil, ErrUnknownDecklistFormat
+	}
+	entries, invalid, err := decklist.Parse(format, []byte(list.Decklist))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unresolved := utility.MapSlice(invalid, func(l *decklist.InvalidLine) *dto.UnresolvedLine {
+		return &dto.UnresolvedLine{
+			Line:   l.Line,
+			Text:   l.Text,
+			Reason: l.Reason,
+		}
+	})
+
+	resolved := []*resolvedEntry{}
+	printings := map[string][]*model.Card{}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name)
+		if _, ok := printings[name]; !ok {
+			printings[name] = ser.cardRepo.FindByKeyName(entry.Name)
+		}
+
+		card, reason := resolveEntry(entry, printings[name])
+		if card == nil {
+			unresolved = append(unresolved, &dto.UnresolvedLine{
+				Line:   entry.Line,
+				Text:   entry.Text,
+				Reason: reason,
+			})
+			continue
+		}
+		resolved = append(resolved, &resolvedEntry{
+			entry: entry,
+			card:  card,
+		})
+	}
+	sort.SliceStable(unresolved, func(i, j int) bool {
+		return unresolved[i].Line < unresolved[j].Line
+	})
+
+	return resolved, unresolved, nil
+}
+
+// resolveEntry picks the printing of a decklist entry, narrowing the printings down by expansion
+// and collector number when the entry has them. If nothing matches the reason is returned instead
+func resolveEntry(entry *decklist.Entry, printings []*model.Card) (*model.Card, string) {
+	if len(printings) == 0 {
+		return nil, fmt.Sprintf("no card named %s", entry.Name)
+	}
+
+	if len(entry.Expansion) > 0 {
+		inExpansion := []*model.Card{}
+		for _, printing := range printings {
+			if strings.EqualFold(printing.Expansion.ShortName, entry.Expansion) || strings.EqualFold(printing.ExpansionID, entry.Expansion) {
+				inExpansion = append(inExpansion, printing)
+			}
+		}
+		if len(inExpansion) == 0 {
+			return nil, fmt.Sprintf("%s has no printing in expansion %s", entry.Name, entry.Expansion)
+		}
+		printings = inExpansion
+	}
+
+	if len(entry.CollectorNumber) > 0 {
+		for _, printing := range printings {
+			if strings.EqualFold(printing.CollectorNumber, entry.CollectorNumber) {
+				return printing, ""
+			}
+		}
+		return nil, fmt.Sprintf("%s has no printing with collector number %s in expansion %s", entry.Name, entry.CollectorNumber, entry.Expansion)
+	}
+
+	return printings[0], ""
+}
+
+func (ser *CollectionServiceImpl) Export(id uint, userId uint, format string) (*dto.DecklistExport, error) {
+	f, err := decklist.ParseFormat(format)
+	if err != nil {
+		return nil, ErrUnknownDecklistFormat
+	}
+
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*decklist.Entry{}
+	for _, slot := range collection.Cards {
+		card := ser.cardRepo.FindById(slot.CardID)
+		if card == nil {
+			continue
+		}
+
+		name := card.CardKey.EngName
+		if len(name) == 0 {
+			name = card.Name
+		}
+		entries = append(entries, &decklist.Entry{
+			Amount:          slot.Amount,
+			Name:            name,
+			Expansion:       card.Expansion.ShortName,
+			CollectorNumber: card.CollectorNumber,
+		})
+	}
+
+	data, err := decklist.Write(f, entries)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.DecklistExport{
+		FileName:    collection.Name + "." + f.Extension(),
+		ContentType: f.ContentType(),
+		Data:        data,
+	}, nil
+}
+
+func (ser *CollectionServiceImpl) ToCart(id uint, userId uint, substitute bool) (*dto.CollectionToCart, error) {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := ser.cartRepo.FindSingleByUserId(userId)
+
+	// card id -> amount already in the cart or about to be added, stock is capped by it
+	reserved := map[uint]uint{}
+	for _, slot := range cart.Cards {
+		reserved[slot.CardID] += slot.Amount
+	}
+	// card id -> amount to add, in the order the cards are added
+	cardIds := []uint{}
+	amounts := map[uint]uint{}
+	add := func(card *model.Card, wanted uint) uint {
+		if card.InStockAmount <= reserved[card.ID] {
+			return 0
+		}
+		amount := min(wanted, card.InStockAmount-reserved[card.ID])
+		if _, ok := amounts[card.ID]; !ok {
+			cardIds = append(cardIds, card.ID)
+		}
+		amounts[card.ID] += amount
+		reserved[card.ID] += amount
+		return amount
+	}
+
+	result := &dto.CollectionToCart{
+		Shortfalls: []*dto.CartShortfall{},
+	}
+	for _, slot := range collection.Cards {
+		card := ser.cardRepo.FindById(slot.CardID)
+		if card == nil {
+			continue
+		}
+
+		added := add(card, slot.Amount)
+		result.Added += added
+		if added == slot.Amount {
+			continue
+		}
+
+		shortfall := &dto.CartShortfall{
+			CardId:      card.ID,
+			Requested:   slot.Amount,
+			Added:       added,
+			Substitutes: []*dto.CartSubstitute{},
+			Missing:     slot.Amount - added,
+		}
+		if substitute {
+			for _, printing := range ser.cardRepo.FindInStockByKeyId(card.CardKeyID) {
+				if shortfall.Missing == 0 {
+					break
+				}
+				if printing.ID == card.ID {
+					continue
+				}
+				amount := add(printing, shortfall.Missing)
+				if amount == 0 {
+					continue
+				}
+				shortfall.Substitutes = append(shortfall.Substitutes, &dto.CartSubstitute{
+					CardId: printing.ID,
+					Amount: amount,
+					Price:  printing.Price,
+				})
+				shortfall.Missing -= amount
+				result.Added += amount
+			}
+		}
+		result.Shortfalls = append(result.Shortfalls, shortfall)
+	}
+
+	existing := map[uint]*model.CartSlot{}
+	for i := range cart.Cards {
+		existing[cart.Cards[i].CardID] = &cart.Cards[i]
+	}
+	saved := []*model.CartSlot{}
+	for _, cardId := range cardIds {
+		if found, ok := existing[cardId]; ok {
+			found.Amount += amounts[cardId]
+			saved = append(saved, found)
+			continue
+		}
+		saved = append(saved, &model.CartSlot{
+			Amount: amounts[cardId],
+			CardID: cardId,
+			CartID: cart.ID,
+		})
+	}
+
+	err = ser.cartRepo.UpdateSlots(cart.ID, saved, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Cart = ser.pricer.Price(ser.cartRepo.FindSingleByUserId(userId))
+	return result, nil
+}
+
+func (ser *CollectionServiceImpl) Diff(id uint, otherId uint, userId uint) (*dto.CollectionDiff, error) {
+	collection, other, err := ser.getComparable(id, otherId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffPrintings(collection.ID, ser.countPrintings(collection), ser.countPrintings(other)), nil
+}
+
+func (ser *CollectionServiceImpl) DiffDecklist(id uint, userId uint, list *dto.DecklistImport) (*dto.CollectionDiff, error) {
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	other, unresolved, err := ser.countDecklistPrintings(list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := diffPrintings(collection.ID, ser.countPrintings(collection), other)
+	result.Unresolved = unresolved
+	return result, nil
+}
+
+func (ser *CollectionServiceImpl) Needs(id uint, otherId uint, userId uint) (*dto.CollectionNeeds, error) {
+	collection, other, err := ser.getComparable(id, otherId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ser.priceNeeds(collection.ID, ser.countCardKeys(collection), ser.countCardKeys(other)), nil
+}
+
+func (ser *CollectionServiceImpl) NeedsDecklist(id uint, userId uint, list *dto.DecklistImport) (*dto.CollectionNeeds, error) {
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	target, unresolved, err := ser.countDecklistCardKeys(list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ser.priceNeeds(collection.ID, ser.countCardKeys(collection), target)
+	result.Unresolved = unresolved
+	return result, nil
+}
+
+// getComparable fetches the user's collection and the collection it's compared to,
+// which is either the user's or a public one
+func (ser *CollectionServiceImpl) getComparable(id uint, otherId uint, userId uint) (*model.Collection, *model.Collection, error) {
+	collection, err := ser.getById(id, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	other, err := ser.getReadable(otherId, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return collection, other, nil
+}
+
+// getReadable fetches a collection that is either the user's or public
+func (ser *CollectionServiceImpl) getReadable(id uint, userId uint) (*model.Collection, error) {
+	result := ser.colRepo.FindById(id)
+	if result == nil || (result.OwnerID != userId && result.Visibility != model.VisibilityPublic) {
+		return nil, ErrCollectionNotFound
+	}
+	return result, nil
+}
+
+// cardKeyCounts counts cards by card key, so that any printing of a card counts as the same card
+type cardKeyCounts struct {
+	amounts map[string]uint
+	names   map[string]string
+}
+
+func newCardKeyCounts() *cardKeyCounts {
+	return &cardKeyCounts{
+		amounts: map[string]uint{},
+		names:   map[string]string{},
+	}
+}
+
+func (c *cardKeyCounts) add(card *model.Card, amount uint) {
+	c.amounts[card.CardKeyID] += amount
+	c.names[card.CardKeyID] = cardName(card)
+}
+
+// cardName is the english name of the card key, falling back to the printed name
+func cardName(card *model.Card) string {
+	if len(card.CardKey.EngName) == 0 {
+		return card.Name
+	}
+	return card.CardKey.EngName
+}
+
+// printingCounts counts cards by printing, the cards are kept to label the printings
+type printingCounts struct {
+	amounts map[uint]uint
+	cards   map[uint]*model.Card
+}
+
+func newPrintingCounts() *printingCounts {
+	return &printingCounts{
+		amounts: map[uint]uint{},
+		cards:   map[uint]*model.Card{},
+	}
+}
+
+func (c *printingCounts) add(card *model.Card, amount uint) {
+	c.amounts[card.ID] += amount
+	c.cards[card.ID] = card
+}
+
+func (ser *CollectionServiceImpl) countCardKeys(collection *model.Collection) *cardKeyCounts {
+	result := newCardKeyCounts()
+	for _, slot := range collection.Cards {
+		card := ser.cardRepo.FindById(slot.CardID)
+		if card == nil {
+			continue
+		}
+		result.add(card, slot.Amount)
+	}
+	return result
+}
+
+func (ser *CollectionServiceImpl) countPrintings(collection *model.Collection) *printingCounts {
+	result := newPrintingCounts()
+	for _, slot := range collection.Cards {
+		card := ser.cardRepo.FindById(slot.CardID)
+		if card == nil {
+			continue
+		}
+		result.add(card, slot.Amount)
+	}
+	return result
+}
+
+func (ser *CollectionServiceImpl) countDecklistPrintings(list *dto.DecklistImport) (*printingCounts, []*dto.UnresolvedLine, error) {
+	resolved, unresolved, err := ser.resolveDecklist(list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := newPrintingCounts()
+	for _, r := range resolved {
+		result.add(r.card, r.entry.Amount)
+	}
+	return result, unresolved, nil
+}
+
+func (ser *CollectionServiceImpl) countDecklistCardKeys(list *dto.DecklistImport) (*cardKeyCounts, []*dto.UnresolvedLine, error) {
+	resolved, unresolved, err := ser.resolveDecklist(list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := newCardKeyCounts()
+	for _, r := range resolved {
+		result.add(r.card, r.entry.Amount)
+	}
+	return result, unresolved, nil
+}
+
+// sortedKeys returns the card keys of both counts ordered by card name
+func sortedKeys(a *cardKeyCounts, b *cardKeyCounts) []string {
+	names := map[string]string{}
+	for key, name := range a.names {
+		names[key] = name
+	}
+	for key, name := range b.names {
+		names[key] = name
+	}
+
+	result := make([]string, 0, len(names))
+	for key := range names {
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if names[result[i]] == names[result[j]] {
+			return result[i] < result[j]
+		}
+		return names[result[i]] < names[result[j]]
+	})
+	return result
+}
+
+func diffPrintings(collectionId uint, own *printingCounts, other *printingCounts) *dto.CollectionDiff {
+	cards := map[uint]*model.Card{}
+	for id, card := range own.cards {
+		cards[id] = card
+	}
+	for id, card := range other.cards {
+		cards[id] = card
+	}
+
+	// grouped by card name and key, then by printing
+	ids := make([]uint, 0, len(cards))
+	for id := range cards {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := cards[ids[i]], cards[ids[j]]
+		if cardName(a) != cardName(b) {
+			return cardName(a) < cardName(b)
+		}
+		if a.CardKeyID != b.CardKeyID {
+			return a.CardKeyID < b.CardKeyID
+		}
+		return a.ID < b.ID
+	})
+
+	result := &dto.CollectionDiff{
+		CollectionId: collectionId,
+		Cards:        []*dto.DiffEntry{},
+	}
+	for _, id := range ids {
+		amount := own.amounts[id]
+		otherAmount := other.amounts[id]
+		if amount == otherAmount {
+			continue
+		}
+
+		result.Cards = append(result.Cards, &dto.DiffEntry{
+			CardId:      id,
+			CardKeyId:   cards[id].CardKeyID,
+			Name:        cardName(cards[id]),
+			Amount:      amount,
+			OtherAmount: otherAmount,
+			Delta:       int(otherAmount) - int(amount),
+		})
+	}
+	return result
+}
+
+// priceNeeds prices the cards missing from own to match target, buying from the cheapest printings in stock first
+func (ser *CollectionServiceImpl) priceNeeds(collectionId uint, own *cardKeyCounts, target *cardKeyCounts) *dto.CollectionNeeds {
+	result := &dto.CollectionNeeds{
+		CollectionId: collectionId,
+		Cards:        []*dto.NeededCard{},
+	}
+	for _, key := range sortedKeys(target, newCardKeyCounts()) {
+		if target.amounts[key] <= own.amounts[key] {
+			continue
+		}
+
+		needed := &dto.NeededCard{
+			CardKeyId:   key,
+			Name:        target.names[key],
+			Needed:      target.amounts[key] - own.amounts[key],
+			Printings:   []*dto.NeededPrinting{},
+			Unavailable: target.amounts[key] - own.amounts[key],
+		}
+		for _, printing := range ser.cardRepo.FindInStockByKeyId(key) {
+			if needed.Unavailable == 0 {
+				break
+			}
+			amount := min(needed.Unavailable, printing.InStockAmount)
+			needed.Printings = append(needed.Printings, &dto.NeededPrinting{
+				CardId: printing.ID,
+				Amount: amount,
+				Price:  printing.Price,
+			})
+			needed.Cost += printing.Price.Times(amount)
+			needed.Unavailable -= amount
+		}
+
+		result.Cards = append(result.Cards, needed)
+		result.TotalCost += needed.Cost
+	}
+	return result
+}
+
+type valueBreakdowns map[string]*dto.ValueBreakdown
+
+func (b valueBreakdowns) add(id string, name string, cards uint, value model.Money) {
+	existing, ok := b[id]
+	if !ok {
+		existing = &dto.ValueBreakdown{
+			Id:   id,
+			Name: name,
+		}
+		b[id] = existing
+	}
+	existing.Cards += cards
+	existing.Value += value
+}
+
+// sorted returns the breakdowns from the most to the least valuable
+func (b valueBreakdowns) sorted() []*dto.ValueBreakdown {
+	result := make([]*dto.ValueBreakdown, 0, len(b))
+	for _, breakdown := range b {
+		result = append(result, breakdown)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value == result[j].Value {
+			return result[i].Id < result[j].Id
+		}
+		return result[i].Value > result[j].Value
+	})
+	return result
+}
+
+// setVisibility gives shared collections a share token and revokes it when the collection is made private,
+// so sharing a collection again invalidates the old links
+func setVisibility(col *model.Collection, visibility model.CollectionVisibility) error {
+	col.Visibility = visibility
+	if visibility == model.VisibilityPrivate {
+		col.ShareToken = nil
+		return nil
+	}
+	if col.ShareToken != nil {
+		return nil
+	}
+
+	token, err := security.RandomToken()
+	if err != nil {
+		return err
+	}
+	col.ShareToken = &token
+	return nil
+}
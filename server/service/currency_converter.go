@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+)
+
+// CurrencyConverter converts prices from the store's currency with the current exchange rates
+type CurrencyConverter struct {
+	config   *config.Configuration
+	rateRepo repository.ExchangeRateRepository
+}
+
+func NewCurrencyConverter(config *config.Configuration, rateRepo repository.ExchangeRateRepository) *CurrencyConverter {
+	return &CurrencyConverter{
+		config:   config,
+		rateRepo: rateRepo,
+	}
+}
+
+// To returns the conversion to the currency, an empty currency is the store's currency
+func (c *CurrencyConverter) To(currency string) (*Conversion, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == c.config.Store.Currency {
+		return c.Store(), nil
+	}
+	rate := c.rateRepo.FindByCurrency(currency)
+	if rate == nil {
+		return nil, ErrUnknownCurrency
+	}
+	return &Conversion{Currency: rate.Currency, Rate: rate.Rate}, nil
+}
+
+// Store returns the conversion that leaves prices in the store's currency
+func (c *CurrencyConverter) Store() *Conversion {
+	return &Conversion{Currency: c.config.Store.Currency, Rate: 1}
+}
+
+// Conversion converts amounts between the store's currency and Currency
+type Conversion struct {
+	Currency string
+	Rate     float64
+}
+
+// Price converts an amount in the store's currency, the result is rounded to cents
+func (c *Conversion) Price(amount model.Money) model.Money {
+	return amount.Scale(c.Rate)
+}
+
+// StorePrice converts an amount in the conversion's currency back to the store's currency
+func (c *Conversion) StorePrice(amount model.Money) model.Money {
+	return amount.Scale(1 / c.Rate)
+}
+
+func (c *Conversion) Card(card *model.Card) *dto.GetCard {
+	result := dto.NewGetCard(card)
+	result.Price = c.Price(card.Price)
+	result.Currency = c.Currency
+	return result
+}
+
+func (c *Conversion) Listing(listing *model.Listing) *dto.GetListing {
+	result := dto.NewGetListing(listing)
+	result.Price = c.Price(listing.Price)
+	result.Currency = c.Currency
+	return result
+}
+
+// Offers converts the summary of a card's marketplace listings
+func (c *Conversion) Offers(summary *repository.OfferSummary) *dto.OfferSummary {
+	return &dto.OfferSummary{
+		Listings:          summary.Listings,
+		Amount:            summary.Amount,
+		CheapestListingId: summary.CheapestID,
+		CheapestPrice:     c.Price(summary.CheapestPrice),
+	}
+}
+
+// Cart converts a priced cart, the amounts are rounded separately so the total can be a cent off their sum
+func (c *Conversion) Cart(cart *dto.GetCart) *dto.GetCart {
+	if cart.Currency == c.Currency {
+		return cart
+	}
+	result := *cart
+	result.Cards = make([]*dto.GetCartSlot, 0, len(cart.Cards))
+	for _, slot := range cart.Cards {
+		converted := *slot
+		converted.Price = c.Price(slot.Price)
+		converted.LineTotal = c.Price(slot.LineTotal)
+		result.Cards = append(result.Cards, &converted)
+	}
+	result.Promotions = make([]*dto.AppliedPromotion, 0, len(cart.Promotions))
+	for _, promotion := range cart.Promotions {
+		converted := *promotion
+		converted.Discount = c.Price(promotion.Discount)
+		result.Promotions = append(result.Promotions, &converted)
+	}
+	result.Subtotal = c.Price(cart.Subtotal)
+	result.Discount = c.Price(cart.Discount)
+	result.Shipping = c.Price(cart.Shipping)
+	result.Total = c.Price(cart.Total)
+	result.Currency = c.Currency
+	return &result
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrUnknownCurrency      = errors.New("no exchange rate for the currency")
+	ErrStoreCurrencyRate    = errors.New("the store's currency can't have an exchange rate")
+	ErrNoExchangeRateSource = errors.New("no exchange rate source is configured")
+)
+
+type CurrencyService interface {
+	Rates() *dto.GetCurrencies
+	// SetRate creates or replaces the currency's exchange rate
+	SetRate(currency string, rate *dto.PostExchangeRate) (*dto.GetExchangeRate, error)
+	DeleteRate(currency string) error
+	// Load updates the rates from the configured source, rates missing from it are kept
+	Load() (*dto.GetCurrencies, error)
+}
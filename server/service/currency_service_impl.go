@@ -0,0 +1,175 @@
+package service
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+const exchangeRateFeedTimeout = 30 * time.Second
+
+type CurrencyServiceImpl struct {
+	config   *config.Configuration
+	rateRepo repository.ExchangeRateRepository
+	validate *validator.Validate
+}
+
+func NewCurrencyServiceImpl(config *config.Configuration, rateRepo repository.ExchangeRateRepository, validate *validator.Validate) *CurrencyServiceImpl {
+	return &CurrencyServiceImpl{
+		config:   config,
+		rateRepo: rateRepo,
+		validate: validate,
+	}
+}
+
+func (ser *CurrencyServiceImpl) Rates() *dto.GetCurrencies {
+	return &dto.GetCurrencies{
+		StoreCurrency: ser.config.Store.Currency,
+		Rates:         utility.MapSlice(ser.rateRepo.FindAll(), dto.NewGetExchangeRate),
+	}
+}
+
+func (ser *CurrencyServiceImpl) SetRate(currency string, rate *dto.PostExchangeRate) (*dto.GetExchangeRate, error) {
+	currency, err := ser.checkCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	err = ser.validate.Struct(rate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ExchangeRate{
+		Currency: currency,
+		Rate:     rate.Rate,
+	}
+	err = ser.rateRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetExchangeRate(result), nil
+}
+
+func (ser *CurrencyServiceImpl) DeleteRate(currency string) error {
+	currency = strings.ToUpper(currency)
+	if ser.rateRepo.FindByCurrency(currency) == nil {
+		return ErrUnknownCurrency
+	}
+	return ser.rateRepo.Delete(currency)
+}
+
+func (ser *CurrencyServiceImpl) Load() (*dto.GetCurrencies, error) {
+	source := ser.config.Store.ExchangeRates.Source
+	if source == "" {
+		return nil, ErrNoExchangeRateSource
+	}
+
+	feed, err := readExchangeRateFeed(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates from %s: %w", source, err)
+	}
+	rates, err := ser.rebase(feed)
+	if err != nil {
+		return nil, err
+	}
+	err = ser.rateRepo.Save(rates...)
+	if err != nil {
+		return nil, err
+	}
+	return ser.Rates(), nil
+}
+
+// checkCurrency returns the upper case currency code, the store's own currency is rejected
+func (ser *CurrencyServiceImpl) checkCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(currency)
+	err := ser.validate.Var(currency, "iso4217")
+	if err != nil {
+		return "", err
+	}
+	if currency == ser.config.Store.Currency {
+		return "", ErrStoreCurrencyRate
+	}
+	return currency, nil
+}
+
+// rebase converts the feed's rates to rates per unit of the store's currency,
+// unknown currency codes and non-positive rates are skipped
+func (ser *CurrencyServiceImpl) rebase(feed *dto.ExchangeRateFeed) ([]*model.ExchangeRate, error) {
+	rates := make(map[string]float64, len(feed.Rates)+1)
+	for code, rate := range feed.Rates {
+		rates[strings.ToUpper(code)] = rate
+	}
+
+	storeRate := 1.0
+	base := strings.ToUpper(feed.Base)
+	if base != ser.config.Store.Currency {
+		storeRate = rates[ser.config.Store.Currency]
+		if storeRate <= 0 {
+			return nil, fmt.Errorf("exchange rate feed has no rate for the store's currency %s", ser.config.Store.Currency)
+		}
+		rates[base] = 1
+	}
+
+	result := []*model.ExchangeRate{}
+	for code, rate := range rates {
+		if rate <= 0 {
+			continue
+		}
+		code, err := ser.checkCurrency(code)
+		if err != nil {
+			continue
+		}
+		result = append(result, &model.ExchangeRate{
+			Currency: code,
+			Rate:     rate / storeRate,
+		})
+	}
+	slices.SortFunc(result, func(a, b *model.ExchangeRate) int {
+		return cmp.Compare(a.Currency, b.Currency)
+	})
+	return result, nil
+}
+
+// readExchangeRateFeed reads the feed from an http(s) url or a local file
+func readExchangeRateFeed(source string) (*dto.ExchangeRateFeed, error) {
+	var reader io.Reader
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: exchangeRateFeedTimeout}
+		response, err := client.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("feed responded with status %d", response.StatusCode)
+		}
+		reader = response.Body
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var result dto.ExchangeRateFeed
+	err := json.NewDecoder(reader).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrListingNotFound    = errors.New("listing not found")
+	ErrOwnListing         = errors.New("can't buy your own listing")
+	ErrListingUnavailable = errors.New("listing was changed or doesn't have enough copies left")
+)
+
+type ListingService interface {
+	// Offers returns the store's own offer of the card and its marketplace listings, converted to the currency
+	Offers(cardId uint, currency string) (*dto.CardOffers, error)
+	ById(id uint, currency string) (*dto.GetListing, error)
+	// BySeller returns all of the seller's listings, including the ones without copies left
+	BySeller(sellerId uint) []*dto.GetListing
+	// Create lists copies of a catalogue printing, only verified users can sell
+	Create(listing *dto.PostListing, sellerId uint) (*dto.GetListing, error)
+	Update(id uint, patch *dto.PatchListing, sellerId uint) (*dto.GetListing, error)
+	Delete(id uint, sellerId uint) error
+	// Buy orders copies of another seller's listing at its current price, the store keeps the commission
+	// and records the rest as the seller's sale
+	Buy(id uint, purchase *dto.PostListingPurchase, buyerId uint) (*dto.GetOrder, error)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type ListingServiceImpl struct {
+	config      *config.Configuration
+	listingRepo repository.ListingRepository
+	cardRepo    repository.CardRepository
+	userRepo    repository.UserRepository
+	payoutRepo  repository.PayoutRepository
+	converter   *CurrencyConverter
+	validate    *validator.Validate
+}
+
+func NewListingServiceImpl(config *config.Configuration, listingRepo repository.ListingRepository, cardRepo repository.CardRepository, userRepo repository.UserRepository, payoutRepo repository.PayoutRepository, converter *CurrencyConverter, validate *validator.Validate) *ListingServiceImpl {
+	return &ListingServiceImpl{
+		config:      config,
+		listingRepo: listingRepo,
+		cardRepo:    cardRepo,
+		userRepo:    userRepo,
+		payoutRepo:  payoutRepo,
+		converter:   converter,
+		validate:    validate,
+	}
+}
+
+func (ser *ListingServiceImpl) Offers(cardId uint, currency string) (*dto.CardOffers, error) {
+	conversion, err := ser.converter.To(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	card := ser.cardRepo.FindById(cardId)
+	if card == nil {
+		return nil, ErrCardNotFound
+	}
+
+	result := &dto.CardOffers{
+		Card:     conversion.Card(card),
+		Listings: utility.MapSlice(ser.listingRepo.FindOffers(cardId), conversion.Listing),
+	}
+	// the listings are sorted by price
+	if len(result.Listings) > 0 {
+		result.Cheapest = result.Listings[0]
+	}
+	result.StoreIsCheapest = card.InStockAmount > 0 &&
+		(result.Cheapest == nil || result.Card.Price <= result.Cheapest.Price)
+	return result, nil
+}
+
+func (ser *ListingServiceImpl) ById(id uint, currency string) (*dto.GetListing, error) {
+	conversion, err := ser.converter.To(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := ser.listingRepo.FindById(id)
+	if listing == nil {
+		return nil, ErrListingNotFound
+	}
+	return conversion.Listing(listing), nil
+}
+
+func (ser *ListingServiceImpl) BySeller(sellerId uint) []*dto.GetListing {
+	return utility.MapSlice(
+		ser.listingRepo.FindBySeller(sellerId),
+		ser.converter.Store().Listing,
+	)
+}
+
+func (ser *ListingServiceImpl) Create(newListing *dto.PostListing, sellerId uint) (*dto.GetListing, error) {
+	err := ser.validate.Struct(newListing)
+	if err != nil {
+		return nil, err
+	}
+
+	seller := ser.userRepo.FindById(sellerId)
+	if seller == nil {
+		return nil, ErrUserNotFound
+	}
+	if !seller.Verified {
+		return nil, ErrNotVerified
+	}
+	if ser.cardRepo.FindById(newListing.CardId) == nil {
+		return nil, ErrCardNotFound
+	}
+
+	result := newListing.ToListing(sellerId)
+	err = ser.listingRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	result.Seller = *seller
+	return ser.converter.Store().Listing(result), nil
+}
+
+func (ser *ListingServiceImpl) Update(id uint, patch *dto.PatchListing, sellerId uint) (*dto.GetListing, error) {
+	err := ser.validate.Struct(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	listing, err := ser.sellersListing(id, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the patched fields are saved, sales and withdrawals can change the others meanwhile
+	fields := []string{}
+	if patch.Price != nil {
+		listing.Price = *patch.Price
+		fields = append(fields, "Price")
+	}
+	if patch.Condition != nil {
+		listing.Condition = model.CardCondition(*patch.Condition)
+		fields = append(fields, "Condition")
+	}
+	if patch.Amount != nil {
+		listing.Amount = *patch.Amount
+		fields = append(fields, "Amount")
+	}
+	if patch.Comment != nil {
+		listing.Comment = *patch.Comment
+		fields = append(fields, "Comment")
+	}
+
+	err = ser.listingRepo.Update(listing, fields...)
+	if err != nil {
+		return nil, err
+	}
+	updated := ser.listingRepo.FindById(id)
+	if updated == nil {
+		return nil, ErrListingNotFound
+	}
+	return ser.converter.Store().Listing(updated), nil
+}
+
+func (ser *ListingServiceImpl) Delete(id uint, sellerId uint) error {
+	listing, err := ser.sellersListing(id, sellerId)
+	if err != nil {
+		return err
+	}
+	return ser.listingRepo.Delete(listing.ID)
+}
+
+func (ser *ListingServiceImpl) Buy(id uint, purchase *dto.PostListingPurchase, buyerId uint) (*dto.GetOrder, error) {
+	err := ser.validate.Struct(purchase)
+	if err != nil {
+		return nil, err
+	}
+
+	buyer := ser.userRepo.FindById(buyerId)
+	if buyer == nil {
+		return nil, ErrUserNotFound
+	}
+	if !buyer.Verified {
+		return nil, ErrNotVerified
+	}
+	listing := ser.listingRepo.FindById(id)
+	if listing == nil {
+		return nil, ErrListingNotFound
+	}
+	if listing.SellerID == buyerId {
+		return nil, ErrOwnListing
+	}
+	// deleted sellers can't ship the copies and suspended ones mustn't sell
+	seller := ser.userRepo.FindById(listing.SellerID)
+	if seller == nil || seller.IsSuspended(time.Now()) {
+		return nil, ErrListingUnavailable
+	}
+	card := ser.cardRepo.FindById(listing.CardID)
+	if card == nil || listing.Amount < purchase.Amount {
+		return nil, ErrListingUnavailable
+	}
+
+	// sellers ship their own copies, so the store's shipping and promotions don't apply
+	total := listing.Price.Times(purchase.Amount)
+	order := &model.Order{
+		UserID:   buyerId,
+		Subtotal: total,
+		Total:    total,
+		Lines: []model.OrderLine{{
+			CardID:    card.ID,
+			Name:      card.Name,
+			UnitPrice: listing.Price,
+			Amount:    purchase.Amount,
+			LineTotal: total,
+			ListingID: &listing.ID,
+			SellerID:  &listing.SellerID,
+		}},
+	}
+	percentage := commissionPercentage(ser.config, ser.payoutRepo, listing.SellerID)
+	commission := total.Scale(float64(percentage) / 100)
+	sale := &model.SellerSale{
+		SellerID:             listing.SellerID,
+		Gross:                total,
+		CommissionPercentage: percentage,
+		Commission:           commission,
+		Net:                  total - commission,
+	}
+
+	err = ser.listingRepo.Sell(listing, order, sale)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingUnavailable) {
+			return nil, ErrListingUnavailable
+		}
+		return nil, err
+	}
+	return dto.NewGetOrder(order), nil
+}
+
+// sellersListing finds the listing if it belongs to the seller, other sellers' listings can't be told apart from missing ones
+func (ser *ListingServiceImpl) sellersListing(id uint, sellerId uint) (*model.Listing, error) {
+	listing := ser.listingRepo.FindById(id)
+	if listing == nil || listing.SellerID != sellerId {
+		return nil, ErrListingNotFound
+	}
+	return listing, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"store.api/mail"
+	"store.api/model"
+	"store.api/repository"
+)
+
+// LowStockNotifier mails the admins when a sale or stock update makes a card's stock
+// fall to its low stock threshold, so it can be reordered
+type LowStockNotifier struct {
+	expansionRepo repository.ExpansionRepository
+	userRepo      repository.UserRepository
+	mailer        mail.Mailer
+}
+
+func NewLowStockNotifier(expansionRepo repository.ExpansionRepository, userRepo repository.UserRepository, mailer mail.Mailer) *LowStockNotifier {
+	return &LowStockNotifier{
+		expansionRepo: expansionRepo,
+		userRepo:      userRepo,
+		mailer:        mailer,
+	}
+}
+
+func (n *LowStockNotifier) StockChanged(card *model.Card, oldAmount uint) {
+	if card.InStockAmount >= oldAmount {
+		return
+	}
+
+	// cards that were already low were notified when they crossed the threshold
+	threshold := card.StockThreshold(n.expansion(card.ExpansionID))
+	if threshold == nil || card.InStockAmount > *threshold || oldAmount <= *threshold {
+		return
+	}
+
+	message := fmt.Sprintf("%s (%s) is running out, %d left with a threshold of %d.", card.Name, card.ExpansionID, card.InStockAmount, *threshold)
+	if card.InStockAmount == 0 {
+		message = fmt.Sprintf("%s (%s) is out of stock.", card.Name, card.ExpansionID)
+	}
+	for _, admin := range n.userRepo.FindAdmins() {
+		if admin.IsSuspended(time.Now()) || len(admin.Email) == 0 {
+			continue
+		}
+		n.notify(admin, message)
+	}
+}
+
+func (n *LowStockNotifier) PriceChanged(card *model.Card, oldPrice model.Money) {
+}
+
+// expansion finds the expansion among the cached ones, the card's own may be cached from before its default changed
+func (n *LowStockNotifier) expansion(id string) *model.Expansion {
+	for _, expansion := range n.expansionRepo.All() {
+		if expansion.ID == id {
+			return expansion
+		}
+	}
+	return nil
+}
+
+// notify mails the admin, failures are only logged so they don't fail the stock update
+func (n *LowStockNotifier) notify(admin *model.User, message string) {
+	err := n.mailer.Send(
+		admin.Email,
+		"Low stock",
+		fmt.Sprintf("Hello %s,\n\na card should be reordered: %s", admin.Username, message),
+	)
+	if err != nil {
+		log.Printf("failed to send low stock notification to user %d: %v", admin.ID, err)
+	}
+}
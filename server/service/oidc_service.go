@@ -0,0 +1,19 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrOidcDisabled     = errors.New("OIDC login is disabled")
+	ErrOidcInvalidState = errors.New("unknown or expired OIDC login state")
+)
+
+type OidcService interface {
+	// AuthorizationUrl starts a login, the state identifies it in the provider's callback
+	AuthorizationUrl() (url string, state string, err error)
+	Callback(code string, state string) (*dto.PrivateUserInfo, error)
+	CompleteTwoFactor(details *dto.OidcTwoFactor) (*dto.PrivateUserInfo, error)
+}
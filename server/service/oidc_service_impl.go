@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"store.api/cache"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/security"
+)
+
+const oidcFlowTtl = 10 * time.Minute
+
+var nonUsernameChars = regexp.MustCompile("[^a-zA-Z0-9_.-]")
+
+type OidcServiceImpl struct {
+	config   *config.Configuration
+	provider *security.OidcProvider
+
+	userRepo repository.UserRepository
+	cartRepo repository.CartRepository
+	flows    cache.OidcFlowCache
+}
+
+func NewOidcServiceImpl(config *config.Configuration, provider *security.OidcProvider, userRepo repository.UserRepository, cartRepo repository.CartRepository, flows cache.OidcFlowCache) *OidcServiceImpl {
+	return &OidcServiceImpl{
+		config:   config,
+		provider: provider,
+
+		userRepo: userRepo,
+		cartRepo: cartRepo,
+		flows:    flows,
+	}
+}
+
+func (s *OidcServiceImpl) AuthorizationUrl() (string, string, error) {
+	if !s.config.Oidc.Enabled {
+		return "", "", ErrOidcDisabled
+	}
+
+	state, err := security.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := security.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := security.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	result, err := s.provider.AuthCodeUrl(state, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	s.flows.Remember(state, &model.OidcFlow{
+		Verifier: verifier,
+		Nonce:    nonce,
+	}, oidcFlowTtl)
+
+	return result, state, nil
+}
+
+func (s *OidcServiceImpl) Callback(code string, state string) (*dto.PrivateUserInfo, error) {
+	if !s.config.Oidc.Enabled {
+		return nil, ErrOidcDisabled
+	}
+
+	flow := s.flows.Take(state)
+	if flow == nil || flow.UserID != 0 {
+		return nil, ErrOidcInvalidState
+	}
+
+	claims, err := s.provider.Exchange(code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.linkOrCreate(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkOidcUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled {
+		// the state can be used once more to enter the code
+		s.flows.Remember(state, &model.OidcFlow{
+			UserID: user.ID,
+		}, oidcFlowTtl)
+		return nil, ErrTwoFactorRequired
+	}
+
+	return dto.NewPrivateUserInfo(user), nil
+}
+
+func (s *OidcServiceImpl) CompleteTwoFactor(details *dto.OidcTwoFactor) (*dto.PrivateUserInfo, error) {
+	if !s.config.Oidc.Enabled {
+		return nil, ErrOidcDisabled
+	}
+
+	// the flow is taken even if the code is wrong so codes can't be guessed with the same state
+	flow := s.flows.Take(details.State)
+	if flow == nil || flow.UserID == 0 {
+		return nil, ErrOidcInvalidState
+	}
+
+	user := s.userRepo.FindById(flow.UserID)
+	if user == nil || !user.TwoFactorEnabled {
+		return nil, ErrOidcInvalidState
+	}
+
+	err := checkOidcUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := checkTwoFactorCode(s.userRepo, s.config.AuthKey, user, details.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return dto.NewPrivateUserInfo(user), nil
+}
+
+// checkOidcUser applies the same account checks as the password login
+func checkOidcUser(user *model.User) error {
+	if user.IsSuspended(time.Now()) {
+		return ErrUserSuspended
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
+
+// linkOrCreate finds the user linked to the identity, links the identity to an existing verified user with the same
+// verified email or registers a new user
+func (s *OidcServiceImpl) linkOrCreate(claims *security.OidcClaims) (*model.User, error) {
+	issuer := s.provider.Issuer()
+	subject := claims.Subject
+
+	existing := s.userRepo.FindByOidcSubject(issuer, subject)
+	if existing != nil {
+		return existing, nil
+	}
+
+	if claims.EmailVerified && len(claims.Email) > 0 {
+		existing = s.userRepo.FindByEmail(claims.Email)
+		// an unverified local account may have been registered by someone else with the victim's email
+		if existing != nil && existing.Verified && existing.OidcSubject == nil {
+			existing.OidcIssuer = &issuer
+			existing.OidcSubject = &subject
+			err := s.userRepo.Update(existing)
+			if err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	// users created through OIDC have no password and can only log in through the provider
+	newUser := &model.User{
+		Username:    s.freeUsername(claims),
+		Email:       claims.Email,
+		Verified:    claims.EmailVerified,
+		OidcIssuer:  &issuer,
+		OidcSubject: &subject,
+	}
+	err := s.userRepo.Save(newUser)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cartRepo.Save(&model.Cart{
+		UserID: newUser.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newUser, nil
+}
+
+func (s *OidcServiceImpl) freeUsername(claims *security.OidcClaims) string {
+	base := claims.PreferredUsername
+	if len(base) == 0 {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = nonUsernameChars.ReplaceAllString(base, "")
+	if len(base) < 4 {
+		base = "user" + base
+	}
+	if len(base) > 16 {
+		base = base[:16]
+	}
+
+	result := base
+	for i := 1; s.userRepo.FindByUsername(result) != nil; i++ {
+		result = fmt.Sprintf("%s%d", base, i)
+	}
+	return result
+}
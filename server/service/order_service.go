@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrOrderNotFound = errors.New("order not found")
+)
+
+type OrderService interface {
+	All(userId uint) []*dto.GetOrder
+	// ById only returns the user's own orders
+	ById(userId uint, id uint) (*dto.GetOrder, error)
+}
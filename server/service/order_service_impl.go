@@ -0,0 +1,32 @@
+package service
+
+import (
+	"store.api/dto"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type OrderServiceImpl struct {
+	orderRepo repository.OrderRepository
+}
+
+func NewOrderServiceImpl(orderRepo repository.OrderRepository) *OrderServiceImpl {
+	return &OrderServiceImpl{
+		orderRepo: orderRepo,
+	}
+}
+
+func (ser *OrderServiceImpl) All(userId uint) []*dto.GetOrder {
+	return utility.MapSlice(
+		ser.orderRepo.FindByUserId(userId),
+		dto.NewGetOrder,
+	)
+}
+
+func (ser *OrderServiceImpl) ById(userId uint, id uint) (*dto.GetOrder, error) {
+	order := ser.orderRepo.FindById(id)
+	if order == nil || order.UserID != userId {
+		return nil, ErrOrderNotFound
+	}
+	return dto.NewGetOrder(order), nil
+}
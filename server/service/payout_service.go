@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrInvalidPeriod     = errors.New("a period can't end before it starts")
+	ErrNothingToPay      = errors.New("the seller has no unpaid sales in the period")
+	ErrSalesUnreconciled = errors.New("the seller's payouts in the period don't add up to the sales they paid")
+)
+
+type PayoutService interface {
+	// Statement sums up the seller's sales in the period and reconciles them against the order lines
+	Statement(sellerId uint, period *dto.Period) (*dto.PayoutStatement, error)
+	// Payouts returns the seller's payouts, the newest first
+	Payouts(sellerId uint) []*dto.GetPayout
+	// Pay records that the admin paid out the seller's unpaid sales in the period, the period has to be reconciled
+	Pay(payout *dto.PostPayout, adminId uint) (*dto.GetPayout, error)
+	Commission(sellerId uint) (*dto.GetCommission, error)
+	UpdateCommission(sellerId uint, update *dto.CommissionUpdate) (*dto.GetCommission, error)
+}
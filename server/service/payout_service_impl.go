@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type PayoutServiceImpl struct {
+	config     *config.Configuration
+	payoutRepo repository.PayoutRepository
+	userRepo   repository.UserRepository
+	validate   *validator.Validate
+}
+
+func NewPayoutServiceImpl(config *config.Configuration, payoutRepo repository.PayoutRepository, userRepo repository.UserRepository, validate *validator.Validate) *PayoutServiceImpl {
+	return &PayoutServiceImpl{
+		config:     config,
+		payoutRepo: payoutRepo,
+		userRepo:   userRepo,
+		validate:   validate,
+	}
+}
+
+func (ser *PayoutServiceImpl) Statement(sellerId uint, period *dto.Period) (*dto.PayoutStatement, error) {
+	err := ser.validate.Struct(period)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := periodBounds(period.From, period.To, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if ser.userRepo.FindById(sellerId) == nil {
+		return nil, ErrUserNotFound
+	}
+
+	result := &dto.PayoutStatement{
+		SellerId: sellerId,
+		From:     from.Format(time.DateOnly),
+		To:       to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Sales:    utility.MapSlice(ser.payoutRepo.FindSales(sellerId, from, to), dto.NewGetSellerSale),
+	}
+	for _, sale := range result.Sales {
+		result.Gross += sale.Gross
+		result.Commission += sale.Commission
+		result.Net += sale.Net
+		if sale.PayoutId != nil {
+			result.Paid += sale.Net
+		} else {
+			result.Unpaid += sale.Net
+		}
+	}
+
+	reconciliation := ser.payoutRepo.Reconcile(sellerId, from, to)
+	result.Payouts = reconciliation.Payouts
+	result.UnreconciledPayouts = reconciliation.Mismatched
+	result.Reconciled = reconciled(reconciliation)
+	return result, nil
+}
+
+func (ser *PayoutServiceImpl) Payouts(sellerId uint) []*dto.GetPayout {
+	return utility.MapSlice(
+		ser.payoutRepo.FindBySeller(sellerId),
+		dto.NewGetPayout,
+	)
+}
+
+func (ser *PayoutServiceImpl) Pay(payout *dto.PostPayout, adminId uint) (*dto.GetPayout, error) {
+	err := ser.validate.Struct(payout)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := periodBounds(payout.From, payout.To, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if ser.userRepo.FindById(payout.SellerId) == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// sellers are only paid for periods in which every earlier payout is accounted for
+	if !reconciled(ser.payoutRepo.Reconcile(payout.SellerId, from, to)) {
+		return nil, ErrSalesUnreconciled
+	}
+
+	result := &model.Payout{
+		SellerID:    payout.SellerId,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Reference:   payout.Reference,
+		PaidByID:    adminId,
+	}
+	err = ser.payoutRepo.Pay(result)
+	if err != nil {
+		if errors.Is(err, repository.ErrNothingToPay) {
+			return nil, ErrNothingToPay
+		}
+		return nil, err
+	}
+	return dto.NewGetPayout(result), nil
+}
+
+func (ser *PayoutServiceImpl) Commission(sellerId uint) (*dto.GetCommission, error) {
+	if ser.userRepo.FindById(sellerId) == nil {
+		return nil, ErrUserNotFound
+	}
+
+	commission := ser.payoutRepo.FindCommission(sellerId)
+	if commission == nil {
+		return &dto.GetCommission{
+			SellerId:   sellerId,
+			Percentage: ser.config.Store.CommissionPercentage,
+			Default:    true,
+		}, nil
+	}
+	return &dto.GetCommission{
+		SellerId:   sellerId,
+		Percentage: commission.Percentage,
+	}, nil
+}
+
+func (ser *PayoutServiceImpl) UpdateCommission(sellerId uint, update *dto.CommissionUpdate) (*dto.GetCommission, error) {
+	err := ser.validate.Struct(update)
+	if err != nil {
+		return nil, err
+	}
+	if ser.userRepo.FindById(sellerId) == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// sales that were already made keep the percentage they were made at
+	if update.Percentage == nil {
+		err = ser.payoutRepo.DeleteCommission(sellerId)
+	} else {
+		err = ser.payoutRepo.SaveCommission(&model.SellerCommission{
+			SellerID:   sellerId,
+			Percentage: *update.Percentage,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ser.Commission(sellerId)
+}
+
+// commissionPercentage returns the seller's own commission percentage or the store's
+func commissionPercentage(config *config.Configuration, payoutRepo repository.PayoutRepository, sellerId uint) float32 {
+	commission := payoutRepo.FindCommission(sellerId)
+	if commission == nil {
+		return config.Store.CommissionPercentage
+	}
+	return commission.Percentage
+}
+
+// periodBounds turns the inclusive days of a period into its start and exclusive end,
+// it starts on the first of the month and ends today if omitted
+func periodBounds(from string, to string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, 1-today.Day())
+	end := today
+	var err error
+	if from != "" {
+		start, err = time.Parse(time.DateOnly, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if to != "" {
+		end, err = time.Parse(time.DateOnly, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, ErrInvalidPeriod
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// reconciled checks that every payout adds up to the sales it paid
+func reconciled(r *repository.SalesReconciliation) bool {
+	return len(r.Mismatched) == 0
+}
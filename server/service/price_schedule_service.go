@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"store.api/dto"
+)
+
+var (
+	ErrScheduledPriceNotFound = errors.New("scheduled price change not found")
+	ErrScheduledPriceWindow   = errors.New("sales window has to end after it starts and in the future")
+	ErrScheduledPriceOverlap  = errors.New("card already has a price change scheduled in that window")
+	ErrScheduledPriceDone     = errors.New("scheduled price change is already done")
+	ErrScheduledPriceApplied  = errors.New("scheduled price change was applied while cancelling it")
+)
+
+type PriceScheduleService interface {
+	// Pending returns the changes that are pending or in an active sales window, of all cards if cardId is 0
+	Pending(cardId uint) []*dto.GetScheduledPrice
+	ById(id uint) (*dto.GetScheduledPrice, error)
+	Schedule(change *dto.PostScheduledPrice) (*dto.GetScheduledPrice, error)
+	// Cancel cancels a pending change, an active sales window is ended right away by restoring the previous price
+	Cancel(id uint) error
+	// Run restores the prices of ended sales windows and applies the changes that are due. Changes that failed
+	// are left as they were to be retried by the next run
+	Run(now time.Time) error
+}
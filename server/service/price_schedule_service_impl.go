@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type PriceScheduleServiceImpl struct {
+	scheduleRepo repository.ScheduledPriceRepository
+	cardRepo     repository.CardRepository
+	validate     *validator.Validate
+}
+
+func NewPriceScheduleServiceImpl(scheduleRepo repository.ScheduledPriceRepository, cardRepo repository.CardRepository, validate *validator.Validate) *PriceScheduleServiceImpl {
+	return &PriceScheduleServiceImpl{
+		scheduleRepo: scheduleRepo,
+		cardRepo:     cardRepo,
+		validate:     validate,
+	}
+}
+
+func (ser *PriceScheduleServiceImpl) Pending(cardId uint) []*dto.GetScheduledPrice {
+	return utility.MapSlice(
+		ser.scheduleRepo.FindPending(cardId),
+		dto.NewGetScheduledPrice,
+	)
+}
+
+func (ser *PriceScheduleServiceImpl) ById(id uint) (*dto.GetScheduledPrice, error) {
+	change := ser.scheduleRepo.FindById(id)
+	if change == nil {
+		return nil, ErrScheduledPriceNotFound
+	}
+	return dto.NewGetScheduledPrice(change), nil
+}
+
+func (ser *PriceScheduleServiceImpl) Schedule(newChange *dto.PostScheduledPrice) (*dto.GetScheduledPrice, error) {
+	err := ser.validate.Struct(newChange)
+	if err != nil {
+		return nil, err
+	}
+	if newChange.EndsAt != nil && (!newChange.EndsAt.After(newChange.StartsAt) || !newChange.EndsAt.After(time.Now())) {
+		return nil, ErrScheduledPriceWindow
+	}
+	if ser.cardRepo.FindById(newChange.CardId) == nil {
+		return nil, ErrCardNotFound
+	}
+
+	result := &model.ScheduledPriceChange{
+		CardID:   newChange.CardId,
+		Price:    newChange.Price,
+		StartsAt: newChange.StartsAt,
+		EndsAt:   newChange.EndsAt,
+		Status:   model.ScheduledPricePending,
+	}
+	// restoring the price after overlapping windows would depend on the order they end in
+	for _, existing := range ser.scheduleRepo.FindPending(newChange.CardId) {
+		if existing.Overlaps(result) {
+			return nil, ErrScheduledPriceOverlap
+		}
+	}
+
+	err = ser.scheduleRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetScheduledPrice(result), nil
+}
+
+func (ser *PriceScheduleServiceImpl) Cancel(id uint) error {
+	change := ser.scheduleRepo.FindById(id)
+	if change == nil {
+		return ErrScheduledPriceNotFound
+	}
+
+	switch change.Status {
+	case model.ScheduledPricePending:
+		change.Status = model.ScheduledPriceCancelled
+		claimed, err := ser.scheduleRepo.Transition(change, model.ScheduledPricePending)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return ErrScheduledPriceApplied
+		}
+		return nil
+	case model.ScheduledPriceActive:
+		return ser.restore(change)
+	default:
+		return ErrScheduledPriceDone
+	}
+}
+
+func (ser *PriceScheduleServiceImpl) Run(now time.Time) error {
+	var errs []error
+	// ended windows first, so a window starting right as another one ends remembers the restored price
+	for _, change := range ser.scheduleRepo.FindEnded(now) {
+		if err := ser.restore(change); err != nil {
+			errs = append(errs, fmt.Errorf("failed to end scheduled price change %d: %w", change.ID, err))
+		}
+	}
+	for _, change := range ser.scheduleRepo.FindDue(now) {
+		if err := ser.apply(change, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply scheduled price change %d: %w", change.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply sets the card's new price, changes whose window passed while they were pending are skipped.
+// The change is claimed before the price is set so only one instance applies it
+func (ser *PriceScheduleServiceImpl) apply(change *model.ScheduledPriceChange, now time.Time) error {
+	card := ser.cardRepo.FindById(change.CardID)
+	if card == nil || (change.EndsAt != nil && !now.Before(*change.EndsAt)) {
+		change.Status = model.ScheduledPriceDone
+		_, err := ser.scheduleRepo.Transition(change, model.ScheduledPricePending)
+		return err
+	}
+
+	previous := card.Price
+	change.PreviousPrice = &previous
+	change.Status = model.ScheduledPriceDone
+	if change.EndsAt != nil {
+		change.Status = model.ScheduledPriceActive
+	}
+	claimed, err := ser.scheduleRepo.Transition(change, model.ScheduledPricePending)
+	if err != nil || !claimed {
+		return err
+	}
+
+	_, err = ser.cardRepo.UpdatePrice(change.CardID, change.Price)
+	if err != nil {
+		// released so the next run retries it
+		claimedStatus := change.Status
+		change.Status = model.ScheduledPricePending
+		change.PreviousPrice = nil
+		_, releaseErr := ser.scheduleRepo.Transition(change, claimedStatus)
+		return errors.Join(err, releaseErr)
+	}
+	return nil
+}
+
+// restore ends the sales window by setting the price the card had before it
+func (ser *PriceScheduleServiceImpl) restore(change *model.ScheduledPriceChange) error {
+	change.Status = model.ScheduledPriceDone
+	claimed, err := ser.scheduleRepo.Transition(change, model.ScheduledPriceActive)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if change.PreviousPrice != nil {
+		_, err = ser.cardRepo.UpdatePrice(change.CardID, *change.PreviousPrice)
+		if err != nil {
+			change.Status = model.ScheduledPriceActive
+			_, releaseErr := ser.scheduleRepo.Transition(change, model.ScheduledPriceDone)
+			return errors.Join(err, releaseErr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrPromotionCodeTaken = errors.New("promotion code is already used")
+	ErrPromotionWindow    = errors.New("promotion has to end after it starts")
+)
+
+type PromotionService interface {
+	All() []*dto.GetPromotion
+	ById(id uint) (*dto.GetPromotion, error)
+	Create(promotion *dto.PostPromotion) (*dto.GetPromotion, error)
+	// Update replaces the promotion, past redemptions are kept
+	Update(id uint, promotion *dto.PostPromotion) (*dto.GetPromotion, error)
+	Delete(id uint) error
+	Redemptions(id uint) ([]*dto.GetPromotionRedemption, error)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type PromotionServiceImpl struct {
+	promotionRepo repository.PromotionRepository
+	validate      *validator.Validate
+}
+
+func NewPromotionServiceImpl(promotionRepo repository.PromotionRepository, validate *validator.Validate) *PromotionServiceImpl {
+	return &PromotionServiceImpl{
+		promotionRepo: promotionRepo,
+		validate:      validate,
+	}
+}
+
+func (ser *PromotionServiceImpl) All() []*dto.GetPromotion {
+	return utility.MapSlice(
+		ser.promotionRepo.FindAll(),
+		ser.toGetPromotion,
+	)
+}
+
+func (ser *PromotionServiceImpl) ById(id uint) (*dto.GetPromotion, error) {
+	promotion := ser.promotionRepo.FindById(id)
+	if promotion == nil {
+		return nil, ErrPromotionNotFound
+	}
+	return ser.toGetPromotion(promotion), nil
+}
+
+func (ser *PromotionServiceImpl) Create(newPromotion *dto.PostPromotion) (*dto.GetPromotion, error) {
+	err := ser.checkPromotion(0, newPromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newPromotion.ToPromotion()
+	err = ser.promotionRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return ser.toGetPromotion(result), nil
+}
+
+func (ser *PromotionServiceImpl) Update(id uint, newPromotion *dto.PostPromotion) (*dto.GetPromotion, error) {
+	existing := ser.promotionRepo.FindById(id)
+	if existing == nil {
+		return nil, ErrPromotionNotFound
+	}
+
+	err := ser.checkPromotion(id, newPromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newPromotion.ToPromotion()
+	result.Model = existing.Model
+	err = ser.promotionRepo.Update(result)
+	if err != nil {
+		return nil, err
+	}
+	return ser.toGetPromotion(result), nil
+}
+
+func (ser *PromotionServiceImpl) Delete(id uint) error {
+	if ser.promotionRepo.FindById(id) == nil {
+		return ErrPromotionNotFound
+	}
+	return ser.promotionRepo.Delete(id)
+}
+
+func (ser *PromotionServiceImpl) Redemptions(id uint) ([]*dto.GetPromotionRedemption, error) {
+	if ser.promotionRepo.FindById(id) == nil {
+		return nil, ErrPromotionNotFound
+	}
+	return utility.MapSlice(
+		ser.promotionRepo.FindRedemptions(id),
+		dto.NewGetPromotionRedemption,
+	), nil
+}
+
+// checkPromotion validates the promotion and makes sure its code isn't used by another promotion than id
+func (ser *PromotionServiceImpl) checkPromotion(id uint, promotion *dto.PostPromotion) error {
+	err := ser.validate.Struct(promotion)
+	if err != nil {
+		return err
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return ErrPromotionWindow
+	}
+
+	if promotion.Code != nil {
+		existing := ser.promotionRepo.FindByCode(*promotion.Code)
+		if existing != nil && existing.ID != id {
+			return ErrPromotionCodeTaken
+		}
+	}
+	return nil
+}
+
+func (ser *PromotionServiceImpl) toGetPromotion(promotion *model.Promotion) *dto.GetPromotion {
+	return dto.NewGetPromotion(promotion, ser.promotionRepo.CountRedemptions(promotion.ID))
+}
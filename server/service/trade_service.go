@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrTradeNotFound    = errors.New("trade not found")
+	ErrTradeClosed      = errors.New("trade is no longer open")
+	ErrTradeUnavailable = errors.New("the collections don't have enough of the traded cards")
+	ErrTradeInvalid     = errors.New("a trade needs at least one card and can list each card once per side")
+	ErrTradeWithSelf    = errors.New("can't trade with yourself")
+	ErrTradeForbidden   = errors.New("only the recipient can answer a trade and only the proposer can cancel it")
+)
+
+type TradeService interface {
+	// Mine returns the trades the user proposed or received, newest first
+	Mine(userId uint) []*dto.GetTrade
+	// ById returns the trade if the user is part of it
+	ById(id uint, userId uint) (*dto.GetTrade, error)
+	Propose(trade *dto.PostTrade, userId uint) (*dto.GetTrade, error)
+	// Counter replaces a trade the user received with a new one in which they're the proposer
+	Counter(id uint, counter *dto.PostCounterTrade, userId uint) (*dto.GetTrade, error)
+	// Accept exchanges the cards of a trade the user received, all at once or not at all
+	Accept(id uint, userId uint) (*dto.GetTrade, error)
+	Decline(id uint, userId uint) (*dto.GetTrade, error)
+	// Cancel withdraws a trade the user proposed
+	Cancel(id uint, userId uint) (*dto.GetTrade, error)
+}
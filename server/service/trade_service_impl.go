@@ -0,0 +1,263 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type TradeServiceImpl struct {
+	tradeRepo      repository.TradeRepository
+	collectionRepo repository.CollectionRepository
+	userRepo       repository.UserRepository
+	validate       *validator.Validate
+}
+
+func NewTradeServiceImpl(tradeRepo repository.TradeRepository, collectionRepo repository.CollectionRepository, userRepo repository.UserRepository, validate *validator.Validate) *TradeServiceImpl {
+	return &TradeServiceImpl{
+		tradeRepo:      tradeRepo,
+		collectionRepo: collectionRepo,
+		userRepo:       userRepo,
+		validate:       validate,
+	}
+}
+
+func (ser *TradeServiceImpl) Mine(userId uint) []*dto.GetTrade {
+	return utility.MapSlice(
+		ser.tradeRepo.FindByUserId(userId),
+		dto.NewGetTrade,
+	)
+}
+
+func (ser *TradeServiceImpl) ById(id uint, userId uint) (*dto.GetTrade, error) {
+	trade, err := ser.usersTrade(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetTrade(trade), nil
+}
+
+func (ser *TradeServiceImpl) Propose(newTrade *dto.PostTrade, userId uint) (*dto.GetTrade, error) {
+	err := ser.validate.Struct(newTrade)
+	if err != nil {
+		return nil, err
+	}
+	err = ser.checkVerified(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	own := ser.collectionRepo.FindById(newTrade.CollectionId)
+	if own == nil || own.OwnerID != userId {
+		return nil, ErrCollectionNotFound
+	}
+	// other users' collections can only be traded for if they're public
+	other := ser.collectionRepo.FindById(newTrade.RecipientCollectionId)
+	if other == nil || other.Visibility != model.VisibilityPublic {
+		return nil, ErrCollectionNotFound
+	}
+	if other.OwnerID == userId {
+		return nil, ErrTradeWithSelf
+	}
+
+	result := &model.Trade{
+		ProposerID:            userId,
+		ProposerCollectionID:  own.ID,
+		RecipientID:           other.OwnerID,
+		RecipientCollectionID: other.ID,
+		Status:                model.TradeProposed,
+		Message:               newTrade.Message,
+	}
+	result.Lines, err = tradeLines(own, newTrade.Offered, other, newTrade.Requested)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ser.tradeRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetTrade(ser.tradeRepo.FindById(result.ID)), nil
+}
+
+func (ser *TradeServiceImpl) Counter(id uint, counter *dto.PostCounterTrade, userId uint) (*dto.GetTrade, error) {
+	err := ser.validate.Struct(counter)
+	if err != nil {
+		return nil, err
+	}
+	err = ser.checkVerified(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	trade, err := ser.receivedTrade(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the proposer's collection can be private, it's already part of the negotiation
+	own := ser.collectionRepo.FindById(trade.RecipientCollectionID)
+	other := ser.collectionRepo.FindById(trade.ProposerCollectionID)
+	if own == nil || other == nil {
+		return nil, ErrTradeUnavailable
+	}
+
+	result := &model.Trade{
+		ProposerID:            userId,
+		ProposerCollectionID:  own.ID,
+		RecipientID:           trade.ProposerID,
+		RecipientCollectionID: other.ID,
+		Status:                model.TradeProposed,
+		Message:               counter.Message,
+	}
+	result.Lines, err = tradeLines(own, counter.Offered, other, counter.Requested)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ser.tradeRepo.Counter(trade, result)
+	if err != nil {
+		return nil, tradeError(err)
+	}
+	return dto.NewGetTrade(ser.tradeRepo.FindById(result.ID)), nil
+}
+
+func (ser *TradeServiceImpl) Accept(id uint, userId uint) (*dto.GetTrade, error) {
+	err := ser.checkVerified(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	trade, err := ser.receivedTrade(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ser.tradeRepo.Accept(trade)
+	if err != nil {
+		return nil, tradeError(err)
+	}
+	return dto.NewGetTrade(trade), nil
+}
+
+func (ser *TradeServiceImpl) Decline(id uint, userId uint) (*dto.GetTrade, error) {
+	trade, err := ser.receivedTrade(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ser.tradeRepo.Close(trade, model.TradeDeclined)
+	if err != nil {
+		return nil, tradeError(err)
+	}
+	return dto.NewGetTrade(trade), nil
+}
+
+func (ser *TradeServiceImpl) Cancel(id uint, userId uint) (*dto.GetTrade, error) {
+	trade, err := ser.usersTrade(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if trade.ProposerID != userId {
+		return nil, ErrTradeForbidden
+	}
+	if !trade.Open() {
+		return nil, ErrTradeClosed
+	}
+
+	err = ser.tradeRepo.Close(trade, model.TradeCancelled)
+	if err != nil {
+		return nil, tradeError(err)
+	}
+	return dto.NewGetTrade(trade), nil
+}
+
+func (ser *TradeServiceImpl) checkVerified(userId uint) error {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !user.Verified {
+		return ErrNotVerified
+	}
+	return nil
+}
+
+// usersTrade finds the trade if the user is part of it, other users' trades can't be told apart from missing ones
+func (ser *TradeServiceImpl) usersTrade(id uint, userId uint) (*model.Trade, error) {
+	trade := ser.tradeRepo.FindById(id)
+	if trade == nil || (trade.ProposerID != userId && trade.RecipientID != userId) {
+		return nil, ErrTradeNotFound
+	}
+	return trade, nil
+}
+
+// receivedTrade finds the open trade the user can answer
+func (ser *TradeServiceImpl) receivedTrade(id uint, userId uint) (*model.Trade, error) {
+	trade, err := ser.usersTrade(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if trade.RecipientID != userId {
+		return nil, ErrTradeForbidden
+	}
+	if !trade.Open() {
+		return nil, ErrTradeClosed
+	}
+	return trade, nil
+}
+
+// tradeLines checks that the collections have the cards of each side and turns them into the trade's lines
+func tradeLines(own *model.Collection, offered []dto.TradeCard, other *model.Collection, requested []dto.TradeCard) ([]model.TradeLine, error) {
+	if len(offered)+len(requested) == 0 {
+		return nil, ErrTradeInvalid
+	}
+
+	result := make([]model.TradeLine, 0, len(offered)+len(requested))
+	for _, side := range []struct {
+		collection *model.Collection
+		cards      []dto.TradeCard
+		offered    bool
+	}{
+		{own, offered, true},
+		{other, requested, false},
+	} {
+		amounts := map[uint]uint{}
+		for _, slot := range side.collection.Cards {
+			amounts[slot.CardID] += slot.Amount
+		}
+
+		listed := map[uint]bool{}
+		for _, card := range side.cards {
+			if listed[card.CardId] {
+				return nil, ErrTradeInvalid
+			}
+			listed[card.CardId] = true
+
+			if amounts[card.CardId] < card.Amount {
+				return nil, ErrTradeUnavailable
+			}
+			result = append(result, model.TradeLine{
+				CardID:  card.CardId,
+				Amount:  card.Amount,
+				Offered: side.offered,
+			})
+		}
+	}
+	return result, nil
+}
+
+// tradeError translates the repository's errors of closed trades and missing cards
+func tradeError(err error) error {
+	if errors.Is(err, repository.ErrTradeClosed) {
+		return ErrTradeClosed
+	}
+	if errors.Is(err, repository.ErrTradeUnavailable) {
+		return ErrTradeUnavailable
+	}
+	return err
+}
@@ -1,7 +1,43 @@
 package service
 
-import "store.api/dto"
+import (
+	"errors"
+	"time"
+
+	"store.api/dto"
+	"store.api/query"
+)
+
+var (
+	ErrUnknownLanguage   = errors.New("unknown language")
+	ErrEmailTaken        = errors.New("email is already used by another account")
+	ErrEmailUnchanged    = errors.New("email is unchanged")
+	ErrNoPendingEmail    = errors.New("no pending email change")
+	ErrInvalidEmailToken = errors.New("invalid or expired email verification token")
+	ErrIncorrectPassword = errors.New("incorrect password")
+	ErrLoginTooOld       = errors.New("log in again to confirm")
+	ErrCannotModifySelf  = errors.New("admins can't suspend or demote themselves")
+	ErrSuspensionEnded   = errors.New("suspension end is in the past")
+)
+
+type UserQueryResult struct {
+	Users      []*dto.GetUser `json:"users"`
+	TotalCount int64          `json:"totalUsers"`
+	PerPage    uint           `json:"perPage"`
+}
 
 type UserService interface {
 	ById(id uint) (*dto.PrivateUserInfo, error)
+	UpdateProfile(userId uint, profile *dto.PatchProfile) (*dto.PrivateUserInfo, error)
+	RequestEmailChange(userId uint, change *dto.EmailChange) error
+	VerifyEmail(userId uint, verification *dto.EmailVerification) (*dto.PrivateUserInfo, error)
+	Export(userId uint) (*dto.UserExport, error)
+	// Delete confirms the deletion with the password, or with a recent login for users without one
+	Delete(userId uint, deletion *dto.AccountDeletion, loggedInAt time.Time) error
+	Query(query *query.UserQuery) *UserQueryResult
+	Verify(userId uint) (*dto.GetUser, error)
+	Suspend(adminId uint, userId uint, suspension *dto.Suspension) (*dto.GetUser, error)
+	Unsuspend(userId uint) (*dto.GetUser, error)
+	SetAdmin(adminId uint, userId uint, isAdmin bool) (*dto.GetUser, error)
+	ForcePasswordReset(userId uint) (*dto.GetUser, error)
 }
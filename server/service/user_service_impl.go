@@ -1,17 +1,47 @@
 package service
 
 import (
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"store.api/config"
 	"store.api/dto"
+	"store.api/mail"
+	"store.api/model"
+	"store.api/query"
 	"store.api/repository"
+	"store.api/security"
+	"store.api/utility"
 )
 
+// how recent the login of a user without a password has to be to delete the account
+const deletionLoginAge = 5 * time.Minute
+
 type UserServiceImpl struct {
-	userRepo repository.UserRepository
+	config       *config.Configuration
+	userRepo     repository.UserRepository
+	colRepo      repository.CollectionRepository
+	cartRepo     repository.CartRepository
+	wishlistRepo repository.WishlistRepository
+	orderRepo    repository.OrderRepository
+	langRepo     repository.LanguageRepository
+	mailer       mail.Mailer
+	validate     *validator.Validate
 }
 
-func NewUserServiceImpl(userRepo repository.UserRepository) *UserServiceImpl {
+func NewUserServiceImpl(config *config.Configuration, userRepo repository.UserRepository, colRepo repository.CollectionRepository, cartRepo repository.CartRepository, wishlistRepo repository.WishlistRepository, orderRepo repository.OrderRepository, langRepo repository.LanguageRepository, mailer mail.Mailer, validate *validator.Validate) *UserServiceImpl {
 	return &UserServiceImpl{
-		userRepo: userRepo,
+		config:       config,
+		userRepo:     userRepo,
+		colRepo:      colRepo,
+		cartRepo:     cartRepo,
+		wishlistRepo: wishlistRepo,
+		orderRepo:    orderRepo,
+		langRepo:     langRepo,
+		mailer:       mailer,
+		validate:     validate,
 	}
 }
 
@@ -23,3 +53,278 @@ func (ser *UserServiceImpl) ById(id uint) (*dto.PrivateUserInfo, error) {
 
 	return dto.NewPrivateUserInfo(user), nil
 }
+
+func (ser *UserServiceImpl) UpdateProfile(userId uint, profile *dto.PatchProfile) (*dto.PrivateUserInfo, error) {
+	err := ser.validate.Struct(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if profile.DisplayName != nil {
+		user.DisplayName = *profile.DisplayName
+	}
+	if profile.PreferredCurrency != nil {
+		user.PreferredCurrency = *profile.PreferredCurrency
+	}
+	if profile.PreferredLanguageId != nil {
+		if len(*profile.PreferredLanguageId) == 0 {
+			user.PreferredLanguageID = nil
+		} else {
+			if !ser.languageExists(*profile.PreferredLanguageId) {
+				return nil, ErrUnknownLanguage
+			}
+			user.PreferredLanguageID = profile.PreferredLanguageId
+		}
+	}
+
+	err = ser.userRepo.Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPrivateUserInfo(user), nil
+}
+
+func (ser *UserServiceImpl) languageExists(id string) bool {
+	for _, lang := range ser.langRepo.All() {
+		if lang.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestEmailChange sends a verification token to the new email, the current email
+// is kept until the token is confirmed
+func (ser *UserServiceImpl) RequestEmailChange(userId uint, change *dto.EmailChange) error {
+	err := ser.validate.Struct(change)
+	if err != nil {
+		return err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if change.Email == user.Email && user.Verified {
+		return ErrEmailUnchanged
+	}
+
+	existing := ser.userRepo.FindByEmail(change.Email)
+	if existing != nil && existing.ID != user.ID && existing.Verified {
+		return ErrEmailTaken
+	}
+
+	token, err := security.RandomToken()
+	if err != nil {
+		return err
+	}
+	expires := time.Now().Add(time.Duration(ser.config.Account.EmailTokenTtl) * time.Second)
+
+	user.PendingEmail = change.Email
+	user.EmailTokenHash = security.HashToken(token)
+	user.EmailTokenExpiresAt = &expires
+	err = ser.userRepo.Update(user)
+	if err != nil {
+		return err
+	}
+
+	return ser.mailer.Send(
+		change.Email,
+		"Verify your email",
+		fmt.Sprintf("Hello %s,\n\nyour email verification token is %s\nIt expires at %s.", user.Username, token, expires.Format(time.RFC1123)),
+	)
+}
+
+func (ser *UserServiceImpl) VerifyEmail(userId uint, verification *dto.EmailVerification) (*dto.PrivateUserInfo, error) {
+	err := ser.validate.Struct(verification)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if len(user.PendingEmail) == 0 || len(user.EmailTokenHash) == 0 {
+		return nil, ErrNoPendingEmail
+	}
+	if user.EmailTokenExpiresAt == nil || time.Now().After(*user.EmailTokenExpiresAt) {
+		return nil, ErrInvalidEmailToken
+	}
+	if subtle.ConstantTimeCompare([]byte(security.HashToken(verification.Token)), []byte(user.EmailTokenHash)) != 1 {
+		return nil, ErrInvalidEmailToken
+	}
+
+	// the address could have been verified by another account since the token was sent
+	existing := ser.userRepo.FindByEmail(user.PendingEmail)
+	if existing != nil && existing.ID != user.ID && existing.Verified {
+		return nil, ErrEmailTaken
+	}
+
+	user.Email = user.PendingEmail
+	user.Verified = true
+	user.PendingEmail = ""
+	user.EmailTokenHash = ""
+	user.EmailTokenExpiresAt = nil
+	err = ser.userRepo.Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewPrivateUserInfo(user), nil
+}
+
+func (ser *UserServiceImpl) Export(userId uint) (*dto.UserExport, error) {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// collections fetched by owner don't include their slots
+	collections := make([]*dto.GetCollection, 0)
+	for _, col := range ser.colRepo.FindByOwnerId(userId) {
+		full := ser.colRepo.FindById(col.ID)
+		if full == nil {
+			continue
+		}
+		collections = append(collections, dto.NewGetCollection(full))
+	}
+
+	return &dto.UserExport{
+		Profile:     dto.NewPrivateUserInfo(user),
+		Collections: collections,
+		Cart:        dto.NewGetCart(ser.cartRepo.FindSingleByUserId(userId)),
+		Wishlist:    utility.MapSlice(ser.wishlistRepo.FindByUserId(userId), dto.NewGetWishlist),
+		Orders:      utility.MapSlice(ser.orderRepo.FindByUserId(userId), dto.NewGetOrder),
+	}, nil
+}
+
+// Delete removes the user's collections and cart and anonymises the account, orders are kept for bookkeeping
+func (ser *UserServiceImpl) Delete(userId uint, deletion *dto.AccountDeletion, loggedInAt time.Time) error {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if len(user.PasswordHash) > 0 {
+		if !security.CheckPasswordHash(deletion.Password, user.PasswordHash) {
+			return ErrIncorrectPassword
+		}
+	} else if time.Since(loggedInAt) > deletionLoginAge {
+		// users without a password confirm by logging in through the identity provider again
+		return ErrLoginTooOld
+	}
+
+	return ser.userRepo.Anonymise(user)
+}
+
+func (ser *UserServiceImpl) Query(query *query.UserQuery) *UserQueryResult {
+	users, count := ser.userRepo.Query(query)
+	return &UserQueryResult{
+		Users: utility.MapSlice(
+			users,
+			func(u *model.User) *dto.GetUser { return dto.NewGetUser(u) },
+		),
+		TotalCount: count,
+		PerPage:    ser.config.Db.Users.PageSize,
+	}
+}
+
+func (ser *UserServiceImpl) Verify(userId uint) (*dto.GetUser, error) {
+	return ser.updateUser(userId, func(user *model.User) {
+		user.Verified = true
+	})
+}
+
+func (ser *UserServiceImpl) Suspend(adminId uint, userId uint, suspension *dto.Suspension) (*dto.GetUser, error) {
+	err := ser.validate.Struct(suspension)
+	if err != nil {
+		return nil, err
+	}
+	if adminId == userId {
+		return nil, ErrCannotModifySelf
+	}
+	if suspension.Until != nil && suspension.Until.Before(time.Now()) {
+		return nil, ErrSuspensionEnded
+	}
+
+	return ser.updateUser(userId, func(user *model.User) {
+		user.Suspended = true
+		user.SuspendedUntil = suspension.Until
+		user.SuspensionReason = suspension.Reason
+	})
+}
+
+func (ser *UserServiceImpl) Unsuspend(userId uint) (*dto.GetUser, error) {
+	return ser.updateUser(userId, func(user *model.User) {
+		user.Suspended = false
+		user.SuspendedUntil = nil
+		user.SuspensionReason = ""
+	})
+}
+
+func (ser *UserServiceImpl) SetAdmin(adminId uint, userId uint, isAdmin bool) (*dto.GetUser, error) {
+	if adminId == userId && !isAdmin {
+		return nil, ErrCannotModifySelf
+	}
+
+	return ser.updateUser(userId, func(user *model.User) {
+		user.IsAdmin = isAdmin
+	})
+}
+
+// ForcePasswordReset blocks password logins for the user until the password is reset
+// with the token sent to the user's email
+func (ser *UserServiceImpl) ForcePasswordReset(userId uint) (*dto.GetUser, error) {
+	token, err := security.RandomToken()
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(time.Duration(ser.config.Account.PasswordResetTokenTtl) * time.Second)
+
+	var email, username string
+	result, err := ser.updateUser(userId, func(user *model.User) {
+		user.PasswordResetRequired = true
+		user.PasswordResetTokenHash = security.HashToken(token)
+		user.PasswordResetExpiresAt = &expires
+		email = user.Email
+		username = user.Username
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = ser.mailer.Send(
+		email,
+		"Reset your password",
+		fmt.Sprintf("Hello %s,\n\nan administrator requested a password reset for your account, your reset token is %s\nIt expires at %s.", username, token, expires.Format(time.RFC1123)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (ser *UserServiceImpl) updateUser(userId uint, change func(*model.User)) (*dto.GetUser, error) {
+	user := ser.userRepo.FindById(userId)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	change(user)
+	err := ser.userRepo.Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewGetUser(user), nil
+}
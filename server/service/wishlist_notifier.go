@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"store.api/mail"
+	"store.api/model"
+	"store.api/repository"
+)
+
+// WishlistNotifier mails users when a card on their wishlist comes back in stock
+// or its price drops to the price they want to pay
+type WishlistNotifier struct {
+	wishlistRepo repository.WishlistRepository
+	userRepo     repository.UserRepository
+	mailer       mail.Mailer
+}
+
+func NewWishlistNotifier(wishlistRepo repository.WishlistRepository, userRepo repository.UserRepository, mailer mail.Mailer) *WishlistNotifier {
+	return &WishlistNotifier{
+		wishlistRepo: wishlistRepo,
+		userRepo:     userRepo,
+		mailer:       mailer,
+	}
+}
+
+func (n *WishlistNotifier) StockChanged(card *model.Card, oldAmount uint) {
+	if oldAmount > 0 || card.InStockAmount == 0 {
+		return
+	}
+
+	for _, wishlist := range n.wishlistRepo.FindForCard(card) {
+		if !wishlist.Matches(card) {
+			continue
+		}
+		n.notify(wishlist, "Back in stock", fmt.Sprintf("%s is back in stock for %s.", card.Name, card.Price))
+	}
+}
+
+func (n *WishlistNotifier) PriceChanged(card *model.Card, oldPrice model.Money) {
+	// restocks are notified separately
+	if card.InStockAmount == 0 || card.Price >= oldPrice {
+		return
+	}
+
+	for _, wishlist := range n.wishlistRepo.FindForCard(card) {
+		// users without a target price would be notified of every price drop
+		if wishlist.MaxPrice == nil || oldPrice <= *wishlist.MaxPrice || !wishlist.Matches(card) {
+			continue
+		}
+		n.notify(wishlist, "Price drop", fmt.Sprintf("%s dropped from %s to %s.", card.Name, oldPrice, card.Price))
+	}
+}
+
+// notify mails the owner of the wishlist, failures are only logged so they don't fail the card update
+func (n *WishlistNotifier) notify(wishlist *model.Wishlist, subject string, message string) {
+	user := n.userRepo.FindById(wishlist.UserID)
+	if user == nil || len(user.Email) == 0 {
+		return
+	}
+
+	err := n.mailer.Send(
+		user.Email,
+		subject,
+		fmt.Sprintf("Hello %s,\n\na card on your wishlist is available: %s", user.Username, message),
+	)
+	if err != nil {
+		log.Printf("failed to send wishlist notification to user %d: %v", user.ID, err)
+	}
+}
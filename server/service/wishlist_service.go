@@ -0,0 +1,20 @@
+package service
+
+import (
+	"errors"
+
+	"store.api/dto"
+)
+
+var (
+	ErrWishlistNotFound = errors.New("wishlist not found")
+	ErrCardKeyNotFound  = errors.New("card key not found")
+	ErrAlreadyWished    = errors.New("card is already on the wishlist")
+)
+
+type WishlistService interface {
+	All(userId uint) []*dto.GetWishlist
+	Add(userId uint, wishlist *dto.PostWishlist) (*dto.GetWishlist, error)
+	Update(userId uint, id uint, wishlist *dto.PatchWishlist) (*dto.GetWishlist, error)
+	Delete(userId uint, id uint) error
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/utility"
+)
+
+type WishlistServiceImpl struct {
+	wishlistRepo repository.WishlistRepository
+	cardRepo     repository.CardRepository
+	cardKeyRepo  repository.CardKeyRepository
+	validate     *validator.Validate
+}
+
+func NewWishlistServiceImpl(wishlistRepo repository.WishlistRepository, cardRepo repository.CardRepository, cardKeyRepo repository.CardKeyRepository, validate *validator.Validate) *WishlistServiceImpl {
+	return &WishlistServiceImpl{
+		wishlistRepo: wishlistRepo,
+		cardRepo:     cardRepo,
+		cardKeyRepo:  cardKeyRepo,
+		validate:     validate,
+	}
+}
+
+func (ser *WishlistServiceImpl) All(userId uint) []*dto.GetWishlist {
+	return utility.MapSlice(
+		ser.wishlistRepo.FindByUserId(userId),
+		dto.NewGetWishlist,
+	)
+}
+
+func (ser *WishlistServiceImpl) Add(userId uint, wishlist *dto.PostWishlist) (*dto.GetWishlist, error) {
+	err := ser.validate.Struct(wishlist)
+	if err != nil {
+		return nil, err
+	}
+
+	if wishlist.CardId != nil && ser.cardRepo.FindById(*wishlist.CardId) == nil {
+		return nil, ErrCardNotFound
+	}
+	if wishlist.CardKeyId != nil && ser.cardKeyRepo.FindById(*wishlist.CardKeyId) == nil {
+		return nil, ErrCardKeyNotFound
+	}
+
+	for _, existing := range ser.wishlistRepo.FindByUserId(userId) {
+		if equalPtr(existing.CardID, wishlist.CardId) && equalPtr(existing.CardKeyID, wishlist.CardKeyId) {
+			return nil, ErrAlreadyWished
+		}
+	}
+
+	result := wishlist.ToWishlist(userId)
+	err = ser.wishlistRepo.Save(result)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetWishlist(result), nil
+}
+
+func (ser *WishlistServiceImpl) Update(userId uint, id uint, patch *dto.PatchWishlist) (*dto.GetWishlist, error) {
+	err := ser.validate.Struct(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist, err := ser.getById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.MaxPrice != nil {
+		wishlist.MaxPrice = patch.MaxPrice
+		if *patch.MaxPrice == 0 {
+			wishlist.MaxPrice = nil
+		}
+	}
+	if patch.MinCondition != nil {
+		wishlist.MinCondition = model.CardCondition(*patch.MinCondition)
+	}
+
+	err = ser.wishlistRepo.Update(wishlist)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewGetWishlist(wishlist), nil
+}
+
+func (ser *WishlistServiceImpl) Delete(userId uint, id uint) error {
+	_, err := ser.getById(userId, id)
+	if err != nil {
+		return err
+	}
+	return ser.wishlistRepo.Delete(id)
+}
+
+func (ser *WishlistServiceImpl) getById(userId uint, id uint) (*model.Wishlist, error) {
+	result := ser.wishlistRepo.FindById(id)
+	if result == nil || result.UserID != userId {
+		return nil, ErrWishlistNotFound
+	}
+	return result, nil
+}
+
+func equalPtr[T comparable](a *T, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,192 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/query"
+	"store.api/service"
+)
+
+func newAdminController(authService service.AuthService) *controller.AdminController {
+	return newAdminControllerWithUsers(authService, newMockUserService())
+}
+
+func newAdminControllerWithUsers(authService service.AuthService, userService service.UserService) *controller.AdminController {
+	return controller.NewAdminController(
+		authService,
+		userService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_Admin_ShouldUnlock(t *testing.T) {
+	// arrange
+	authService := newMockAuthService()
+	controller := newAdminController(authService)
+	authService.On("UnlockUser", uint(2)).Return(nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.Unlock(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Admin_ShouldNotUnlockUserNotFound(t *testing.T) {
+	// arrange
+	authService := newMockAuthService()
+	controller := newAdminController(authService)
+	authService.On("UnlockUser", uint(2)).Return(service.ErrUserNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.Unlock(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Admin_ShouldNotUnlockInvalidId(t *testing.T) {
+	// arrange
+	authService := newMockAuthService()
+	controller := newAdminController(authService)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "user")
+
+	// act
+	controller.Unlock(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Admin_ShouldFetchFailedLogins(t *testing.T) {
+	// arrange
+	authService := newMockAuthService()
+	controller := newAdminController(authService)
+	authService.On("FailedLogins", mock.Anything).Return([]*dto.GetFailedLogin{})
+	c, w := createTestContext(nil)
+
+	// act
+	controller.FailedLogins(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Admin_ShouldQueryUsers(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	controller := newAdminControllerWithUsers(newMockAuthService(), userService)
+	userService.On("Query", mock.MatchedBy(func(q *query.UserQuery) bool {
+		return q.Search == "user" && q.Page == 2
+	})).Return(&service.UserQueryResult{})
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "search=user&page=2"
+
+	// act
+	controller.QueryUsers(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	userService.AssertExpectations(t)
+}
+
+func Test_Admin_ShouldNotQueryUsersInvalidPage(t *testing.T) {
+	// arrange
+	controller := newAdminController(newMockAuthService())
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "page=0"
+
+	// act
+	controller.QueryUsers(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Admin_ShouldVerify(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	controller := newAdminControllerWithUsers(newMockAuthService(), userService)
+	userService.On("Verify", uint(2)).Return(&dto.GetUser{}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.Verify(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Admin_ShouldSuspend(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	controller := newAdminControllerWithUsers(newMockAuthService(), userService)
+	userService.On("Suspend", uint(1), uint(2), mock.Anything).Return(&dto.GetUser{}, nil)
+	c, w := createTestContext(&dto.Suspension{Reason: "spam"})
+	c.AddParam("id", "2")
+
+	// act
+	controller.Suspend(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Admin_ShouldNotSuspendUserNotFound(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	controller := newAdminControllerWithUsers(newMockAuthService(), userService)
+	userService.On("Suspend", uint(1), uint(2), mock.Anything).Return(nil, service.ErrUserNotFound)
+	c, w := createTestContext(&dto.Suspension{Reason: "spam"})
+	c.AddParam("id", "2")
+
+	// act
+	controller.Suspend(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Admin_ShouldNotDemoteSelf(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	controller := newAdminControllerWithUsers(newMockAuthService(), userService)
+	userService.On("SetAdmin", uint(1), uint(1), false).Return(nil, service.ErrCannotModifySelf)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "1")
+
+	// act
+	controller.Demote(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Admin_ShouldForcePasswordReset(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	controller := newAdminControllerWithUsers(newMockAuthService(), userService)
+	userService.On("ForcePasswordReset", uint(2)).Return(&dto.GetUser{PasswordReset: true}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.ForcePasswordReset(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
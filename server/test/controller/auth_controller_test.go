@@ -2,8 +2,10 @@ package controller_test
 
 import (
 	"errors"
+	"net/http"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"store.api/auth"
@@ -11,15 +13,33 @@ import (
 	"store.api/controller"
 	"store.api/dto"
 	"store.api/repository"
+	"store.api/security"
 	"store.api/service"
 )
 
 func newAuthController(service service.AuthService, repo repository.UserRepository) *controller.AuthController {
+	return newAuthControllerWithOidc(service, newMockOidcService(), repo)
+}
+
+func newAuthControllerWithOidc(service service.AuthService, oidcService service.OidcService, repo repository.UserRepository) *controller.AuthController {
+	return newAuthControllerWithCart(service, oidcService, newMockCartService(), repo)
+}
+
+func newAuthControllerWithCart(service service.AuthService, oidcService service.OidcService, cartService service.CartService, repo repository.UserRepository) *controller.AuthController {
+	config := &config.Configuration{
+		AuthKey: "test secret key",
+	}
+	middleware := auth.NewJwtMiddleware(config, service, cartService, repo, auth.NewGuestCartCookie(config))
 	return controller.NewAuthController(
 		service,
-		auth.NewJwtMiddleware(&config.Configuration{
-			AuthKey: "test secret key",
-		}, service, repo).Middle.LoginHandler,
+		oidcService,
+		auth.NewOidcStateCookie(config),
+		middleware.Middle.LoginHandler,
+		middleware.IssueToken,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
 	)
 }
 
@@ -102,6 +122,65 @@ func Test_Auth_ShouldLogin(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 }
 
+func Test_Auth_ShouldLoginAndMergeGuestCart(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	service := newMockAuthService()
+	cartService := newMockCartService()
+	controller := newAuthControllerWithCart(service, newMockOidcService(), cartService, repo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	service.On("Login", mock.Anything).Return(&dto.PrivateUserInfo{
+		Id:       "1",
+		Username: "user",
+	}, nil)
+	cartService.On("MergeGuest", uint(1), "guest").Return(nil)
+
+	c, w := createTestContext(data)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "guestCart",
+		Value: security.SignValue("test secret key", "guest"),
+	})
+
+	// act
+	controller.Login(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cartService.AssertExpectations(t)
+}
+
+func Test_Auth_ShouldLoginIgnoringForgedGuestCart(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	service := newMockAuthService()
+	cartService := newMockCartService()
+	controller := newAuthControllerWithCart(service, newMockOidcService(), cartService, repo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	service.On("Login", mock.Anything).Return(&dto.PrivateUserInfo{
+		Id:       "1",
+		Username: "user",
+	}, nil)
+
+	c, w := createTestContext(data)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "guestCart",
+		Value: security.SignValue("other key", "guest"),
+	})
+
+	// act
+	controller.Login(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cartService.AssertNotCalled(t, "MergeGuest", mock.Anything, mock.Anything)
+}
+
 func Test_Auth_ShouldNotLogin(t *testing.T) {
 	// arrange
 	repo := newMockUserRepository()
@@ -121,3 +200,271 @@ func Test_Auth_ShouldNotLogin(t *testing.T) {
 	// assert
 	assert.Equal(t, 401, w.Code)
 }
+
+func Test_Auth_ShouldNotLoginTwoFactorRequired(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	s := newMockAuthService()
+	controller := newAuthController(s, repo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	s.On("Login", mock.Anything).Return(nil, service.ErrTwoFactorRequired)
+
+	c, w := createTestContext(data)
+
+	// act
+	controller.Login(c)
+
+	// assert
+	assert.Equal(t, 401, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrTwoFactorRequired.Error())
+}
+
+func Test_Auth_ShouldEnrollTwoFactor(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	service := newMockAuthService()
+	controller := newAuthController(service, repo)
+	service.On("EnrollTwoFactor", uint(1)).Return(&dto.TwoFactorEnrollment{}, nil)
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.EnrollTwoFactor(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Auth_ShouldNotConfirmTwoFactorInvalidCode(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	s := newMockAuthService()
+	controller := newAuthController(s, repo)
+	s.On("ConfirmTwoFactor", uint(1), mock.Anything).Return(service.ErrInvalidTwoFactorCode)
+
+	c, w := createTestContext(dto.TwoFactorCode{Code: "123456"})
+
+	// act
+	controller.ConfirmTwoFactor(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Auth_ShouldRedirectToOidcProvider(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("AuthorizationUrl").Return("http://issuer/authorize?state=s", "s", nil)
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.OidcLogin(c)
+
+	// assert
+	assert.Equal(t, 302, c.Writer.Status())
+	assert.Equal(t, "http://issuer/authorize?state=s", w.Header().Get("Location"))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "oidcState="+security.SignValue("test secret key", "s"))
+}
+
+func Test_Auth_ShouldNotRedirectOidcDisabled(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("AuthorizationUrl").Return("", "", service.ErrOidcDisabled)
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.OidcLogin(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Auth_ShouldLoginWithOidc(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("Callback", "code", "state").Return(&dto.PrivateUserInfo{Id: "1"}, nil)
+
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "code=code&state=state"
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "state"),
+	})
+
+	// act
+	controller.OidcCallback(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "token")
+}
+
+func Test_Auth_ShouldNotLoginWithOidcFromOtherBrowser(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "code=code&state=state"
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "other"),
+	})
+
+	// act
+	controller.OidcCallback(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	oidcService.AssertNotCalled(t, "Callback", mock.Anything, mock.Anything)
+}
+
+func Test_Auth_ShouldNotLoginWithOidcInvalidState(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("Callback", "code", "state").Return(nil, service.ErrOidcInvalidState)
+
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "code=code&state=state"
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "state"),
+	})
+
+	// act
+	controller.OidcCallback(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Auth_ShouldRequireTwoFactorWithOidc(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("Callback", "code", "state").Return(nil, service.ErrTwoFactorRequired)
+
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "code=code&state=state"
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "state"),
+	})
+
+	// act
+	controller.OidcCallback(c)
+
+	// assert
+	assert.Equal(t, 401, w.Code)
+	assert.NotContains(t, w.Body.String(), "token")
+}
+
+func Test_Auth_ShouldNotLoginWithOidcSuspended(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("Callback", "code", "state").Return(nil, service.ErrUserSuspended)
+
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "code=code&state=state"
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "state"),
+	})
+
+	// act
+	controller.OidcCallback(c)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_Auth_ShouldLoginWithOidcTwoFactor(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("CompleteTwoFactor", &dto.OidcTwoFactor{State: "state", Code: "123456"}).Return(&dto.PrivateUserInfo{Id: "1"}, nil)
+
+	c, w := createTestContext(dto.OidcTwoFactor{State: "state", Code: "123456"})
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "state"),
+	})
+
+	// act
+	controller.OidcTwoFactor(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "token")
+}
+
+func Test_Auth_ShouldNotLoginWithOidcWrongTwoFactorCode(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	oidcService := newMockOidcService()
+	controller := newAuthControllerWithOidc(newMockAuthService(), oidcService, repo)
+	oidcService.On("CompleteTwoFactor", mock.Anything).Return(nil, service.ErrInvalidTwoFactorCode)
+
+	c, w := createTestContext(dto.OidcTwoFactor{State: "state", Code: "123456"})
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "oidcState",
+		Value: security.SignValue("test secret key", "state"),
+	})
+
+	// act
+	controller.OidcTwoFactor(c)
+
+	// assert
+	assert.Equal(t, 401, w.Code)
+}
+
+func Test_Auth_ShouldResetPassword(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	service := newMockAuthService()
+	controller := newAuthController(service, repo)
+	service.On("ResetPassword", mock.Anything).Return(nil)
+
+	c, w := createTestContext(dto.PasswordReset{Token: "token", Password: "password"})
+
+	// act
+	controller.ResetPassword(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Auth_ShouldNotLoginSuspended(t *testing.T) {
+	// arrange
+	repo := newMockUserRepository()
+	s := newMockAuthService()
+	controller := newAuthController(s, repo)
+	s.On("Login", mock.Anything).Return(nil, service.ErrUserSuspended)
+
+	c, w := createTestContext(dto.LoginDetails{Username: "user", Password: "password"})
+
+	// act
+	controller.Login(c)
+
+	// assert
+	assert.Equal(t, 401, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrUserSuspended.Error())
+}
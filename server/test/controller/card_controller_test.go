@@ -2,6 +2,7 @@ package controller_test
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"store.api/controller"
 	"store.api/dto"
 	"store.api/model"
+	"store.api/query"
 	"store.api/service"
 )
 
@@ -112,7 +114,7 @@ func Test_Card_ShouldFetchById(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("GetById", mock.Anything).Return(&dto.GetCard{}, nil)
+	service.On("GetById", mock.Anything, mock.Anything).Return(&dto.GetCard{}, nil)
 	c, w := createTestContext(nil)
 	c.AddParam("id", "12")
 
@@ -127,7 +129,7 @@ func Test_Card_ShouldNotFetchById(t *testing.T) {
 	// arrange
 	s := newMockCardService()
 	controller := newCardController(s)
-	s.On("GetById", mock.Anything).Return(nil, service.ErrCardNotFound)
+	s.On("GetById", mock.Anything, mock.Anything).Return(nil, service.ErrCardNotFound)
 	c, w := createTestContext(nil)
 	c.AddParam("id", "1")
 
@@ -138,11 +140,59 @@ func Test_Card_ShouldNotFetchById(t *testing.T) {
 	assert.Equal(t, 404, w.Code)
 }
 
+func Test_Card_ShouldFetchByIdInCurrency(t *testing.T) {
+	// arrange
+	s := newMockCardService()
+	controller := newCardController(s)
+	s.On("GetById", uint(12), "EUR").Return(&dto.GetCard{Currency: "EUR"}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.Request.URL, _ = url.Parse("?currency=EUR")
+
+	// act
+	controller.ById(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	s.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotFetchByIdInUnknownCurrency(t *testing.T) {
+	// arrange
+	s := newMockCardService()
+	controller := newCardController(s)
+	s.On("GetById", uint(12), "XYZ").Return(nil, service.ErrUnknownCurrency)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.Request.URL, _ = url.Parse("?currency=XYZ")
+
+	// act
+	controller.ById(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Card_ShouldNotQueryInUnknownCurrency(t *testing.T) {
+	// arrange
+	s := newMockCardService()
+	controller := newCardController(s)
+	s.On("Query", mock.Anything).Return(nil, service.ErrUnknownCurrency)
+	c, w := createTestContext(nil)
+	c.Request.URL, _ = url.Parse("?minPrice=30&currency=XYZ")
+
+	// act
+	controller.Query(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
 func Test_Card_ShouldFetchByType(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("Query", mock.Anything).Return([]*dto.GetCard{})
+	service.On("Query", mock.Anything).Return([]*dto.GetCard{}, nil)
 	c, w := createTestContext(nil)
 	c.Request.URL, _ = url.Parse("?type=CT1")
 
@@ -157,7 +207,7 @@ func Test_Card_ShouldFetchByName(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("Query", mock.Anything).Return([]*dto.GetCard{})
+	service.On("Query", mock.Anything).Return([]*dto.GetCard{}, nil)
 	c, w := createTestContext(nil)
 	c.Request.URL, _ = url.Parse("?name=card")
 
@@ -172,7 +222,7 @@ func Test_Card_ShouldFetchByMinPrice(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("Query", mock.Anything).Return([]*dto.GetCard{})
+	service.On("Query", mock.Anything).Return([]*dto.GetCard{}, nil)
 	c, w := createTestContext(nil)
 	c.Request.URL, _ = url.Parse("?minPrice=30")
 
@@ -187,7 +237,7 @@ func Test_Card_ShouldFetchByMaxPrice(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("Query", mock.Anything).Return([]*dto.GetCard{})
+	service.On("Query", mock.Anything).Return([]*dto.GetCard{}, nil)
 	c, w := createTestContext(nil)
 	c.Request.URL, _ = url.Parse("?maxPrice=400")
 
@@ -202,7 +252,7 @@ func Test_Card_ShouldUpdate(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("Update", mock.Anything, mock.Anything).Return(&dto.GetCard{}, nil)
+	service.On("Update", mock.Anything, mock.Anything, uint(1)).Return(&dto.GetCard{}, nil)
 	data := dto.PostCard{
 		Name:     "card name",
 		Text:     "card text",
@@ -224,7 +274,7 @@ func Test_Card_ShouldNotUpdateCollectionNotFound(t *testing.T) {
 	// arrange
 	s := newMockCardService()
 	controller := newCardController(s)
-	s.On("Update", mock.Anything, mock.Anything).Return(nil, service.ErrCardNotFound)
+	s.On("Update", mock.Anything, mock.Anything, uint(1)).Return(nil, service.ErrCardNotFound)
 	data := dto.PostCard{
 		Name:     "card name",
 		Text:     "card text",
@@ -246,7 +296,7 @@ func Test_Card_ShouldNotUpdateBadRequest(t *testing.T) {
 	// arrange
 	s := newMockCardService()
 	controller := newCardController(s)
-	s.On("Update", mock.Anything, mock.Anything).Return(nil, errors.New(""))
+	s.On("Update", mock.Anything, mock.Anything, uint(1)).Return(nil, errors.New(""))
 	data := dto.PostCard{
 		Name:     "card name",
 		Text:     "card text",
@@ -322,7 +372,7 @@ func Test_Card_ShouldUpdateInStockAmount(t *testing.T) {
 	// arrange
 	service := newMockCardService()
 	controller := newCardController(service)
-	service.On("UpdateInStockAmount", mock.Anything, mock.Anything).Return(&dto.GetCard{}, nil)
+	service.On("UpdateInStockAmount", mock.Anything, mock.Anything, uint(1)).Return(&dto.GetCard{}, nil)
 	data := dto.StockedAmountUpdate{
 		NewAmount: 12,
 	}
@@ -340,7 +390,7 @@ func Test_Card_ShouldUpdateInStockAmountCardNotFound(t *testing.T) {
 	// arrange
 	s := newMockCardService()
 	controller := newCardController(s)
-	s.On("UpdateInStockAmount", mock.Anything, mock.Anything).Return(nil, service.ErrCardNotFound)
+	s.On("UpdateInStockAmount", mock.Anything, mock.Anything, uint(1)).Return(nil, service.ErrCardNotFound)
 	data := dto.StockedAmountUpdate{
 		NewAmount: 12,
 	}
@@ -354,6 +404,170 @@ func Test_Card_ShouldUpdateInStockAmountCardNotFound(t *testing.T) {
 	assert.Equal(t, 404, w.Code)
 }
 
+func Test_Card_ShouldUpdatePrices(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("UpdatePrices", mock.Anything).Return([]*dto.GetCard{{}, {}}, nil)
+	c, w := createTestContext(dto.BulkPriceUpdate{
+		Prices: []dto.BulkPriceEntry{{CardId: 1, Price: 1000}, {CardId: 2, Price: 250}},
+	})
+
+	// act
+	controller.UpdatePrices(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Card_ShouldNotUpdatePricesCardNotFound(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("UpdatePrices", mock.Anything).Return(nil, fmt.Errorf("%w: [2]", service.ErrCardNotFound))
+	c, w := createTestContext(dto.BulkPriceUpdate{
+		Prices: []dto.BulkPriceEntry{{CardId: 1, Price: 1000}, {CardId: 2, Price: 250}},
+	})
+
+	// act
+	controller.UpdatePrices(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Card_ShouldScalePricesOfFilteredCards(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("ScalePrices", mock.Anything, mock.Anything).Return([]*dto.GetCard{}, nil)
+	c, w := createTestContext(dto.BulkPriceScale{
+		Filter:     "expansion=LEA&minPrice=5&t=  lightning   bolt",
+		Percentage: 10,
+	})
+
+	// act
+	controller.ScalePrices(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	filter := cardService.Calls[0].Arguments.Get(0).(*query.CardQuery)
+	assert.Equal(t, "LEA", filter.Expansion)
+	assert.Equal(t, model.Money(500), filter.MinPrice)
+	assert.Equal(t, model.Money(-100), filter.MaxPrice)
+	assert.Equal(t, "lightning bolt", filter.Keywords)
+}
+
+func Test_Card_ShouldNotScalePricesInvalidFilter(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	c, w := createTestContext(dto.BulkPriceScale{
+		Filter:     "minPrice=cheap",
+		Percentage: 10,
+	})
+
+	// act
+	controller.ScalePrices(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	cardService.AssertNotCalled(t, "ScalePrices", mock.Anything, mock.Anything)
+}
+
+func Test_Card_ShouldNotUpdateInStockAmountsBelowZero(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("UpdateInStockAmounts", mock.Anything, uint(1)).Return(nil, fmt.Errorf("%w: card 1 has 2", service.ErrInsufficientStock))
+	c, w := createTestContext(dto.BulkStockUpdate{
+		Stock: []dto.BulkStockEntry{{CardId: 1, Amount: -3, Relative: true}},
+	})
+
+	// act
+	controller.UpdateInStockAmounts(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_Card_ShouldFetchStockMovements(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("StockMovements", uint(12)).Return([]*dto.GetStockMovement{{CardId: 12, Kind: "sale", Delta: -1, Balance: 3}}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+
+	// act
+	controller.StockMovements(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cardService.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotFetchStockMovementsCardNotFound(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("StockMovements", uint(12)).Return(nil, service.ErrCardNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+
+	// act
+	controller.StockMovements(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Card_ShouldFetchLowStock(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("LowStock").Return([]*dto.GetLowStockCard{{Card: &dto.GetCard{ID: 1}, Threshold: 3}})
+	c, w := createTestContext(nil)
+
+	// act
+	controller.LowStock(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Card_ShouldUpdateExpansionThreshold(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("UpdateExpansionThreshold", "LEA", mock.Anything).Return(&model.Expansion{ID: "LEA"}, nil)
+	threshold := uint(2)
+	c, w := createTestContext(dto.LowStockThresholdUpdate{Threshold: &threshold})
+	c.AddParam("id", "LEA")
+
+	// act
+	controller.UpdateExpansionThreshold(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cardService.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotUpdateThresholdOfMissingExpansion(t *testing.T) {
+	// arrange
+	cardService := newMockCardService()
+	controller := newCardController(cardService)
+	cardService.On("UpdateExpansionThreshold", "LEA", mock.Anything).Return(nil, service.ErrExpansionNotFound)
+	c, w := createTestContext(dto.LowStockThresholdUpdate{})
+	c.AddParam("id", "LEA")
+
+	// act
+	controller.UpdateExpansionThreshold(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
 func Test_Card_ShouldFetchLanguages(t *testing.T) {
 	// arrange
 	s := newMockCardService()
@@ -2,6 +2,7 @@ package controller_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"store.api/controller"
 	"store.api/dto"
+	"store.api/query"
 	"store.api/service"
 )
 
@@ -83,7 +85,7 @@ func Test_Collection_ShouldFetchAll(t *testing.T) {
 	// arrange
 	service := newMockCollectionService()
 	controller := newCollectionController(service)
-	service.On("GetAll", mock.Anything).Return([]*dto.GetCollection{})
+	service.On("GetAll", mock.Anything, mock.Anything).Return(&dto.CollectionTree{}, nil)
 	c, w := createTestContext(nil)
 
 	// act
@@ -263,3 +265,609 @@ func Test_Collection_ShouldNotUpdateInfoBadRequest(t *testing.T) {
 	// assert
 	assert.Equal(t, 400, w.Code)
 }
+
+func Test_Collection_ShouldGetShared(t *testing.T) {
+	// arrange
+	service := newMockCollectionService()
+	controller := newCollectionController(service)
+	service.On("GetShared", "token").Return(&dto.GetSharedCollection{}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("token", "token")
+
+	// act
+	controller.Shared(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotGetSharedNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("GetShared", "token").Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("token", "token")
+
+	// act
+	controller.Shared(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldGetPublic(t *testing.T) {
+	// arrange
+	service := newMockCollectionService()
+	controller := newCollectionController(service)
+	service.On("GetPublic", "user").Return([]*dto.GetSharedCollection{}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("username", "user")
+
+	// act
+	controller.Public(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotGetPublicUnknownUser(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("GetPublic", "user").Return(nil, service.ErrUserNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("username", "user")
+
+	// act
+	controller.Public(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldGetValue(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Value", uint(12), mock.Anything).Return(&dto.CollectionValue{CollectionId: 12}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+
+	// act
+	controller.Value(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotGetValueNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Value", mock.Anything, mock.Anything).Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+
+	// act
+	controller.Value(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldNotGetValueBadId(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "abc")
+
+	// act
+	controller.Value(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	s.AssertNotCalled(t, "Value", mock.Anything, mock.Anything)
+}
+
+func Test_Collection_ShouldImport(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Import", uint(12), mock.Anything, mock.Anything).Return(&dto.DecklistImportResult{Imported: 4}, nil)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt (M11) 149",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Import(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotImportNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Import", mock.Anything, mock.Anything, mock.Anything).Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Import(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldNotImportUnverified(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Import", mock.Anything, mock.Anything, mock.Anything).Return(nil, service.ErrNotVerified)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Import(c)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_Collection_ShouldExport(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Export", uint(12), mock.Anything, "arena").Return(&dto.DecklistExport{
+		FileName:    "deck.txt",
+		ContentType: "text/plain; charset=utf-8",
+		Data:        []byte("Deck\n4 Lightning Bolt (M11) 149\n"),
+	}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.Request.URL.RawQuery = "format=arena"
+
+	// act
+	controller.Export(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "Deck\n4 Lightning Bolt (M11) 149\n", w.Body.String())
+	assert.Equal(t, `attachment; filename="deck.txt"`, w.Header().Get("Content-Disposition"))
+}
+
+func Test_Collection_ShouldNotExportUnknownFormat(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Export", mock.Anything, mock.Anything, "csv").Return(nil, service.ErrUnknownDecklistFormat)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.Request.URL.RawQuery = "format=csv"
+
+	// act
+	controller.Export(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Collection_ShouldMoveToCart(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("ToCart", uint(12), mock.Anything, true).Return(&dto.CollectionToCart{}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("collectionId", "12")
+	c.Request.URL.RawQuery = "substitute=true"
+
+	// act
+	controller.ToCart(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotMoveToCartNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("ToCart", uint(12), mock.Anything, false).Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.ToCart(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldNotMoveToCartBadSubstitute(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	c, w := createTestContext(nil)
+	c.AddParam("collectionId", "12")
+	c.Request.URL.RawQuery = "substitute=maybe"
+
+	// act
+	controller.ToCart(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	s.AssertNotCalled(t, "ToCart", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Collection_ShouldDiff(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Diff", uint(12), uint(13), mock.Anything).Return(&dto.CollectionDiff{CollectionId: 12}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.AddParam("otherId", "13")
+
+	// act
+	controller.Diff(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotDiffNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Diff", uint(12), uint(13), mock.Anything).Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.AddParam("otherId", "13")
+
+	// act
+	controller.Diff(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldNotDiffBadOtherId(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.AddParam("otherId", "abc")
+
+	// act
+	controller.Diff(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	s.AssertNotCalled(t, "Diff", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Collection_ShouldDiffDecklist(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("DiffDecklist", uint(12), mock.Anything, mock.Anything).Return(&dto.CollectionDiff{CollectionId: 12}, nil)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.DiffDecklist(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotDiffDecklistUnknownFormat(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("DiffDecklist", uint(12), mock.Anything, mock.Anything).Return(nil, service.ErrUnknownDecklistFormat)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "csv",
+		Decklist: "4 Lightning Bolt",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.DiffDecklist(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Collection_ShouldGetNeeds(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Needs", uint(12), uint(13), mock.Anything).Return(&dto.CollectionNeeds{CollectionId: 12}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "12")
+	c.AddParam("otherId", "13")
+
+	// act
+	controller.Needs(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldGetNeedsDecklist(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("NeedsDecklist", uint(12), mock.Anything, mock.Anything).Return(&dto.CollectionNeeds{CollectionId: 12}, nil)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.NeedsDecklist(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotGetNeedsDecklistNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("NeedsDecklist", uint(12), mock.Anything, mock.Anything).Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(&dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt",
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.NeedsDecklist(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldEditSlots(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("EditSlots", mock.Anything, uint(12), mock.Anything).Return(&dto.GetCollection{}, nil)
+	c, w := createTestContext(&dto.PostCollectionSlots{
+		Slots: []*dto.PostCollectionSlot{
+			{CardId: 1, Amount: 4},
+			{CardId: 2, Amount: -1},
+		},
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.EditSlots(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotEditSlotsCardNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("EditSlots", mock.Anything, uint(12), mock.Anything).Return(nil, fmt.Errorf("%w: no card with id 2", service.ErrCardNotFound))
+	c, w := createTestContext(&dto.PostCollectionSlots{
+		Slots: []*dto.PostCollectionSlot{
+			{CardId: 2, Amount: 1},
+		},
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.EditSlots(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldNotEditSlotsUnverified(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("EditSlots", mock.Anything, uint(12), mock.Anything).Return(nil, service.ErrNotVerified)
+	c, w := createTestContext(&dto.PostCollectionSlots{
+		Slots: []*dto.PostCollectionSlot{
+			{CardId: 2, Amount: 1},
+		},
+	})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.EditSlots(c)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_Collection_ShouldFetchAllInFolder(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("GetAll", uint(1), mock.MatchedBy(func(q *query.CollectionQuery) bool {
+		return *q.Folder == 4 && q.Tag == "modern"
+	})).Return(&dto.CollectionTree{}, nil)
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "folder=4&tag=modern"
+
+	// act
+	controller.All(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	s.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldNotFetchAllUnknownFolder(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("GetAll", mock.Anything, mock.Anything).Return(nil, service.ErrFolderNotFound)
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "folder=4"
+
+	// act
+	controller.All(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldNotFetchAllBadFolder(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "folder=abc"
+
+	// act
+	controller.All(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	s.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func Test_Collection_ShouldCopy(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Copy", uint(12), mock.Anything, mock.Anything).Return(&dto.GetCollection{}, nil)
+	c, w := createTestContext(&dto.CopyCollection{Name: "burn v2"})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Copy(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_Collection_ShouldNotCopyNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Copy", uint(12), mock.Anything, mock.Anything).Return(nil, service.ErrCollectionNotFound)
+	c, w := createTestContext(&dto.CopyCollection{})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Copy(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Collection_ShouldMerge(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Merge", uint(12), mock.Anything, mock.Anything).Return(&dto.GetCollection{}, nil)
+	c, w := createTestContext(&dto.MergeCollections{SourceId: 13, DeleteSource: true})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Merge(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotMergeIntoSelf(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("Merge", uint(12), mock.Anything, mock.Anything).Return(nil, service.ErrMergeIntoSelf)
+	c, w := createTestContext(&dto.MergeCollections{SourceId: 12})
+	c.AddParam("collectionId", "12")
+
+	// act
+	controller.Merge(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Collection_ShouldCreateFolder(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("CreateFolder", mock.Anything, mock.Anything).Return(&dto.GetCollectionFolder{ID: 1}, nil)
+	c, w := createTestContext(&dto.PostCollectionFolder{Name: "decks"})
+
+	// act
+	controller.CreateFolder(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_Collection_ShouldNotUpdateFolderCycle(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	var parentId uint = 2
+	s.On("UpdateFolder", uint(1), mock.Anything, mock.Anything).Return(nil, service.ErrFolderCycle)
+	c, w := createTestContext(&dto.PostCollectionFolder{Name: "decks", ParentId: &parentId})
+	c.AddParam("id", "1")
+
+	// act
+	controller.UpdateFolder(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Collection_ShouldDeleteFolder(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("DeleteFolder", uint(1), mock.Anything).Return(nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "1")
+
+	// act
+	controller.DeleteFolder(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Collection_ShouldNotDeleteFolderNotFound(t *testing.T) {
+	// arrange
+	s := newMockCollectionService()
+	controller := newCollectionController(s)
+	s.On("DeleteFolder", uint(1), mock.Anything).Return(service.ErrFolderNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "1")
+
+	// act
+	controller.DeleteFolder(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
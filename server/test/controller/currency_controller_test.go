@@ -0,0 +1,111 @@
+package controller_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/service"
+)
+
+func newCurrencyController(currencyService service.CurrencyService) *controller.CurrencyController {
+	return controller.NewCurrencyController(
+		currencyService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_Currency_ShouldFetchRates(t *testing.T) {
+	// arrange
+	currencyService := newMockCurrencyService()
+	controller := newCurrencyController(currencyService)
+	currencyService.On("Rates").Return(&dto.GetCurrencies{StoreCurrency: "USD"})
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Rates(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_Currency_ShouldSetRate(t *testing.T) {
+	// arrange
+	currencyService := newMockCurrencyService()
+	controller := newCurrencyController(currencyService)
+	currencyService.On("SetRate", "EUR", &dto.PostExchangeRate{Rate: 0.9}).Return(&dto.GetExchangeRate{Currency: "EUR", Rate: 0.9}, nil)
+	c, w := createTestContext(dto.PostExchangeRate{Rate: 0.9})
+	c.AddParam("currency", "EUR")
+
+	// act
+	controller.SetRate(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	currencyService.AssertExpectations(t)
+}
+
+func Test_Currency_ShouldNotSetRateOfStoreCurrency(t *testing.T) {
+	// arrange
+	currencyService := newMockCurrencyService()
+	controller := newCurrencyController(currencyService)
+	currencyService.On("SetRate", "USD", mock.Anything).Return(nil, service.ErrStoreCurrencyRate)
+	c, w := createTestContext(dto.PostExchangeRate{Rate: 1})
+	c.AddParam("currency", "USD")
+
+	// act
+	controller.SetRate(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Currency_ShouldNotDeleteUnknownRate(t *testing.T) {
+	// arrange
+	currencyService := newMockCurrencyService()
+	controller := newCurrencyController(currencyService)
+	currencyService.On("DeleteRate", "GBP").Return(service.ErrUnknownCurrency)
+	c, w := createTestContext(nil)
+	c.AddParam("currency", "GBP")
+
+	// act
+	controller.DeleteRate(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Currency_ShouldNotLoadWithoutSource(t *testing.T) {
+	// arrange
+	currencyService := newMockCurrencyService()
+	controller := newCurrencyController(currencyService)
+	currencyService.On("Load").Return(nil, service.ErrNoExchangeRateSource)
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Load(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_Currency_ShouldNotLoadUnreachableFeed(t *testing.T) {
+	// arrange
+	currencyService := newMockCurrencyService()
+	controller := newCurrencyController(currencyService)
+	currencyService.On("Load").Return(nil, errors.New("connection refused"))
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Load(c)
+
+	// assert
+	assert.Equal(t, 502, w.Code)
+}
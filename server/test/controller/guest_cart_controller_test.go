@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/auth"
+	"store.api/config"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/security"
+	"store.api/service"
+)
+
+const guestCartKey = "test secret key"
+
+func newGuestCartController(cartService service.CartService) *controller.GuestCartController {
+	return controller.NewGuestCartController(
+		cartService,
+		auth.NewGuestCartCookie(&config.Configuration{
+			AuthKey: guestCartKey,
+			Store: config.StoreConfiguration{
+				GuestCartTtl: 3600,
+			},
+		}),
+	)
+}
+
+func Test_GuestCart_ShouldGetEmptyWithoutCookie(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newGuestCartController(cartService)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Get(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cartService.AssertNotCalled(t, "GetGuest", mock.Anything)
+}
+
+func Test_GuestCart_ShouldGet(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newGuestCartController(cartService)
+	cartService.On("GetGuest", "guest").Return(&dto.GetCart{})
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
+
+	c, w := createTestContext(nil)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "guestCart",
+		Value: security.SignValue(guestCartKey, "guest"),
+	})
+
+	// act
+	controller.Get(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cartService.AssertExpectations(t)
+}
+
+func Test_GuestCart_ShouldEditSlotAndSetCookie(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newGuestCartController(cartService)
+	cartService.On("EditGuestSlot", mock.Anything, mock.Anything).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
+
+	c, w := createTestContext(dto.PostCartSlot{CardId: 1, Amount: 1})
+
+	// act
+	controller.EditSlot(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Set-Cookie"), "guestCart="))
+}
+
+func Test_GuestCart_ShouldEditSlotWithExistingCookie(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newGuestCartController(cartService)
+	cartService.On("EditGuestSlot", "guest", mock.Anything).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
+
+	c, w := createTestContext(dto.PostCartSlot{CardId: 1, Amount: 1})
+	c.Request.AddCookie(&http.Cookie{
+		Name:  "guestCart",
+		Value: security.SignValue(guestCartKey, "guest"),
+	})
+
+	// act
+	controller.EditSlot(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	cartService.AssertExpectations(t)
+}
+
+func Test_GuestCart_ShouldNotEditSlotsCardNotFound(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newGuestCartController(cartService)
+	cartService.On("EditGuestSlots", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: no card with id 1", service.ErrCardNotFound))
+
+	c, w := createTestContext(dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{{CardId: 1, Amount: 1}},
+	})
+
+	// act
+	controller.EditSlots(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
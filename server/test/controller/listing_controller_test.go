@@ -0,0 +1,144 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/service"
+)
+
+func newListingController(listingService service.ListingService) *controller.ListingController {
+	return controller.NewListingController(
+		listingService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_Listing_ShouldFetchOffers(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Offers", uint(3), "EUR").Return(&dto.CardOffers{Card: &dto.GetCard{ID: 3}}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("cardId", "3")
+	c.Request.URL.RawQuery = "currency=EUR"
+
+	// act
+	controller.Offers(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	listingService.AssertExpectations(t)
+}
+
+func Test_Listing_ShouldNotFetchOffersCardNotFound(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Offers", uint(3), "").Return(nil, service.ErrCardNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("cardId", "3")
+
+	// act
+	controller.Offers(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Listing_ShouldCreate(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Create", mock.Anything, uint(1)).Return(&dto.GetListing{Id: 1}, nil)
+	c, w := createTestContext(dto.PostListing{CardId: 3, Price: 500, Condition: "NM", Amount: 1})
+
+	// act
+	controller.Create(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_Listing_ShouldNotCreateNotVerified(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Create", mock.Anything, uint(1)).Return(nil, service.ErrNotVerified)
+	c, w := createTestContext(dto.PostListing{CardId: 3, Price: 500, Condition: "NM", Amount: 1})
+
+	// act
+	controller.Create(c)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_Listing_ShouldNotUpdateMissing(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Update", uint(2), mock.Anything, uint(1)).Return(nil, service.ErrListingNotFound)
+	c, w := createTestContext(dto.PatchListing{})
+	c.AddParam("id", "2")
+
+	// act
+	controller.Update(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Listing_ShouldDelete(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Delete", uint(2), uint(1)).Return(nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.Delete(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	listingService.AssertExpectations(t)
+}
+
+func Test_Listing_ShouldBuy(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Buy", uint(3), mock.Anything, uint(1)).Return(&dto.GetOrder{Id: 1}, nil)
+	c, w := createTestContext(dto.PostListingPurchase{Amount: 1})
+	c.AddParam("id", "3")
+
+	// act
+	controller.Buy(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+	listingService.AssertExpectations(t)
+}
+
+func Test_Listing_ShouldNotBuySoldOut(t *testing.T) {
+	// arrange
+	listingService := newMockListingService()
+	controller := newListingController(listingService)
+	listingService.On("Buy", uint(3), mock.Anything, uint(1)).Return(nil, service.ErrListingUnavailable)
+	c, w := createTestContext(dto.PostListingPurchase{Amount: 2})
+	c.AddParam("id", "3")
+
+	// act
+	controller.Buy(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
@@ -1,6 +1,8 @@
 package controller_test
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"store.api/dto"
 	"store.api/model"
@@ -32,6 +34,77 @@ func (ser *MockAuthService) Register(user *dto.RegisterDetails) error {
 	return args.Error(0)
 }
 
+func (ser *MockAuthService) EnrollTwoFactor(userId uint) (*dto.TwoFactorEnrollment, error) {
+	args := ser.Called(userId)
+	switch result := args.Get(0).(type) {
+	case *dto.TwoFactorEnrollment:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockAuthService) ConfirmTwoFactor(userId uint, code *dto.TwoFactorCode) error {
+	args := ser.Called(userId, code)
+	return args.Error(0)
+}
+
+func (ser *MockAuthService) DisableTwoFactor(userId uint, code *dto.TwoFactorCode) error {
+	args := ser.Called(userId, code)
+	return args.Error(0)
+}
+
+func (ser *MockAuthService) UnlockUser(userId uint) error {
+	args := ser.Called(userId)
+	return args.Error(0)
+}
+
+func (ser *MockAuthService) FailedLogins(username string) []*dto.GetFailedLogin {
+	args := ser.Called(username)
+	return args.Get(0).([]*dto.GetFailedLogin)
+}
+
+func (ser *MockAuthService) ResetPassword(reset *dto.PasswordReset) error {
+	args := ser.Called(reset)
+	return args.Error(0)
+}
+
+type MockOidcService struct {
+	mock.Mock
+}
+
+func newMockOidcService() *MockOidcService {
+	return new(MockOidcService)
+}
+
+func (ser *MockOidcService) AuthorizationUrl() (string, string, error) {
+	args := ser.Called()
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (ser *MockOidcService) Callback(code string, state string) (*dto.PrivateUserInfo, error) {
+	args := ser.Called(code, state)
+	switch user := args.Get(0).(type) {
+	case *dto.PrivateUserInfo:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockOidcService) CompleteTwoFactor(details *dto.OidcTwoFactor) (*dto.PrivateUserInfo, error) {
+	args := ser.Called(details)
+	switch user := args.Get(0).(type) {
+	case *dto.PrivateUserInfo:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 type MockCardService struct {
 	mock.Mock
 }
@@ -51,8 +124,8 @@ func (ser *MockCardService) Add(c *dto.PostCard, posterId uint) (*dto.GetCard, e
 	return nil, args.Error(1)
 }
 
-func (ser *MockCardService) GetById(id uint) (*dto.GetCard, error) {
-	args := ser.Called(id)
+func (ser *MockCardService) GetById(id uint, currency string) (*dto.GetCard, error) {
+	args := ser.Called(id, currency)
 	switch card := args.Get(0).(type) {
 	case *dto.GetCard:
 		return card, args.Error(1)
@@ -62,19 +135,19 @@ func (ser *MockCardService) GetById(id uint) (*dto.GetCard, error) {
 	return nil, args.Error(1)
 }
 
-func (ser *MockCardService) Query(query *query.CardQuery) *service.CardQueryResult {
+func (ser *MockCardService) Query(query *query.CardQuery) (*service.CardQueryResult, error) {
 	args := ser.Called(query)
 	switch result := args.Get(0).(type) {
 	case *service.CardQueryResult:
-		return result
+		return result, args.Error(1)
 	case nil:
-		return nil
+		return nil, args.Error(1)
 	}
-	return nil
+	return nil, args.Error(1)
 }
 
-func (ser *MockCardService) Update(c *dto.PostCard, cardId uint) (*dto.GetCard, error) {
-	args := ser.Called(c, cardId)
+func (ser *MockCardService) Update(c *dto.PostCard, cardId uint, actorId uint) (*dto.GetCard, error) {
+	args := ser.Called(c, cardId, actorId)
 	switch card := args.Get(0).(type) {
 	case *dto.GetCard:
 		return card, args.Error(1)
@@ -95,8 +168,8 @@ func (ser *MockCardService) UpdatePrice(id uint, update *dto.PriceUpdate) (*dto.
 	return nil, args.Error(1)
 }
 
-func (ser *MockCardService) UpdateInStockAmount(id uint, update *dto.StockedAmountUpdate) (*dto.GetCard, error) {
-	args := ser.Called(id, update)
+func (ser *MockCardService) UpdateInStockAmount(id uint, update *dto.StockedAmountUpdate, actorId uint) (*dto.GetCard, error) {
+	args := ser.Called(id, update, actorId)
 	switch card := args.Get(0).(type) {
 	case *dto.GetCard:
 		return card, args.Error(1)
@@ -106,6 +179,66 @@ func (ser *MockCardService) UpdateInStockAmount(id uint, update *dto.StockedAmou
 	return nil, args.Error(1)
 }
 
+func (ser *MockCardService) UpdatePrices(update *dto.BulkPriceUpdate) ([]*dto.GetCard, error) {
+	args := ser.Called(update)
+	switch cards := args.Get(0).(type) {
+	case []*dto.GetCard:
+		return cards, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCardService) ScalePrices(filter *query.CardQuery, scale *dto.BulkPriceScale) ([]*dto.GetCard, error) {
+	args := ser.Called(filter, scale)
+	switch cards := args.Get(0).(type) {
+	case []*dto.GetCard:
+		return cards, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCardService) UpdateInStockAmounts(update *dto.BulkStockUpdate, actorId uint) ([]*dto.GetCard, error) {
+	args := ser.Called(update, actorId)
+	switch cards := args.Get(0).(type) {
+	case []*dto.GetCard:
+		return cards, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCardService) StockMovements(id uint) ([]*dto.GetStockMovement, error) {
+	args := ser.Called(id)
+	switch movements := args.Get(0).(type) {
+	case []*dto.GetStockMovement:
+		return movements, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCardService) LowStock() []*dto.GetLowStockCard {
+	args := ser.Called()
+	return args.Get(0).([]*dto.GetLowStockCard)
+}
+
+func (ser *MockCardService) UpdateExpansionThreshold(id string, update *dto.LowStockThresholdUpdate) (*model.Expansion, error) {
+	args := ser.Called(id, update)
+	switch expansion := args.Get(0).(type) {
+	case *model.Expansion:
+		return expansion, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (ser *MockCardService) Languages() []*model.Language {
 	args := ser.Called()
 	return args.Get(0).([]*model.Language)
@@ -116,6 +249,11 @@ func (ser *MockCardService) Expansions() []*model.Expansion {
 	return args.Get(0).([]*model.Expansion)
 }
 
+func (ser *MockCardService) Keys() []*model.CardKey {
+	args := ser.Called()
+	return args.Get(0).([]*model.CardKey)
+}
+
 type MockCollectionService struct {
 	mock.Mock
 }
@@ -124,9 +262,15 @@ func newMockCollectionService() *MockCollectionService {
 	return new(MockCollectionService)
 }
 
-func (ser *MockCollectionService) GetAll(userId uint) []*dto.GetCollection {
-	args := ser.Called(userId)
-	return args.Get(0).([]*dto.GetCollection)
+func (ser *MockCollectionService) GetAll(userId uint, query *query.CollectionQuery) (*dto.CollectionTree, error) {
+	args := ser.Called(userId, query)
+	switch tree := args.Get(0).(type) {
+	case *dto.CollectionTree:
+		return tree, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 func (ser *MockCollectionService) Create(c *dto.PostCollection, userId uint) (*dto.GetCollection, error) {
@@ -140,6 +284,17 @@ func (ser *MockCollectionService) Create(c *dto.PostCollection, userId uint) (*d
 	return nil, args.Error(1)
 }
 
+func (ser *MockCollectionService) EditSlots(cs *dto.PostCollectionSlots, colId uint, userId uint) (*dto.GetCollection, error) {
+	args := ser.Called(cs, colId, userId)
+	switch col := args.Get(0).(type) {
+	case *dto.GetCollection:
+		return col, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (ser *MockCollectionService) EditSlot(cs *dto.PostCollectionSlot, colId uint, userId uint) (*dto.GetCollection, error) {
 	args := ser.Called(cs, colId, userId)
 	switch col := args.Get(0).(type) {
@@ -178,98 +333,924 @@ func (ser *MockCollectionService) UpdateInfo(col *dto.PostCollection, id uint, u
 	return nil, args.Error(1)
 }
 
-type MockCartService struct {
-	mock.Mock
+func (ser *MockCollectionService) GetShared(token string) (*dto.GetSharedCollection, error) {
+	args := ser.Called(token)
+	switch col := args.Get(0).(type) {
+	case *dto.GetSharedCollection:
+		return col, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func newMockCartService() *MockCartService {
-	return new(MockCartService)
+func (ser *MockCollectionService) GetPublic(username string) ([]*dto.GetSharedCollection, error) {
+	args := ser.Called(username)
+	switch cols := args.Get(0).(type) {
+	case []*dto.GetSharedCollection:
+		return cols, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (ser *MockCartService) Get(userId uint) (*dto.GetCart, error) {
-	args := ser.Called(userId)
-	switch cart := args.Get(0).(type) {
-	case *dto.GetCart:
-		return cart, args.Error(1)
+func (ser *MockCollectionService) Value(id uint, userId uint) (*dto.CollectionValue, error) {
+	args := ser.Called(id, userId)
+	switch value := args.Get(0).(type) {
+	case *dto.CollectionValue:
+		return value, args.Error(1)
 	case nil:
 		return nil, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (ser *MockCartService) EditSlot(userId uint, cartSlot *dto.PostCartSlot) (*dto.GetCart, error) {
-	args := ser.Called(userId, cartSlot)
-	switch cart := args.Get(0).(type) {
-	case *dto.GetCart:
-		return cart, args.Error(1)
+func (ser *MockCollectionService) Import(id uint, userId uint, list *dto.DecklistImport) (*dto.DecklistImportResult, error) {
+	args := ser.Called(id, userId, list)
+	switch result := args.Get(0).(type) {
+	case *dto.DecklistImportResult:
+		return result, args.Error(1)
 	case nil:
 		return nil, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-type MockUserService struct {
-	mock.Mock
+func (ser *MockCollectionService) Export(id uint, userId uint, format string) (*dto.DecklistExport, error) {
+	args := ser.Called(id, userId, format)
+	switch result := args.Get(0).(type) {
+	case *dto.DecklistExport:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func newMockUserService() *MockUserService {
-	return new(MockUserService)
+func (ser *MockCollectionService) ToCart(id uint, userId uint, substitute bool) (*dto.CollectionToCart, error) {
+	args := ser.Called(id, userId, substitute)
+	switch result := args.Get(0).(type) {
+	case *dto.CollectionToCart:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (ser *MockUserService) ById(id uint) (*dto.PrivateUserInfo, error) {
-	args := ser.Called(id)
-	switch user := args.Get(0).(type) {
-	case *dto.PrivateUserInfo:
-		return user, args.Error(1)
+func (ser *MockCollectionService) Diff(id uint, otherId uint, userId uint) (*dto.CollectionDiff, error) {
+	args := ser.Called(id, otherId, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.CollectionDiff:
+		return result, args.Error(1)
 	case nil:
 		return nil, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-// ! duplicated from test/service/mocks_test.go
-type MockUserRepository struct {
-	mock.Mock
+func (ser *MockCollectionService) DiffDecklist(id uint, userId uint, list *dto.DecklistImport) (*dto.CollectionDiff, error) {
+	args := ser.Called(id, userId, list)
+	switch result := args.Get(0).(type) {
+	case *dto.CollectionDiff:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func newMockUserRepository() *MockUserRepository {
-	return new(MockUserRepository)
+func (ser *MockCollectionService) Needs(id uint, otherId uint, userId uint) (*dto.CollectionNeeds, error) {
+	args := ser.Called(id, otherId, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.CollectionNeeds:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (m *MockUserRepository) FindByUsername(username string) *model.User {
-	args := m.Called(username)
-	switch user := args.Get(0).(type) {
-	case *model.User:
-		return user
+func (ser *MockCollectionService) NeedsDecklist(id uint, userId uint, list *dto.DecklistImport) (*dto.CollectionNeeds, error) {
+	args := ser.Called(id, userId, list)
+	switch result := args.Get(0).(type) {
+	case *dto.CollectionNeeds:
+		return result, args.Error(1)
 	case nil:
-		return nil
+		return nil, args.Error(1)
 	}
-	return nil
+	return nil, args.Error(1)
 }
 
-func (m *MockUserRepository) FindByEmail(email string) *model.User {
-	args := m.Called(email)
-	switch user := args.Get(0).(type) {
-	case *model.User:
-		return user
+func (ser *MockCollectionService) Copy(id uint, userId uint, options *dto.CopyCollection) (*dto.GetCollection, error) {
+	args := ser.Called(id, userId, options)
+	switch result := args.Get(0).(type) {
+	case *dto.GetCollection:
+		return result, args.Error(1)
 	case nil:
-		return nil
+		return nil, args.Error(1)
 	}
-	return nil
+	return nil, args.Error(1)
 }
 
-func (m *MockUserRepository) Save(user *model.User) error {
-	args := m.Called(user)
+func (ser *MockCollectionService) Merge(id uint, userId uint, merge *dto.MergeCollections) (*dto.GetCollection, error) {
+	args := ser.Called(id, userId, merge)
+	switch result := args.Get(0).(type) {
+	case *dto.GetCollection:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCollectionService) CreateFolder(userId uint, folder *dto.PostCollectionFolder) (*dto.GetCollectionFolder, error) {
+	args := ser.Called(userId, folder)
+	switch result := args.Get(0).(type) {
+	case *dto.GetCollectionFolder:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCollectionService) UpdateFolder(id uint, userId uint, folder *dto.PostCollectionFolder) (*dto.GetCollectionFolder, error) {
+	args := ser.Called(id, userId, folder)
+	switch result := args.Get(0).(type) {
+	case *dto.GetCollectionFolder:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCollectionService) DeleteFolder(id uint, userId uint) error {
+	args := ser.Called(id, userId)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) FindById(id uint) *model.User {
-	args := m.Called(id)
-	switch user := args.Get(0).(type) {
-	case *model.User:
-		return user
+type MockCartService struct {
+	mock.Mock
+}
+
+func newMockCartService() *MockCartService {
+	return new(MockCartService)
+}
+
+func (ser *MockCartService) Get(userId uint) (*dto.GetCart, error) {
+	args := ser.Called(userId)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
 	case nil:
-		return nil
+		return nil, args.Error(1)
 	}
-	return nil
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) EditSlots(userId uint, cartSlots *dto.PostCartSlots) (*dto.GetCart, error) {
+	args := ser.Called(userId, cartSlots)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) EditSlot(userId uint, cartSlot *dto.PostCartSlot) (*dto.GetCart, error) {
+	args := ser.Called(userId, cartSlot)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) Convert(cart *dto.GetCart, currency string) (*dto.GetCart, error) {
+	args := ser.Called(cart, currency)
+	switch converted := args.Get(0).(type) {
+	case *dto.GetCart:
+		return converted, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) GetGuest(guestId string) *dto.GetCart {
+	args := ser.Called(guestId)
+	return args.Get(0).(*dto.GetCart)
+}
+
+func (ser *MockCartService) EditGuestSlot(guestId string, cartSlot *dto.PostCartSlot) (*dto.GetCart, error) {
+	args := ser.Called(guestId, cartSlot)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) EditGuestSlots(guestId string, cartSlots *dto.PostCartSlots) (*dto.GetCart, error) {
+	args := ser.Called(guestId, cartSlots)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) ApplyPromotion(userId uint, code *dto.PromotionCode) (*dto.GetCart, error) {
+	args := ser.Called(userId, code)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) RemovePromotion(userId uint) (*dto.GetCart, error) {
+	args := ser.Called(userId)
+	switch cart := args.Get(0).(type) {
+	case *dto.GetCart:
+		return cart, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) Checkout(userId uint) (*dto.GetOrder, error) {
+	args := ser.Called(userId)
+	switch order := args.Get(0).(type) {
+	case *dto.GetOrder:
+		return order, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCartService) MergeGuest(userId uint, guestId string) error {
+	args := ser.Called(userId, guestId)
+	return args.Error(0)
+}
+
+type MockWishlistService struct {
+	mock.Mock
+}
+
+func newMockWishlistService() *MockWishlistService {
+	return new(MockWishlistService)
+}
+
+func (ser *MockWishlistService) All(userId uint) []*dto.GetWishlist {
+	args := ser.Called(userId)
+	return args.Get(0).([]*dto.GetWishlist)
+}
+
+func (ser *MockWishlistService) Add(userId uint, wishlist *dto.PostWishlist) (*dto.GetWishlist, error) {
+	args := ser.Called(userId, wishlist)
+	switch result := args.Get(0).(type) {
+	case *dto.GetWishlist:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockWishlistService) Update(userId uint, id uint, wishlist *dto.PatchWishlist) (*dto.GetWishlist, error) {
+	args := ser.Called(userId, id, wishlist)
+	switch result := args.Get(0).(type) {
+	case *dto.GetWishlist:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockWishlistService) Delete(userId uint, id uint) error {
+	args := ser.Called(userId, id)
+	return args.Error(0)
+}
+
+type MockUserService struct {
+	mock.Mock
+}
+
+func newMockUserService() *MockUserService {
+	return new(MockUserService)
+}
+
+func (ser *MockUserService) ById(id uint) (*dto.PrivateUserInfo, error) {
+	args := ser.Called(id)
+	switch user := args.Get(0).(type) {
+	case *dto.PrivateUserInfo:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) UpdateProfile(userId uint, profile *dto.PatchProfile) (*dto.PrivateUserInfo, error) {
+	args := ser.Called(userId, profile)
+	switch user := args.Get(0).(type) {
+	case *dto.PrivateUserInfo:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) RequestEmailChange(userId uint, change *dto.EmailChange) error {
+	args := ser.Called(userId, change)
+	return args.Error(0)
+}
+
+func (ser *MockUserService) VerifyEmail(userId uint, verification *dto.EmailVerification) (*dto.PrivateUserInfo, error) {
+	args := ser.Called(userId, verification)
+	switch user := args.Get(0).(type) {
+	case *dto.PrivateUserInfo:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) Export(userId uint) (*dto.UserExport, error) {
+	args := ser.Called(userId)
+	switch export := args.Get(0).(type) {
+	case *dto.UserExport:
+		return export, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) Delete(userId uint, deletion *dto.AccountDeletion, loggedInAt time.Time) error {
+	args := ser.Called(userId, deletion, loggedInAt)
+	return args.Error(0)
+}
+
+func (ser *MockUserService) Query(query *query.UserQuery) *service.UserQueryResult {
+	args := ser.Called(query)
+	return args.Get(0).(*service.UserQueryResult)
+}
+
+func (ser *MockUserService) Verify(userId uint) (*dto.GetUser, error) {
+	args := ser.Called(userId)
+	switch user := args.Get(0).(type) {
+	case *dto.GetUser:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) Suspend(adminId uint, userId uint, suspension *dto.Suspension) (*dto.GetUser, error) {
+	args := ser.Called(adminId, userId, suspension)
+	switch user := args.Get(0).(type) {
+	case *dto.GetUser:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) Unsuspend(userId uint) (*dto.GetUser, error) {
+	args := ser.Called(userId)
+	switch user := args.Get(0).(type) {
+	case *dto.GetUser:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) SetAdmin(adminId uint, userId uint, isAdmin bool) (*dto.GetUser, error) {
+	args := ser.Called(adminId, userId, isAdmin)
+	switch user := args.Get(0).(type) {
+	case *dto.GetUser:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockUserService) ForcePasswordReset(userId uint) (*dto.GetUser, error) {
+	args := ser.Called(userId)
+	switch user := args.Get(0).(type) {
+	case *dto.GetUser:
+		return user, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ! duplicated from test/service/mocks_test.go
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func newMockUserRepository() *MockUserRepository {
+	return new(MockUserRepository)
+}
+
+func (m *MockUserRepository) FindByUsername(username string) *model.User {
+	args := m.Called(username)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) FindByEmail(email string) *model.User {
+	args := m.Called(email)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Save(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindById(id uint) *model.User {
+	args := m.Called(id)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) FindByOidcSubject(issuer string, subject string) *model.User {
+	args := m.Called(issuer, subject)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Update(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ReplaceRecoveryCodes(userId uint, codes []*model.RecoveryCode) error {
+	args := m.Called(userId, codes)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UseRecoveryCode(userId uint, codeHash string) (bool, error) {
+	args := m.Called(userId, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UseTotpStep(userId uint, step uint64) (bool, error) {
+	args := m.Called(userId, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Anonymise(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByPasswordResetToken(tokenHash string) *model.User {
+	args := m.Called(tokenHash)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Query(query *query.UserQuery) ([]*model.User, int64) {
+	args := m.Called(query)
+	return args.Get(0).([]*model.User), args.Get(1).(int64)
+}
+
+func (m *MockUserRepository) FindAdmins() []*model.User {
+	args := m.Called()
+	return args.Get(0).([]*model.User)
+}
+
+type MockOrderService struct {
+	mock.Mock
+}
+
+func newMockOrderService() *MockOrderService {
+	return new(MockOrderService)
+}
+
+func (ser *MockOrderService) All(userId uint) []*dto.GetOrder {
+	args := ser.Called(userId)
+	return args.Get(0).([]*dto.GetOrder)
+}
+
+func (ser *MockOrderService) ById(userId uint, id uint) (*dto.GetOrder, error) {
+	args := ser.Called(userId, id)
+	switch order := args.Get(0).(type) {
+	case *dto.GetOrder:
+		return order, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func newMockPromotionService() *MockPromotionService {
+	return new(MockPromotionService)
+}
+
+func (ser *MockPromotionService) All() []*dto.GetPromotion {
+	args := ser.Called()
+	return args.Get(0).([]*dto.GetPromotion)
+}
+
+func (ser *MockPromotionService) ById(id uint) (*dto.GetPromotion, error) {
+	args := ser.Called(id)
+	switch promotion := args.Get(0).(type) {
+	case *dto.GetPromotion:
+		return promotion, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPromotionService) Create(promotion *dto.PostPromotion) (*dto.GetPromotion, error) {
+	args := ser.Called(promotion)
+	switch result := args.Get(0).(type) {
+	case *dto.GetPromotion:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPromotionService) Update(id uint, promotion *dto.PostPromotion) (*dto.GetPromotion, error) {
+	args := ser.Called(id, promotion)
+	switch result := args.Get(0).(type) {
+	case *dto.GetPromotion:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPromotionService) Delete(id uint) error {
+	args := ser.Called(id)
+	return args.Error(0)
+}
+
+func (ser *MockPromotionService) Redemptions(id uint) ([]*dto.GetPromotionRedemption, error) {
+	args := ser.Called(id)
+	switch result := args.Get(0).(type) {
+	case []*dto.GetPromotionRedemption:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockCurrencyService struct {
+	mock.Mock
+}
+
+func newMockCurrencyService() *MockCurrencyService {
+	return new(MockCurrencyService)
+}
+
+func (ser *MockCurrencyService) Rates() *dto.GetCurrencies {
+	args := ser.Called()
+	return args.Get(0).(*dto.GetCurrencies)
+}
+
+func (ser *MockCurrencyService) SetRate(currency string, rate *dto.PostExchangeRate) (*dto.GetExchangeRate, error) {
+	args := ser.Called(currency, rate)
+	switch result := args.Get(0).(type) {
+	case *dto.GetExchangeRate:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockCurrencyService) DeleteRate(currency string) error {
+	args := ser.Called(currency)
+	return args.Error(0)
+}
+
+func (ser *MockCurrencyService) Load() (*dto.GetCurrencies, error) {
+	args := ser.Called()
+	switch result := args.Get(0).(type) {
+	case *dto.GetCurrencies:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockPriceScheduleService struct {
+	mock.Mock
+}
+
+func newMockPriceScheduleService() *MockPriceScheduleService {
+	return new(MockPriceScheduleService)
+}
+
+func (ser *MockPriceScheduleService) Pending(cardId uint) []*dto.GetScheduledPrice {
+	args := ser.Called(cardId)
+	return args.Get(0).([]*dto.GetScheduledPrice)
+}
+
+func (ser *MockPriceScheduleService) ById(id uint) (*dto.GetScheduledPrice, error) {
+	args := ser.Called(id)
+	switch result := args.Get(0).(type) {
+	case *dto.GetScheduledPrice:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPriceScheduleService) Schedule(change *dto.PostScheduledPrice) (*dto.GetScheduledPrice, error) {
+	args := ser.Called(change)
+	switch result := args.Get(0).(type) {
+	case *dto.GetScheduledPrice:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPriceScheduleService) Cancel(id uint) error {
+	args := ser.Called(id)
+	return args.Error(0)
+}
+
+func (ser *MockPriceScheduleService) Run(now time.Time) error {
+	args := ser.Called(now)
+	return args.Error(0)
+}
+
+type MockListingService struct {
+	mock.Mock
+}
+
+func newMockListingService() *MockListingService {
+	return new(MockListingService)
+}
+
+func (ser *MockListingService) Offers(cardId uint, currency string) (*dto.CardOffers, error) {
+	args := ser.Called(cardId, currency)
+	switch offers := args.Get(0).(type) {
+	case *dto.CardOffers:
+		return offers, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockListingService) ById(id uint, currency string) (*dto.GetListing, error) {
+	args := ser.Called(id, currency)
+	switch listing := args.Get(0).(type) {
+	case *dto.GetListing:
+		return listing, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockListingService) BySeller(sellerId uint) []*dto.GetListing {
+	args := ser.Called(sellerId)
+	return args.Get(0).([]*dto.GetListing)
+}
+
+func (ser *MockListingService) Create(listing *dto.PostListing, sellerId uint) (*dto.GetListing, error) {
+	args := ser.Called(listing, sellerId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetListing:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockListingService) Update(id uint, patch *dto.PatchListing, sellerId uint) (*dto.GetListing, error) {
+	args := ser.Called(id, patch, sellerId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetListing:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockListingService) Delete(id uint, sellerId uint) error {
+	args := ser.Called(id, sellerId)
+	return args.Error(0)
+}
+
+func (ser *MockListingService) Buy(id uint, purchase *dto.PostListingPurchase, buyerId uint) (*dto.GetOrder, error) {
+	args := ser.Called(id, purchase, buyerId)
+	switch order := args.Get(0).(type) {
+	case *dto.GetOrder:
+		return order, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockTradeService struct {
+	mock.Mock
+}
+
+func newMockTradeService() *MockTradeService {
+	return new(MockTradeService)
+}
+
+func (ser *MockTradeService) Mine(userId uint) []*dto.GetTrade {
+	args := ser.Called(userId)
+	return args.Get(0).([]*dto.GetTrade)
+}
+
+func (ser *MockTradeService) ById(id uint, userId uint) (*dto.GetTrade, error) {
+	args := ser.Called(id, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetTrade:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockTradeService) Propose(trade *dto.PostTrade, userId uint) (*dto.GetTrade, error) {
+	args := ser.Called(trade, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetTrade:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockTradeService) Counter(id uint, counter *dto.PostCounterTrade, userId uint) (*dto.GetTrade, error) {
+	args := ser.Called(id, counter, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetTrade:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockTradeService) Accept(id uint, userId uint) (*dto.GetTrade, error) {
+	args := ser.Called(id, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetTrade:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockTradeService) Decline(id uint, userId uint) (*dto.GetTrade, error) {
+	args := ser.Called(id, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetTrade:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockTradeService) Cancel(id uint, userId uint) (*dto.GetTrade, error) {
+	args := ser.Called(id, userId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetTrade:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockPayoutService struct {
+	mock.Mock
+}
+
+func newMockPayoutService() *MockPayoutService {
+	return new(MockPayoutService)
+}
+
+func (ser *MockPayoutService) Statement(sellerId uint, period *dto.Period) (*dto.PayoutStatement, error) {
+	args := ser.Called(sellerId, period)
+	switch statement := args.Get(0).(type) {
+	case *dto.PayoutStatement:
+		return statement, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPayoutService) Payouts(sellerId uint) []*dto.GetPayout {
+	args := ser.Called(sellerId)
+	return args.Get(0).([]*dto.GetPayout)
+}
+
+func (ser *MockPayoutService) Pay(payout *dto.PostPayout, adminId uint) (*dto.GetPayout, error) {
+	args := ser.Called(payout, adminId)
+	switch result := args.Get(0).(type) {
+	case *dto.GetPayout:
+		return result, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPayoutService) Commission(sellerId uint) (*dto.GetCommission, error) {
+	args := ser.Called(sellerId)
+	switch commission := args.Get(0).(type) {
+	case *dto.GetCommission:
+		return commission, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ser *MockPayoutService) UpdateCommission(sellerId uint, update *dto.CommissionUpdate) (*dto.GetCommission, error) {
+	args := ser.Called(sellerId, update)
+	switch commission := args.Get(0).(type) {
+	case *dto.GetCommission:
+		return commission, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
@@ -0,0 +1,99 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/service"
+)
+
+func newPayoutController(payoutService service.PayoutService) *controller.PayoutController {
+	return controller.NewPayoutController(
+		payoutService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_Payout_ShouldFetchOwnStatement(t *testing.T) {
+	// arrange
+	payoutService := newMockPayoutService()
+	controller := newPayoutController(payoutService)
+	payoutService.On("Statement", uint(1), &dto.Period{From: "2024-03-01", To: "2024-03-31"}).Return(&dto.PayoutStatement{SellerId: 1}, nil)
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "from=2024-03-01&to=2024-03-31"
+
+	// act
+	controller.MyStatement(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	payoutService.AssertExpectations(t)
+}
+
+func Test_Payout_ShouldNotFetchStatementUnknownSeller(t *testing.T) {
+	// arrange
+	payoutService := newMockPayoutService()
+	controller := newPayoutController(payoutService)
+	payoutService.On("Statement", uint(7), mock.Anything).Return(nil, service.ErrUserNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("sellerId", "7")
+
+	// act
+	controller.SellerStatement(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Payout_ShouldPay(t *testing.T) {
+	// arrange
+	payoutService := newMockPayoutService()
+	controller := newPayoutController(payoutService)
+	payoutService.On("Pay", mock.Anything, uint(1)).Return(&dto.GetPayout{Id: 1}, nil)
+	c, w := createTestContext(dto.PostPayout{SellerId: 2, From: "2024-03-01", To: "2024-03-31"})
+
+	// act
+	controller.Pay(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+	payoutService.AssertExpectations(t)
+}
+
+func Test_Payout_ShouldNotPayUnreconciled(t *testing.T) {
+	// arrange
+	payoutService := newMockPayoutService()
+	controller := newPayoutController(payoutService)
+	payoutService.On("Pay", mock.Anything, uint(1)).Return(nil, service.ErrSalesUnreconciled)
+	c, w := createTestContext(dto.PostPayout{SellerId: 2, From: "2024-03-01", To: "2024-03-31"})
+
+	// act
+	controller.Pay(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_Payout_ShouldUpdateCommission(t *testing.T) {
+	// arrange
+	payoutService := newMockPayoutService()
+	controller := newPayoutController(payoutService)
+	percentage := float32(15)
+	payoutService.On("UpdateCommission", uint(2), &dto.CommissionUpdate{Percentage: &percentage}).Return(&dto.GetCommission{SellerId: 2, Percentage: 15}, nil)
+	c, w := createTestContext(dto.CommissionUpdate{Percentage: &percentage})
+	c.AddParam("sellerId", "2")
+
+	// act
+	controller.UpdateCommission(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	payoutService.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+package controller_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/service"
+)
+
+func newPriceScheduleController(priceScheduleService service.PriceScheduleService) *controller.PriceScheduleController {
+	return controller.NewPriceScheduleController(
+		priceScheduleService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_PriceSchedule_ShouldFetchPendingOfCard(t *testing.T) {
+	// arrange
+	priceScheduleService := newMockPriceScheduleService()
+	controller := newPriceScheduleController(priceScheduleService)
+	priceScheduleService.On("Pending", uint(3)).Return([]*dto.GetScheduledPrice{{Id: 1, CardId: 3}})
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "cardId=3"
+
+	// act
+	controller.Pending(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	priceScheduleService.AssertExpectations(t)
+}
+
+func Test_PriceSchedule_ShouldNotFetchPendingOfInvalidCard(t *testing.T) {
+	// arrange
+	priceScheduleService := newMockPriceScheduleService()
+	controller := newPriceScheduleController(priceScheduleService)
+	c, w := createTestContext(nil)
+	c.Request.URL.RawQuery = "cardId=abc"
+
+	// act
+	controller.Pending(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_PriceSchedule_ShouldSchedule(t *testing.T) {
+	// arrange
+	priceScheduleService := newMockPriceScheduleService()
+	controller := newPriceScheduleController(priceScheduleService)
+	priceScheduleService.On("Schedule", mock.Anything).Return(&dto.GetScheduledPrice{Id: 1, CardId: 3, Price: 500}, nil)
+	c, w := createTestContext(dto.PostScheduledPrice{CardId: 3, Price: 500, StartsAt: time.Now().Add(time.Hour)})
+
+	// act
+	controller.Schedule(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_PriceSchedule_ShouldNotScheduleOverlapping(t *testing.T) {
+	// arrange
+	priceScheduleService := newMockPriceScheduleService()
+	controller := newPriceScheduleController(priceScheduleService)
+	priceScheduleService.On("Schedule", mock.Anything).Return(nil, service.ErrScheduledPriceOverlap)
+	c, w := createTestContext(dto.PostScheduledPrice{CardId: 3, Price: 500, StartsAt: time.Now().Add(time.Hour)})
+
+	// act
+	controller.Schedule(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_PriceSchedule_ShouldCancel(t *testing.T) {
+	// arrange
+	priceScheduleService := newMockPriceScheduleService()
+	controller := newPriceScheduleController(priceScheduleService)
+	priceScheduleService.On("Cancel", uint(2)).Return(nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.Cancel(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	priceScheduleService.AssertExpectations(t)
+}
+
+func Test_PriceSchedule_ShouldNotCancelMissing(t *testing.T) {
+	// arrange
+	priceScheduleService := newMockPriceScheduleService()
+	controller := newPriceScheduleController(priceScheduleService)
+	priceScheduleService.On("Cancel", uint(2)).Return(service.ErrScheduledPriceNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.Cancel(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,112 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/service"
+)
+
+func newPromotionController(promotionService service.PromotionService) *controller.PromotionController {
+	return controller.NewPromotionController(
+		promotionService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_Promotion_ShouldCreate(t *testing.T) {
+	// arrange
+	promotionService := newMockPromotionService()
+	controller := newPromotionController(promotionService)
+	promotionService.On("Create", mock.Anything).Return(&dto.GetPromotion{Id: 1}, nil)
+
+	c, w := createTestContext(dto.PostPromotion{
+		Description: "Summer sale",
+		Kind:        "percentage",
+		Percentage:  10,
+	})
+
+	// act
+	controller.Create(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_Promotion_ShouldNotCreateInvalid(t *testing.T) {
+	// arrange
+	promotionService := newMockPromotionService()
+	controller := newPromotionController(promotionService)
+	promotionService.On("Create", mock.Anything).Return(nil, service.ErrPromotionCodeTaken)
+
+	c, w := createTestContext(dto.PostPromotion{
+		Description: "Summer sale",
+		Kind:        "percentage",
+		Percentage:  10,
+	})
+
+	// act
+	controller.Create(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_Promotion_ShouldNotUpdateNotFound(t *testing.T) {
+	// arrange
+	promotionService := newMockPromotionService()
+	controller := newPromotionController(promotionService)
+	promotionService.On("Update", uint(4), mock.Anything).Return(nil, service.ErrPromotionNotFound)
+
+	c, w := createTestContext(dto.PostPromotion{
+		Description: "Summer sale",
+		Kind:        "fixed",
+		Amount:      5,
+	})
+	c.AddParam("id", "4")
+
+	// act
+	controller.Update(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Promotion_ShouldNotDeleteInvalidId(t *testing.T) {
+	// arrange
+	promotionService := newMockPromotionService()
+	controller := newPromotionController(promotionService)
+
+	c, w := createTestContext(nil)
+	c.AddParam("id", "abc")
+
+	// act
+	controller.Delete(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	promotionService.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func Test_Promotion_ShouldGetRedemptions(t *testing.T) {
+	// arrange
+	promotionService := newMockPromotionService()
+	controller := newPromotionController(promotionService)
+	promotionService.On("Redemptions", uint(4)).Return([]*dto.GetPromotionRedemption{{Id: 1, OrderId: 2}}, nil)
+
+	c, w := createTestContext(nil)
+	c.AddParam("id", "4")
+
+	// act
+	controller.Redemptions(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
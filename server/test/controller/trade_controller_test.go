@@ -0,0 +1,120 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/controller"
+	"store.api/dto"
+	"store.api/service"
+)
+
+func newTradeController(tradeService service.TradeService) *controller.TradeController {
+	return controller.NewTradeController(
+		tradeService,
+		func(ctx *gin.Context) {},
+		func(s string, ctx *gin.Context) (string, error) {
+			return "1", nil
+		},
+	)
+}
+
+func Test_Trade_ShouldPropose(t *testing.T) {
+	// arrange
+	tradeService := newMockTradeService()
+	controller := newTradeController(tradeService)
+	tradeService.On("Propose", mock.Anything, uint(1)).Return(&dto.GetTrade{Id: 1}, nil)
+	c, w := createTestContext(dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 2,
+		Offered:               []dto.TradeCard{{CardId: 3, Amount: 1}},
+	})
+
+	// act
+	controller.Propose(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+	tradeService.AssertExpectations(t)
+}
+
+func Test_Trade_ShouldNotProposeUnavailable(t *testing.T) {
+	// arrange
+	tradeService := newMockTradeService()
+	controller := newTradeController(tradeService)
+	tradeService.On("Propose", mock.Anything, uint(1)).Return(nil, service.ErrTradeUnavailable)
+	c, w := createTestContext(dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 2,
+		Offered:               []dto.TradeCard{{CardId: 3, Amount: 5}},
+	})
+
+	// act
+	controller.Propose(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_Trade_ShouldAccept(t *testing.T) {
+	// arrange
+	tradeService := newMockTradeService()
+	controller := newTradeController(tradeService)
+	tradeService.On("Accept", uint(5), uint(1)).Return(&dto.GetTrade{Id: 5, Status: "accepted"}, nil)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "5")
+
+	// act
+	controller.Accept(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	tradeService.AssertExpectations(t)
+}
+
+func Test_Trade_ShouldNotAcceptClosed(t *testing.T) {
+	// arrange
+	tradeService := newMockTradeService()
+	controller := newTradeController(tradeService)
+	tradeService.On("Accept", uint(5), uint(1)).Return(nil, service.ErrTradeClosed)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "5")
+
+	// act
+	controller.Accept(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_Trade_ShouldNotCancelOtherUsersTrade(t *testing.T) {
+	// arrange
+	tradeService := newMockTradeService()
+	controller := newTradeController(tradeService)
+	tradeService.On("Cancel", uint(5), uint(1)).Return(nil, service.ErrTradeNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "5")
+
+	// act
+	controller.Cancel(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_Trade_ShouldNotCounterInvalidId(t *testing.T) {
+	// arrange
+	tradeService := newMockTradeService()
+	controller := newTradeController(tradeService)
+	c, w := createTestContext(dto.PostCounterTrade{})
+	c.AddParam("id", "abc")
+
+	// act
+	controller.Counter(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	tradeService.AssertNotCalled(t, "Counter", mock.Anything, mock.Anything, mock.Anything)
+}
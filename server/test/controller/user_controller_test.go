@@ -2,6 +2,8 @@ package controller_test
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -9,13 +11,24 @@ import (
 	"github.com/stretchr/testify/mock"
 	"store.api/controller"
 	"store.api/dto"
+	"store.api/model"
 	"store.api/service"
 )
 
 func newUserController(userService service.UserService, cartService service.CartService) *controller.UserController {
+	return newUserControllerWithWishlist(userService, cartService, newMockWishlistService())
+}
+
+func newUserControllerWithWishlist(userService service.UserService, cartService service.CartService, wishlistService service.WishlistService) *controller.UserController {
+	return newUserControllerWithOrders(userService, cartService, wishlistService, newMockOrderService())
+}
+
+func newUserControllerWithOrders(userService service.UserService, cartService service.CartService, wishlistService service.WishlistService, orderService service.OrderService) *controller.UserController {
 	return controller.NewUserController(
 		userService,
 		cartService,
+		wishlistService,
+		orderService,
 		func(ctx *gin.Context) {},
 		func(s string, ctx *gin.Context) (string, error) {
 			return "1", nil
@@ -29,6 +42,7 @@ func Test_User_ShouldGetCart(t *testing.T) {
 	cartService := newMockCartService()
 	controller := newUserController(userService, cartService)
 	cartService.On("Get", mock.Anything).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
 	c, w := createTestContext(nil)
 
 	// act
@@ -38,6 +52,42 @@ func Test_User_ShouldGetCart(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 }
 
+func Test_User_ShouldGetCartInCurrency(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	cart := &dto.GetCart{Currency: "USD"}
+	cartService.On("Get", uint(1)).Return(cart, nil)
+	cartService.On("Convert", cart, "EUR").Return(&dto.GetCart{Currency: "EUR"}, nil)
+	c, w := createTestContext(nil)
+	c.Request.URL, _ = url.Parse("?currency=EUR")
+
+	// act
+	controller.GetCart(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"currency": "EUR"`)
+}
+
+func Test_User_ShouldNotGetCartInUnknownCurrency(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	cartService.On("Get", uint(1)).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "XYZ").Return(nil, service.ErrUnknownCurrency)
+	c, w := createTestContext(nil)
+	c.Request.URL, _ = url.Parse("?currency=XYZ")
+
+	// act
+	controller.GetCart(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
 func Test_User_ShouldNotGetCartNoUser(t *testing.T) {
 	// arrange
 	userService := newMockUserService()
@@ -59,6 +109,7 @@ func Test_User_ShouldEditCartSlot(t *testing.T) {
 	cartService := newMockCartService()
 	controller := newUserController(userService, cartService)
 	cartService.On("EditSlot", mock.Anything, mock.Anything).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
 	c, w := createTestContext(&dto.PostCartSlot{
 		CardId: 1,
 		Amount: 1,
@@ -142,3 +193,341 @@ func Test_User_ShouldNotGetInfo(t *testing.T) {
 	// assert
 	assert.Equal(t, 401, w.Code)
 }
+
+func Test_User_ShouldUpdateProfile(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	userService.On("UpdateProfile", uint(1), mock.Anything).Return(&dto.PrivateUserInfo{}, nil)
+	name := "name"
+	c, w := createTestContext(&dto.PatchProfile{
+		DisplayName: &name,
+	})
+
+	// act
+	controller.UpdateProfile(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldNotRequestEmailChangeTaken(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	userService.On("RequestEmailChange", uint(1), mock.Anything).Return(service.ErrEmailTaken)
+	c, w := createTestContext(&dto.EmailChange{
+		Email: "mail@mail.com",
+	})
+
+	// act
+	controller.RequestEmailChange(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_User_ShouldNotVerifyEmailInvalidToken(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	userService.On("VerifyEmail", uint(1), mock.Anything).Return(nil, service.ErrInvalidEmailToken)
+	c, w := createTestContext(&dto.EmailVerification{
+		Token: "token",
+	})
+
+	// act
+	controller.VerifyEmail(c)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_User_ShouldExport(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	userService.On("Export", uint(1)).Return(&dto.UserExport{}, nil)
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Export(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldDelete(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	userService.On("Delete", uint(1), mock.Anything, mock.Anything).Return(nil)
+	c, w := createTestContext(&dto.AccountDeletion{
+		Password: "password",
+	})
+
+	// act
+	controller.Delete(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldNotDeleteIncorrectPassword(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	userService.On("Delete", uint(1), mock.Anything, mock.Anything).Return(service.ErrIncorrectPassword)
+	c, w := createTestContext(&dto.AccountDeletion{
+		Password: "wrong",
+	})
+
+	// act
+	controller.Delete(c)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_User_ShouldGetWishlist(t *testing.T) {
+	// arrange
+	wishlistService := newMockWishlistService()
+	controller := newUserControllerWithWishlist(newMockUserService(), newMockCartService(), wishlistService)
+	wishlistService.On("All", uint(1)).Return([]*dto.GetWishlist{{ID: 1}})
+	c, w := createTestContext(nil)
+
+	// act
+	controller.GetWishlist(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldAddToWishlist(t *testing.T) {
+	// arrange
+	wishlistService := newMockWishlistService()
+	controller := newUserControllerWithWishlist(newMockUserService(), newMockCartService(), wishlistService)
+	wishlistService.On("Add", uint(1), mock.Anything).Return(&dto.GetWishlist{ID: 1}, nil)
+	key := "key"
+	c, w := createTestContext(&dto.PostWishlist{CardKeyId: &key})
+
+	// act
+	controller.AddToWishlist(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_User_ShouldNotAddToWishlistTwice(t *testing.T) {
+	// arrange
+	wishlistService := newMockWishlistService()
+	controller := newUserControllerWithWishlist(newMockUserService(), newMockCartService(), wishlistService)
+	wishlistService.On("Add", uint(1), mock.Anything).Return(nil, service.ErrAlreadyWished)
+	key := "key"
+	c, w := createTestContext(&dto.PostWishlist{CardKeyId: &key})
+
+	// act
+	controller.AddToWishlist(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_User_ShouldNotAddUnknownCardToWishlist(t *testing.T) {
+	// arrange
+	wishlistService := newMockWishlistService()
+	controller := newUserControllerWithWishlist(newMockUserService(), newMockCartService(), wishlistService)
+	wishlistService.On("Add", uint(1), mock.Anything).Return(nil, service.ErrCardNotFound)
+	id := uint(3)
+	c, w := createTestContext(&dto.PostWishlist{CardId: &id})
+
+	// act
+	controller.AddToWishlist(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_User_ShouldUpdateWishlist(t *testing.T) {
+	// arrange
+	wishlistService := newMockWishlistService()
+	controller := newUserControllerWithWishlist(newMockUserService(), newMockCartService(), wishlistService)
+	wishlistService.On("Update", uint(1), uint(2), mock.Anything).Return(&dto.GetWishlist{ID: 2}, nil)
+	price := model.Money(300)
+	c, w := createTestContext(&dto.PatchWishlist{MaxPrice: &price})
+	c.AddParam("id", "2")
+
+	// act
+	controller.UpdateWishlist(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldNotRemoveFromWishlistNotFound(t *testing.T) {
+	// arrange
+	wishlistService := newMockWishlistService()
+	controller := newUserControllerWithWishlist(newMockUserService(), newMockCartService(), wishlistService)
+	wishlistService.On("Delete", uint(1), uint(2)).Return(service.ErrWishlistNotFound)
+	c, w := createTestContext(nil)
+	c.AddParam("id", "2")
+
+	// act
+	controller.RemoveFromWishlist(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_User_ShouldEditCartSlots(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	cartService.On("EditSlots", mock.Anything, mock.Anything).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
+	c, w := createTestContext(&dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{
+			{CardId: 1, Amount: 1},
+			{CardId: 2, Amount: -1},
+		},
+	})
+
+	// act
+	controller.EditCartSlots(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldNotEditCartSlotsCardNotFound(t *testing.T) {
+	// arrange
+	userService := newMockUserService()
+	cartService := newMockCartService()
+	controller := newUserController(userService, cartService)
+	cartService.On("EditSlots", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: no card with id 2", service.ErrCardNotFound))
+	c, w := createTestContext(&dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{
+			{CardId: 2, Amount: 1},
+		},
+	})
+
+	// act
+	controller.EditCartSlots(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_User_ShouldApplyPromotion(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newUserController(newMockUserService(), cartService)
+	cartService.On("ApplyPromotion", uint(1), &dto.PromotionCode{Code: "SUMMER10"}).Return(&dto.GetCart{}, nil)
+	cartService.On("Convert", mock.Anything, "").Return(&dto.GetCart{}, nil)
+
+	c, w := createTestContext(dto.PromotionCode{Code: "SUMMER10"})
+
+	// act
+	controller.ApplyPromotion(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldNotApplyPromotionNotFound(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newUserController(newMockUserService(), cartService)
+	cartService.On("ApplyPromotion", uint(1), mock.Anything).Return(nil, service.ErrPromotionNotFound)
+
+	c, w := createTestContext(dto.PromotionCode{Code: "NOPE"})
+
+	// act
+	controller.ApplyPromotion(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_User_ShouldCheckout(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newUserController(newMockUserService(), cartService)
+	cartService.On("Checkout", uint(1)).Return(&dto.GetOrder{Id: 3}, nil)
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Checkout(c)
+
+	// assert
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_User_ShouldNotCheckoutOutOfStock(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newUserController(newMockUserService(), cartService)
+	cartService.On("Checkout", uint(1)).Return(nil, fmt.Errorf("%w: only 1 of Lightning Bolt left", service.ErrInsufficientStock))
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Checkout(c)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+}
+
+func Test_User_ShouldNotCheckoutNotVerified(t *testing.T) {
+	// arrange
+	cartService := newMockCartService()
+	controller := newUserController(newMockUserService(), cartService)
+	cartService.On("Checkout", uint(1)).Return(nil, service.ErrNotVerified)
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.Checkout(c)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_User_ShouldGetOrders(t *testing.T) {
+	// arrange
+	orderService := newMockOrderService()
+	controller := newUserControllerWithOrders(newMockUserService(), newMockCartService(), newMockWishlistService(), orderService)
+	orderService.On("All", uint(1)).Return([]*dto.GetOrder{{Id: 3}})
+
+	c, w := createTestContext(nil)
+
+	// act
+	controller.GetOrders(c)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+}
+
+func Test_User_ShouldNotGetOrderNotFound(t *testing.T) {
+	// arrange
+	orderService := newMockOrderService()
+	controller := newUserControllerWithOrders(newMockUserService(), newMockCartService(), newMockWishlistService(), orderService)
+	orderService.On("ById", uint(1), uint(3)).Return(nil, service.ErrOrderNotFound)
+
+	c, w := createTestContext(nil)
+	c.AddParam("id", "3")
+
+	// act
+	controller.GetOrder(c)
+
+	// assert
+	assert.Equal(t, 404, w.Code)
+}
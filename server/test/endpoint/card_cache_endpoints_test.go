@@ -60,7 +60,7 @@ func Test_CardCache_ShouldCreateAndCache(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -136,7 +136,7 @@ func Test_CardCache_ShouldFetchWithPatchedPrice(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -151,7 +151,7 @@ func Test_CardCache_ShouldFetchWithPatchedPrice(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	patch, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), dto.PriceUpdate{NewPrice: 100}, token)
+	patch, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), dto.PriceUpdate{NewPrice: 10000}, token)
 	assert.Equal(t, 200, patch.Code)
 
 	// act
@@ -203,7 +203,7 @@ func Test_CardCache_ShouldNotFetchWithPatchedPrice(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -218,7 +218,7 @@ func Test_CardCache_ShouldNotFetchWithPatchedPrice(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	patch, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), dto.PriceUpdate{NewPrice: 100}, token)
+	patch, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), dto.PriceUpdate{NewPrice: 10000}, token)
 	assert.Equal(t, 200, patch.Code)
 
 	// act
@@ -270,7 +270,7 @@ func Test_CardCache_ShouldFetchWithPatchedStockAmount(t *testing.T) {
 	card := dto.PostCard{
 		Name:          "card1",
 		Text:          "card text",
-		Price:         10,
+		Price:         1000,
 		InStockAmount: 0,
 		Type:          "ct1",
 		Language:      "ENG",
@@ -338,7 +338,7 @@ func Test_CardCache_ShouldNotFetchWithPatchedStockAmount(t *testing.T) {
 	card := dto.PostCard{
 		Name:          "card1",
 		Text:          "card text",
-		Price:         10,
+		Price:         1000,
 		InStockAmount: 10,
 		Type:          "ct1",
 		Language:      "ENG",
@@ -406,7 +406,7 @@ func Test_CardCache_ShouldFetchAfterUpdate(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -474,7 +474,7 @@ func Test_CardCache_ShouldNotFetchAfterUpdate(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -542,7 +542,7 @@ func Test_CardCache_ShouldFetchAndChangeAfterNewCard(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -607,7 +607,7 @@ func Test_CardCache_ShouldFetchAfterPatchedPrice(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "ct1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -626,7 +626,7 @@ func Test_CardCache_ShouldFetchAfterPatchedPrice(t *testing.T) {
 
 	req(r, t, "GET", query, nil, token)
 
-	patch, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), dto.PriceUpdate{NewPrice: 100}, token)
+	patch, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), dto.PriceUpdate{NewPrice: 10000}, token)
 	assert.Equal(t, 200, patch.Code)
 
 	// act
@@ -678,7 +678,7 @@ func Test_CardCache_ShouldFetchAfterPatchedInStockAmount(t *testing.T) {
 	card := dto.PostCard{
 		Name:          "card1",
 		Text:          "card text",
-		Price:         10,
+		Price:         1000,
 		InStockAmount: 0,
 		Type:          "ct1",
 		Language:      "ENG",
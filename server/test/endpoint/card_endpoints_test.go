@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"store.api/dto"
 	"store.api/model"
+	"store.api/service"
 )
 
 func Test_Card_ShouldNotCreateNoType(t *testing.T) {
@@ -18,7 +19,7 @@ func Test_Card_ShouldNotCreateNoType(t *testing.T) {
 	w, _ := req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:  "card name",
 		Text:  "card text",
-		Price: 10,
+		Price: 1000,
 	}, "")
 
 	// assert
@@ -82,7 +83,7 @@ func Test_Card_ShouldCreate(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -157,7 +158,7 @@ func Test_Card_ShouldNotCreateNotEnoughPrivileges(t *testing.T) {
 			w, _ := req(r, t, "POST", "/api/v1/card", dto.PostCard{
 				Name:      "card name",
 				Text:      "card text",
-				Price:     10,
+				Price:     1000,
 				Type:      "CT1",
 				Language:  "ENG",
 				Key:       "key1",
@@ -227,7 +228,7 @@ func Test_Card_ShouldPatch(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -316,7 +317,7 @@ func Test_Card_ShouldNotPatchBadData1(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Language:  "ENG",
 		Type:      "CT1",
 		Key:       "key1",
@@ -397,7 +398,7 @@ func Test_Card_ShouldNotPatchUnauthorized(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -450,7 +451,7 @@ func Test_Card_ShouldNotPatchCardNotFound(t *testing.T) {
 	update := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -521,7 +522,7 @@ func Test_Card_ShouldNotPatchBadData2(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -602,7 +603,7 @@ func Test_Card_ShouldPatchPrice(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -617,7 +618,7 @@ func Test_Card_ShouldPatchPrice(t *testing.T) {
 	}
 
 	update := dto.PriceUpdate{
-		NewPrice: 100,
+		NewPrice: 10000,
 	}
 
 	// act
@@ -635,7 +636,7 @@ func Test_Card_ShouldPatchPrice(t *testing.T) {
 	assert.Equal(t, card.Language, result.Language.ID)
 }
 
-func Test_Card_ShouldNotPatchPriceCardNotFound(t *testing.T) {
+func Test_Card_ShouldRecordPriceChange(t *testing.T) {
 	// arrange
 	r, db := setupRouter(10)
 	username := "user"
@@ -651,10 +652,101 @@ func Test_Card_ShouldNotPatchPriceCardNotFound(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	err = db.
+		Create(&model.CardType{
+			ID:       "CT1",
+			LongName: "Card type 1",
+		}).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.
+		Create(&model.Language{
+			ID:       "ENG",
+			LongName: "English",
+		}).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.
+		Create(&model.CardKey{
+			ID:      "key1",
+			EngName: "card1",
+		}).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.
+		Create(&model.Expansion{
+			ID:        "exp1",
+			ShortName: "exp1",
+			FullName:  "expansion",
+		}).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card := dto.PostCard{
+		Name:      "card1",
+		Text:      "card text",
+		Price:     10,
+		Type:      "CT1",
+		Language:  "ENG",
+		Key:       "key1",
+		Expansion: "exp1",
+	}
+
+	_, createdBody := req(r, t, "POST", "/api/v1/card", card, token)
+	var created dto.GetCard
+	err = json.Unmarshal(createdBody, &created)
+	if err != nil {
+		panic(err)
+	}
+
 	update := dto.PriceUpdate{
 		NewPrice: 100,
 	}
 
+	// act
+	w, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/price/%v", created.ID), update, token)
+	var changes []*model.PriceChange
+	err = db.
+		Where("card_id=?", created.ID).
+		Find(&changes).
+		Error
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, card.Price, changes[0].OldPrice)
+	assert.Equal(t, update.NewPrice, changes[0].NewPrice)
+}
+
+func Test_Card_ShouldNotPatchPriceCardNotFound(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := dto.PriceUpdate{
+		NewPrice: 10000,
+	}
+
 	// act
 	w, _ := req(r, t, "PATCH", "/api/v1/card/price/1", update, token)
 
@@ -719,7 +811,7 @@ func Test_Card_ShouldPatchPriceBadRequest(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -801,7 +893,7 @@ func Test_Card_ShouldPatchPriceNotAuthorized(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -826,7 +918,7 @@ func Test_Card_ShouldPatchPriceNotAuthorized(t *testing.T) {
 	}
 
 	update := dto.PriceUpdate{
-		NewPrice: 100,
+		NewPrice: 10000,
 	}
 
 	// act
@@ -893,7 +985,7 @@ func Test_Card_ShouldPatchInStockAmount(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -1010,7 +1102,7 @@ func Test_Card_ShouldPatchInStockAmountNotAuthorized(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -1214,7 +1306,7 @@ func Test_Card_ShouldCreateWithFoiling(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -1294,7 +1386,7 @@ func Test_Card_ShouldNotCreateInvalidFoiling(t *testing.T) {
 	card := dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -1308,3 +1400,409 @@ func Test_Card_ShouldNotCreateInvalidFoiling(t *testing.T) {
 	// assert
 	assert.Equal(t, 400, w.Code)
 }
+
+func Test_Card_ShouldPatchPricesInBulk(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Create(&model.CardType{ID: "CT1", LongName: "Card type 1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Language{ID: "ENG", LongName: "English"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardKey{ID: "key1", EngName: "card1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []uint{}
+	for _, name := range []string{"card1", "card2"} {
+		_, createdBody := req(r, t, "POST", "/api/v1/card", dto.PostCard{
+			Name:      name,
+			Text:      "card text",
+			Price:     1000,
+			Type:      "CT1",
+			Language:  "ENG",
+			Key:       "key1",
+			Expansion: "exp1",
+		}, token)
+		var created dto.GetCard
+		err = json.Unmarshal(createdBody, &created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	// cache the query before the update
+	req(r, t, "GET", "/api/v1/card?minPrice=15", nil, token)
+
+	update := dto.BulkPriceUpdate{
+		Prices: []dto.BulkPriceEntry{
+			{CardId: ids[0], Price: 2000},
+			{CardId: ids[1], Price: 3000},
+		},
+	}
+
+	// act
+	w, _ := req(r, t, "PATCH", "/api/v1/card/price", update, token)
+	_, body := req(r, t, "GET", "/api/v1/card?minPrice=15", nil, token)
+	var result service.CardQueryResult
+	err = json.Unmarshal(body, &result)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.TotalCount)
+}
+
+func Test_Card_ShouldNotPatchStockBelowZeroInBulk(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Create(&model.CardType{ID: "CT1", LongName: "Card type 1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Language{ID: "ENG", LongName: "English"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardKey{ID: "key1", EngName: "card1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []uint{}
+	for _, name := range []string{"card1", "card2"} {
+		_, createdBody := req(r, t, "POST", "/api/v1/card", dto.PostCard{
+			Name:          name,
+			Text:          "card text",
+			Price:         1000,
+			InStockAmount: 2,
+			Type:          "CT1",
+			Language:      "ENG",
+			Key:           "key1",
+			Expansion:     "exp1",
+		}, token)
+		var created dto.GetCard
+		err = json.Unmarshal(createdBody, &created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	update := dto.BulkStockUpdate{
+		Stock: []dto.BulkStockEntry{
+			{CardId: ids[0], Amount: 5, Relative: true},
+			{CardId: ids[1], Amount: -3, Relative: true},
+		},
+	}
+
+	// act
+	w, _ := req(r, t, "PATCH", "/api/v1/card/stocked", update, token)
+	_, body := req(r, t, "GET", fmt.Sprintf("/api/v1/card/%v", ids[0]), nil, token)
+	var result dto.GetCard
+	err = json.Unmarshal(body, &result)
+
+	// assert
+	assert.Equal(t, 409, w.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(2), result.InStockAmount)
+}
+
+func Test_Card_ShouldRecordStockMovements(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardType{ID: "CT1", LongName: "Card type 1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Language{ID: "ENG", LongName: "English"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardKey{ID: "key1", EngName: "card1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, createdBody := req(r, t, "POST", "/api/v1/card", dto.PostCard{
+		Name:          "card1",
+		Text:          "card text",
+		Price:         1000,
+		InStockAmount: 5,
+		Type:          "CT1",
+		Language:      "ENG",
+		Key:           "key1",
+		Expansion:     "exp1",
+	}, token)
+	var created dto.GetCard
+	err = json.Unmarshal(createdBody, &created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/stocked/%v", created.ID), dto.StockedAmountUpdate{
+		NewAmount: 3,
+		Kind:      "correction",
+		Reason:    "damaged",
+	}, token)
+
+	// act
+	w, body := req(r, t, "GET", fmt.Sprintf("/api/v1/card/stocked/%v/movements", created.ID), nil, token)
+	var result []dto.GetStockMovement
+	err = json.Unmarshal(body, &result)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, -2, result[0].Delta)
+	assert.Equal(t, uint(3), result[0].Balance)
+	assert.Equal(t, "damaged", result[0].Reason)
+	assert.Equal(t, "restock", result[1].Kind)
+	assert.Equal(t, uint(5), result[1].Balance)
+}
+
+func Test_Card_ShouldNotRestockByTakingCards(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardType{ID: "CT1", LongName: "Card type 1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Language{ID: "ENG", LongName: "English"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardKey{ID: "key1", EngName: "card1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, createdBody := req(r, t, "POST", "/api/v1/card", dto.PostCard{
+		Name:          "card1",
+		Text:          "card text",
+		Price:         1000,
+		InStockAmount: 5,
+		Type:          "CT1",
+		Language:      "ENG",
+		Key:           "key1",
+		Expansion:     "exp1",
+	}, token)
+	var created dto.GetCard
+	err = json.Unmarshal(createdBody, &created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	w, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/stocked/%v", created.ID), dto.StockedAmountUpdate{
+		NewAmount: 3,
+		Kind:      "restock",
+	}, token)
+
+	// assert
+	assert.Equal(t, 400, w.Code)
+	var card model.Card
+	db.First(&card, created.ID)
+	assert.Equal(t, uint(5), card.InStockAmount)
+}
+
+func Test_Card_ShouldRecordUpdatedStockAsCorrection(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardType{ID: "CT1", LongName: "Card type 1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Language{ID: "ENG", LongName: "English"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardKey{ID: "key1", EngName: "card1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, createdBody := req(r, t, "POST", "/api/v1/card", dto.PostCard{
+		Name:          "card1",
+		Text:          "card text",
+		Price:         1000,
+		InStockAmount: 5,
+		Type:          "CT1",
+		Language:      "ENG",
+		Key:           "key1",
+		Expansion:     "exp1",
+	}, token)
+	var created dto.GetCard
+	err = json.Unmarshal(createdBody, &created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	w, _ := req(r, t, "PATCH", fmt.Sprintf("/api/v1/card/%v", created.ID), dto.PostCard{
+		Name:          "card1",
+		Text:          "card text",
+		Price:         1000,
+		InStockAmount: 7,
+		Type:          "CT1",
+		Language:      "ENG",
+		Key:           "key1",
+		Expansion:     "exp1",
+	}, token)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	var movements []*model.StockMovement
+	db.Where("card_id=?", created.ID).Order("id").Find(&movements)
+	assert.Len(t, movements, 2)
+	assert.Equal(t, model.StockCorrection, movements[1].Kind)
+	assert.Equal(t, 2, movements[1].Delta)
+	assert.Equal(t, uint(7), movements[1].Balance)
+}
+
+func Test_Card_ShouldReportLowStock(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "user"
+	token := loginAs(r, t, username, "password", "mail@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("is_admin", true).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardType{ID: "CT1", LongName: "Card type 1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Language{ID: "ENG", LongName: "English"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.CardKey{ID: "key1", EngName: "card1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []uint{}
+	for _, amount := range []uint{1, 5} {
+		_, createdBody := req(r, t, "POST", "/api/v1/card", dto.PostCard{
+			Name:          "card1",
+			Text:          "card text",
+			Price:         1000,
+			InStockAmount: amount,
+			Type:          "CT1",
+			Language:      "ENG",
+			Key:           "key1",
+			Expansion:     "exp1",
+		}, token)
+		var created dto.GetCard
+		err = json.Unmarshal(createdBody, &created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	threshold := uint(2)
+	req(r, t, "PATCH", "/api/v1/card/expansions/exp1/threshold", dto.LowStockThresholdUpdate{Threshold: &threshold}, token)
+
+	// act
+	w, body := req(r, t, "GET", "/api/v1/card/low-stock", nil, token)
+	var result []dto.GetLowStockCard
+	err = json.Unmarshal(body, &result)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, ids[0], result[0].Card.ID)
+	assert.Equal(t, threshold, result[0].Threshold)
+}
@@ -91,7 +91,7 @@ func Test_CardKeywordQuery_ShouldFetchByName(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -100,7 +100,7 @@ func Test_CardKeywordQuery_ShouldFetchByName(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key2",
@@ -109,7 +109,7 @@ func Test_CardKeywordQuery_ShouldFetchByName(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -148,7 +148,7 @@ func Test_CardKeywordQuery_ShouldFetchByType(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT2",
 		Language:  "ENG",
 		Key:       "key1",
@@ -157,7 +157,7 @@ func Test_CardKeywordQuery_ShouldFetchByType(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key2",
@@ -166,7 +166,7 @@ func Test_CardKeywordQuery_ShouldFetchByType(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
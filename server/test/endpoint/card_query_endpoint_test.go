@@ -67,7 +67,7 @@ func Test_Card_ShouldFetchById(t *testing.T) {
 	_, b := req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -178,7 +178,7 @@ func Test_Card_ShouldFetchByType(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -187,7 +187,7 @@ func Test_Card_ShouldFetchByType(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT2",
 		Language:  "ENG",
 		Key:       "key2",
@@ -273,7 +273,7 @@ func Test_Card_ShouldFetchByName(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -282,7 +282,7 @@ func Test_Card_ShouldFetchByName(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key2",
@@ -359,7 +359,7 @@ func Test_Card_ShouldFetchByMinPrice(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -368,7 +368,7 @@ func Test_Card_ShouldFetchByMinPrice(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -445,7 +445,7 @@ func Test_Card_ShouldFetchByMaxPrice(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -454,7 +454,7 @@ func Test_Card_ShouldFetchByMaxPrice(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -540,7 +540,7 @@ func Test_Card_ShouldFetchByLanguage(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -549,7 +549,7 @@ func Test_Card_ShouldFetchByLanguage(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "RUS",
 		Key:       "key1",
@@ -635,7 +635,7 @@ func Test_Card_ShouldFetchByCardKey(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -644,7 +644,7 @@ func Test_Card_ShouldFetchByCardKey(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key2",
@@ -653,7 +653,7 @@ func Test_Card_ShouldFetchByCardKey(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -741,7 +741,7 @@ func Test_Card_ShouldFetchByExpansion(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -750,7 +750,7 @@ func Test_Card_ShouldFetchByExpansion(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -760,7 +760,7 @@ func Test_Card_ShouldFetchByExpansion(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -840,7 +840,7 @@ func Test_Card_ShouldFetchPages(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card1",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -849,7 +849,7 @@ func Test_Card_ShouldFetchPages(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -858,7 +858,7 @@ func Test_Card_ShouldFetchPages(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card3",
 		Text:      "card text",
-		Price:     10,
+		Price:     1000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -942,7 +942,7 @@ func Test_Card_ShouldFetchByBeingInStock(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:          "card1",
 		Text:          "card text",
-		Price:         10,
+		Price:         1000,
 		Type:          "CT1",
 		Language:      "ENG",
 		Key:           "key1",
@@ -952,7 +952,7 @@ func Test_Card_ShouldFetchByBeingInStock(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:          "card2",
 		Text:          "card text",
-		Price:         400,
+		Price:         40000,
 		Type:          "CT1",
 		Language:      "ENG",
 		Key:           "key1",
@@ -963,7 +963,7 @@ func Test_Card_ShouldFetchByBeingInStock(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -1059,7 +1059,7 @@ func Test_Card_ShouldFetchFoilOnly(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:          "card1",
 		Text:          "card text",
-		Price:         10,
+		Price:         1000,
 		Type:          "CT1",
 		Language:      "ENG",
 		Key:           "key1",
@@ -1070,7 +1070,7 @@ func Test_Card_ShouldFetchFoilOnly(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:          "card2",
 		Text:          "card text",
-		Price:         400,
+		Price:         40000,
 		Type:          "CT1",
 		Language:      "ENG",
 		Key:           "key1",
@@ -1082,7 +1082,7 @@ func Test_Card_ShouldFetchFoilOnly(t *testing.T) {
 	req(r, t, "POST", "/api/v1/card", dto.PostCard{
 		Name:      "card2",
 		Text:      "card text",
-		Price:     400,
+		Price:     40000,
 		Type:      "CT1",
 		Language:  "ENG",
 		Key:       "key1",
@@ -127,13 +127,14 @@ func Test_Collection_ShouldFetchAll(t *testing.T) {
 	// act
 	w, body := req(r, t, "GET", "/api/v1/collection/all", nil, token)
 
-	var result []*dto.GetCollection
+	var result dto.CollectionTree
 	err = json.Unmarshal(body, &result)
 
 	// assert
 	assert.Equal(t, 200, w.Code)
 	assert.Nil(t, err)
-	assert.Len(t, result, 3)
+	assert.Len(t, result.Collections, 3)
+	assert.Empty(t, result.Folders)
 }
 
 func Test_Collection_ShouldNotFetchAllUnauthorized(t *testing.T) {
@@ -277,7 +278,7 @@ func Test_Collection_ShouldNotAddCardUnverified(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -375,7 +376,7 @@ func Test_Collection_ShouldAddCard(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -474,7 +475,7 @@ func Test_Collection_ShouldNotEditSlotNegativeAmount(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -563,7 +564,7 @@ func Test_Collection_ShouldNotAddCardInvalidCollectionId(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -695,7 +696,7 @@ func Test_Collection_ShouldAddCardConsecutive(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -796,7 +797,7 @@ func Test_Collection_ShouldSubtractCardConsecutive(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -901,7 +902,7 @@ func Test_Collection_ShouldRemoveCard(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -1195,7 +1196,7 @@ func Test_Collection_ShouldUpdateCardSlotAfterCardModification(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
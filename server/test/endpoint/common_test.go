@@ -132,6 +132,13 @@ func setupRouter(cardPageSize uint) (*gin.Engine, *gorm.DB) {
 		},
 		Store: config.StoreConfiguration{
 			QueryKeywordLimit: 5,
+			Shipping: config.ShippingConfiguration{
+				BaseCost: 500,
+				FreeFrom: 10000,
+			},
+			GuestCartTtl:         3600,
+			Currency:             "USD",
+			CommissionPercentage: 10,
 		},
 	}
 
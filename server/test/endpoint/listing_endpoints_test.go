@@ -0,0 +1,96 @@
+package endpoint_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+func Test_Listing_ShouldOfferCheapestListing(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	username := "seller"
+	token := loginAs(r, t, username, "password", "seller@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username=?", username).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []interface{}{
+		&model.CardType{ID: "CT1", LongName: "Card type 1"},
+		&model.CardKey{ID: "key1", EngName: "card1"},
+		&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"},
+		&model.Language{ID: "ENG", LongName: "English"},
+	} {
+		err = db.Create(record).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	adminId := createAdmin(r, t, db)
+	cardId := createCard(t, db, &model.Card{
+		Name:          "card1",
+		Text:          "card text",
+		Price:         1000,
+		InStockAmount: 2,
+		PosterID:      adminId,
+		CardTypeID:    "CT1",
+		LanguageID:    "ENG",
+		CardKeyID:     "key1",
+		ExpansionID:   "exp1",
+	})
+
+	for _, price := range []model.Money{1200, 800} {
+		req(r, t, "POST", "/api/v1/listing", dto.PostListing{
+			CardId:    cardId,
+			Price:     price,
+			Condition: "NM",
+			Amount:    1,
+		}, token)
+	}
+
+	// act
+	w, body := req(r, t, "GET", fmt.Sprintf("/api/v1/listing/card/%v", cardId), nil, "")
+	var offers dto.CardOffers
+	err = json.Unmarshal(body, &offers)
+	_, queryBody := req(r, t, "GET", "/api/v1/card?name=card1", nil, "")
+	var queried service.CardQueryResult
+	queryErr := json.Unmarshal(queryBody, &queried)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Nil(t, err)
+	assert.Len(t, offers.Listings, 2)
+	assert.Equal(t, model.Money(800), offers.Cheapest.Price)
+	assert.Equal(t, username, offers.Cheapest.Seller)
+	assert.False(t, offers.StoreIsCheapest)
+	assert.Nil(t, queryErr)
+	assert.Equal(t, uint(2), queried.Cards[0].Offers.Listings)
+	assert.Equal(t, offers.Cheapest.Id, queried.Cards[0].Offers.CheapestListingId)
+}
+
+func Test_Listing_ShouldNotCreateNotVerified(t *testing.T) {
+	// arrange
+	r, _ := setupRouter(10)
+	token := loginAs(r, t, "seller", "password", "seller@mail.com")
+
+	// act
+	w, _ := req(r, t, "POST", "/api/v1/listing", dto.PostListing{
+		CardId:    1,
+		Price:     500,
+		Condition: "NM",
+		Amount:    1,
+	}, token)
+
+	// assert
+	assert.Equal(t, 403, w.Code)
+}
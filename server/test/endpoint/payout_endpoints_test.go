@@ -0,0 +1,96 @@
+package endpoint_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"store.api/dto"
+	"store.api/model"
+)
+
+func Test_Payout_ShouldPayOutReconciledSales(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	sellerToken := loginAs(r, t, "seller", "password", "seller@mail.com")
+	buyerToken := loginAs(r, t, "buyer", "password", "buyer@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username IN ?", []string{"seller", "buyer"}).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seller model.User
+	err = db.Where("username=?", "seller").Find(&seller).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []interface{}{
+		&model.CardType{ID: "CT1", LongName: "Card type 1"},
+		&model.CardKey{ID: "key1", EngName: "card1"},
+		&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"},
+		&model.Language{ID: "ENG", LongName: "English"},
+	} {
+		err = db.Create(record).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	adminId := createAdmin(r, t, db)
+	adminToken := loginAs(r, t, "admin", "password", "admin@mail.com")
+	cardId := createCard(t, db, &model.Card{
+		Name:        "card1",
+		Text:        "card text",
+		Price:       1000,
+		PosterID:    adminId,
+		CardTypeID:  "CT1",
+		LanguageID:  "ENG",
+		CardKeyID:   "key1",
+		ExpansionID: "exp1",
+	})
+
+	_, listingBody := req(r, t, "POST", "/api/v1/listing", dto.PostListing{
+		CardId:    cardId,
+		Price:     1000,
+		Condition: "NM",
+		Amount:    3,
+	}, sellerToken)
+	var listing dto.GetListing
+	err = json.Unmarshal(listingBody, &listing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	today := time.Now().UTC().Format(time.DateOnly)
+
+	// act
+	buyW, _ := req(r, t, "POST", fmt.Sprintf("/api/v1/listing/%v/buy", listing.Id), dto.PostListingPurchase{Amount: 2}, buyerToken)
+	_, statementBody := req(r, t, "GET", "/api/v1/payout/statement", nil, sellerToken)
+	var statement dto.PayoutStatement
+	statementErr := json.Unmarshal(statementBody, &statement)
+	forbiddenW, _ := req(r, t, "POST", "/api/v1/payout", dto.PostPayout{SellerId: seller.ID, From: today, To: today}, sellerToken)
+	payW, payBody := req(r, t, "POST", "/api/v1/payout", dto.PostPayout{SellerId: seller.ID, From: today, To: today}, adminToken)
+	var payout dto.GetPayout
+	payErr := json.Unmarshal(payBody, &payout)
+	againW, _ := req(r, t, "POST", "/api/v1/payout", dto.PostPayout{SellerId: seller.ID, From: today, To: today}, adminToken)
+
+	// assert
+	assert.Equal(t, 201, buyW.Code)
+	assert.Nil(t, statementErr)
+	assert.Len(t, statement.Sales, 1)
+	assert.Equal(t, model.Money(2000), statement.Gross)
+	assert.Equal(t, model.Money(200), statement.Commission)
+	assert.Equal(t, model.Money(1800), statement.Unpaid)
+	assert.True(t, statement.Reconciled)
+	assert.Equal(t, 403, forbiddenW.Code)
+	assert.Equal(t, 201, payW.Code)
+	assert.Nil(t, payErr)
+	assert.Equal(t, model.Money(1800), payout.Amount)
+	assert.Equal(t, model.Money(200), payout.Commission)
+	assert.Equal(t, uint(1), payout.Sales)
+	assert.Equal(t, 409, againW.Code)
+}
@@ -0,0 +1,109 @@
+package endpoint_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"store.api/dto"
+	"store.api/model"
+)
+
+func Test_Trade_ShouldExchangeCardsOnAccept(t *testing.T) {
+	// arrange
+	r, db := setupRouter(10)
+	proposerToken := loginAs(r, t, "proposer", "password", "proposer@mail.com")
+	recipientToken := loginAs(r, t, "recipient", "password", "recipient@mail.com")
+	err := db.
+		Model(&model.User{}).
+		Where("username IN ?", []string{"proposer", "recipient"}).
+		Update("verified", true).
+		Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []model.User
+	err = db.Order("username").Find(&users, "username IN ?", []string{"proposer", "recipient"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []interface{}{
+		&model.CardType{ID: "CT1", LongName: "Card type 1"},
+		&model.CardKey{ID: "key1", EngName: "card1"},
+		&model.CardKey{ID: "key2", EngName: "card2"},
+		&model.Expansion{ID: "exp1", ShortName: "exp1", FullName: "expansion"},
+		&model.Language{ID: "ENG", LongName: "English"},
+	} {
+		err = db.Create(record).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	adminId := createAdmin(r, t, db)
+	cardIds := []uint{}
+	for _, key := range []string{"key1", "key2"} {
+		cardIds = append(cardIds, createCard(t, db, &model.Card{
+			Name:        key,
+			Text:        "card text",
+			Price:       100,
+			PosterID:    adminId,
+			CardTypeID:  "CT1",
+			LanguageID:  "ENG",
+			CardKeyID:   key,
+			ExpansionID: "exp1",
+		}))
+	}
+
+	own := &model.Collection{
+		Name:    "own",
+		OwnerID: users[0].ID,
+		Cards:   []model.CollectionSlot{{CardID: cardIds[0], Amount: 2}},
+	}
+	other := &model.Collection{
+		Name:       "other",
+		OwnerID:    users[1].ID,
+		Visibility: model.VisibilityPublic,
+		Cards:      []model.CollectionSlot{{CardID: cardIds[1], Amount: 1}},
+	}
+	for _, collection := range []*model.Collection{own, other} {
+		err = db.Create(collection).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, proposedBody := req(r, t, "POST", "/api/v1/trade", dto.PostTrade{
+		CollectionId:          own.ID,
+		RecipientCollectionId: other.ID,
+		Offered:               []dto.TradeCard{{CardId: cardIds[0], Amount: 2}},
+		Requested:             []dto.TradeCard{{CardId: cardIds[1], Amount: 1}},
+	}, proposerToken)
+	var proposed dto.GetTrade
+	err = json.Unmarshal(proposedBody, &proposed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	w, body := req(r, t, "POST", fmt.Sprintf("/api/v1/trade/%v/accept", proposed.Id), nil, recipientToken)
+	var accepted dto.GetTrade
+	err = json.Unmarshal(body, &accepted)
+	againW, _ := req(r, t, "POST", fmt.Sprintf("/api/v1/trade/%v/accept", proposed.Id), nil, recipientToken)
+	var ownSlots, otherSlots []model.CollectionSlot
+	db.Find(&ownSlots, "collection_id = ?", own.ID)
+	db.Find(&otherSlots, "collection_id = ?", other.ID)
+
+	// assert
+	assert.Equal(t, 200, w.Code)
+	assert.Nil(t, err)
+	assert.Equal(t, string(model.TradeAccepted), accepted.Status)
+	assert.Equal(t, 409, againW.Code)
+	assert.Len(t, ownSlots, 1)
+	assert.Equal(t, cardIds[1], ownSlots[0].CardID)
+	assert.Equal(t, uint(1), ownSlots[0].Amount)
+	assert.Len(t, otherSlots, 1)
+	assert.Equal(t, cardIds[0], otherSlots[0].CardID)
+	assert.Equal(t, uint(2), otherSlots[0].Amount)
+}
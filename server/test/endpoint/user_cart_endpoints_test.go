@@ -96,7 +96,7 @@ func Test_UserCart_ShouldAddCard(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -182,7 +182,7 @@ func Test_UserCart_ShouldNotEditSlotNegativeAmount(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -304,7 +304,7 @@ func Test_UserCart_ShouldAddCardConsecutive(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -392,7 +392,7 @@ func Test_User_Cart_ShouldSubtractCardConsecutive(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
@@ -484,7 +484,7 @@ func Test_UserCart_ShouldRemoveCard(t *testing.T) {
 	cardId := createCard(t, db, &model.Card{
 		Name:        "card1",
 		Text:        "card text",
-		Price:       1,
+		Price:       100,
 		PosterID:    adminId,
 		CardTypeID:  "CT1",
 		LanguageID:  "ENG",
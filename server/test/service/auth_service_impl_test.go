@@ -2,10 +2,12 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"store.api/config"
 	"store.api/dto"
 	"store.api/model"
 	"store.api/security"
@@ -13,11 +15,38 @@ import (
 )
 
 func newAuthService(userRepo *MockUserRepository, cartRepo *MockCartRepository) service.AuthService {
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+
+	auditRepo.On("Save", mock.Anything).Return(nil)
+	attempts.On("LockedFor", mock.Anything).Return(time.Duration(0))
+	attempts.On("Fail", mock.Anything, mock.Anything).Return(int64(1))
+	attempts.On("Reset", mock.Anything)
+
+	return newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+}
+
+func newAuthServiceWithAttempts(userRepo *MockUserRepository, cartRepo *MockCartRepository, auditRepo *MockLoginAuditRepository, attempts *MockLoginAttemptCache) service.AuthService {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
 	return service.NewAuthServiceImpl(
+		&config.Configuration{
+			JwtRealm: "card-store",
+			Auth: config.AuthConfiguration{
+				RecoveryCodeCount: 10,
+				Lockout: config.LockoutConfiguration{
+					MaxUsernameFailures: 3,
+					MaxIpFailures:       10,
+					FailureWindow:       60,
+					BaseLockout:         30,
+					MaxLockout:          100,
+				},
+			},
+		},
 		userRepo,
 		cartRepo,
+		auditRepo,
+		attempts,
 		validate,
 	)
 }
@@ -159,3 +188,498 @@ func Test_User_ShouldLogin(t *testing.T) {
 	assert.NotNil(t, login)
 	assert.Nil(t, err)
 }
+
+func Test_User_ShouldNotLoginTwoFactorCodeMissing(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	hash, _ := security.HashPassword(data.Password)
+	secret, _ := security.GenerateTotpSecret()
+	existing := model.User{
+		Username:         data.Username,
+		PasswordHash:     hash,
+		TwoFactorSecret:  secret,
+		TwoFactorEnabled: true,
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(&existing)
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrTwoFactorRequired, err)
+}
+
+func Test_User_ShouldLoginTwoFactor(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	service := newAuthService(userRepo, cartRepo)
+	secret, _ := security.GenerateTotpSecret()
+	code, _ := security.TotpCode(secret, time.Now())
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+		Code:     code,
+	}
+	hash, _ := security.HashPassword(data.Password)
+	existing := model.User{
+		Username:         data.Username,
+		PasswordHash:     hash,
+		TwoFactorSecret:  secret,
+		TwoFactorEnabled: true,
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(&existing)
+	userRepo.On("UseTotpStep", existing.ID, mock.Anything).Return(true, nil)
+
+	// act
+	login, err := service.Login(&data)
+
+	// assert
+	assert.NotNil(t, login)
+	assert.Nil(t, err)
+}
+
+func Test_User_ShouldNotLoginTwoFactorReusedCode(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+	secret, _ := security.GenerateTotpSecret()
+	code, _ := security.TotpCode(secret, time.Now())
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+		Code:     code,
+	}
+	hash, _ := security.HashPassword(data.Password)
+	existing := model.User{
+		Username:         data.Username,
+		PasswordHash:     hash,
+		TwoFactorSecret:  secret,
+		TwoFactorEnabled: true,
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(&existing)
+	userRepo.On("UseTotpStep", existing.ID, mock.Anything).Return(false, nil)
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrInvalidTwoFactorCode, err)
+}
+
+func Test_User_ShouldLoginTwoFactorRecoveryCode(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	service := newAuthService(userRepo, cartRepo)
+	secret, _ := security.GenerateTotpSecret()
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+		Code:     "abcde-12345",
+	}
+	hash, _ := security.HashPassword(data.Password)
+	existing := model.User{
+		Username:         data.Username,
+		PasswordHash:     hash,
+		TwoFactorSecret:  secret,
+		TwoFactorEnabled: true,
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(&existing)
+	userRepo.On("UseRecoveryCode", existing.ID, security.HashRecoveryCode("", data.Code)).Return(true, nil)
+
+	// act
+	login, err := service.Login(&data)
+
+	// assert
+	assert.NotNil(t, login)
+	assert.Nil(t, err)
+}
+
+func Test_User_ShouldNotLoginTwoFactorInvalidCode(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+	secret, _ := security.GenerateTotpSecret()
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+		Code:     "000000",
+	}
+	hash, _ := security.HashPassword(data.Password)
+	existing := model.User{
+		Username:         data.Username,
+		PasswordHash:     hash,
+		TwoFactorSecret:  secret,
+		TwoFactorEnabled: true,
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(&existing)
+	userRepo.On("UseRecoveryCode", mock.Anything, mock.Anything).Return(false, nil)
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrInvalidTwoFactorCode, err)
+}
+
+func Test_User_ShouldEnrollTwoFactor(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	service := newAuthService(userRepo, cartRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Username: "user"})
+	userRepo.On("Update", mock.Anything).Return(nil)
+	userRepo.On("ReplaceRecoveryCodes", mock.Anything, mock.Anything).Return(nil)
+
+	// act
+	result, err := service.EnrollTwoFactor(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, result.Secret)
+	assert.Contains(t, result.Uri, "otpauth://totp/card-store:user")
+	assert.Len(t, result.RecoveryCodes, 10)
+}
+
+func Test_User_ShouldNotEnrollTwoFactorAlreadyEnabled(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{TwoFactorEnabled: true})
+
+	// act
+	result, err := s.EnrollTwoFactor(1)
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrTwoFactorAlreadyEnabled, err)
+}
+
+func Test_User_ShouldConfirmTwoFactor(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	service := newAuthService(userRepo, cartRepo)
+	secret, _ := security.GenerateTotpSecret()
+	code, _ := security.TotpCode(secret, time.Now())
+	user := &model.User{TwoFactorSecret: secret}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("Update", mock.Anything).Return(nil)
+
+	// act
+	err := service.ConfirmTwoFactor(1, &dto.TwoFactorCode{Code: code})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, user.TwoFactorEnabled)
+	assert.NotZero(t, user.TotpLastStep)
+}
+
+func Test_User_ShouldNotConfirmTwoFactorNotEnrolled(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+
+	// act
+	err := s.ConfirmTwoFactor(1, &dto.TwoFactorCode{Code: "123456"})
+
+	// assert
+	assert.Equal(t, service.ErrTwoFactorNotEnrolled, err)
+}
+
+func Test_User_ShouldNotLoginLocked(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+	s := newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+
+	attempts.On("LockedFor", "user:user").Return(time.Minute)
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrLoginLocked, err)
+	userRepo.AssertNotCalled(t, "FindByUsername", mock.Anything)
+}
+
+func Test_User_ShouldNotLoginIpLocked(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+	s := newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+		ClientIp: "10.0.0.1",
+	}
+
+	attempts.On("LockedFor", "user:user").Return(time.Duration(0))
+	attempts.On("LockedFor", "ip:10.0.0.1").Return(time.Minute)
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrLoginLocked, err)
+}
+
+func Test_User_ShouldRecordFailedLogin(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+	service := newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+		ClientIp: "10.0.0.1",
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(nil)
+	attempts.On("LockedFor", mock.Anything).Return(time.Duration(0))
+	attempts.On("Fail", "user:user", time.Minute).Return(int64(1))
+	attempts.On("Fail", "ip:10.0.0.1", time.Minute).Return(int64(1))
+	auditRepo.On("Save", mock.MatchedBy(func(f *model.FailedLogin) bool {
+		return f.Username == data.Username && f.ClientIp == data.ClientIp
+	})).Return(nil)
+
+	// act
+	login, err := service.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.NotNil(t, err)
+	attempts.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+	auditRepo.AssertExpectations(t)
+}
+
+func Test_User_ShouldLockWithBackoff(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+	service := newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(nil)
+	auditRepo.On("Save", mock.Anything).Return(nil)
+	attempts.On("LockedFor", mock.Anything).Return(time.Duration(0))
+	attempts.On("Fail", "user:user", mock.Anything).Return(int64(4))
+	attempts.On("Lock", "user:user", 60*time.Second)
+
+	// act
+	_, err := service.Login(&data)
+
+	// assert
+	assert.NotNil(t, err)
+	attempts.AssertExpectations(t)
+}
+
+func Test_User_ShouldCapLockout(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+	service := newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+
+	userRepo.On("FindByUsername", data.Username).Return(nil)
+	auditRepo.On("Save", mock.Anything).Return(nil)
+	attempts.On("LockedFor", mock.Anything).Return(time.Duration(0))
+	attempts.On("Fail", "user:user", mock.Anything).Return(int64(50))
+	attempts.On("Lock", "user:user", 100*time.Second)
+
+	// act
+	_, err := service.Login(&data)
+
+	// assert
+	assert.NotNil(t, err)
+	attempts.AssertExpectations(t)
+}
+
+func Test_User_ShouldUnlock(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	auditRepo := newMockLoginAuditRepository()
+	attempts := newMockLoginAttemptCache()
+	service := newAuthServiceWithAttempts(userRepo, cartRepo, auditRepo, attempts)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Username: "user"})
+	attempts.On("Reset", "user:user")
+
+	// act
+	err := service.UnlockUser(1)
+
+	// assert
+	assert.Nil(t, err)
+	attempts.AssertExpectations(t)
+}
+
+func Test_User_ShouldNotLoginSuspended(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	hash, _ := security.HashPassword(data.Password)
+
+	userRepo.On("FindByUsername", data.Username).Return(&model.User{
+		Username:     data.Username,
+		PasswordHash: hash,
+		Suspended:    true,
+	})
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrUserSuspended, err)
+}
+
+func Test_User_ShouldLoginSuspensionEnded(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	service := newAuthService(userRepo, cartRepo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	hash, _ := security.HashPassword(data.Password)
+	until := time.Now().Add(-time.Minute)
+
+	userRepo.On("FindByUsername", data.Username).Return(&model.User{
+		Username:       data.Username,
+		PasswordHash:   hash,
+		Suspended:      true,
+		SuspendedUntil: &until,
+	})
+
+	// act
+	login, err := service.Login(&data)
+
+	// assert
+	assert.NotNil(t, login)
+	assert.Nil(t, err)
+}
+
+func Test_User_ShouldNotLoginPasswordResetRequired(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+	data := dto.LoginDetails{
+		Username: "user",
+		Password: "password",
+	}
+	hash, _ := security.HashPassword(data.Password)
+
+	userRepo.On("FindByUsername", data.Username).Return(&model.User{
+		Username:              data.Username,
+		PasswordHash:          hash,
+		PasswordResetRequired: true,
+	})
+
+	// act
+	login, err := s.Login(&data)
+
+	// assert
+	assert.Nil(t, login)
+	assert.Equal(t, service.ErrPasswordResetRequired, err)
+}
+
+func Test_User_ShouldResetPassword(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	service := newAuthService(userRepo, cartRepo)
+	expires := time.Now().Add(time.Minute)
+	user := &model.User{
+		Username:               "user",
+		PasswordResetRequired:  true,
+		PasswordResetTokenHash: security.HashToken("token"),
+		PasswordResetExpiresAt: &expires,
+	}
+
+	userRepo.On("FindByPasswordResetToken", security.HashToken("token")).Return(user)
+	userRepo.On("Update", user).Return(nil)
+
+	// act
+	err := service.ResetPassword(&dto.PasswordReset{Token: "token", Password: "new password"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.False(t, user.PasswordResetRequired)
+	assert.Empty(t, user.PasswordResetTokenHash)
+	assert.True(t, security.CheckPasswordHash("new password", user.PasswordHash))
+}
+
+func Test_User_ShouldNotResetPasswordExpiredToken(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	s := newAuthService(userRepo, cartRepo)
+	expires := time.Now().Add(-time.Minute)
+
+	userRepo.On("FindByPasswordResetToken", security.HashToken("token")).Return(&model.User{
+		PasswordResetRequired:  true,
+		PasswordResetTokenHash: security.HashToken("token"),
+		PasswordResetExpiresAt: &expires,
+	})
+
+	// act
+	err := s.ResetPassword(&dto.PasswordReset{Token: "token", Password: "new password"})
+
+	// assert
+	assert.Equal(t, service.ErrInvalidResetToken, err)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
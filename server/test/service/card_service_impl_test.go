@@ -2,33 +2,55 @@ package service_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 	"store.api/config"
 	"store.api/dto"
 	"store.api/model"
 	"store.api/query"
+	"store.api/repository"
 	"store.api/service"
 )
 
 func newCardService(cardRepo *MockCardRepository, userRepo *MockUserRepository, langRepo *MockLanguageRepository, expRepo *MockExpansionRepository) service.CardService {
-	validate := validator.New(validator.WithRequiredStructEnabled())
+	return newCardServiceWithRates(cardRepo, userRepo, langRepo, expRepo, newMockExchangeRateRepository())
+}
 
-	return service.NewCardServiceImpl(
-		&config.Configuration{
-			Db: config.DbConfiguration{
-				Cards: config.CardsDbConfiguration{
-					PageSize: 30,
-				},
+func newCardServiceWithRates(cardRepo *MockCardRepository, userRepo *MockUserRepository, langRepo *MockLanguageRepository, expRepo *MockExpansionRepository, rateRepo *MockExchangeRateRepository) service.CardService {
+	// cards without listings
+	listingRepo := newMockListingRepository()
+	listingRepo.On("Summaries", mock.Anything).Return(map[uint]*repository.OfferSummary{})
+
+	return newCardServiceWithListings(cardRepo, userRepo, langRepo, expRepo, rateRepo, listingRepo)
+}
+
+func newCardServiceWithListings(cardRepo *MockCardRepository, userRepo *MockUserRepository, langRepo *MockLanguageRepository, expRepo *MockExpansionRepository, rateRepo *MockExchangeRateRepository, listingRepo *MockListingRepository) service.CardService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	config := &config.Configuration{
+		Db: config.DbConfiguration{
+			Cards: config.CardsDbConfiguration{
+				PageSize: 30,
 			},
 		},
+		Store: config.StoreConfiguration{
+			Currency: "USD",
+		},
+	}
+
+	return service.NewCardServiceImpl(
+		config,
 		cardRepo,
 		userRepo,
 		langRepo,
 		expRepo,
+		newMockCardKeyRepository(),
+		listingRepo,
+		service.NewCurrencyConverter(config, rateRepo),
 		validate,
 	)
 }
@@ -120,7 +142,7 @@ func Test_Card_ShouldGetById(t *testing.T) {
 	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
 
 	// act
-	card, err := service.GetById(1)
+	card, err := service.GetById(1, "")
 
 	// assert
 	assert.NotNil(t, card)
@@ -138,7 +160,7 @@ func Test_Card_ShouldNotGetById(t *testing.T) {
 	cardRepo.On("FindById", mock.Anything).Return(nil)
 
 	// act
-	card, err := service.GetById(1)
+	card, err := service.GetById(1, "")
 
 	// assert
 	assert.Nil(t, card)
@@ -157,10 +179,93 @@ func Test_Card_ShouldGetByQuery(t *testing.T) {
 	cardRepo.On("Count").Return(0)
 
 	// act
-	cards := service.Query(&query.CardQuery{})
+	cards, err := service.Query(&query.CardQuery{})
 
 	// assert
 	assert.NotNil(t, cards)
+	assert.Nil(t, err)
+}
+
+func Test_Card_ShouldGetByIdInCurrency(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	rateRepo := newMockExchangeRateRepository()
+	cardService := newCardServiceWithRates(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository(), rateRepo)
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+	rateRepo.On("FindByCurrency", "EUR").Return(&model.ExchangeRate{Currency: "EUR", Rate: 0.9})
+
+	// act
+	card, err := cardService.GetById(1, "eur")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(900), card.Price)
+	assert.Equal(t, "EUR", card.Currency)
+}
+
+func Test_Card_ShouldNotGetByIdInUnknownCurrency(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	rateRepo := newMockExchangeRateRepository()
+	cardService := newCardServiceWithRates(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository(), rateRepo)
+
+	rateRepo.On("FindByCurrency", "XYZ").Return(nil)
+
+	// act
+	card, err := cardService.GetById(1, "XYZ")
+
+	// assert
+	assert.Nil(t, card)
+	assert.ErrorIs(t, err, service.ErrUnknownCurrency)
+	cardRepo.AssertNotCalled(t, "FindById", mock.Anything)
+}
+
+func Test_Card_ShouldQueryPricesInCurrency(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	rateRepo := newMockExchangeRateRepository()
+	cardService := newCardServiceWithRates(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository(), rateRepo)
+
+	rateRepo.On("FindByCurrency", "EUR").Return(&model.ExchangeRate{Currency: "EUR", Rate: 0.5})
+	cardRepo.On("Query", mock.MatchedBy(func(q *query.CardQuery) bool {
+		// cached by the converted filters rather than the requested ones
+		return q.MinPrice == 1000 && q.MaxPrice == -1 && strings.Contains(q.Raw, "minPrice=10.00") && !strings.Contains(q.Raw, "EUR")
+	})).Return([]*model.Card{{Price: 1200}}, 1)
+
+	// act
+	result, err := cardService.Query(&query.CardQuery{Raw: "currency=EUR&minPrice=500", Currency: "EUR", MinPrice: 500, MaxPrice: -1})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(600), result.Cards[0].Price)
+	assert.Equal(t, "EUR", result.Cards[0].Currency)
+}
+
+func Test_Card_ShouldQueryWithCheapestOffer(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	rateRepo := newMockExchangeRateRepository()
+	listingRepo := newMockListingRepository()
+	cardService := newCardServiceWithListings(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository(), rateRepo, listingRepo)
+
+	rateRepo.On("FindByCurrency", "EUR").Return(&model.ExchangeRate{Currency: "EUR", Rate: 0.5})
+	cardRepo.On("Query", mock.Anything).Return([]*model.Card{
+		{Model: gorm.Model{ID: 1}, Price: 1200},
+		{Model: gorm.Model{ID: 2}, Price: 300},
+	}, 2)
+	listingRepo.On("Summaries", []uint{1, 2}).Return(map[uint]*repository.OfferSummary{
+		1: {CardID: 1, Listings: 2, Amount: 5, CheapestID: 7, CheapestPrice: 1000},
+	})
+
+	// act
+	result, err := cardService.Query(&query.CardQuery{Currency: "EUR", MinPrice: -1, MaxPrice: -1})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(7), result.Cards[0].Offers.CheapestListingId)
+	assert.Equal(t, model.Money(500), result.Cards[0].Offers.CheapestPrice)
+	assert.Nil(t, result.Cards[1].Offers)
 }
 
 func Test_Card_ShouldUpdate(t *testing.T) {
@@ -172,7 +277,7 @@ func Test_Card_ShouldUpdate(t *testing.T) {
 	service := newCardService(cardRepo, userRepo, langRepo, expRepo)
 
 	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
-	cardRepo.On("Update", mock.Anything).Return(nil)
+	cardRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
 
 	// act
 	card, err := service.Update(&dto.PostCard{
@@ -183,7 +288,7 @@ func Test_Card_ShouldUpdate(t *testing.T) {
 		Language:  "ENG",
 		Key:       "key1",
 		Expansion: "exp1",
-	}, 1)
+	}, 1, 1)
 
 	// assert
 	assert.NotNil(t, card)
@@ -199,7 +304,7 @@ func Test_Card_ShouldNotUpdate(t *testing.T) {
 	service := newCardService(cardRepo, userRepo, langRepo, expRepo)
 
 	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
-	cardRepo.On("Update", mock.Anything).Return(errors.New(""))
+	cardRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New(""))
 
 	// act
 	card, err := service.Update(&dto.PostCard{
@@ -210,7 +315,7 @@ func Test_Card_ShouldNotUpdate(t *testing.T) {
 		Language:  "ENG",
 		Key:       "key1",
 		Expansion: "exp1",
-	}, 1)
+	}, 1, 1)
 
 	// assert
 	assert.Nil(t, card)
@@ -236,7 +341,7 @@ func Test_Card_ShouldNotUpdateCardNotFound(t *testing.T) {
 		Language:  "ENG",
 		Key:       "key1",
 		Expansion: "exp1",
-	}, 1)
+	}, 1, 1)
 
 	// assert
 	assert.Nil(t, card)
@@ -315,12 +420,12 @@ func Test_Card_ShouldUpdateInStockAmount(t *testing.T) {
 	service := newCardService(cardRepo, userRepo, langRepo, expRepo)
 
 	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
-	cardRepo.On("UpdateInStockAmount", mock.Anything, mock.Anything).Return(&model.Card{}, nil)
+	cardRepo.On("UpdateInStockAmount", mock.Anything, mock.Anything, mock.Anything).Return(&model.Card{}, nil)
 
 	// act
 	card, err := service.UpdateInStockAmount(1, &dto.StockedAmountUpdate{
 		NewAmount: 10,
-	})
+	}, 1)
 
 	// assert
 	assert.NotNil(t, card)
@@ -336,18 +441,384 @@ func Test_Card_ShouldNotUpdateInStockAmountCardNotFound(t *testing.T) {
 	s := newCardService(cardRepo, userRepo, langRepo, expRepo)
 
 	// cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
-	cardRepo.On("UpdateInStockAmount", mock.Anything, mock.Anything).Return(nil, nil)
+	cardRepo.On("UpdateInStockAmount", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
 	// act
 	card, err := s.UpdateInStockAmount(1, &dto.StockedAmountUpdate{
 		NewAmount: 10,
-	})
+	}, 1)
 
 	// assert
 	assert.Nil(t, card)
 	assert.Equal(t, service.ErrCardNotFound, err)
 }
 
+func Test_Card_ShouldRecordInStockAmountUpdateAsCorrection(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	actorId := uint(4)
+	cardRepo.On("UpdateInStockAmount", uint(1), uint(10), model.StockMovement{
+		Kind:    model.StockCorrection,
+		ActorID: &actorId,
+		Reason:  "recount",
+	}).Return(&model.Card{InStockAmount: 10}, nil)
+
+	// act
+	_, err := cardService.UpdateInStockAmount(1, &dto.StockedAmountUpdate{NewAmount: 10, Reason: "recount"}, actorId)
+
+	// assert
+	assert.Nil(t, err)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_Card_ShouldRecordUpdatedStockAsCorrection(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	actorId := uint(4)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{InStockAmount: 5})
+	cardRepo.On("Update", mock.MatchedBy(func(c *model.Card) bool {
+		return c.InStockAmount == 3
+	}), model.StockMovement{
+		Kind:    model.StockCorrection,
+		ActorID: &actorId,
+		Reason:  "card updated",
+	}).Return(nil)
+
+	// act
+	_, err := cardService.Update(&dto.PostCard{
+		Name:          "card name",
+		Text:          "card text",
+		Price:         10,
+		InStockAmount: 3,
+		Type:          "CT1",
+		Language:      "ENG",
+		Key:           "key1",
+		Expansion:     "exp1",
+	}, 1, actorId)
+
+	// assert
+	assert.Nil(t, err)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotUpdateInStockAmountUnknownKind(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	// act
+	_, err := cardService.UpdateInStockAmount(1, &dto.StockedAmountUpdate{NewAmount: 10, Kind: "theft"}, 4)
+
+	// assert
+	assert.NotNil(t, err)
+	cardRepo.AssertNotCalled(t, "UpdateInStockAmount", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Card_ShouldGetStockMovements(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{InStockAmount: 7})
+	cardRepo.On("StockMovements", uint(1)).Return([]*model.StockMovement{
+		{CardID: 1, Kind: model.StockSale, Delta: -3, Balance: 7},
+		{CardID: 1, Kind: model.StockRestock, Delta: 10, Balance: 10},
+	})
+
+	// act
+	movements, err := cardService.StockMovements(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, movements, 2)
+	assert.Equal(t, -3, movements[0].Delta)
+}
+
+func Test_Card_ShouldNotGetStockMovementsCardNotFound(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("FindById", uint(1)).Return(nil)
+
+	// act
+	_, err := cardService.StockMovements(1)
+
+	// assert
+	assert.Equal(t, service.ErrCardNotFound, err)
+}
+
+func Test_Card_ShouldUpdatePrices(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("UpdatePrices", map[uint]model.Money{1: 1000, 2: 250}).Return([]*model.Card{{Price: 1000}, {Price: 250}}, nil)
+
+	// act
+	cards, err := cardService.UpdatePrices(&dto.BulkPriceUpdate{
+		Prices: []dto.BulkPriceEntry{{CardId: 1, Price: 1000}, {CardId: 2, Price: 250}},
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, cards, 2)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotUpdatePricesOfDuplicateCard(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	// act
+	_, err := cardService.UpdatePrices(&dto.BulkPriceUpdate{
+		Prices: []dto.BulkPriceEntry{{CardId: 1, Price: 1000}, {CardId: 1, Price: 250}},
+	})
+
+	// assert
+	assert.ErrorIs(t, err, service.ErrBulkDuplicateCard)
+	cardRepo.AssertNotCalled(t, "UpdatePrices", mock.Anything)
+}
+
+func Test_Card_ShouldNotUpdatePricesOfMissingCards(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("UpdatePrices", mock.Anything).Return(nil, &repository.CardsNotFoundError{Ids: []uint{2}})
+
+	// act
+	_, err := cardService.UpdatePrices(&dto.BulkPriceUpdate{
+		Prices: []dto.BulkPriceEntry{{CardId: 1, Price: 1000}, {CardId: 2, Price: 250}},
+	})
+
+	// assert
+	assert.ErrorIs(t, err, service.ErrCardNotFound)
+}
+
+func Test_Card_ShouldScalePricesByPercentage(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	filter := &query.CardQuery{Expansion: "LEA", MinPrice: -1, MaxPrice: -1}
+	cardRepo.On("ScalePrices", filter, 0.75).Return([]*model.Card{{Price: 750}}, nil)
+
+	// act
+	cards, err := cardService.ScalePrices(filter, &dto.BulkPriceScale{Percentage: -25})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(750), cards[0].Price)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotScalePricesToZero(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	// act
+	_, err := cardService.ScalePrices(&query.CardQuery{}, &dto.BulkPriceScale{Percentage: -100})
+
+	// assert
+	assert.NotNil(t, err)
+	cardRepo.AssertNotCalled(t, "ScalePrices", mock.Anything, mock.Anything)
+}
+
+func Test_Card_ShouldScaleAllPricesWhenConfirmed(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	filter := &query.CardQuery{MinPrice: -1, MaxPrice: -1}
+	cardRepo.On("ScalePrices", filter, 1.1).Return([]*model.Card{{Price: 1100}}, nil)
+
+	// act
+	_, err := cardService.ScalePrices(filter, &dto.BulkPriceScale{All: true, Percentage: 10})
+
+	// assert
+	assert.Nil(t, err)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotScalePricesWithoutFilter(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	// act
+	_, err := cardService.ScalePrices(&query.CardQuery{MinPrice: -1, MaxPrice: -1, Page: 2}, &dto.BulkPriceScale{Percentage: 10})
+
+	// assert
+	assert.Equal(t, service.ErrScaleWithoutFilter, err)
+	cardRepo.AssertNotCalled(t, "ScalePrices", mock.Anything, mock.Anything)
+}
+
+func Test_Card_ShouldNotScalePricesFilteredInCurrency(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	// act
+	_, err := cardService.ScalePrices(&query.CardQuery{MinPrice: 500, MaxPrice: -1, Currency: "EUR"}, &dto.BulkPriceScale{Percentage: 10})
+
+	// assert
+	assert.Equal(t, service.ErrScaleCurrency, err)
+	cardRepo.AssertNotCalled(t, "ScalePrices", mock.Anything, mock.Anything)
+}
+
+func Test_Card_ShouldUpdateInStockAmounts(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("AdjustStock", []repository.StockAdjustment{
+		{CardID: 1, Amount: 10},
+		{CardID: 2, Amount: -3, Relative: true},
+	}, mock.Anything).Return([]*model.Card{{InStockAmount: 10}, {InStockAmount: 2}}, nil)
+
+	// act
+	cards, err := cardService.UpdateInStockAmounts(&dto.BulkStockUpdate{
+		Stock: []dto.BulkStockEntry{
+			{CardId: 1, Amount: 10},
+			{CardId: 2, Amount: -3, Relative: true},
+		},
+	}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, cards, 2)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_Card_ShouldNotSetNegativeInStockAmount(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	// act
+	_, err := cardService.UpdateInStockAmounts(&dto.BulkStockUpdate{
+		Stock: []dto.BulkStockEntry{{CardId: 1, Amount: -3}},
+	}, 1)
+
+	// assert
+	assert.NotNil(t, err)
+	cardRepo.AssertNotCalled(t, "AdjustStock", mock.Anything, mock.Anything)
+}
+
+func Test_Card_ShouldNotAdjustInStockAmountBelowZero(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("AdjustStock", mock.Anything, mock.Anything).Return(nil, &repository.StockShortageError{CardId: 1, InStock: 2})
+
+	// act
+	_, err := cardService.UpdateInStockAmounts(&dto.BulkStockUpdate{
+		Stock: []dto.BulkStockEntry{{CardId: 1, Amount: -3, Relative: true}},
+	}, 1)
+
+	// assert
+	assert.ErrorIs(t, err, service.ErrInsufficientStock)
+}
+
+func Test_Card_ShouldGetLowStockWithExpansionDefault(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	cardService := newCardService(cardRepo, newMockUserRepository(), newMockLanguageRepository(), newMockExpansionRepository())
+
+	cardRepo.On("FindLowStock").Return([]*model.Card{
+		{InStockAmount: 0, LowStockThreshold: uintPtr(4), Expansion: model.Expansion{ID: "LEA", LowStockThreshold: uintPtr(2)}},
+		{InStockAmount: 1, Expansion: model.Expansion{ID: "LEA", LowStockThreshold: uintPtr(2)}},
+	})
+
+	// act
+	result := cardService.LowStock()
+
+	// assert
+	assert.Len(t, result, 2)
+	assert.Equal(t, uint(4), result[0].Threshold)
+	assert.Equal(t, uint(2), result[1].Threshold)
+}
+
+func Test_Card_ShouldNotUpdateThresholdOfMissingExpansion(t *testing.T) {
+	// arrange
+	expRepo := newMockExpansionRepository()
+	cardService := newCardService(newMockCardRepository(), newMockUserRepository(), newMockLanguageRepository(), expRepo)
+
+	expRepo.On("UpdateLowStockThreshold", "LEA", mock.Anything).Return(nil, nil)
+
+	// act
+	_, err := cardService.UpdateExpansionThreshold("LEA", &dto.LowStockThresholdUpdate{Threshold: uintPtr(2)})
+
+	// assert
+	assert.Equal(t, service.ErrExpansionNotFound, err)
+}
+
+func Test_LowStockNotifier_ShouldNotifyAdminsWhenCrossingThreshold(t *testing.T) {
+	// arrange
+	expRepo := newMockExpansionRepository()
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	notifier := service.NewLowStockNotifier(expRepo, userRepo, mailer)
+
+	expRepo.On("All").Return([]*model.Expansion{{ID: "LEA"}})
+	userRepo.On("FindAdmins").Return([]*model.User{
+		{Username: "admin", Email: "admin@mail.com"},
+		// banned
+		{Username: "banned", Email: "banned@mail.com", Suspended: true},
+	})
+	mailer.On("Send", "admin@mail.com", "Low stock", mock.Anything).Return(nil)
+
+	// act
+	notifier.StockChanged(&model.Card{Name: "Lightning Bolt", ExpansionID: "LEA", InStockAmount: 2, LowStockThreshold: uintPtr(3)}, 5)
+
+	// assert
+	mailer.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func Test_LowStockNotifier_ShouldUseExpansionDefault(t *testing.T) {
+	// arrange
+	expRepo := newMockExpansionRepository()
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	notifier := service.NewLowStockNotifier(expRepo, userRepo, mailer)
+
+	expRepo.On("All").Return([]*model.Expansion{{ID: "LEA", LowStockThreshold: uintPtr(2)}})
+	userRepo.On("FindAdmins").Return([]*model.User{{Username: "admin", Email: "admin@mail.com"}})
+	mailer.On("Send", "admin@mail.com", "Low stock", mock.Anything).Return(nil)
+
+	// act
+	notifier.StockChanged(&model.Card{Name: "Lightning Bolt", ExpansionID: "LEA", InStockAmount: 2}, 3)
+
+	// assert
+	mailer.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func Test_LowStockNotifier_ShouldNotNotifyAlreadyLowStock(t *testing.T) {
+	// arrange
+	expRepo := newMockExpansionRepository()
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	notifier := service.NewLowStockNotifier(expRepo, userRepo, mailer)
+
+	expRepo.On("All").Return([]*model.Expansion{{ID: "LEA"}})
+
+	// act
+	notifier.StockChanged(&model.Card{ExpansionID: "LEA", InStockAmount: 1, LowStockThreshold: uintPtr(3)}, 2)
+	notifier.StockChanged(&model.Card{ExpansionID: "LEA", InStockAmount: 1}, 5)
+
+	// assert
+	userRepo.AssertNotCalled(t, "FindAdmins")
+	mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func Test_Card_ShouldGetLanguages(t *testing.T) {
 	// arrange
 	cardRepo := newMockCardRepository()
@@ -3,22 +3,68 @@ package service_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"store.api/config"
 	"store.api/dto"
 	"store.api/model"
+	"store.api/repository"
 	"store.api/service"
 )
 
+func newCartPricer(cardRepo *MockCardRepository) *service.CartPricer {
+	promotionRepo := newMockPromotionRepository()
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{}).Maybe()
+	return newCartPricerWithPromotions(cardRepo, promotionRepo)
+}
+
+func newCartPricerWithPromotions(cardRepo *MockCardRepository, promotionRepo *MockPromotionRepository) *service.CartPricer {
+	return service.NewCartPricer(
+		&config.Configuration{
+			Store: config.StoreConfiguration{
+				Shipping: config.ShippingConfiguration{
+					BaseCost:    500,
+					PerCardCost: 10,
+					FreeFrom:    10000,
+				},
+				Currency: "USD",
+			},
+		},
+		cardRepo,
+		promotionRepo,
+	)
+}
+
 func newCartService(cartRepo *MockCartRepository, userRepo *MockUserRepository, cardRepo *MockCardRepository) service.CartService {
+	return newCartServiceWithGuests(cartRepo, userRepo, cardRepo, newMockGuestCartCache())
+}
+
+func newCartServiceWithGuests(cartRepo *MockCartRepository, userRepo *MockUserRepository, cardRepo *MockCardRepository, guestCarts *MockGuestCartCache) service.CartService {
+	return newCartServiceWithRepos(cartRepo, userRepo, cardRepo, newMockOrderRepository(), newCartPricer(cardRepo), guestCarts)
+}
+
+func newCartServiceWithRepos(cartRepo *MockCartRepository, userRepo *MockUserRepository, cardRepo *MockCardRepository, orderRepo *MockOrderRepository, pricer *service.CartPricer, guestCarts *MockGuestCartCache) service.CartService {
 	validate := validator.New(validator.WithRequiredStructEnabled())
+	config := &config.Configuration{
+		Store: config.StoreConfiguration{
+			GuestCartTtl: 3600,
+			Currency:     "USD",
+		},
+	}
 
 	return service.NewCartServiceImpl(
+		config,
 		cartRepo,
 		userRepo,
 		cardRepo,
+		orderRepo,
+		guestCarts,
+		pricer,
+		service.NewCurrencyConverter(config, newMockExchangeRateRepository()),
 		validate,
 	)
 }
@@ -324,3 +370,529 @@ func Test_Cart_ShouldEditSlotDeleteSlot(t *testing.T) {
 	assert.NotNil(t, col)
 	assert.Nil(t, err)
 }
+
+func Test_Cart_ShouldEditSlots(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Model: gorm.Model{ID: 5},
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 1},
+		},
+	})
+	cartRepo.On("UpdateSlots", uint(5), mock.MatchedBy(func(saved []*model.CartSlot) bool {
+		return len(saved) == 2 &&
+			saved[0].CardID == 1 && saved[0].Amount == 5 &&
+			saved[1].CardID == 3 && saved[1].Amount == 1 && saved[1].CartID == 5
+	}), mock.MatchedBy(func(deleted []*model.CartSlot) bool {
+		return len(deleted) == 1 && deleted[0].CardID == 2
+	})).Return(nil)
+
+	// act
+	cart, err := service.EditSlots(1, &dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{
+			{CardId: 1, Amount: 1},
+			{CardId: 2, Amount: -1},
+			{CardId: 3, Amount: 1},
+			{CardId: 1, Amount: 2},
+		},
+	})
+
+	// assert
+	assert.NotNil(t, cart)
+	assert.Nil(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func Test_Cart_ShouldNotEditSlotsCardNotFound(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{})
+	cardRepo.On("FindById", uint(2)).Return(nil)
+
+	// act
+	cart, err := s.EditSlots(1, &dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{
+			{CardId: 1, Amount: 1},
+			{CardId: 2, Amount: 1},
+		},
+	})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.ErrorIs(t, err, service.ErrCardNotFound)
+	cartRepo.AssertNotCalled(t, "UpdateSlots", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Cart_ShouldNotEditSlotsNegativeNewSlot(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 2},
+		},
+	})
+
+	// act
+	cart, err := service.EditSlots(1, &dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{
+			{CardId: 1, Amount: 1},
+			{CardId: 2, Amount: -1},
+		},
+	})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.NotNil(t, err)
+	cartRepo.AssertNotCalled(t, "UpdateSlots", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Cart_ShouldNotEditSlotsEmpty(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	// act
+	cart, err := service.EditSlots(1, &dto.PostCartSlots{})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.NotNil(t, err)
+	userRepo.AssertNotCalled(t, "FindById", mock.Anything)
+}
+
+func Test_Cart_ShouldNotEditSlotAmountTooLarge(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	// act
+	cart, err := service.EditSlot(1, &dto.PostCartSlot{CardId: 1, Amount: 2000000000})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.NotNil(t, err)
+	userRepo.AssertNotCalled(t, "FindById", mock.Anything)
+}
+
+func Test_Cart_ShouldGetPriced(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 4},
+			{CardID: 3, Amount: 1},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Name: "Lightning Bolt", Price: 125, InStockAmount: 10})
+	cardRepo.On("FindById", uint(2)).Return(&model.Card{Name: "Counterspell", Price: 250, InStockAmount: 3})
+	cardRepo.On("FindById", uint(3)).Return(nil)
+
+	// act
+	cart, err := service.Get(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(250), cart.Cards[0].LineTotal)
+	assert.False(t, cart.Cards[0].ExceedsStock)
+	assert.Equal(t, "Counterspell", cart.Cards[1].Name)
+	assert.Equal(t, model.Money(1000), cart.Cards[1].LineTotal)
+	assert.True(t, cart.Cards[1].ExceedsStock)
+	assert.Equal(t, uint(3), cart.Cards[1].InStockAmount)
+	assert.True(t, cart.Cards[2].Archived)
+	assert.Equal(t, model.Money(0), cart.Cards[2].LineTotal)
+	assert.Equal(t, model.Money(1250), cart.Subtotal)
+	assert.Equal(t, model.Money(560), cart.Shipping)
+	assert.Equal(t, model.Money(1810), cart.Total)
+	assert.Empty(t, cart.Promotions)
+}
+
+func Test_Cart_ShouldGetPricedFreeShipping(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 4},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 2500, InStockAmount: 4})
+
+	// act
+	cart, err := service.Get(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(10000), cart.Subtotal)
+	assert.Equal(t, model.Money(0), cart.Shipping)
+	assert.Equal(t, model.Money(10000), cart.Total)
+}
+
+func Test_Cart_ShouldGetPricedEmpty(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{})
+
+	// act
+	cart, err := service.Get(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(0), cart.Shipping)
+	assert.Equal(t, model.Money(0), cart.Total)
+}
+
+func Test_Cart_ShouldGetGuestEmpty(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	guestCarts := newMockGuestCartCache()
+	service := newCartServiceWithGuests(cartRepo, userRepo, cardRepo, guestCarts)
+
+	guestCarts.On("Get", "guest").Return(nil)
+
+	// act
+	cart := service.GetGuest("guest")
+
+	// assert
+	assert.NotNil(t, cart)
+	assert.Empty(t, cart.Cards)
+	assert.Equal(t, model.Money(0), cart.Total)
+}
+
+func Test_Cart_ShouldEditGuestSlots(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	guestCarts := newMockGuestCartCache()
+	service := newCartServiceWithGuests(cartRepo, userRepo, cardRepo, guestCarts)
+
+	cardRepo.On("FindById", mock.Anything).Return(&model.Card{Price: 100, InStockAmount: 10})
+	guestCarts.On("Get", "guest").Return(&model.Cart{
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 1},
+		},
+	})
+	guestCarts.On("Remember", "guest", mock.MatchedBy(func(cart *model.Cart) bool {
+		return len(cart.Cards) == 2 &&
+			cart.Cards[0].CardID == 1 && cart.Cards[0].Amount == 5 &&
+			cart.Cards[1].CardID == 3 && cart.Cards[1].Amount == 1
+	}), time.Hour).Return()
+
+	// act
+	cart, err := service.EditGuestSlots("guest", &dto.PostCartSlots{
+		Slots: []*dto.PostCartSlot{
+			{CardId: 1, Amount: 1},
+			{CardId: 2, Amount: -1},
+			{CardId: 3, Amount: 1},
+			{CardId: 1, Amount: 2},
+		},
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, cart.Cards, 2)
+	assert.Equal(t, model.Money(600), cart.Subtotal)
+	guestCarts.AssertExpectations(t)
+}
+
+func Test_Cart_ShouldNotEditGuestSlotNegativeNewSlot(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	guestCarts := newMockGuestCartCache()
+	service := newCartServiceWithGuests(cartRepo, userRepo, cardRepo, guestCarts)
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{})
+	guestCarts.On("Get", "guest").Return(nil)
+
+	// act
+	cart, err := service.EditGuestSlot("guest", &dto.PostCartSlot{CardId: 1, Amount: -1})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.NotNil(t, err)
+	guestCarts.AssertNotCalled(t, "Remember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Cart_ShouldNotEditGuestSlotCardNotFound(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	guestCarts := newMockGuestCartCache()
+	s := newCartServiceWithGuests(cartRepo, userRepo, cardRepo, guestCarts)
+
+	cardRepo.On("FindById", uint(1)).Return(nil)
+
+	// act
+	cart, err := s.EditGuestSlot("guest", &dto.PostCartSlot{CardId: 1, Amount: 1})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.True(t, errors.Is(err, service.ErrCardNotFound))
+}
+
+func Test_Cart_ShouldMergeGuest(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	guestCarts := newMockGuestCartCache()
+	service := newCartServiceWithGuests(cartRepo, userRepo, cardRepo, guestCarts)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{})
+	cardRepo.On("FindById", uint(2)).Return(nil)
+	cardRepo.On("FindById", uint(3)).Return(&model.Card{})
+	guestCarts.On("Take", "guest").Return(&model.Cart{
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 1},
+			{CardID: 3, Amount: 4},
+		},
+	})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Model: gorm.Model{ID: 5},
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 1, CartID: 5},
+		},
+	})
+	cartRepo.On("UpdateSlots", uint(5), mock.MatchedBy(func(saved []*model.CartSlot) bool {
+		return len(saved) == 2 &&
+			saved[0].CardID == 1 && saved[0].Amount == 3 &&
+			saved[1].CardID == 3 && saved[1].Amount == 4 && saved[1].CartID == 5
+	}), mock.Anything).Return(nil)
+
+	// act
+	err := service.MergeGuest(1, "guest")
+
+	// assert
+	assert.Nil(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func Test_Cart_ShouldMergeGuestNothing(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	guestCarts := newMockGuestCartCache()
+	service := newCartServiceWithGuests(cartRepo, userRepo, cardRepo, guestCarts)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	guestCarts.On("Take", "guest").Return(nil)
+
+	// act
+	err := service.MergeGuest(1, "guest")
+
+	// assert
+	assert.Nil(t, err)
+	cartRepo.AssertNotCalled(t, "UpdateSlots", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Cart_ShouldApplyPromotion(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	service := newCartServiceWithRepos(cartRepo, userRepo, cardRepo, newMockOrderRepository(), newCartPricerWithPromotions(cardRepo, promotionRepo), newMockGuestCartCache())
+	code := "SUMMER10"
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	promotionRepo.On("FindByCode", "summer10").Return(&model.Promotion{Code: &code, Kind: model.PromotionFixed, Amount: 1000, Active: true})
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{Model: gorm.Model{ID: 5}})
+	cartRepo.On("UpdatePromotionCode", uint(5), &code).Return(nil)
+
+	// act
+	cart, err := service.ApplyPromotion(1, &dto.PromotionCode{Code: "summer10"})
+
+	// assert
+	assert.NotNil(t, cart)
+	assert.Nil(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func Test_Cart_ShouldNotApplyPromotionExpired(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	s := newCartServiceWithRepos(cartRepo, userRepo, cardRepo, newMockOrderRepository(), newCartPricerWithPromotions(cardRepo, promotionRepo), newMockGuestCartCache())
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	promotionRepo.On("FindByCode", "old").Return(&model.Promotion{Code: strPtr("OLD"), Kind: model.PromotionFixed, Amount: 1000, Active: true, EndsAt: &yesterday})
+
+	// act
+	cart, err := s.ApplyPromotion(1, &dto.PromotionCode{Code: "old"})
+
+	// assert
+	assert.Nil(t, cart)
+	assert.Equal(t, service.ErrPromotionNotRunning, err)
+	cartRepo.AssertNotCalled(t, "UpdatePromotionCode", mock.Anything, mock.Anything)
+}
+
+func Test_Cart_ShouldCheckout(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	orderRepo := newMockOrderRepository()
+	promotionRepo := newMockPromotionRepository()
+	service := newCartServiceWithRepos(cartRepo, userRepo, cardRepo, orderRepo, newCartPricerWithPromotions(cardRepo, promotionRepo), newMockGuestCartCache())
+	coupon := &model.Promotion{Code: strPtr("TEN"), Kind: model.PromotionFixed, Amount: 1000, Active: true}
+	coupon.ID = 7
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Model:         gorm.Model{ID: 5},
+		UserID:        1,
+		Cards:         []model.CartSlot{{CardID: 1, Amount: 3}},
+		PromotionCode: strPtr("TEN"),
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Name: "Lightning Bolt", Price: 2000, InStockAmount: 3})
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{})
+	promotionRepo.On("FindByCode", "TEN").Return(coupon)
+	orderRepo.On("Create", mock.MatchedBy(func(o *model.Order) bool {
+		return o.UserID == 1 && len(o.Lines) == 1 &&
+			o.Lines[0].Amount == 3 && o.Lines[0].UnitPrice == 2000 &&
+			o.Subtotal == 6000 && o.Discount == 1000 && o.Total == 5530 &&
+			*o.PromotionCode == "TEN"
+	}), uint(5), mock.MatchedBy(func(r []*model.PromotionRedemption) bool {
+		return len(r) == 1 && r[0].PromotionID == 7 && r[0].UserID == 1 && r[0].Discount == 1000
+	})).Return(nil)
+
+	// act
+	order, err := service.Checkout(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(5530), order.Total)
+	orderRepo.AssertExpectations(t)
+}
+
+func Test_Cart_ShouldNotCheckoutEmpty(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{})
+
+	// act
+	order, err := s.Checkout(1)
+
+	// assert
+	assert.Nil(t, order)
+	assert.Equal(t, service.ErrCartEmpty, err)
+}
+
+func Test_Cart_ShouldNotCheckoutExceedingStock(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	orderRepo := newMockOrderRepository()
+	s := newCartServiceWithRepos(cartRepo, userRepo, cardRepo, orderRepo, newCartPricer(cardRepo), newMockGuestCartCache())
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Cards: []model.CartSlot{{CardID: 1, Amount: 3}},
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 100, InStockAmount: 2})
+
+	// act
+	order, err := s.Checkout(1)
+
+	// assert
+	assert.Nil(t, order)
+	assert.True(t, errors.Is(err, service.ErrInsufficientStock))
+	orderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Cart_ShouldNotCheckoutPromotionTakenMeanwhile(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	orderRepo := newMockOrderRepository()
+	s := newCartServiceWithRepos(cartRepo, userRepo, cardRepo, orderRepo, newCartPricer(cardRepo), newMockGuestCartCache())
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{
+		Cards: []model.CartSlot{{CardID: 1, Amount: 1}},
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 100, InStockAmount: 2})
+	orderRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrPromotionExhausted)
+
+	// act
+	order, err := s.Checkout(1)
+
+	// assert
+	assert.Nil(t, order)
+	assert.Equal(t, service.ErrPromotionLimitReached, err)
+}
+
+func Test_Cart_ShouldNotCheckoutNotVerified(t *testing.T) {
+	// arrange
+	cartRepo := newMockCartRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCartService(cartRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+
+	// act
+	order, err := s.Checkout(1)
+
+	// assert
+	assert.Nil(t, order)
+	assert.Equal(t, service.ErrNotVerified, err)
+}
@@ -7,18 +7,35 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 	"store.api/dto"
 	"store.api/model"
+	"store.api/query"
 	"store.api/service"
 )
 
 func newCollectionService(collectionRepo *MockCollectionRepository, userRepo *MockUserRepository, cardRepo *MockCardRepository) service.CollectionService {
+	return newCollectionServiceWithCart(collectionRepo, userRepo, cardRepo, newMockCartRepository())
+}
+
+func newCollectionServiceWithCart(collectionRepo *MockCollectionRepository, userRepo *MockUserRepository, cardRepo *MockCardRepository, cartRepo *MockCartRepository) service.CollectionService {
+	return newCollectionServiceWithRepos(collectionRepo, newMockCollectionFolderRepository(), userRepo, cardRepo, cartRepo)
+}
+
+func newCollectionServiceWithFolders(collectionRepo *MockCollectionRepository, folderRepo *MockCollectionFolderRepository, userRepo *MockUserRepository) service.CollectionService {
+	return newCollectionServiceWithRepos(collectionRepo, folderRepo, userRepo, newMockCardRepository(), newMockCartRepository())
+}
+
+func newCollectionServiceWithRepos(collectionRepo *MockCollectionRepository, folderRepo *MockCollectionFolderRepository, userRepo *MockUserRepository, cardRepo *MockCardRepository, cartRepo *MockCartRepository) service.CollectionService {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
 	return service.NewCollectionServiceImpl(
 		collectionRepo,
+		folderRepo,
 		userRepo,
 		cardRepo,
+		cartRepo,
+		newCartPricer(cardRepo),
 		validate,
 	)
 }
@@ -111,17 +128,19 @@ func Test_Collection_ShouldNotCreateSaveFail(t *testing.T) {
 func Test_Collection_ShouldGetAll(t *testing.T) {
 	// arrange
 	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
 	userRepo := newMockUserRepository()
-	cardRepo := newMockCardRepository()
-	service := newCollectionService(colRepo, userRepo, cardRepo)
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
 
+	folderRepo.On("FindByOwnerId", mock.Anything).Return([]*model.CollectionFolder{})
 	colRepo.On("FindByOwnerId", mock.Anything).Return([]*model.Collection{})
 
 	// act
-	cards := service.GetAll(1)
+	cards, err := service.GetAll(1, &query.CollectionQuery{})
 
 	// assert
 	assert.NotNil(t, cards)
+	assert.Nil(t, err)
 }
 
 func Test_Collection_ShouldGetById(t *testing.T) {
@@ -694,3 +713,1237 @@ func Test_Collection_ShouldNotUpdateInfoBadUpdate(t *testing.T) {
 	assert.Nil(t, col)
 	assert.NotNil(t, err)
 }
+
+func Test_Collection_ShouldCreateShared(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", mock.Anything).Return(&model.User{Verified: true})
+	colRepo.On("Save", mock.MatchedBy(func(c *model.Collection) bool {
+		return c.Visibility == model.VisibilityUnlisted && c.ShareToken != nil
+	})).Return(nil)
+
+	// act
+	col, err := service.Create(&dto.PostCollection{
+		Name:       "collection1",
+		Visibility: "unlisted",
+	}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "unlisted", col.Visibility)
+	assert.NotEmpty(t, col.ShareToken)
+}
+
+func Test_Collection_ShouldRevokeShareTokenWhenPrivate(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	const userId uint = 1
+	token := "token"
+
+	colRepo.On("FindById", uint(2)).Return(&model.Collection{OwnerID: userId, Visibility: model.VisibilityPublic, ShareToken: &token})
+	colRepo.On("Update", mock.MatchedBy(func(c *model.Collection) bool {
+		return c.Visibility == model.VisibilityPrivate && c.ShareToken == nil
+	})).Return(nil)
+	userRepo.On("FindById", userId).Return(&model.User{Verified: true})
+
+	// act
+	col, err := service.UpdateInfo(&dto.PostCollection{
+		Name:       "collection1",
+		Visibility: "private",
+	}, 2, userId)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Empty(t, col.ShareToken)
+	colRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldKeepVisibilityOnUpdateInfo(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	const userId uint = 1
+	token := "token"
+
+	colRepo.On("FindById", uint(2)).Return(&model.Collection{OwnerID: userId, Visibility: model.VisibilityUnlisted, ShareToken: &token})
+	colRepo.On("Update", mock.Anything).Return(nil)
+	userRepo.On("FindById", userId).Return(&model.User{Verified: true})
+
+	// act
+	col, err := service.UpdateInfo(&dto.PostCollection{
+		Name: "collection1",
+	}, 2, userId)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "unlisted", col.Visibility)
+	assert.Equal(t, token, col.ShareToken)
+}
+
+func Test_Collection_ShouldGetShared(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	token := "token"
+
+	colRepo.On("FindByShareToken", token).Return(&model.Collection{OwnerID: 1, Visibility: model.VisibilityUnlisted, ShareToken: &token})
+	userRepo.On("FindById", uint(1)).Return(&model.User{Username: "user"})
+
+	// act
+	col, err := service.GetShared(token)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "user", col.Owner)
+}
+
+func Test_Collection_ShouldNotGetSharedPrivate(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+	token := "token"
+
+	colRepo.On("FindByShareToken", token).Return(&model.Collection{OwnerID: 1, Visibility: model.VisibilityPrivate, ShareToken: &token})
+
+	// act
+	col, err := s.GetShared(token)
+
+	// assert
+	assert.Nil(t, col)
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+}
+
+func Test_Collection_ShouldGetPublic(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	owner := &model.User{Username: "user"}
+	owner.ID = 1
+
+	userRepo.On("FindByUsername", "user").Return(owner)
+	colRepo.On("FindPublicByOwnerId", uint(1)).Return([]*model.Collection{{Visibility: model.VisibilityPublic}})
+
+	// act
+	cols, err := service.GetPublic("user")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, cols, 1)
+}
+
+func Test_Collection_ShouldNotGetPublicUnknownUser(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindByUsername", "user").Return(nil)
+
+	// act
+	cols, err := s.GetPublic("user")
+
+	// assert
+	assert.Nil(t, cols)
+	assert.Equal(t, service.ErrUserNotFound, err)
+}
+
+func Test_Collection_ShouldGetValue(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 1},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{
+		Model:       gorm.Model{ID: 1},
+		Price:       1000,
+		ExpansionID: "exp1",
+		CardTypeID:  "type1",
+		LanguageID:  "ENG",
+	})
+	cardRepo.On("FindById", uint(2)).Return(&model.Card{
+		Model:       gorm.Model{ID: 2},
+		Price:       500,
+		ExpansionID: "exp2",
+		CardTypeID:  "type1",
+		LanguageID:  "ENG",
+	})
+	cardRepo.On("PricesAt", mock.Anything, mock.Anything).Return(map[uint]model.Money{1: 500, 2: 500})
+
+	// act
+	value, err := service.Value(1, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), value.Cards)
+	assert.Equal(t, model.Money(2500), value.Value)
+	assert.Len(t, value.ByExpansion, 2)
+	assert.Equal(t, "exp1", value.ByExpansion[0].Id)
+	assert.Len(t, value.ByType, 1)
+	assert.Equal(t, uint(3), value.ByType[0].Cards)
+	assert.Len(t, value.Changes, 2)
+	assert.Equal(t, model.Money(1500), value.Changes[0].PreviousValue)
+	assert.Equal(t, model.Money(1000), value.Changes[0].Change)
+	assert.InDelta(t, 66.67, *value.Changes[0].ChangePercent, 0.01)
+}
+
+func Test_Collection_ShouldGetValueEmpty(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{OwnerID: 1})
+	cardRepo.On("PricesAt", mock.Anything, mock.Anything).Return(map[uint]model.Money{})
+
+	// act
+	value, err := service.Value(1, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(0), value.Value)
+	assert.Empty(t, value.ByExpansion)
+	assert.Nil(t, value.Changes[0].ChangePercent)
+}
+
+func Test_Collection_ShouldNotGetValueOwnerMismatch(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{OwnerID: 2})
+
+	// act
+	value, err := s.Value(1, 1)
+
+	// assert
+	assert.Nil(t, value)
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+	cardRepo.AssertNotCalled(t, "PricesAt", mock.Anything, mock.Anything)
+}
+
+func boltPrintings() []*model.Card {
+	return []*model.Card{
+		{
+			Model:           gorm.Model{ID: 1},
+			CardKey:         model.CardKey{ID: "bolt", EngName: "Lightning Bolt"},
+			ExpansionID:     "m11",
+			Expansion:       model.Expansion{ID: "m11", ShortName: "M11"},
+			CollectorNumber: "149",
+		},
+		{
+			Model:           gorm.Model{ID: 2},
+			CardKey:         model.CardKey{ID: "bolt", EngName: "Lightning Bolt"},
+			ExpansionID:     "2x2",
+			Expansion:       model.Expansion{ID: "2x2", ShortName: "2X2"},
+			CollectorNumber: "117",
+		},
+	}
+}
+
+func Test_Collection_ShouldImportText(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 2, Amount: 1},
+		},
+	})
+	cardRepo.On("FindByKeyName", "Lightning Bolt").Return(boltPrintings())
+	cardRepo.On("FindByKeyName", "Counterspell").Return([]*model.Card{})
+	colRepo.On("UpdateSlots", uint(1), mock.MatchedBy(func(saved []*model.CollectionSlot) bool {
+		return len(saved) == 2 &&
+			saved[0].CardID == 1 && saved[0].Amount == 4 && saved[0].CollectionID == 1 &&
+			saved[1].CardID == 2 && saved[1].Amount == 3
+	}), mock.Anything).Return(nil)
+
+	// act
+	result, err := service.Import(1, 1, &dto.DecklistImport{
+		Format: "text",
+		Decklist: "4 Lightning Bolt (M11) 149\n" +
+			"2x Lightning Bolt (2X2)\n" +
+			"1 Lightning Bolt (M11) 150\n" +
+			"\n" +
+			"SIDEBOARD:\n" +
+			"3 Counterspell\n" +
+			"Lightning Bolt\n",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(6), result.Imported)
+	assert.Len(t, result.Unresolved, 3)
+	assert.Equal(t, 3, result.Unresolved[0].Line)
+	assert.Equal(t, 6, result.Unresolved[1].Line)
+	assert.Equal(t, 7, result.Unresolved[2].Line)
+	cardRepo.AssertNumberOfCalls(t, "FindByKeyName", 2)
+	colRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldImportArena(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, OwnerID: 1})
+	cardRepo.On("FindByKeyName", "Lightning Bolt").Return(boltPrintings())
+	colRepo.On("UpdateSlots", uint(1), mock.MatchedBy(func(saved []*model.CollectionSlot) bool {
+		return len(saved) == 1 && saved[0].CardID == 2 && saved[0].Amount == 5
+	}), mock.Anything).Return(nil)
+
+	// act
+	result, err := service.Import(1, 1, &dto.DecklistImport{
+		Format:   "arena",
+		Decklist: "About\nName Burn\n\nDeck\n4 Lightning Bolt (2X2) 117\n\nSideboard\n1 Lightning Bolt (2X2) 117\n",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(5), result.Imported)
+	assert.Empty(t, result.Unresolved)
+	colRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldImportMtgo(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, OwnerID: 1})
+	cardRepo.On("FindByKeyName", "Lightning Bolt").Return(boltPrintings())
+	colRepo.On("UpdateSlots", uint(1), mock.MatchedBy(func(saved []*model.CollectionSlot) bool {
+		return len(saved) == 1 && saved[0].CardID == 1 && saved[0].Amount == 6
+	}), mock.Anything).Return(nil)
+
+	// act
+	result, err := service.Import(1, 1, &dto.DecklistImport{
+		Format: "mtgo",
+		Decklist: `<?xml version="1.0" encoding="utf-8"?>
+<Deck xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <NetDeckID>0</NetDeckID>
+  <PreconstructedDeckID>0</PreconstructedDeckID>
+  <Cards CatID="37812" Quantity="4" Sideboard="false" Name="Lightning Bolt" Annotation="0" />
+  <Cards CatID="37812" Quantity="2" Sideboard="true" Name="Lightning Bolt" Annotation="0" />
+  <Cards CatID="1" Quantity="zero" Sideboard="true" Name="Lightning Bolt" Annotation="0" />
+</Deck>`,
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(6), result.Imported)
+	assert.Len(t, result.Unresolved, 1)
+	assert.Equal(t, 3, result.Unresolved[0].Line)
+}
+
+func Test_Collection_ShouldNotImportInvalidMtgo(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, OwnerID: 1})
+
+	// act
+	result, err := service.Import(1, 1, &dto.DecklistImport{
+		Format:   "mtgo",
+		Decklist: "4 Lightning Bolt",
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	colRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func Test_Collection_ShouldNotImportUnverified(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: false})
+
+	// act
+	result, err := s.Import(1, 1, &dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt",
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrNotVerified, err)
+}
+
+func Test_Collection_ShouldExportText(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Name:    "burn",
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 4},
+			{CardID: 3, Amount: 2},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(boltPrintings()[0])
+	cardRepo.On("FindById", uint(3)).Return(&model.Card{
+		Name:      "Молния",
+		Expansion: model.Expansion{ShortName: "M11"},
+	})
+
+	// act
+	result, err := service.Export(1, 1, "text")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "burn.txt", result.FileName)
+	assert.Equal(t, "4 Lightning Bolt (M11) 149\n2 Молния (M11)\n", string(result.Data))
+}
+
+func Test_Collection_ShouldExportMtgo(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Name:    "burn",
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 4},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(boltPrintings()[0])
+
+	// act
+	result, err := service.Export(1, 1, "mtgo")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "burn.dek", result.FileName)
+	assert.Contains(t, string(result.Data), `<Cards Quantity="4" Sideboard="false" Name="Lightning Bolt"></Cards>`)
+}
+
+func Test_Collection_ShouldNotExportUnknownFormat(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+
+	// act
+	result, err := s.Export(1, 1, "csv")
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrUnknownDecklistFormat, err)
+}
+
+func Test_Collection_ShouldMoveToCart(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	cartRepo := newMockCartRepository()
+	service := newCollectionServiceWithCart(colRepo, userRepo, cardRepo, cartRepo)
+	cart := &model.Cart{
+		Model: gorm.Model{ID: 5},
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 1, CartID: 5},
+		},
+	}
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 4},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Model: gorm.Model{ID: 1}, InStockAmount: 5})
+	cardRepo.On("FindById", uint(2)).Return(&model.Card{Model: gorm.Model{ID: 2}, InStockAmount: 3, CardKeyID: "bolt"})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(cart)
+	cartRepo.On("UpdateSlots", uint(5), mock.MatchedBy(func(saved []*model.CartSlot) bool {
+		return len(saved) == 2 &&
+			saved[0].CardID == 1 && saved[0].Amount == 3 &&
+			saved[1].CardID == 2 && saved[1].Amount == 3 && saved[1].CartID == 5
+	}), mock.Anything).Return(nil)
+
+	// act
+	result, err := service.ToCart(1, 1, false)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(5), result.Added)
+	assert.Len(t, result.Shortfalls, 1)
+	assert.Equal(t, uint(2), result.Shortfalls[0].CardId)
+	assert.Equal(t, uint(3), result.Shortfalls[0].Added)
+	assert.Equal(t, uint(1), result.Shortfalls[0].Missing)
+	assert.Empty(t, result.Shortfalls[0].Substitutes)
+	cardRepo.AssertNotCalled(t, "FindInStockByKeyId", mock.Anything)
+	cartRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldMoveToCartWithSubstitutes(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	cartRepo := newMockCartRepository()
+	service := newCollectionServiceWithCart(colRepo, userRepo, cardRepo, cartRepo)
+	wanted := &model.Card{Model: gorm.Model{ID: 1}, InStockAmount: 1, CardKeyID: "bolt", Price: 1}
+	cheap := &model.Card{Model: gorm.Model{ID: 2}, InStockAmount: 2, CardKeyID: "bolt", Price: 2}
+	expensive := &model.Card{Model: gorm.Model{ID: 3}, InStockAmount: 5, CardKeyID: "bolt", Price: 3}
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 4},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(wanted)
+	cardRepo.On("FindById", uint(2)).Return(cheap)
+	cardRepo.On("FindById", uint(3)).Return(expensive)
+	cardRepo.On("FindInStockByKeyId", "bolt").Return([]*model.Card{wanted, cheap, expensive})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{})
+	cartRepo.On("UpdateSlots", uint(0), mock.MatchedBy(func(saved []*model.CartSlot) bool {
+		return len(saved) == 3 && saved[0].Amount == 1 && saved[1].Amount == 2 && saved[2].Amount == 1
+	}), mock.Anything).Return(nil)
+
+	// act
+	result, err := service.ToCart(1, 1, true)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(4), result.Added)
+	assert.Len(t, result.Shortfalls, 1)
+	assert.Equal(t, uint(0), result.Shortfalls[0].Missing)
+	assert.Len(t, result.Shortfalls[0].Substitutes, 2)
+	assert.Equal(t, uint(2), result.Shortfalls[0].Substitutes[0].CardId)
+	assert.Equal(t, uint(2), result.Shortfalls[0].Substitutes[0].Amount)
+	assert.Equal(t, uint(3), result.Shortfalls[0].Substitutes[1].CardId)
+	assert.Equal(t, uint(1), result.Shortfalls[0].Substitutes[1].Amount)
+	cartRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldNotMoveToCartOwnerMismatch(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	cartRepo := newMockCartRepository()
+	s := newCollectionServiceWithCart(colRepo, userRepo, cardRepo, cartRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{OwnerID: 2})
+
+	// act
+	result, err := s.ToCart(1, 1, false)
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+	cartRepo.AssertNotCalled(t, "FindSingleByUserId", mock.Anything)
+}
+
+func Test_Collection_ShouldDiff(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	printings := boltPrintings()
+	counterspell := &model.Card{Model: gorm.Model{ID: 3}, CardKeyID: "counterspell", CardKey: model.CardKey{ID: "counterspell", EngName: "Counterspell"}}
+	printings[0].CardKeyID = "bolt"
+	printings[1].CardKeyID = "bolt"
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 2},
+			{CardID: 3, Amount: 1},
+		},
+	})
+	colRepo.On("FindById", uint(2)).Return(&model.Collection{
+		Model:      gorm.Model{ID: 2},
+		OwnerID:    2,
+		Visibility: model.VisibilityPublic,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 4},
+			{CardID: 3, Amount: 3},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(printings[0])
+	cardRepo.On("FindById", uint(2)).Return(printings[1])
+	cardRepo.On("FindById", uint(3)).Return(counterspell)
+
+	// act
+	result, err := service.Diff(1, 2, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, result.Cards, 3)
+	assert.Equal(t, uint(3), result.Cards[0].CardId)
+	assert.Equal(t, "counterspell", result.Cards[0].CardKeyId)
+	assert.Equal(t, "Counterspell", result.Cards[0].Name)
+	assert.Equal(t, uint(1), result.Cards[0].Amount)
+	assert.Equal(t, uint(3), result.Cards[0].OtherAmount)
+	assert.Equal(t, 2, result.Cards[0].Delta)
+	// the other collection has as many bolts, but not of the same printings
+	assert.Equal(t, uint(1), result.Cards[1].CardId)
+	assert.Equal(t, "bolt", result.Cards[1].CardKeyId)
+	assert.Equal(t, 2, result.Cards[1].Delta)
+	assert.Equal(t, uint(2), result.Cards[2].CardId)
+	assert.Equal(t, "bolt", result.Cards[2].CardKeyId)
+	assert.Equal(t, -2, result.Cards[2].Delta)
+}
+
+func Test_Collection_ShouldNotDiffPrivateCollection(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{OwnerID: 1})
+	colRepo.On("FindById", uint(2)).Return(&model.Collection{OwnerID: 2, Visibility: model.VisibilityUnlisted})
+
+	// act
+	result, err := s.Diff(1, 2, 1)
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+}
+
+func Test_Collection_ShouldDiffDecklist(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	printings := boltPrintings()
+	printings[0].CardKeyID = "bolt"
+	printings[1].CardKeyID = "bolt"
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 2, Amount: 1},
+		},
+	})
+	cardRepo.On("FindById", uint(2)).Return(printings[1])
+	cardRepo.On("FindByKeyName", "Lightning Bolt").Return(printings)
+	cardRepo.On("FindByKeyName", "Counterspell").Return([]*model.Card{})
+
+	// act
+	result, err := service.DiffDecklist(1, 1, &dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt (M11)\n2 Counterspell\n",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, result.Cards, 2)
+	assert.Equal(t, uint(1), result.Cards[0].CardId)
+	assert.Equal(t, "bolt", result.Cards[0].CardKeyId)
+	assert.Equal(t, uint(0), result.Cards[0].Amount)
+	assert.Equal(t, uint(4), result.Cards[0].OtherAmount)
+	assert.Equal(t, 4, result.Cards[0].Delta)
+	assert.Equal(t, uint(2), result.Cards[1].CardId)
+	assert.Equal(t, "bolt", result.Cards[1].CardKeyId)
+	assert.Equal(t, uint(1), result.Cards[1].Amount)
+	assert.Equal(t, uint(0), result.Cards[1].OtherAmount)
+	assert.Equal(t, -1, result.Cards[1].Delta)
+	assert.Len(t, result.Unresolved, 1)
+	assert.Equal(t, 2, result.Unresolved[0].Line)
+}
+
+func Test_Collection_ShouldGetNeeds(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	owned := &model.Card{Model: gorm.Model{ID: 1}, CardKeyID: "bolt", CardKey: model.CardKey{ID: "bolt", EngName: "Lightning Bolt"}}
+	cheap := &model.Card{Model: gorm.Model{ID: 2}, CardKeyID: "bolt", InStockAmount: 2, Price: 150}
+	expensive := &model.Card{Model: gorm.Model{ID: 3}, CardKeyID: "bolt", InStockAmount: 1, Price: 400}
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 1},
+		},
+	})
+	colRepo.On("FindById", uint(2)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 2},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 5},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(owned)
+	cardRepo.On("FindInStockByKeyId", "bolt").Return([]*model.Card{cheap, expensive})
+
+	// act
+	result, err := service.Needs(1, 2, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, result.Cards, 1)
+	assert.Equal(t, uint(4), result.Cards[0].Needed)
+	assert.Len(t, result.Cards[0].Printings, 2)
+	assert.Equal(t, uint(2), result.Cards[0].Printings[0].CardId)
+	assert.Equal(t, uint(2), result.Cards[0].Printings[0].Amount)
+	assert.Equal(t, uint(3), result.Cards[0].Printings[1].CardId)
+	assert.Equal(t, uint(1), result.Cards[0].Printings[1].Amount)
+	assert.Equal(t, uint(1), result.Cards[0].Unavailable)
+	assert.Equal(t, model.Money(700), result.Cards[0].Cost)
+	assert.Equal(t, model.Money(700), result.TotalCost)
+}
+
+func Test_Collection_ShouldGetNeedsDecklistNothingMissing(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+	printings := boltPrintings()
+	printings[0].CardKeyID = "bolt"
+	printings[1].CardKeyID = "bolt"
+
+	colRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 2},
+		},
+	})
+	cardRepo.On("FindById", uint(1)).Return(printings[0])
+	cardRepo.On("FindById", uint(2)).Return(printings[1])
+	cardRepo.On("FindByKeyName", "Lightning Bolt").Return(printings)
+
+	// act
+	result, err := service.NeedsDecklist(1, 1, &dto.DecklistImport{
+		Format:   "text",
+		Decklist: "4 Lightning Bolt (M11)\n",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Empty(t, result.Cards)
+	assert.Equal(t, model.Money(0), result.TotalCost)
+	cardRepo.AssertNotCalled(t, "FindInStockByKeyId", mock.Anything)
+}
+
+func Test_Collection_ShouldEditSlots(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 3},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 1},
+		},
+	})
+	colRepo.On("UpdateSlots", uint(3), mock.MatchedBy(func(saved []*model.CollectionSlot) bool {
+		return len(saved) == 1 && saved[0].CardID == 4 && saved[0].Amount == 3 && saved[0].CollectionID == 3
+	}), mock.MatchedBy(func(deleted []*model.CollectionSlot) bool {
+		return len(deleted) == 1 && deleted[0].CardID == 2
+	})).Return(nil)
+
+	// act
+	col, err := service.EditSlots(&dto.PostCollectionSlots{
+		Slots: []*dto.PostCollectionSlot{
+			{CardId: 1, Amount: 1},
+			{CardId: 2, Amount: -4},
+			{CardId: 4, Amount: 3},
+			{CardId: 1, Amount: -1},
+		},
+	}, 3, 1)
+
+	// assert
+	assert.NotNil(t, col)
+	assert.Nil(t, err)
+	colRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldNotEditSlotsUnverified(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	s := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+
+	// act
+	col, err := s.EditSlots(&dto.PostCollectionSlots{
+		Slots: []*dto.PostCollectionSlot{
+			{CardId: 1, Amount: 1},
+		},
+	}, 3, 1)
+
+	// assert
+	assert.Nil(t, col)
+	assert.Equal(t, service.ErrNotVerified, err)
+	colRepo.AssertNotCalled(t, "UpdateSlots", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Collection_ShouldNotEditSlotsFailedUpdate(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	cardRepo := newMockCardRepository()
+	service := newCollectionService(colRepo, userRepo, cardRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	cardRepo.On("FindById", mock.Anything).Return(&model.Card{})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{OwnerID: 1})
+	colRepo.On("UpdateSlots", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("constraint violated"))
+
+	// act
+	col, err := service.EditSlots(&dto.PostCollectionSlots{
+		Slots: []*dto.PostCollectionSlot{
+			{CardId: 1, Amount: 1},
+		},
+	}, 3, 1)
+
+	// assert
+	assert.Nil(t, col)
+	assert.NotNil(t, err)
+}
+
+func folderTree() []*model.CollectionFolder {
+	return []*model.CollectionFolder{
+		{Model: gorm.Model{ID: 1}, Name: "decks", OwnerID: 1},
+		{Model: gorm.Model{ID: 2}, Name: "modern", ParentID: uintPtr(1), OwnerID: 1},
+		{Model: gorm.Model{ID: 3}, Name: "trades", OwnerID: 1},
+	}
+}
+
+func Test_Collection_ShouldGetAllAsTree(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	folderRepo.On("FindByOwnerId", uint(1)).Return(folderTree())
+	colRepo.On("FindByOwnerId", uint(1)).Return([]*model.Collection{
+		{Model: gorm.Model{ID: 10}, Name: "burn", FolderID: uintPtr(2)},
+		{Model: gorm.Model{ID: 11}, Name: "binder"},
+	})
+
+	// act
+	tree, err := service.GetAll(1, &query.CollectionQuery{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, tree.FolderId)
+	assert.Len(t, tree.Collections, 1)
+	assert.Equal(t, uint(11), tree.Collections[0].ID)
+	assert.Len(t, tree.Folders, 2)
+	assert.Equal(t, "decks", tree.Folders[0].Name)
+	assert.Empty(t, tree.Folders[0].Collections)
+	assert.Len(t, tree.Folders[0].Folders, 1)
+	assert.Equal(t, uint(10), tree.Folders[0].Folders[0].Collections[0].ID)
+	assert.Equal(t, "trades", tree.Folders[1].Name)
+}
+
+func Test_Collection_ShouldGetAllInFolderWithTag(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+	folders := append(folderTree(), &model.CollectionFolder{Model: gorm.Model{ID: 4}, Name: "legacy", ParentID: uintPtr(1), OwnerID: 1})
+
+	folderRepo.On("FindById", uint(1)).Return(folders[0])
+	folderRepo.On("FindByOwnerId", uint(1)).Return(folders)
+	colRepo.On("FindByOwnerId", uint(1)).Return([]*model.Collection{
+		{Model: gorm.Model{ID: 10}, Name: "burn", FolderID: uintPtr(2), Tags: []string{"red"}},
+		{Model: gorm.Model{ID: 11}, Name: "elves", FolderID: uintPtr(4), Tags: []string{"green"}},
+		{Model: gorm.Model{ID: 12}, Name: "goblins", FolderID: uintPtr(1), Tags: []string{"red", "tribal"}},
+	})
+
+	// act
+	tree, err := service.GetAll(1, &query.CollectionQuery{Folder: uintPtr(1), Tag: " Red"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), *tree.FolderId)
+	assert.Len(t, tree.Collections, 1)
+	assert.Equal(t, uint(12), tree.Collections[0].ID)
+	assert.Len(t, tree.Folders, 1)
+	assert.Equal(t, "modern", tree.Folders[0].Name)
+	assert.Equal(t, uint(10), tree.Folders[0].Collections[0].ID)
+}
+
+func Test_Collection_ShouldNotGetAllOthersFolder(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	folderRepo.On("FindById", uint(5)).Return(&model.CollectionFolder{OwnerID: 2})
+
+	// act
+	tree, err := s.GetAll(1, &query.CollectionQuery{Folder: uintPtr(5)})
+
+	// assert
+	assert.Nil(t, tree)
+	assert.Equal(t, service.ErrFolderNotFound, err)
+}
+
+func Test_Collection_ShouldNotCreateInOthersFolder(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	folderRepo.On("FindById", uint(5)).Return(&model.CollectionFolder{OwnerID: 2})
+
+	// act
+	col, err := s.Create(&dto.PostCollection{
+		Name:     "collection1",
+		FolderId: uintPtr(5),
+	}, 1)
+
+	// assert
+	assert.Nil(t, col)
+	assert.Equal(t, service.ErrFolderNotFound, err)
+	colRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Collection_ShouldCreateWithTags(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	folderRepo.On("FindById", uint(2)).Return(&model.CollectionFolder{OwnerID: 1})
+	colRepo.On("Save", mock.Anything).Return(nil)
+
+	// act
+	col, err := service.Create(&dto.PostCollection{
+		Name:     "collection1",
+		FolderId: uintPtr(2),
+		Tags:     []string{"Modern ", "modern", "burn"},
+	}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(2), *col.FolderId)
+	assert.Equal(t, []string{"modern", "burn"}, col.Tags)
+}
+
+func Test_Collection_ShouldCreateFolder(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	folderRepo.On("FindById", uint(1)).Return(&model.CollectionFolder{OwnerID: 1})
+	folderRepo.On("Save", mock.MatchedBy(func(f *model.CollectionFolder) bool {
+		return f.Name == "modern" && *f.ParentID == 1 && f.OwnerID == 1
+	})).Return(nil)
+
+	// act
+	folder, err := service.CreateFolder(1, &dto.PostCollectionFolder{
+		Name:     "modern",
+		ParentId: uintPtr(1),
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "modern", folder.Name)
+	folderRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldNotMoveFolderIntoItself(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+	folders := folderTree()
+
+	folderRepo.On("FindById", uint(1)).Return(folders[0])
+	folderRepo.On("FindById", uint(2)).Return(folders[1])
+
+	// act
+	folder, err := s.UpdateFolder(1, 1, &dto.PostCollectionFolder{
+		Name:     "decks",
+		ParentId: uintPtr(2),
+	})
+
+	// assert
+	assert.Nil(t, folder)
+	assert.Equal(t, service.ErrFolderCycle, err)
+	folderRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func Test_Collection_ShouldMoveFolderToTopLevel(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	folderRepo.On("FindById", uint(2)).Return(folderTree()[1])
+	folderRepo.On("Update", mock.MatchedBy(func(f *model.CollectionFolder) bool {
+		return f.Name == "pioneer" && f.ParentID == nil
+	})).Return(nil)
+
+	// act
+	folder, err := service.UpdateFolder(2, 1, &dto.PostCollectionFolder{Name: "pioneer"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, folder.ParentId)
+	folderRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldNotDeleteOthersFolder(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	folderRepo.On("FindById", uint(5)).Return(&model.CollectionFolder{OwnerID: 2})
+
+	// act
+	err := s.DeleteFolder(5, 1)
+
+	// assert
+	assert.Equal(t, service.ErrFolderNotFound, err)
+	folderRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func Test_Collection_ShouldCopyOwn(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{
+		Model:      gorm.Model{ID: 3},
+		Name:       "burn",
+		OwnerID:    1,
+		Visibility: model.VisibilityPublic,
+		FolderID:   uintPtr(2),
+		Tags:       []string{"red"},
+		Cards: []model.CollectionSlot{
+			{Model: gorm.Model{ID: 7}, CardID: 1, Amount: 4, CollectionID: 3},
+		},
+	})
+	colRepo.On("Save", mock.MatchedBy(func(c *model.Collection) bool {
+		return c.ID == 0 && c.Visibility == model.VisibilityPrivate && c.ShareToken == nil &&
+			len(c.Cards) == 1 && c.Cards[0].ID == 0 && c.Cards[0].CollectionID == 0 && c.Cards[0].Amount == 4
+	})).Return(nil)
+
+	// act
+	col, err := service.Copy(3, 1, &dto.CopyCollection{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "burn (copy)", col.Name)
+	assert.Equal(t, uint(2), *col.FolderId)
+	assert.Equal(t, []string{"red"}, col.Tags)
+	colRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldCopyPublic(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{
+		Name:       "burn",
+		OwnerID:    2,
+		Visibility: model.VisibilityPublic,
+		FolderID:   uintPtr(9),
+		Tags:       []string{"red"},
+	})
+	colRepo.On("Save", mock.Anything).Return(nil)
+
+	// act
+	col, err := service.Copy(3, 1, &dto.CopyCollection{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "burn", col.Name)
+	assert.Nil(t, col.FolderId)
+	assert.Empty(t, col.Tags)
+}
+
+func Test_Collection_ShouldNotCopyPrivate(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{OwnerID: 2, Visibility: model.VisibilityUnlisted})
+
+	// act
+	col, err := s.Copy(3, 1, &dto.CopyCollection{})
+
+	// assert
+	assert.Nil(t, col)
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+}
+
+func Test_Collection_ShouldMerge(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	service := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 3},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 2, CollectionID: 3},
+		},
+	})
+	colRepo.On("FindById", uint(4)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 4},
+		OwnerID: 1,
+		Cards: []model.CollectionSlot{
+			{CardID: 1, Amount: 1, CollectionID: 4},
+			{CardID: 2, Amount: 3, CollectionID: 4},
+		},
+	})
+	colRepo.On("UpdateSlots", uint(3), mock.MatchedBy(func(saved []*model.CollectionSlot) bool {
+		return len(saved) == 2 &&
+			saved[0].CardID == 1 && saved[0].Amount == 3 && saved[0].CollectionID == 3 &&
+			saved[1].CardID == 2 && saved[1].Amount == 3 && saved[1].CollectionID == 3
+	}), mock.Anything).Return(nil)
+	colRepo.On("Delete", uint(4)).Return(nil)
+
+	// act
+	col, err := service.Merge(3, 1, &dto.MergeCollections{SourceId: 4, DeleteSource: true})
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, col)
+	colRepo.AssertExpectations(t)
+}
+
+func Test_Collection_ShouldNotMergeIntoSelf(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+
+	// act
+	col, err := s.Merge(3, 1, &dto.MergeCollections{SourceId: 3})
+
+	// assert
+	assert.Nil(t, col)
+	assert.Equal(t, service.ErrMergeIntoSelf, err)
+	colRepo.AssertNotCalled(t, "UpdateSlots", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Collection_ShouldNotMergeOthersCollection(t *testing.T) {
+	// arrange
+	colRepo := newMockCollectionRepository()
+	folderRepo := newMockCollectionFolderRepository()
+	userRepo := newMockUserRepository()
+	s := newCollectionServiceWithFolders(colRepo, folderRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Verified: true})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{OwnerID: 1})
+	colRepo.On("FindById", uint(4)).Return(&model.Collection{OwnerID: 2, Visibility: model.VisibilityPublic})
+
+	// act
+	col, err := s.Merge(3, 1, &dto.MergeCollections{SourceId: 4})
+
+	// assert
+	assert.Nil(t, col)
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+	colRepo.AssertNotCalled(t, "UpdateSlots", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+func newCurrencyService(rateRepo *MockExchangeRateRepository, source string) service.CurrencyService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return service.NewCurrencyServiceImpl(
+		&config.Configuration{
+			Store: config.StoreConfiguration{
+				Currency: "USD",
+				ExchangeRates: config.ExchangeRatesConfiguration{
+					Source: source,
+				},
+			},
+		},
+		rateRepo,
+		validate,
+	)
+}
+
+func Test_Currency_ShouldSetRate(t *testing.T) {
+	// arrange
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, "")
+
+	rateRepo.On("Save", []*model.ExchangeRate{{Currency: "EUR", Rate: 0.9}}).Return(nil)
+
+	// act
+	result, err := currencyService.SetRate("eur", &dto.PostExchangeRate{Rate: 0.9})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", result.Currency)
+	rateRepo.AssertExpectations(t)
+}
+
+func Test_Currency_ShouldNotSetRateOfStoreCurrency(t *testing.T) {
+	// arrange
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, "")
+
+	// act
+	result, err := currencyService.SetRate("USD", &dto.PostExchangeRate{Rate: 2})
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrStoreCurrencyRate)
+	rateRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Currency_ShouldNotSetInvalidRate(t *testing.T) {
+	// arrange
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, "")
+
+	// act
+	_, invalidCode := currencyService.SetRate("EURO", &dto.PostExchangeRate{Rate: 2})
+	_, invalidRate := currencyService.SetRate("EUR", &dto.PostExchangeRate{Rate: -1})
+
+	// assert
+	assert.NotNil(t, invalidCode)
+	assert.NotNil(t, invalidRate)
+	rateRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Currency_ShouldNotDeleteUnknownRate(t *testing.T) {
+	// arrange
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, "")
+
+	rateRepo.On("FindByCurrency", "GBP").Return(nil)
+
+	// act
+	err := currencyService.DeleteRate("gbp")
+
+	// assert
+	assert.ErrorIs(t, err, service.ErrUnknownCurrency)
+	rateRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func Test_Currency_ShouldLoadRebasedRatesFromFile(t *testing.T) {
+	// arrange
+	source := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(source, []byte(`{"base": "EUR", "rates": {"usd": 2, "GBP": 1.5, "XXXX": 3, "JPY": 0}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, source)
+
+	rateRepo.On("Save", []*model.ExchangeRate{
+		{Currency: "EUR", Rate: 0.5},
+		{Currency: "GBP", Rate: 0.75},
+	}).Return(nil)
+	rateRepo.On("FindAll").Return([]*model.ExchangeRate{})
+
+	// act
+	result, err := currencyService.Load()
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "USD", result.StoreCurrency)
+	rateRepo.AssertExpectations(t)
+}
+
+func Test_Currency_ShouldNotLoadWithoutStoreCurrency(t *testing.T) {
+	// arrange
+	source := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(source, []byte(`{"base": "EUR", "rates": {"GBP": 0.85}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, source)
+
+	// act
+	result, err := currencyService.Load()
+
+	// assert
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	rateRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Currency_ShouldNotLoadWithoutSource(t *testing.T) {
+	// arrange
+	rateRepo := newMockExchangeRateRepository()
+	currencyService := newCurrencyService(rateRepo, "")
+
+	// act
+	result, err := currencyService.Load()
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrNoExchangeRateSource)
+}
+
+func Test_Currency_ShouldConvertCart(t *testing.T) {
+	// arrange
+	conversion := &service.Conversion{Currency: "EUR", Rate: 0.5}
+	cart := &dto.GetCart{
+		Cards:      []*dto.GetCartSlot{{Amount: 3, Price: 334, LineTotal: 1002}},
+		Promotions: []*dto.AppliedPromotion{{Discount: 100}},
+		Subtotal:   1002,
+		Discount:   100,
+		Shipping:   500,
+		Total:      1402,
+		Currency:   "USD",
+	}
+
+	// act
+	result := conversion.Cart(cart)
+
+	// assert
+	assert.Equal(t, "EUR", result.Currency)
+	assert.Equal(t, model.Money(167), result.Cards[0].Price)
+	assert.Equal(t, model.Money(501), result.Cards[0].LineTotal)
+	assert.Equal(t, model.Money(50), result.Promotions[0].Discount)
+	assert.Equal(t, model.Money(250), result.Shipping)
+	assert.Equal(t, model.Money(701), result.Total)
+	// the priced cart is left as is
+	assert.Equal(t, model.Money(1002), cart.Subtotal)
+	assert.Equal(t, model.Money(334), cart.Cards[0].Price)
+}
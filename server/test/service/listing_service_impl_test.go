@@ -0,0 +1,291 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+func newListingService(listingRepo *MockListingRepository, cardRepo *MockCardRepository, userRepo *MockUserRepository) service.ListingService {
+	return newListingServiceWithPayouts(listingRepo, cardRepo, userRepo, newMockPayoutRepository())
+}
+
+func newListingServiceWithPayouts(listingRepo *MockListingRepository, cardRepo *MockCardRepository, userRepo *MockUserRepository, payoutRepo *MockPayoutRepository) service.ListingService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	config := &config.Configuration{
+		Store: config.StoreConfiguration{
+			Currency:             "USD",
+			CommissionPercentage: 10,
+		},
+	}
+
+	return service.NewListingServiceImpl(
+		config,
+		listingRepo,
+		cardRepo,
+		userRepo,
+		payoutRepo,
+		service.NewCurrencyConverter(config, newMockExchangeRateRepository()),
+		validate,
+	)
+}
+
+func Test_Listing_ShouldHighlightCheapestOffer(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	cardRepo := newMockCardRepository()
+	listingService := newListingService(listingRepo, cardRepo, newMockUserRepository())
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Model: gorm.Model{ID: 1}, Price: 1000, InStockAmount: 2})
+	listingRepo.On("FindOffers", uint(1)).Return([]*model.Listing{
+		{Model: gorm.Model{ID: 3}, CardID: 1, Price: 800, Amount: 1, Seller: model.User{Username: "seller"}},
+		{Model: gorm.Model{ID: 4}, CardID: 1, Price: 1500, Amount: 4},
+	})
+
+	// act
+	offers, err := listingService.Offers(1, "")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, offers.Listings, 2)
+	assert.Equal(t, uint(3), offers.Cheapest.Id)
+	assert.Equal(t, "seller", offers.Cheapest.Seller)
+	assert.False(t, offers.StoreIsCheapest)
+}
+
+func Test_Listing_ShouldHighlightStoreWithoutOffers(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	cardRepo := newMockCardRepository()
+	listingService := newListingService(listingRepo, cardRepo, newMockUserRepository())
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Model: gorm.Model{ID: 1}, Price: 1000, InStockAmount: 2})
+	listingRepo.On("FindOffers", uint(1)).Return([]*model.Listing{})
+
+	// act
+	offers, err := listingService.Offers(1, "")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, offers.Cheapest)
+	assert.True(t, offers.StoreIsCheapest)
+}
+
+func Test_Listing_ShouldCreate(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	cardRepo := newMockCardRepository()
+	userRepo := newMockUserRepository()
+	listingService := newListingService(listingRepo, cardRepo, userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Username: "seller", Verified: true})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{})
+	listingRepo.On("Save", mock.Anything).Return(nil)
+
+	// act
+	listing, err := listingService.Create(&dto.PostListing{CardId: 1, Price: 500, Condition: "LP", Amount: 2}, 2)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(2), listing.SellerId)
+	assert.Equal(t, "seller", listing.Seller)
+	assert.Equal(t, "LP", listing.Condition)
+}
+
+func Test_Listing_ShouldNotCreateNotVerified(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	userRepo := newMockUserRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}})
+
+	// act
+	_, err := listingService.Create(&dto.PostListing{CardId: 1, Price: 500, Condition: "LP", Amount: 2}, 2)
+
+	// assert
+	assert.Equal(t, service.ErrNotVerified, err)
+	listingRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Listing_ShouldUpdateOnlyGivenFields(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), newMockUserRepository())
+
+	listing := &model.Listing{SellerID: 2, Price: 500, Condition: model.ConditionLightlyPlayed, Amount: 2}
+	listingRepo.On("FindById", uint(3)).Return(listing)
+	listingRepo.On("Update", listing, []string{"Amount"}).Return(nil)
+	amount := uint(0)
+
+	// act
+	result, err := listingService.Update(3, &dto.PatchListing{Amount: &amount}, 2)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(0), result.Amount)
+	assert.Equal(t, model.Money(500), result.Price)
+}
+
+func Test_Listing_ShouldNotUpdateOtherSellersListing(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), newMockUserRepository())
+
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{SellerID: 5})
+	price := model.Money(100)
+
+	// act
+	_, err := listingService.Update(3, &dto.PatchListing{Price: &price}, 2)
+
+	// assert
+	assert.Equal(t, service.ErrListingNotFound, err)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func Test_Listing_ShouldNotDeleteOtherSellersListing(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), newMockUserRepository())
+
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{SellerID: 5})
+
+	// act
+	err := listingService.Delete(3, 2)
+
+	// assert
+	assert.Equal(t, service.ErrListingNotFound, err)
+	listingRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func Test_Listing_ShouldBuyWithSellersCommission(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	cardRepo := newMockCardRepository()
+	userRepo := newMockUserRepository()
+	payoutRepo := newMockPayoutRepository()
+	listingService := newListingServiceWithPayouts(listingRepo, cardRepo, userRepo, payoutRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{Model: gorm.Model{ID: 3}, SellerID: 2, CardID: 5, Price: 1000, Amount: 4})
+	cardRepo.On("FindById", uint(5)).Return(&model.Card{Model: gorm.Model{ID: 5}, Name: "card"})
+	payoutRepo.On("FindCommission", uint(2)).Return(&model.SellerCommission{SellerID: 2, Percentage: 15})
+	listingRepo.On("Sell", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// act
+	order, err := listingService.Buy(3, &dto.PostListingPurchase{Amount: 3}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(3000), order.Total)
+	assert.Equal(t, uint(2), *order.Lines[0].SellerId)
+	sale := listingRepo.Calls[1].Arguments.Get(2).(*model.SellerSale)
+	assert.Equal(t, model.Money(3000), sale.Gross)
+	assert.Equal(t, model.Money(450), sale.Commission)
+	assert.Equal(t, model.Money(2550), sale.Net)
+}
+
+func Test_Listing_ShouldBuyWithStoreCommission(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	cardRepo := newMockCardRepository()
+	userRepo := newMockUserRepository()
+	payoutRepo := newMockPayoutRepository()
+	listingService := newListingServiceWithPayouts(listingRepo, cardRepo, userRepo, payoutRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{Model: gorm.Model{ID: 3}, SellerID: 2, CardID: 5, Price: 1000, Amount: 4})
+	cardRepo.On("FindById", uint(5)).Return(&model.Card{Model: gorm.Model{ID: 5}, Name: "card"})
+	payoutRepo.On("FindCommission", uint(2)).Return(nil)
+	listingRepo.On("Sell", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// act
+	_, err := listingService.Buy(3, &dto.PostListingPurchase{Amount: 1}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	sale := listingRepo.Calls[1].Arguments.Get(2).(*model.SellerSale)
+	assert.Equal(t, float32(10), sale.CommissionPercentage)
+	assert.Equal(t, model.Money(100), sale.Commission)
+}
+
+func Test_Listing_ShouldNotBuyOwnListing(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	userRepo := newMockUserRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{Model: gorm.Model{ID: 3}, SellerID: 2, CardID: 5, Price: 1000, Amount: 4})
+
+	// act
+	_, err := listingService.Buy(3, &dto.PostListingPurchase{Amount: 1}, 2)
+
+	// assert
+	assert.Equal(t, service.ErrOwnListing, err)
+	listingRepo.AssertNotCalled(t, "Sell", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Listing_ShouldNotBuyMoreThanListed(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	cardRepo := newMockCardRepository()
+	userRepo := newMockUserRepository()
+	listingService := newListingService(listingRepo, cardRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{Model: gorm.Model{ID: 3}, SellerID: 2, CardID: 5, Price: 1000, Amount: 1})
+	cardRepo.On("FindById", uint(5)).Return(&model.Card{Model: gorm.Model{ID: 5}, Name: "card"})
+
+	// act
+	_, err := listingService.Buy(3, &dto.PostListingPurchase{Amount: 2}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrListingUnavailable, err)
+}
+
+func Test_Listing_ShouldNotBuyFromSuspendedSeller(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	userRepo := newMockUserRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true, Suspended: true})
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{Model: gorm.Model{ID: 3}, SellerID: 2, CardID: 5, Price: 1000, Amount: 4})
+
+	// act
+	_, err := listingService.Buy(3, &dto.PostListingPurchase{Amount: 1}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrListingUnavailable, err)
+	listingRepo.AssertNotCalled(t, "Sell", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_Listing_ShouldNotBuyFromDeletedSeller(t *testing.T) {
+	// arrange
+	listingRepo := newMockListingRepository()
+	userRepo := newMockUserRepository()
+	listingService := newListingService(listingRepo, newMockCardRepository(), userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	userRepo.On("FindById", uint(2)).Return(nil)
+	listingRepo.On("FindById", uint(3)).Return(&model.Listing{Model: gorm.Model{ID: 3}, SellerID: 2, CardID: 5, Price: 1000, Amount: 4})
+
+	// act
+	_, err := listingService.Buy(3, &dto.PostListingPurchase{Amount: 1}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrListingUnavailable, err)
+	listingRepo.AssertNotCalled(t, "Sell", mock.Anything, mock.Anything, mock.Anything)
+}
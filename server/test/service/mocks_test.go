@@ -1,9 +1,12 @@
 package service_test
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"store.api/model"
 	"store.api/query"
+	"store.api/repository"
 )
 
 type MockUserRepository struct {
@@ -52,6 +55,63 @@ func (m *MockUserRepository) FindById(id uint) *model.User {
 	return nil
 }
 
+func (m *MockUserRepository) FindByOidcSubject(issuer string, subject string) *model.User {
+	args := m.Called(issuer, subject)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Update(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ReplaceRecoveryCodes(userId uint, codes []*model.RecoveryCode) error {
+	args := m.Called(userId, codes)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UseRecoveryCode(userId uint, codeHash string) (bool, error) {
+	args := m.Called(userId, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UseTotpStep(userId uint, step uint64) (bool, error) {
+	args := m.Called(userId, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Anonymise(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByPasswordResetToken(tokenHash string) *model.User {
+	args := m.Called(tokenHash)
+	switch user := args.Get(0).(type) {
+	case *model.User:
+		return user
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Query(query *query.UserQuery) ([]*model.User, int64) {
+	args := m.Called(query)
+	return args.Get(0).([]*model.User), args.Get(1).(int64)
+}
+
+func (m *MockUserRepository) FindAdmins() []*model.User {
+	args := m.Called()
+	return args.Get(0).([]*model.User)
+}
+
 type MockCardRepository struct {
 	mock.Mock
 }
@@ -81,12 +141,12 @@ func (m *MockCardRepository) Query(query *query.CardQuery) ([]*model.Card, int64
 	return args.Get(0).([]*model.Card), int64(args.Int(1))
 }
 
-func (m *MockCardRepository) Update(c *model.Card) error {
-	args := m.Called(c)
+func (m *MockCardRepository) Update(c *model.Card, movement model.StockMovement) error {
+	args := m.Called(c, movement)
 	return args.Error(0)
 }
 
-func (m *MockCardRepository) UpdatePrice(id uint, newPrice float32) (*model.Card, error) {
+func (m *MockCardRepository) UpdatePrice(id uint, newPrice model.Money) (*model.Card, error) {
 	args := m.Called(id, newPrice)
 	switch card := args.Get(0).(type) {
 	case *model.Card:
@@ -97,8 +157,8 @@ func (m *MockCardRepository) UpdatePrice(id uint, newPrice float32) (*model.Card
 	return nil, args.Error(1)
 }
 
-func (m *MockCardRepository) UpdateInStockAmount(id uint, newPrice uint) (*model.Card, error) {
-	args := m.Called(id, newPrice)
+func (m *MockCardRepository) UpdateInStockAmount(id uint, amount uint, movement model.StockMovement) (*model.Card, error) {
+	args := m.Called(id, amount, movement)
 	switch card := args.Get(0).(type) {
 	case *model.Card:
 		return card, args.Error(1)
@@ -108,11 +168,69 @@ func (m *MockCardRepository) UpdateInStockAmount(id uint, newPrice uint) (*model
 	return nil, args.Error(1)
 }
 
+func (m *MockCardRepository) UpdatePrices(prices map[uint]model.Money) ([]*model.Card, error) {
+	args := m.Called(prices)
+	switch cards := args.Get(0).(type) {
+	case []*model.Card:
+		return cards, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCardRepository) ScalePrices(query *query.CardQuery, factor float64) ([]*model.Card, error) {
+	args := m.Called(query, factor)
+	switch cards := args.Get(0).(type) {
+	case []*model.Card:
+		return cards, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCardRepository) AdjustStock(adjustments []repository.StockAdjustment, movement model.StockMovement) ([]*model.Card, error) {
+	args := m.Called(adjustments, movement)
+	switch cards := args.Get(0).(type) {
+	case []*model.Card:
+		return cards, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCardRepository) StockMovements(cardId uint) []*model.StockMovement {
+	args := m.Called(cardId)
+	return args.Get(0).([]*model.StockMovement)
+}
+
+func (m *MockCardRepository) FindLowStock() []*model.Card {
+	args := m.Called()
+	return args.Get(0).([]*model.Card)
+}
+
 func (m *MockCardRepository) Count() int64 {
 	args := m.Called()
 	return int64(args.Int(0))
 }
 
+func (m *MockCardRepository) PricesAt(ids []uint, at time.Time) map[uint]model.Money {
+	args := m.Called(ids, at)
+	return args.Get(0).(map[uint]model.Money)
+}
+
+func (m *MockCardRepository) FindByKeyName(name string) []*model.Card {
+	args := m.Called(name)
+	return args.Get(0).([]*model.Card)
+}
+
+func (m *MockCardRepository) FindInStockByKeyId(keyId string) []*model.Card {
+	args := m.Called(keyId)
+	return args.Get(0).([]*model.Card)
+}
+
 type MockCollectionRepository struct {
 	mock.Mock
 }
@@ -142,6 +260,22 @@ func (m *MockCollectionRepository) FindById(id uint) *model.Collection {
 	return nil
 }
 
+func (m *MockCollectionRepository) FindByShareToken(token string) *model.Collection {
+	args := m.Called(token)
+	switch col := args.Get(0).(type) {
+	case *model.Collection:
+		return col
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockCollectionRepository) FindPublicByOwnerId(ownerId uint) []*model.Collection {
+	args := m.Called(ownerId)
+	return args.Get(0).([]*model.Collection)
+}
+
 func (m *MockCollectionRepository) Update(c *model.Collection) error {
 	args := m.Called(c)
 	return args.Error(0)
@@ -157,6 +291,11 @@ func (m *MockCollectionRepository) DeleteSlot(slot *model.CollectionSlot) error
 	return args.Error(0)
 }
 
+func (m *MockCollectionRepository) UpdateSlots(collectionId uint, saved []*model.CollectionSlot, deleted []*model.CollectionSlot) error {
+	args := m.Called(collectionId, saved, deleted)
+	return args.Error(0)
+}
+
 func (m *MockCollectionRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -195,6 +334,21 @@ func (m *MockCartRepository) DeleteSlot(slot *model.CartSlot) error {
 	return args.Error(0)
 }
 
+func (m *MockCartRepository) UpdateSlots(cartId uint, saved []*model.CartSlot, deleted []*model.CartSlot) error {
+	args := m.Called(cartId, saved, deleted)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) UpdatePromotionCode(cartId uint, code *string) error {
+	args := m.Called(cartId, code)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) DeleteByUserId(userId uint) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
 type MockLanguageRepository struct {
 	mock.Mock
 }
@@ -220,3 +374,569 @@ func (m *MockExpansionRepository) All() []*model.Expansion {
 	args := m.Called()
 	return args.Get(0).([]*model.Expansion)
 }
+
+func (m *MockExpansionRepository) UpdateLowStockThreshold(id string, threshold *uint) (*model.Expansion, error) {
+	args := m.Called(id, threshold)
+	switch expansion := args.Get(0).(type) {
+	case *model.Expansion:
+		return expansion, args.Error(1)
+	case nil:
+		return nil, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockCardKeyRepository struct {
+	mock.Mock
+}
+
+func newMockCardKeyRepository() *MockCardKeyRepository {
+	return new(MockCardKeyRepository)
+}
+
+func (m *MockCardKeyRepository) All() []*model.CardKey {
+	args := m.Called()
+	return args.Get(0).([]*model.CardKey)
+}
+
+func (m *MockCardKeyRepository) FindById(id string) *model.CardKey {
+	args := m.Called(id)
+	switch key := args.Get(0).(type) {
+	case *model.CardKey:
+		return key
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+type MockWishlistRepository struct {
+	mock.Mock
+}
+
+func newMockWishlistRepository() *MockWishlistRepository {
+	return new(MockWishlistRepository)
+}
+
+func (m *MockWishlistRepository) FindByUserId(userId uint) []*model.Wishlist {
+	args := m.Called(userId)
+	return args.Get(0).([]*model.Wishlist)
+}
+
+func (m *MockWishlistRepository) FindById(id uint) *model.Wishlist {
+	args := m.Called(id)
+	switch wishlist := args.Get(0).(type) {
+	case *model.Wishlist:
+		return wishlist
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockWishlistRepository) FindForCard(card *model.Card) []*model.Wishlist {
+	args := m.Called(card)
+	return args.Get(0).([]*model.Wishlist)
+}
+
+func (m *MockWishlistRepository) Save(wishlist *model.Wishlist) error {
+	args := m.Called(wishlist)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) Update(wishlist *model.Wishlist) error {
+	args := m.Called(wishlist)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func newMockPromotionRepository() *MockPromotionRepository {
+	return new(MockPromotionRepository)
+}
+
+func (m *MockPromotionRepository) FindAll() []*model.Promotion {
+	args := m.Called()
+	return args.Get(0).([]*model.Promotion)
+}
+
+func (m *MockPromotionRepository) FindById(id uint) *model.Promotion {
+	args := m.Called(id)
+	switch promotion := args.Get(0).(type) {
+	case *model.Promotion:
+		return promotion
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockPromotionRepository) FindByCode(code string) *model.Promotion {
+	args := m.Called(code)
+	switch promotion := args.Get(0).(type) {
+	case *model.Promotion:
+		return promotion
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockPromotionRepository) FindAutomatic() []*model.Promotion {
+	args := m.Called()
+	return args.Get(0).([]*model.Promotion)
+}
+
+func (m *MockPromotionRepository) Save(promotion *model.Promotion) error {
+	args := m.Called(promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) Update(promotion *model.Promotion) error {
+	args := m.Called(promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) CountRedemptions(promotionId uint) int64 {
+	args := m.Called(promotionId)
+	return args.Get(0).(int64)
+}
+
+func (m *MockPromotionRepository) CountUserRedemptions(promotionId uint, userId uint) int64 {
+	args := m.Called(promotionId, userId)
+	return args.Get(0).(int64)
+}
+
+func (m *MockPromotionRepository) FindRedemptions(promotionId uint) []*model.PromotionRedemption {
+	args := m.Called(promotionId)
+	return args.Get(0).([]*model.PromotionRedemption)
+}
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func newMockOrderRepository() *MockOrderRepository {
+	return new(MockOrderRepository)
+}
+
+func (m *MockOrderRepository) Create(order *model.Order, cartId uint, redemptions []*model.PromotionRedemption) error {
+	args := m.Called(order, cartId, redemptions)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) FindByUserId(userId uint) []*model.Order {
+	args := m.Called(userId)
+	return args.Get(0).([]*model.Order)
+}
+
+func (m *MockOrderRepository) FindById(id uint) *model.Order {
+	args := m.Called(id)
+	switch order := args.Get(0).(type) {
+	case *model.Order:
+		return order
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+type MockCollectionFolderRepository struct {
+	mock.Mock
+}
+
+func newMockCollectionFolderRepository() *MockCollectionFolderRepository {
+	return new(MockCollectionFolderRepository)
+}
+
+func (m *MockCollectionFolderRepository) FindByOwnerId(ownerId uint) []*model.CollectionFolder {
+	args := m.Called(ownerId)
+	return args.Get(0).([]*model.CollectionFolder)
+}
+
+func (m *MockCollectionFolderRepository) FindById(id uint) *model.CollectionFolder {
+	args := m.Called(id)
+	switch folder := args.Get(0).(type) {
+	case *model.CollectionFolder:
+		return folder
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockCollectionFolderRepository) Save(folder *model.CollectionFolder) error {
+	args := m.Called(folder)
+	return args.Error(0)
+}
+
+func (m *MockCollectionFolderRepository) Update(folder *model.CollectionFolder) error {
+	args := m.Called(folder)
+	return args.Error(0)
+}
+
+func (m *MockCollectionFolderRepository) Delete(folder *model.CollectionFolder) error {
+	args := m.Called(folder)
+	return args.Error(0)
+}
+
+type MockLoginAuditRepository struct {
+	mock.Mock
+}
+
+func newMockLoginAuditRepository() *MockLoginAuditRepository {
+	return new(MockLoginAuditRepository)
+}
+
+func (m *MockLoginAuditRepository) Save(failed *model.FailedLogin) error {
+	args := m.Called(failed)
+	return args.Error(0)
+}
+
+func (m *MockLoginAuditRepository) FindRecent(username string, limit uint) []*model.FailedLogin {
+	args := m.Called(username, limit)
+	return args.Get(0).([]*model.FailedLogin)
+}
+
+type MockLoginAttemptCache struct {
+	mock.Mock
+}
+
+func newMockLoginAttemptCache() *MockLoginAttemptCache {
+	return new(MockLoginAttemptCache)
+}
+
+func (m *MockLoginAttemptCache) Fail(key string, window time.Duration) int64 {
+	args := m.Called(key, window)
+	return args.Get(0).(int64)
+}
+
+func (m *MockLoginAttemptCache) Lock(key string, duration time.Duration) {
+	m.Called(key, duration)
+}
+
+func (m *MockLoginAttemptCache) LockedFor(key string) time.Duration {
+	args := m.Called(key)
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockLoginAttemptCache) Reset(key string) {
+	m.Called(key)
+}
+
+type MockOidcFlowCache struct {
+	mock.Mock
+}
+
+func newMockOidcFlowCache() *MockOidcFlowCache {
+	return new(MockOidcFlowCache)
+}
+
+func (m *MockOidcFlowCache) Remember(state string, flow *model.OidcFlow, ttl time.Duration) {
+	m.Called(state, flow, ttl)
+}
+
+func (m *MockOidcFlowCache) Take(state string) *model.OidcFlow {
+	args := m.Called(state)
+	switch flow := args.Get(0).(type) {
+	case *model.OidcFlow:
+		return flow
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+type MockGuestCartCache struct {
+	mock.Mock
+}
+
+func newMockGuestCartCache() *MockGuestCartCache {
+	return new(MockGuestCartCache)
+}
+
+func (m *MockGuestCartCache) Remember(guestId string, cart *model.Cart, ttl time.Duration) {
+	m.Called(guestId, cart, ttl)
+}
+
+func (m *MockGuestCartCache) Get(guestId string) *model.Cart {
+	args := m.Called(guestId)
+	switch cart := args.Get(0).(type) {
+	case *model.Cart:
+		return cart
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockGuestCartCache) Take(guestId string) *model.Cart {
+	args := m.Called(guestId)
+	switch cart := args.Get(0).(type) {
+	case *model.Cart:
+		return cart
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func newMockExchangeRateRepository() *MockExchangeRateRepository {
+	return new(MockExchangeRateRepository)
+}
+
+func (m *MockExchangeRateRepository) FindAll() []*model.ExchangeRate {
+	args := m.Called()
+	return args.Get(0).([]*model.ExchangeRate)
+}
+
+func (m *MockExchangeRateRepository) FindByCurrency(currency string) *model.ExchangeRate {
+	args := m.Called(currency)
+	switch rate := args.Get(0).(type) {
+	case *model.ExchangeRate:
+		return rate
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockExchangeRateRepository) Save(rates ...*model.ExchangeRate) error {
+	args := m.Called(rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateRepository) Delete(currency string) error {
+	args := m.Called(currency)
+	return args.Error(0)
+}
+
+type MockScheduledPriceRepository struct {
+	mock.Mock
+}
+
+func newMockScheduledPriceRepository() *MockScheduledPriceRepository {
+	return new(MockScheduledPriceRepository)
+}
+
+func (m *MockScheduledPriceRepository) FindById(id uint) *model.ScheduledPriceChange {
+	args := m.Called(id)
+	switch change := args.Get(0).(type) {
+	case *model.ScheduledPriceChange:
+		return change
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockScheduledPriceRepository) FindPending(cardId uint) []*model.ScheduledPriceChange {
+	args := m.Called(cardId)
+	return args.Get(0).([]*model.ScheduledPriceChange)
+}
+
+func (m *MockScheduledPriceRepository) FindDue(now time.Time) []*model.ScheduledPriceChange {
+	args := m.Called(now)
+	return args.Get(0).([]*model.ScheduledPriceChange)
+}
+
+func (m *MockScheduledPriceRepository) FindEnded(now time.Time) []*model.ScheduledPriceChange {
+	args := m.Called(now)
+	return args.Get(0).([]*model.ScheduledPriceChange)
+}
+
+func (m *MockScheduledPriceRepository) Save(change *model.ScheduledPriceChange) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
+
+func (m *MockScheduledPriceRepository) Update(change *model.ScheduledPriceChange) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
+
+func (m *MockScheduledPriceRepository) Transition(change *model.ScheduledPriceChange, from model.ScheduledPriceStatus) (bool, error) {
+	args := m.Called(change, from)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduledPriceRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func newMockMailer() *MockMailer {
+	return new(MockMailer)
+}
+
+func (m *MockMailer) Send(to string, subject string, body string) error {
+	args := m.Called(to, subject, body)
+	return args.Error(0)
+}
+
+type MockListingRepository struct {
+	mock.Mock
+}
+
+func newMockListingRepository() *MockListingRepository {
+	return new(MockListingRepository)
+}
+
+func (m *MockListingRepository) FindById(id uint) *model.Listing {
+	args := m.Called(id)
+	switch listing := args.Get(0).(type) {
+	case *model.Listing:
+		return listing
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockListingRepository) FindBySeller(sellerId uint) []*model.Listing {
+	args := m.Called(sellerId)
+	return args.Get(0).([]*model.Listing)
+}
+
+func (m *MockListingRepository) FindOffers(cardId uint) []*model.Listing {
+	args := m.Called(cardId)
+	return args.Get(0).([]*model.Listing)
+}
+
+func (m *MockListingRepository) Summaries(cardIds []uint) map[uint]*repository.OfferSummary {
+	args := m.Called(cardIds)
+	return args.Get(0).(map[uint]*repository.OfferSummary)
+}
+
+func (m *MockListingRepository) Save(listing *model.Listing) error {
+	args := m.Called(listing)
+	return args.Error(0)
+}
+
+func (m *MockListingRepository) Update(listing *model.Listing, fields ...string) error {
+	args := m.Called(listing, fields)
+	return args.Error(0)
+}
+
+func (m *MockListingRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockListingRepository) Sell(listing *model.Listing, order *model.Order, sale *model.SellerSale) error {
+	args := m.Called(listing, order, sale)
+	return args.Error(0)
+}
+
+type MockTradeRepository struct {
+	mock.Mock
+}
+
+func newMockTradeRepository() *MockTradeRepository {
+	return new(MockTradeRepository)
+}
+
+func (m *MockTradeRepository) FindById(id uint) *model.Trade {
+	args := m.Called(id)
+	switch trade := args.Get(0).(type) {
+	case *model.Trade:
+		return trade
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockTradeRepository) FindByUserId(userId uint) []*model.Trade {
+	args := m.Called(userId)
+	return args.Get(0).([]*model.Trade)
+}
+
+func (m *MockTradeRepository) Save(trade *model.Trade) error {
+	args := m.Called(trade)
+	return args.Error(0)
+}
+
+func (m *MockTradeRepository) Counter(trade *model.Trade, counter *model.Trade) error {
+	args := m.Called(trade, counter)
+	return args.Error(0)
+}
+
+func (m *MockTradeRepository) Close(trade *model.Trade, status model.TradeStatus) error {
+	args := m.Called(trade, status)
+	return args.Error(0)
+}
+
+func (m *MockTradeRepository) Accept(trade *model.Trade) error {
+	args := m.Called(trade)
+	return args.Error(0)
+}
+
+type MockPayoutRepository struct {
+	mock.Mock
+}
+
+func newMockPayoutRepository() *MockPayoutRepository {
+	return new(MockPayoutRepository)
+}
+
+func (m *MockPayoutRepository) FindSales(sellerId uint, from time.Time, to time.Time) []*model.SellerSale {
+	args := m.Called(sellerId, from, to)
+	return args.Get(0).([]*model.SellerSale)
+}
+
+func (m *MockPayoutRepository) Reconcile(sellerId uint, from time.Time, to time.Time) *repository.SalesReconciliation {
+	args := m.Called(sellerId, from, to)
+	return args.Get(0).(*repository.SalesReconciliation)
+}
+
+func (m *MockPayoutRepository) FindBySeller(sellerId uint) []*model.Payout {
+	args := m.Called(sellerId)
+	return args.Get(0).([]*model.Payout)
+}
+
+func (m *MockPayoutRepository) FindCommission(sellerId uint) *model.SellerCommission {
+	args := m.Called(sellerId)
+	switch commission := args.Get(0).(type) {
+	case *model.SellerCommission:
+		return commission
+	case nil:
+		return nil
+	}
+	return nil
+}
+
+func (m *MockPayoutRepository) SaveCommission(commission *model.SellerCommission) error {
+	args := m.Called(commission)
+	return args.Error(0)
+}
+
+func (m *MockPayoutRepository) DeleteCommission(sellerId uint) error {
+	args := m.Called(sellerId)
+	return args.Error(0)
+}
+
+func (m *MockPayoutRepository) Pay(payout *model.Payout) error {
+	args := m.Called(payout)
+	return args.Error(0)
+}
@@ -0,0 +1,427 @@
+package service_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/security"
+	"store.api/service"
+)
+
+const (
+	mockIssuerClientId = "card-store"
+	mockIssuerKeyId    = "key1"
+)
+
+// mockIssuer is a local OpenID provider that serves the discovery document, the key set and a token endpoint
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	subject       string
+	email         string
+	emailVerified bool
+
+	// code -> authorization request parameters
+	requests map[string]url.Values
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	checkErr(t, err)
+
+	result := &mockIssuer{
+		key:           key,
+		subject:       "subject1",
+		email:         "mail@mail.com",
+		emailVerified: true,
+		requests:      map[string]url.Values{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 result.server.URL,
+			"authorization_endpoint": result.server.URL + "/authorize",
+			"token_endpoint":         result.server.URL + "/token",
+			"jwks_uri":               result.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": mockIssuerKeyId,
+				"kty": "RSA",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		request, ok := result.requests[r.Form.Get("code")]
+		if !ok || security.PkceChallenge(r.Form.Get("code_verifier")) != request.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            result.server.URL,
+			"aud":            mockIssuerClientId,
+			"sub":            result.subject,
+			"nonce":          request.Get("nonce"),
+			"email":          result.email,
+			"email_verified": result.emailVerified,
+			"exp":            time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = mockIssuerKeyId
+		signed, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{
+			"id_token": signed,
+		})
+	})
+	result.server = httptest.NewServer(mux)
+	t.Cleanup(result.server.Close)
+
+	return result
+}
+
+// authorize simulates the user logging in at the provider and returns the code that the provider would redirect with
+func (i *mockIssuer) authorize(t *testing.T, authUrl string) (code string, state string) {
+	parsed, err := url.Parse(authUrl)
+	checkErr(t, err)
+	code = "code" + parsed.Query().Get("state")
+	i.requests[code] = parsed.Query()
+	return code, parsed.Query().Get("state")
+}
+
+func checkErr(t *testing.T, err error) {
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newOidcService(issuer *mockIssuer, userRepo *MockUserRepository, cartRepo *MockCartRepository, flows *MockOidcFlowCache) service.OidcService {
+	return service.NewOidcServiceImpl(
+		&config.Configuration{
+			Oidc: config.OidcConfiguration{
+				Enabled: true,
+			},
+		},
+		security.NewOidcProvider(issuer.server.URL, mockIssuerClientId, "", "http://localhost/callback", []string{"openid", "email"}),
+		userRepo,
+		cartRepo,
+		flows,
+	)
+}
+
+// rememberFlows records the flows stored in the flow cache mock by state
+func rememberFlows(flows *MockOidcFlowCache) map[string]*model.OidcFlow {
+	result := map[string]*model.OidcFlow{}
+	flows.On("Remember", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		result[args.String(0)] = args.Get(1).(*model.OidcFlow)
+	})
+	return result
+}
+
+func Test_Oidc_ShouldBuildAuthorizationUrl(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	flows := newMockOidcFlowCache()
+	service := newOidcService(issuer, newMockUserRepository(), newMockCartRepository(), flows)
+	rememberFlows(flows)
+
+	// act
+	result, _, err := service.AuthorizationUrl()
+
+	// assert
+	assert.Nil(t, err)
+	parsed, _ := url.Parse(result)
+	assert.Equal(t, issuer.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, mockIssuerClientId, parsed.Query().Get("client_id"))
+	assert.NotEmpty(t, parsed.Query().Get("state"))
+	assert.NotEmpty(t, parsed.Query().Get("nonce"))
+}
+
+func Test_Oidc_ShouldCreateUser(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	flows := newMockOidcFlowCache()
+	service := newOidcService(issuer, userRepo, cartRepo, flows)
+	stored := rememberFlows(flows)
+
+	userRepo.On("FindByOidcSubject", issuer.server.URL, issuer.subject).Return(nil)
+	userRepo.On("FindByEmail", issuer.email).Return(nil)
+	userRepo.On("FindByUsername", "mail").Return(&model.User{})
+	userRepo.On("FindByUsername", "mail1").Return(nil)
+	userRepo.On("Save", mock.MatchedBy(func(u *model.User) bool {
+		return u.Username == "mail1" && *u.OidcSubject == issuer.subject && u.Verified
+	})).Return(nil)
+	cartRepo.On("Save", mock.Anything).Return(nil)
+
+	authUrl, _, err := service.AuthorizationUrl()
+	checkErr(t, err)
+	code, state := issuer.authorize(t, authUrl)
+	flows.On("Take", state).Return(stored[state])
+
+	// act
+	user, err := service.Callback(code, state)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "mail1", user.Username)
+	userRepo.AssertExpectations(t)
+	cartRepo.AssertExpectations(t)
+}
+
+func Test_Oidc_ShouldLinkExistingUser(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	flows := newMockOidcFlowCache()
+	service := newOidcService(issuer, userRepo, newMockCartRepository(), flows)
+	stored := rememberFlows(flows)
+	existing := &model.User{Username: "user", Email: issuer.email, Verified: true}
+
+	userRepo.On("FindByOidcSubject", issuer.server.URL, issuer.subject).Return(nil)
+	userRepo.On("FindByEmail", issuer.email).Return(existing)
+	userRepo.On("Update", existing).Return(nil)
+
+	authUrl, _, err := service.AuthorizationUrl()
+	checkErr(t, err)
+	code, state := issuer.authorize(t, authUrl)
+	flows.On("Take", state).Return(stored[state])
+
+	// act
+	user, err := service.Callback(code, state)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "user", user.Username)
+	assert.Equal(t, issuer.subject, *existing.OidcSubject)
+}
+
+func Test_Oidc_ShouldNotLinkUnverifiedUser(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	flows := newMockOidcFlowCache()
+	service := newOidcService(issuer, userRepo, cartRepo, flows)
+	stored := rememberFlows(flows)
+	existing := &model.User{Username: "mail", Email: issuer.email}
+
+	userRepo.On("FindByOidcSubject", issuer.server.URL, issuer.subject).Return(nil)
+	userRepo.On("FindByEmail", issuer.email).Return(existing)
+	userRepo.On("FindByUsername", "mail").Return(existing)
+	userRepo.On("FindByUsername", "mail1").Return(nil)
+	userRepo.On("Save", mock.Anything).Return(nil)
+	cartRepo.On("Save", mock.Anything).Return(nil)
+
+	authUrl, _, err := service.AuthorizationUrl()
+	checkErr(t, err)
+	code, state := issuer.authorize(t, authUrl)
+	flows.On("Take", state).Return(stored[state])
+
+	// act
+	user, err := service.Callback(code, state)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "mail1", user.Username)
+	assert.Nil(t, existing.OidcSubject)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func Test_Oidc_ShouldNotLinkUnverifiedEmail(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	issuer.emailVerified = false
+	userRepo := newMockUserRepository()
+	cartRepo := newMockCartRepository()
+	flows := newMockOidcFlowCache()
+	service := newOidcService(issuer, userRepo, cartRepo, flows)
+	stored := rememberFlows(flows)
+
+	userRepo.On("FindByOidcSubject", issuer.server.URL, issuer.subject).Return(nil)
+	userRepo.On("FindByUsername", mock.Anything).Return(nil)
+	userRepo.On("Save", mock.Anything).Return(nil)
+	cartRepo.On("Save", mock.Anything).Return(nil)
+
+	authUrl, _, err := service.AuthorizationUrl()
+	checkErr(t, err)
+	code, state := issuer.authorize(t, authUrl)
+	flows.On("Take", state).Return(stored[state])
+
+	// act
+	user, err := service.Callback(code, state)
+
+	// assert
+	assert.Nil(t, err)
+	assert.False(t, user.Verified)
+	userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
+
+func Test_Oidc_ShouldNotLoginUnknownState(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	flows := newMockOidcFlowCache()
+	s := newOidcService(issuer, newMockUserRepository(), newMockCartRepository(), flows)
+
+	flows.On("Take", "state").Return(nil)
+
+	// act
+	user, err := s.Callback("code", "state")
+
+	// assert
+	assert.Nil(t, user)
+	assert.Equal(t, service.ErrOidcInvalidState, err)
+}
+
+func Test_Oidc_ShouldNotLoginWrongVerifier(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	flows := newMockOidcFlowCache()
+	service := newOidcService(issuer, newMockUserRepository(), newMockCartRepository(), flows)
+	rememberFlows(flows)
+
+	authUrl, _, err := service.AuthorizationUrl()
+	checkErr(t, err)
+	code, _ := issuer.authorize(t, authUrl)
+	flows.On("Take", "other").Return(&model.OidcFlow{Verifier: "wrong", Nonce: "nonce"})
+
+	// act
+	user, err := service.Callback(code, "other")
+
+	// assert
+	assert.Nil(t, user)
+	assert.NotNil(t, err)
+}
+
+func Test_Oidc_ShouldRequireTwoFactor(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	flows := newMockOidcFlowCache()
+	s := newOidcService(issuer, userRepo, newMockCartRepository(), flows)
+	stored := rememberFlows(flows)
+	existing := &model.User{Username: "user", TwoFactorEnabled: true}
+	existing.ID = 1
+
+	userRepo.On("FindByOidcSubject", issuer.server.URL, issuer.subject).Return(existing)
+
+	authUrl, _, err := s.AuthorizationUrl()
+	checkErr(t, err)
+	code, state := issuer.authorize(t, authUrl)
+	flows.On("Take", state).Return(stored[state])
+
+	// act
+	user, err := s.Callback(code, state)
+
+	// assert
+	assert.Nil(t, user)
+	assert.Equal(t, service.ErrTwoFactorRequired, err)
+	assert.Equal(t, uint(1), stored[state].UserID)
+}
+
+func Test_Oidc_ShouldNotLoginPasswordResetRequired(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	flows := newMockOidcFlowCache()
+	s := newOidcService(issuer, userRepo, newMockCartRepository(), flows)
+	stored := rememberFlows(flows)
+	existing := &model.User{Username: "user", PasswordResetRequired: true}
+
+	userRepo.On("FindByOidcSubject", issuer.server.URL, issuer.subject).Return(existing)
+
+	authUrl, _, err := s.AuthorizationUrl()
+	checkErr(t, err)
+	code, state := issuer.authorize(t, authUrl)
+	flows.On("Take", state).Return(stored[state])
+
+	// act
+	user, err := s.Callback(code, state)
+
+	// assert
+	assert.Nil(t, user)
+	assert.Equal(t, service.ErrPasswordResetRequired, err)
+}
+
+func Test_Oidc_ShouldCompleteTwoFactor(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	flows := newMockOidcFlowCache()
+	s := newOidcService(issuer, userRepo, newMockCartRepository(), flows)
+	existing := &model.User{Username: "user", TwoFactorEnabled: true}
+	existing.ID = 1
+	details := &dto.OidcTwoFactor{State: "state", Code: "recovery"}
+
+	flows.On("Take", "state").Return(&model.OidcFlow{UserID: 1})
+	userRepo.On("FindById", uint(1)).Return(existing)
+	userRepo.On("UseRecoveryCode", uint(1), security.HashRecoveryCode("", details.Code)).Return(true, nil)
+
+	// act
+	user, err := s.CompleteTwoFactor(details)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "user", user.Username)
+}
+
+func Test_Oidc_ShouldNotCompleteTwoFactorWrongCode(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	userRepo := newMockUserRepository()
+	flows := newMockOidcFlowCache()
+	s := newOidcService(issuer, userRepo, newMockCartRepository(), flows)
+	existing := &model.User{Username: "user", TwoFactorEnabled: true}
+	existing.ID = 1
+
+	flows.On("Take", "state").Return(&model.OidcFlow{UserID: 1})
+	userRepo.On("FindById", uint(1)).Return(existing)
+	userRepo.On("UseRecoveryCode", mock.Anything, mock.Anything).Return(false, nil)
+
+	// act
+	user, err := s.CompleteTwoFactor(&dto.OidcTwoFactor{State: "state", Code: "wrong"})
+
+	// assert
+	assert.Nil(t, user)
+	assert.Equal(t, service.ErrInvalidTwoFactorCode, err)
+}
+
+func Test_Oidc_ShouldNotCompleteTwoFactorWithoutLogin(t *testing.T) {
+	// arrange
+	issuer := newMockIssuer(t)
+	flows := newMockOidcFlowCache()
+	s := newOidcService(issuer, newMockUserRepository(), newMockCartRepository(), flows)
+
+	flows.On("Take", "state").Return(&model.OidcFlow{Verifier: "verifier", Nonce: "nonce"})
+
+	// act
+	user, err := s.CompleteTwoFactor(&dto.OidcTwoFactor{State: "state", Code: "123456"})
+
+	// assert
+	assert.Nil(t, user)
+	assert.Equal(t, service.ErrOidcInvalidState, err)
+}
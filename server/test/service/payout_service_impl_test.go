@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"store.api/config"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/service"
+)
+
+func newPayoutService(payoutRepo *MockPayoutRepository, userRepo *MockUserRepository) service.PayoutService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	config := &config.Configuration{
+		Store: config.StoreConfiguration{
+			CommissionPercentage: 10,
+		},
+	}
+
+	return service.NewPayoutServiceImpl(
+		config,
+		payoutRepo,
+		userRepo,
+		validate,
+	)
+}
+
+func Test_Payout_ShouldSumUpStatement(t *testing.T) {
+	// arrange
+	payoutRepo := newMockPayoutRepository()
+	userRepo := newMockUserRepository()
+	payoutService := newPayoutService(payoutRepo, userRepo)
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}})
+	payoutRepo.On("FindSales", uint(2), from, to).Return([]*model.SellerSale{
+		{Gross: 1000, Commission: 100, Net: 900, PayoutID: uintPtr(1)},
+		{Gross: 500, Commission: 50, Net: 450},
+	})
+	payoutRepo.On("Reconcile", uint(2), from, to).Return(&repository.SalesReconciliation{
+		Payouts:    1,
+		Mismatched: []uint{},
+	})
+
+	// act
+	statement, err := payoutService.Statement(2, &dto.Period{From: "2024-03-01", To: "2024-03-31"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-03-31", statement.To)
+	assert.Equal(t, model.Money(1500), statement.Gross)
+	assert.Equal(t, model.Money(150), statement.Commission)
+	assert.Equal(t, model.Money(900), statement.Paid)
+	assert.Equal(t, model.Money(450), statement.Unpaid)
+	assert.Equal(t, uint(1), statement.Payouts)
+	assert.True(t, statement.Reconciled)
+}
+
+func Test_Payout_ShouldNotAcceptReversedPeriod(t *testing.T) {
+	// arrange
+	payoutService := newPayoutService(newMockPayoutRepository(), newMockUserRepository())
+
+	// act
+	_, err := payoutService.Statement(2, &dto.Period{From: "2024-04-01", To: "2024-03-31"})
+
+	// assert
+	assert.Equal(t, service.ErrInvalidPeriod, err)
+}
+
+func Test_Payout_ShouldPay(t *testing.T) {
+	// arrange
+	payoutRepo := newMockPayoutRepository()
+	userRepo := newMockUserRepository()
+	payoutService := newPayoutService(payoutRepo, userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}})
+	payoutRepo.On("Reconcile", uint(2), mock.Anything, mock.Anything).Return(&repository.SalesReconciliation{
+		Mismatched: []uint{},
+	})
+	payoutRepo.On("Pay", mock.Anything).Return(nil)
+
+	// act
+	payout, err := payoutService.Pay(&dto.PostPayout{SellerId: 2, From: "2024-03-01", To: "2024-03-31", Reference: "transfer"}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), payout.PaidById)
+	assert.Equal(t, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC), payout.PeriodEnd)
+	payoutRepo.AssertExpectations(t)
+}
+
+func Test_Payout_ShouldNotPayUnreconciled(t *testing.T) {
+	// arrange
+	payoutRepo := newMockPayoutRepository()
+	userRepo := newMockUserRepository()
+	payoutService := newPayoutService(payoutRepo, userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}})
+	payoutRepo.On("Reconcile", uint(2), mock.Anything, mock.Anything).Return(&repository.SalesReconciliation{
+		Payouts:    2,
+		Mismatched: []uint{4},
+	})
+
+	// act
+	_, err := payoutService.Pay(&dto.PostPayout{SellerId: 2, From: "2024-03-01", To: "2024-03-31"}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrSalesUnreconciled, err)
+	payoutRepo.AssertNotCalled(t, "Pay", mock.Anything)
+}
+
+func Test_Payout_ShouldNotPayWithoutSales(t *testing.T) {
+	// arrange
+	payoutRepo := newMockPayoutRepository()
+	userRepo := newMockUserRepository()
+	payoutService := newPayoutService(payoutRepo, userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}})
+	payoutRepo.On("Reconcile", uint(2), mock.Anything, mock.Anything).Return(&repository.SalesReconciliation{})
+	payoutRepo.On("Pay", mock.Anything).Return(repository.ErrNothingToPay)
+
+	// act
+	_, err := payoutService.Pay(&dto.PostPayout{SellerId: 2, From: "2024-03-01", To: "2024-03-31"}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrNothingToPay, err)
+}
+
+func Test_Payout_ShouldResetCommission(t *testing.T) {
+	// arrange
+	payoutRepo := newMockPayoutRepository()
+	userRepo := newMockUserRepository()
+	payoutService := newPayoutService(payoutRepo, userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}})
+	payoutRepo.On("DeleteCommission", uint(2)).Return(nil)
+	payoutRepo.On("FindCommission", uint(2)).Return(nil)
+
+	// act
+	commission, err := payoutService.UpdateCommission(2, &dto.CommissionUpdate{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, commission.Default)
+	assert.Equal(t, float32(10), commission.Percentage)
+}
+
+func Test_Payout_ShouldNotSetCommissionAboveHundred(t *testing.T) {
+	// arrange
+	payoutRepo := newMockPayoutRepository()
+	payoutService := newPayoutService(payoutRepo, newMockUserRepository())
+	percentage := float32(120)
+
+	// act
+	_, err := payoutService.UpdateCommission(2, &dto.CommissionUpdate{Percentage: &percentage})
+
+	// assert
+	assert.NotNil(t, err)
+	payoutRepo.AssertNotCalled(t, "SaveCommission", mock.Anything)
+}
@@ -0,0 +1,342 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+func newPriceScheduleService(scheduleRepo *MockScheduledPriceRepository, cardRepo *MockCardRepository) service.PriceScheduleService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return service.NewPriceScheduleServiceImpl(
+		scheduleRepo,
+		cardRepo,
+		validate,
+	)
+}
+
+func Test_PriceSchedule_ShouldStartSalesWindow(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	ends := now.Add(time.Hour)
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now.Add(-time.Minute), EndsAt: &ends, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{change})
+	scheduleRepo.On("Transition", change, model.ScheduledPricePending).Return(true, nil)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+	cardRepo.On("UpdatePrice", uint(1), model.Money(500)).Return(&model.Card{Price: 500}, nil)
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.ScheduledPriceActive, change.Status)
+	assert.Equal(t, model.Money(1000), *change.PreviousPrice)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_PriceSchedule_ShouldApplyChangeWithoutEnd(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{change})
+	scheduleRepo.On("Transition", change, model.ScheduledPricePending).Return(true, nil)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+	cardRepo.On("UpdatePrice", uint(1), model.Money(500)).Return(&model.Card{Price: 500}, nil)
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.ScheduledPriceDone, change.Status)
+}
+
+func Test_PriceSchedule_ShouldRestorePriceWhenWindowEnds(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	ends := now.Add(-time.Minute)
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now.Add(-time.Hour), EndsAt: &ends, Status: model.ScheduledPriceActive, PreviousPrice: moneyPtr(1000)}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{change})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("Transition", change, model.ScheduledPriceActive).Return(true, nil)
+	cardRepo.On("UpdatePrice", uint(1), model.Money(1000)).Return(&model.Card{Price: 1000}, nil)
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.ScheduledPriceDone, change.Status)
+	cardRepo.AssertExpectations(t)
+}
+
+func Test_PriceSchedule_ShouldSkipPassedWindow(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	ends := now.Add(-time.Minute)
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now.Add(-time.Hour), EndsAt: &ends, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{change})
+	scheduleRepo.On("Transition", change, model.ScheduledPricePending).Return(true, nil)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.ScheduledPriceDone, change.Status)
+	cardRepo.AssertNotCalled(t, "UpdatePrice", mock.Anything, mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldKeepFailedChangePending(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{change})
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+	scheduleRepo.On("Transition", change, mock.Anything).Return(true, nil)
+	cardRepo.On("UpdatePrice", uint(1), model.Money(500)).Return(nil, errors.New("connection lost"))
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ScheduledPricePending, change.Status)
+	scheduleRepo.AssertCalled(t, "Transition", change, model.ScheduledPriceDone)
+	scheduleRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldNotApplyChangeClaimedByOtherRun(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{change})
+	scheduleRepo.On("Transition", change, model.ScheduledPricePending).Return(false, nil)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.Nil(t, err)
+	cardRepo.AssertNotCalled(t, "UpdatePrice", mock.Anything, mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldNotRestoreWindowEndedByOtherRun(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	now := time.Now()
+	ends := now.Add(-time.Minute)
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, StartsAt: now.Add(-time.Hour), EndsAt: &ends, Status: model.ScheduledPriceActive, PreviousPrice: moneyPtr(1000)}
+	scheduleRepo.On("FindEnded", now).Return([]*model.ScheduledPriceChange{change})
+	scheduleRepo.On("FindDue", now).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("Transition", change, model.ScheduledPriceActive).Return(false, nil)
+
+	// act
+	err := priceScheduleService.Run(now)
+
+	// assert
+	assert.Nil(t, err)
+	cardRepo.AssertNotCalled(t, "UpdatePrice", mock.Anything, mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldSchedule(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	starts := time.Now().Add(time.Hour)
+	ends := starts.Add(72 * time.Hour)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+	scheduleRepo.On("FindPending", uint(1)).Return([]*model.ScheduledPriceChange{})
+	scheduleRepo.On("Save", mock.Anything).Return(nil)
+
+	// act
+	result, err := priceScheduleService.Schedule(&dto.PostScheduledPrice{CardId: 1, Price: 500, StartsAt: starts, EndsAt: &ends})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.Money(500), result.Price)
+	assert.Equal(t, string(model.ScheduledPricePending), result.Status)
+}
+
+func Test_PriceSchedule_ShouldNotScheduleOverlappingWindow(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	starts := time.Now().Add(time.Hour)
+	ends := starts.Add(72 * time.Hour)
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Price: 1000})
+	scheduleRepo.On("FindPending", uint(1)).Return([]*model.ScheduledPriceChange{
+		{CardID: 1, Price: 700, StartsAt: starts.Add(-time.Hour), EndsAt: &ends, Status: model.ScheduledPricePending},
+	})
+
+	// act
+	_, err := priceScheduleService.Schedule(&dto.PostScheduledPrice{CardId: 1, Price: 500, StartsAt: starts})
+
+	// assert
+	assert.Equal(t, service.ErrScheduledPriceOverlap, err)
+	scheduleRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldNotScheduleEndedWindow(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	starts := time.Now().Add(-time.Hour)
+	ends := starts.Add(time.Minute)
+
+	// act
+	_, err := priceScheduleService.Schedule(&dto.PostScheduledPrice{CardId: 1, Price: 500, StartsAt: starts, EndsAt: &ends})
+
+	// assert
+	assert.Equal(t, service.ErrScheduledPriceWindow, err)
+}
+
+func Test_PriceSchedule_ShouldNotScheduleZeroPrice(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	// act
+	_, err := priceScheduleService.Schedule(&dto.PostScheduledPrice{CardId: 1, Price: 0, StartsAt: time.Now()})
+
+	// assert
+	assert.NotNil(t, err)
+	scheduleRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldNotScheduleForMissingCard(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	cardRepo.On("FindById", uint(1)).Return(nil)
+
+	// act
+	_, err := priceScheduleService.Schedule(&dto.PostScheduledPrice{CardId: 1, Price: 500, StartsAt: time.Now()})
+
+	// assert
+	assert.Equal(t, service.ErrCardNotFound, err)
+}
+
+func Test_PriceSchedule_ShouldCancelPendingChange(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindById", uint(2)).Return(change)
+	scheduleRepo.On("Transition", change, model.ScheduledPricePending).Return(true, nil)
+
+	// act
+	err := priceScheduleService.Cancel(2)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.ScheduledPriceCancelled, change.Status)
+	cardRepo.AssertNotCalled(t, "UpdatePrice", mock.Anything, mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldNotCancelChangeAppliedMeanwhile(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, Status: model.ScheduledPricePending}
+	scheduleRepo.On("FindById", uint(2)).Return(change)
+	scheduleRepo.On("Transition", change, model.ScheduledPricePending).Return(false, nil)
+
+	// act
+	err := priceScheduleService.Cancel(2)
+
+	// assert
+	assert.Equal(t, service.ErrScheduledPriceApplied, err)
+	cardRepo.AssertNotCalled(t, "UpdatePrice", mock.Anything, mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldEndActiveWindowOnCancel(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	change := &model.ScheduledPriceChange{CardID: 1, Price: 500, Status: model.ScheduledPriceActive, PreviousPrice: moneyPtr(1000)}
+	scheduleRepo.On("FindById", uint(2)).Return(change)
+	scheduleRepo.On("Transition", change, model.ScheduledPriceActive).Return(true, nil)
+	cardRepo.On("UpdatePrice", uint(1), model.Money(1000)).Return(&model.Card{Price: 1000}, nil)
+
+	// act
+	err := priceScheduleService.Cancel(2)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, model.ScheduledPriceDone, change.Status)
+	scheduleRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func Test_PriceSchedule_ShouldNotCancelDoneChange(t *testing.T) {
+	// arrange
+	scheduleRepo := newMockScheduledPriceRepository()
+	cardRepo := newMockCardRepository()
+	priceScheduleService := newPriceScheduleService(scheduleRepo, cardRepo)
+
+	scheduleRepo.On("FindById", uint(2)).Return(&model.ScheduledPriceChange{CardID: 1, Price: 500, Status: model.ScheduledPriceDone})
+
+	// act
+	err := priceScheduleService.Cancel(2)
+
+	// assert
+	assert.Equal(t, service.ErrScheduledPriceDone, err)
+}
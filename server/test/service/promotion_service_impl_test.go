@@ -0,0 +1,317 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+func newPromotionService(promotionRepo *MockPromotionRepository) service.PromotionService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return service.NewPromotionServiceImpl(
+		promotionRepo,
+		validate,
+	)
+}
+
+func promotionCart() *model.Cart {
+	return &model.Cart{
+		UserID: 1,
+		Cards: []model.CartSlot{
+			{CardID: 1, Amount: 2},
+			{CardID: 2, Amount: 3},
+		},
+	}
+}
+
+func promotionCards(cardRepo *MockCardRepository) {
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Name: "Lightning Bolt", Price: 1000, InStockAmount: 10, ExpansionID: "LEA", CardTypeID: "INS"})
+	cardRepo.On("FindById", uint(2)).Return(&model.Card{Name: "Llanowar Elves", Price: 200, InStockAmount: 10, ExpansionID: "M19", CardTypeID: "CRE"})
+}
+
+func Test_Promotion_ShouldApplyAutomaticPercentageToExpansion(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	pricer := newCartPricerWithPromotions(cardRepo, promotionRepo)
+
+	promotionCards(cardRepo)
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{{
+		Description: "Alpha sale",
+		Kind:        model.PromotionPercentage,
+		Percentage:  25,
+		ExpansionID: strPtr("LEA"),
+		Active:      true,
+	}})
+
+	// act
+	cart := pricer.Price(promotionCart())
+
+	// assert
+	assert.Equal(t, model.Money(2600), cart.Subtotal)
+	assert.Equal(t, model.Money(500), cart.Discount)
+	assert.Len(t, cart.Promotions, 1)
+	assert.Equal(t, "Alpha sale", cart.Promotions[0].Description)
+	assert.Equal(t, model.Money(2650), cart.Total)
+}
+
+func Test_Promotion_ShouldApplyBuyXGetY(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	pricer := newCartPricerWithPromotions(cardRepo, promotionRepo)
+
+	promotionCards(cardRepo)
+	// 10, 10, 2 | 2, 2 -> the 2 of the only full group is free
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{{
+		Description: "Buy 2 get 1",
+		Kind:        model.PromotionBuyXGetY,
+		BuyAmount:   2,
+		FreeAmount:  1,
+		Active:      true,
+	}})
+
+	// act
+	cart := pricer.Price(promotionCart())
+
+	// assert
+	assert.Equal(t, model.Money(200), cart.Discount)
+}
+
+func Test_Promotion_ShouldApplyBuyXGetYToLargeAmounts(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	pricer := newCartPricerWithPromotions(cardRepo, promotionRepo)
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{Name: "Lightning Bolt", Price: 1000, InStockAmount: 2000000})
+	cardRepo.On("FindById", uint(2)).Return(&model.Card{Name: "Llanowar Elves", Price: 200, InStockAmount: 10})
+	// 1000001 bolts and 3 elves, the last bolt starts a group that ends with a free elf
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{{
+		Description: "Buy 2 get 1",
+		Kind:        model.PromotionBuyXGetY,
+		BuyAmount:   2,
+		FreeAmount:  1,
+		Active:      true,
+	}})
+
+	// act
+	cart := pricer.Price(&model.Cart{
+		UserID: 1,
+		Cards: []model.CartSlot{
+			{CardID: 2, Amount: 3},
+			{CardID: 1, Amount: 1000001},
+		},
+	})
+
+	// assert
+	assert.Equal(t, model.Money(333333*1000+200), cart.Discount)
+}
+
+func Test_Promotion_ShouldStackCouponWithoutExceedingSubtotal(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	pricer := newCartPricerWithPromotions(cardRepo, promotionRepo)
+	cart := promotionCart()
+	cart.PromotionCode = strPtr("big")
+	coupon := &model.Promotion{
+		Code:        strPtr("BIG"),
+		Description: "Big coupon",
+		Kind:        model.PromotionFixed,
+		Amount:      5000,
+		Active:      true,
+	}
+	coupon.ID = 2
+
+	promotionCards(cardRepo)
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{{
+		Description: "Creature sale",
+		Kind:        model.PromotionPercentage,
+		Percentage:  50,
+		CardTypeID:  strPtr("CRE"),
+		Active:      true,
+	}})
+	promotionRepo.On("FindByCode", "big").Return(coupon)
+
+	// act
+	result := pricer.Price(cart)
+
+	// assert
+	assert.Len(t, result.Promotions, 2)
+	assert.Equal(t, model.Money(300), result.Promotions[0].Discount)
+	assert.Equal(t, "BIG", result.Promotions[1].Code)
+	assert.Equal(t, model.Money(2300), result.Promotions[1].Discount)
+	assert.Equal(t, model.Money(2600), result.Discount)
+	assert.Equal(t, "big", *result.PromotionCode)
+}
+
+func Test_Promotion_ShouldNotApplyOutsideWindowOrOverLimit(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	promotionRepo := newMockPromotionRepository()
+	pricer := newCartPricerWithPromotions(cardRepo, promotionRepo)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	limited := &model.Promotion{
+		Description:           "Once per user",
+		Kind:                  model.PromotionPercentage,
+		Percentage:            10,
+		MaxRedemptionsPerUser: 1,
+		Active:                true,
+	}
+	limited.ID = 3
+
+	promotionCards(cardRepo)
+	promotionRepo.On("FindAutomatic").Return([]*model.Promotion{
+		{
+			Description: "Next week",
+			Kind:        model.PromotionPercentage,
+			Percentage:  10,
+			StartsAt:    &tomorrow,
+			Active:      true,
+		},
+		limited,
+	})
+	promotionRepo.On("CountUserRedemptions", uint(3), uint(1)).Return(int64(1))
+
+	// act
+	cart := pricer.Price(promotionCart())
+
+	// assert
+	assert.Empty(t, cart.Promotions)
+	assert.Equal(t, model.Money(0), cart.Discount)
+}
+
+func Test_Promotion_ShouldCreate(t *testing.T) {
+	// arrange
+	promotionRepo := newMockPromotionRepository()
+	service := newPromotionService(promotionRepo)
+
+	promotionRepo.On("FindByCode", "summer10").Return(nil)
+	promotionRepo.On("Save", mock.MatchedBy(func(p *model.Promotion) bool {
+		return *p.Code == "SUMMER10" && p.Kind == model.PromotionPercentage
+	})).Return(nil)
+	promotionRepo.On("CountRedemptions", mock.Anything).Return(int64(0))
+
+	// act
+	result, err := service.Create(&dto.PostPromotion{
+		Code:        strPtr("summer10"),
+		Description: "Summer sale",
+		Kind:        "percentage",
+		Percentage:  10,
+		Active:      true,
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "SUMMER10", *result.Code)
+	promotionRepo.AssertExpectations(t)
+}
+
+func Test_Promotion_ShouldNotCreateWithoutKindValues(t *testing.T) {
+	// arrange
+	promotionRepo := newMockPromotionRepository()
+	service := newPromotionService(promotionRepo)
+
+	// act
+	result, err := service.Create(&dto.PostPromotion{
+		Description: "Buy some get some",
+		Kind:        "buyXGetY",
+		BuyAmount:   2,
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	promotionRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Promotion_ShouldNotCreateCodeTaken(t *testing.T) {
+	// arrange
+	promotionRepo := newMockPromotionRepository()
+	s := newPromotionService(promotionRepo)
+	existing := &model.Promotion{Code: strPtr("SUMMER10")}
+	existing.ID = 4
+
+	promotionRepo.On("FindByCode", "summer10").Return(existing)
+
+	// act
+	result, err := s.Create(&dto.PostPromotion{
+		Code:        strPtr("summer10"),
+		Description: "Summer sale",
+		Kind:        "fixed",
+		Amount:      500,
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrPromotionCodeTaken, err)
+}
+
+func Test_Promotion_ShouldNotCreateEndingBeforeStart(t *testing.T) {
+	// arrange
+	promotionRepo := newMockPromotionRepository()
+	s := newPromotionService(promotionRepo)
+	start := time.Now()
+	end := start.Add(-time.Hour)
+
+	// act
+	result, err := s.Create(&dto.PostPromotion{
+		Description: "Backwards",
+		Kind:        "fixed",
+		Amount:      500,
+		StartsAt:    &start,
+		EndsAt:      &end,
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrPromotionWindow, err)
+}
+
+func Test_Promotion_ShouldUpdateKeepingId(t *testing.T) {
+	// arrange
+	promotionRepo := newMockPromotionRepository()
+	service := newPromotionService(promotionRepo)
+	existing := &model.Promotion{Description: "old"}
+	existing.ID = 5
+
+	promotionRepo.On("FindById", uint(5)).Return(existing)
+	promotionRepo.On("Update", mock.MatchedBy(func(p *model.Promotion) bool {
+		return p.ID == 5 && p.Description == "new"
+	})).Return(nil)
+	promotionRepo.On("CountRedemptions", uint(5)).Return(int64(2))
+
+	// act
+	result, err := service.Update(5, &dto.PostPromotion{
+		Description: "new",
+		Kind:        "fixed",
+		Amount:      500,
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.Redemptions)
+}
+
+func Test_Promotion_ShouldNotGetRedemptionsNotFound(t *testing.T) {
+	// arrange
+	promotionRepo := newMockPromotionRepository()
+	s := newPromotionService(promotionRepo)
+
+	promotionRepo.On("FindById", uint(5)).Return(nil)
+
+	// act
+	result, err := s.Redemptions(5)
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrPromotionNotFound, err)
+}
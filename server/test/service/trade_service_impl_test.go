@@ -0,0 +1,298 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/repository"
+	"store.api/service"
+)
+
+func newTradeService(tradeRepo *MockTradeRepository, collectionRepo *MockCollectionRepository, userRepo *MockUserRepository) service.TradeService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return service.NewTradeServiceImpl(
+		tradeRepo,
+		collectionRepo,
+		userRepo,
+		validate,
+	)
+}
+
+func newTradeCollections(collectionRepo *MockCollectionRepository) {
+	collectionRepo.On("FindById", uint(1)).Return(&model.Collection{
+		Model:   gorm.Model{ID: 1},
+		OwnerID: 1,
+		Cards:   []model.CollectionSlot{{CardID: 10, Amount: 2}},
+	})
+	collectionRepo.On("FindById", uint(2)).Return(&model.Collection{
+		Model:      gorm.Model{ID: 2},
+		OwnerID:    2,
+		Visibility: model.VisibilityPublic,
+		Cards:      []model.CollectionSlot{{CardID: 20, Amount: 1}},
+	})
+}
+
+func Test_Trade_ShouldPropose(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	collectionRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, collectionRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	newTradeCollections(collectionRepo)
+	tradeRepo.On("Save", mock.Anything).Return(nil)
+	tradeRepo.On("FindById", uint(0)).Return(&model.Trade{
+		ProposerID:  1,
+		RecipientID: 2,
+		Status:      model.TradeProposed,
+		Lines: []model.TradeLine{
+			{CardID: 10, Amount: 2, Offered: true},
+			{CardID: 20, Amount: 1},
+		},
+	})
+
+	// act
+	trade, err := tradeService.Propose(&dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 2,
+		Offered:               []dto.TradeCard{{CardId: 10, Amount: 2}},
+		Requested:             []dto.TradeCard{{CardId: 20, Amount: 1}},
+	}, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, trade.Offered, 1)
+	assert.Len(t, trade.Requested, 1)
+	saved := tradeRepo.Calls[0].Arguments.Get(0).(*model.Trade)
+	assert.Equal(t, uint(2), saved.RecipientID)
+	assert.Equal(t, model.TradeProposed, saved.Status)
+	assert.True(t, saved.Lines[0].Offered)
+	assert.False(t, saved.Lines[1].Offered)
+}
+
+func Test_Trade_ShouldNotProposeUnverified(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}})
+
+	// act
+	_, err := tradeService.Propose(&dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 2,
+		Offered:               []dto.TradeCard{{CardId: 10, Amount: 1}},
+	}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrNotVerified, err)
+	tradeRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Trade_ShouldNotProposeForPrivateCollection(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	collectionRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, collectionRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	collectionRepo.On("FindById", uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, OwnerID: 1})
+	collectionRepo.On("FindById", uint(3)).Return(&model.Collection{Model: gorm.Model{ID: 3}, OwnerID: 2})
+
+	// act
+	_, err := tradeService.Propose(&dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 3,
+		Requested:             []dto.TradeCard{{CardId: 20, Amount: 1}},
+	}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrCollectionNotFound, err)
+}
+
+func Test_Trade_ShouldNotProposeMissingCards(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	collectionRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, collectionRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	newTradeCollections(collectionRepo)
+
+	// act
+	_, err := tradeService.Propose(&dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 2,
+		Requested:             []dto.TradeCard{{CardId: 20, Amount: 2}},
+	}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrTradeUnavailable, err)
+	tradeRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Trade_ShouldNotProposeDuplicateCards(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	collectionRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, collectionRepo, userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	newTradeCollections(collectionRepo)
+
+	// act
+	_, err := tradeService.Propose(&dto.PostTrade{
+		CollectionId:          1,
+		RecipientCollectionId: 2,
+		Offered:               []dto.TradeCard{{CardId: 10, Amount: 1}, {CardId: 10, Amount: 1}},
+	}, 1)
+
+	// assert
+	assert.Equal(t, service.ErrTradeInvalid, err)
+}
+
+func Test_Trade_ShouldCounterWithRolesSwapped(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	collectionRepo := newMockCollectionRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, collectionRepo, userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	newTradeCollections(collectionRepo)
+	trade := &model.Trade{
+		Model:                 gorm.Model{ID: 5},
+		ProposerID:            1,
+		ProposerCollectionID:  1,
+		RecipientID:           2,
+		RecipientCollectionID: 2,
+		Status:                model.TradeProposed,
+	}
+	tradeRepo.On("FindById", uint(5)).Return(trade)
+	tradeRepo.On("Counter", trade, mock.Anything).Return(nil)
+	tradeRepo.On("FindById", uint(0)).Return(&model.Trade{ProposerID: 2, RecipientID: 1})
+
+	// act
+	_, err := tradeService.Counter(5, &dto.PostCounterTrade{
+		Offered:   []dto.TradeCard{{CardId: 20, Amount: 1}},
+		Requested: []dto.TradeCard{{CardId: 10, Amount: 1}},
+	}, 2)
+
+	// assert
+	assert.Nil(t, err)
+	counter := tradeRepo.Calls[1].Arguments.Get(1).(*model.Trade)
+	assert.Equal(t, uint(2), counter.ProposerID)
+	assert.Equal(t, uint(2), counter.ProposerCollectionID)
+	assert.Equal(t, uint(1), counter.RecipientID)
+	assert.Equal(t, uint(1), counter.RecipientCollectionID)
+}
+
+func Test_Trade_ShouldAccept(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	trade := &model.Trade{Model: gorm.Model{ID: 5}, ProposerID: 1, RecipientID: 2, Status: model.TradeProposed}
+	tradeRepo.On("FindById", uint(5)).Return(trade)
+	tradeRepo.On("Accept", trade).Return(nil)
+
+	// act
+	_, err := tradeService.Accept(5, 2)
+
+	// assert
+	assert.Nil(t, err)
+	tradeRepo.AssertExpectations(t)
+}
+
+func Test_Trade_ShouldNotAcceptWithoutCards(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), userRepo)
+
+	userRepo.On("FindById", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Verified: true})
+	trade := &model.Trade{Model: gorm.Model{ID: 5}, ProposerID: 1, RecipientID: 2, Status: model.TradeProposed}
+	tradeRepo.On("FindById", uint(5)).Return(trade)
+	tradeRepo.On("Accept", trade).Return(repository.ErrTradeUnavailable)
+
+	// act
+	_, err := tradeService.Accept(5, 2)
+
+	// assert
+	assert.Equal(t, service.ErrTradeUnavailable, err)
+}
+
+func Test_Trade_ShouldNotAcceptOwnProposal(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	userRepo := newMockUserRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Verified: true})
+	tradeRepo.On("FindById", uint(5)).Return(&model.Trade{Model: gorm.Model{ID: 5}, ProposerID: 1, RecipientID: 2, Status: model.TradeProposed})
+
+	// act
+	_, err := tradeService.Accept(5, 1)
+
+	// assert
+	assert.Equal(t, service.ErrTradeForbidden, err)
+	tradeRepo.AssertNotCalled(t, "Accept", mock.Anything)
+}
+
+func Test_Trade_ShouldNotDeclineClosed(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), newMockUserRepository())
+
+	tradeRepo.On("FindById", uint(5)).Return(&model.Trade{Model: gorm.Model{ID: 5}, ProposerID: 1, RecipientID: 2, Status: model.TradeCancelled})
+
+	// act
+	_, err := tradeService.Decline(5, 2)
+
+	// assert
+	assert.Equal(t, service.ErrTradeClosed, err)
+}
+
+func Test_Trade_ShouldNotFindOtherUsersTrade(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), newMockUserRepository())
+
+	tradeRepo.On("FindById", uint(5)).Return(&model.Trade{Model: gorm.Model{ID: 5}, ProposerID: 1, RecipientID: 2})
+
+	// act
+	_, err := tradeService.ById(5, 3)
+
+	// assert
+	assert.Equal(t, service.ErrTradeNotFound, err)
+}
+
+func Test_Trade_ShouldCancel(t *testing.T) {
+	// arrange
+	tradeRepo := newMockTradeRepository()
+	tradeService := newTradeService(tradeRepo, newMockCollectionRepository(), newMockUserRepository())
+
+	trade := &model.Trade{Model: gorm.Model{ID: 5}, ProposerID: 1, RecipientID: 2, Status: model.TradeProposed}
+	tradeRepo.On("FindById", uint(5)).Return(trade)
+	tradeRepo.On("Close", trade, model.TradeCancelled).Return(nil)
+
+	// act
+	_, err := tradeService.Cancel(5, 1)
+
+	// assert
+	assert.Nil(t, err)
+	tradeRepo.AssertExpectations(t)
+}
@@ -2,19 +2,47 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"store.api/config"
+	"store.api/dto"
 	"store.api/model"
+	"store.api/query"
+	"store.api/security"
 	"store.api/service"
 )
 
 func newUserService(userRepo *MockUserRepository) service.UserService {
+	return newUserServiceWithRepos(userRepo, newMockCollectionRepository(), newMockCartRepository(), newMockWishlistRepository(), newMockOrderRepository(), newMockLanguageRepository(), newMockMailer())
+}
+
+func newUserServiceWithRepos(userRepo *MockUserRepository, colRepo *MockCollectionRepository, cartRepo *MockCartRepository, wishlistRepo *MockWishlistRepository, orderRepo *MockOrderRepository, langRepo *MockLanguageRepository, mailer *MockMailer) service.UserService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
 	return service.NewUserServiceImpl(
+		&config.Configuration{
+			Account: config.AccountConfiguration{
+				EmailTokenTtl: 60,
+			},
+		},
 		userRepo,
+		colRepo,
+		cartRepo,
+		wishlistRepo,
+		orderRepo,
+		langRepo,
+		mailer,
+		validate,
 	)
 }
 
+func strPtr(s string) *string {
+	return &s
+}
+
 func Test_User_ShouldGetById(t *testing.T) {
 	// arrange
 	userRepo := newMockUserRepository()
@@ -44,3 +72,370 @@ func Test_User_ShouldNotGetById(t *testing.T) {
 	assert.Nil(t, user)
 	assert.Equal(t, service.ErrUserNotFound, err)
 }
+
+func Test_User_ShouldUpdateProfile(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	langRepo := newMockLanguageRepository()
+	service := newUserServiceWithRepos(userRepo, newMockCollectionRepository(), newMockCartRepository(), newMockWishlistRepository(), newMockOrderRepository(), langRepo, newMockMailer())
+	user := &model.User{DisplayName: "old", PreferredCurrency: "EUR"}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("Update", user).Return(nil)
+	langRepo.On("All").Return([]*model.Language{{ID: "ENG"}})
+
+	// act
+	result, err := service.UpdateProfile(1, &dto.PatchProfile{
+		DisplayName:         strPtr("new"),
+		PreferredLanguageId: strPtr("ENG"),
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "new", result.DisplayName)
+	assert.Equal(t, "EUR", result.PreferredCurrency)
+	assert.Equal(t, "ENG", *result.PreferredLanguageId)
+}
+
+func Test_User_ShouldNotUpdateProfileUnknownLanguage(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	langRepo := newMockLanguageRepository()
+	s := newUserServiceWithRepos(userRepo, newMockCollectionRepository(), newMockCartRepository(), newMockWishlistRepository(), newMockOrderRepository(), langRepo, newMockMailer())
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+	langRepo.On("All").Return([]*model.Language{{ID: "ENG"}})
+
+	// act
+	result, err := s.UpdateProfile(1, &dto.PatchProfile{
+		PreferredLanguageId: strPtr("XYZ"),
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrUnknownLanguage, err)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func Test_User_ShouldNotUpdateProfileInvalidCurrency(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	service := newUserService(userRepo)
+
+	// act
+	result, err := service.UpdateProfile(1, &dto.PatchProfile{
+		PreferredCurrency: strPtr("euros"),
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+}
+
+func Test_User_ShouldRequestEmailChange(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	service := newUserServiceWithRepos(userRepo, newMockCollectionRepository(), newMockCartRepository(), newMockWishlistRepository(), newMockOrderRepository(), newMockLanguageRepository(), mailer)
+	user := &model.User{Email: "old@mail.com", Verified: true}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("FindByEmail", "new@mail.com").Return(nil)
+	userRepo.On("Update", user).Return(nil)
+	mailer.On("Send", "new@mail.com", mock.Anything, mock.Anything).Return(nil)
+
+	// act
+	err := service.RequestEmailChange(1, &dto.EmailChange{Email: "new@mail.com"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "old@mail.com", user.Email)
+	assert.Equal(t, "new@mail.com", user.PendingEmail)
+	assert.NotEmpty(t, user.EmailTokenHash)
+	mailer.AssertExpectations(t)
+}
+
+func Test_User_ShouldNotRequestEmailChangeTaken(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+	user := &model.User{Email: "old@mail.com", Verified: true}
+	user.ID = 1
+	other := &model.User{Email: "new@mail.com", Verified: true}
+	other.ID = 2
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("FindByEmail", "new@mail.com").Return(other)
+
+	// act
+	err := s.RequestEmailChange(1, &dto.EmailChange{Email: "new@mail.com"})
+
+	// assert
+	assert.Equal(t, service.ErrEmailTaken, err)
+}
+
+func Test_User_ShouldVerifyEmail(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	service := newUserService(userRepo)
+	expires := time.Now().Add(time.Minute)
+	user := &model.User{
+		Email:               "old@mail.com",
+		PendingEmail:        "new@mail.com",
+		EmailTokenHash:      security.HashToken("token"),
+		EmailTokenExpiresAt: &expires,
+	}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("FindByEmail", "new@mail.com").Return(nil)
+	userRepo.On("Update", user).Return(nil)
+
+	// act
+	result, err := service.VerifyEmail(1, &dto.EmailVerification{Token: "token"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "new@mail.com", result.Email)
+	assert.True(t, result.Verified)
+	assert.Empty(t, user.EmailTokenHash)
+}
+
+func Test_User_ShouldNotVerifyEmailExpiredToken(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+	expires := time.Now().Add(-time.Minute)
+	user := &model.User{
+		Email:               "old@mail.com",
+		PendingEmail:        "new@mail.com",
+		EmailTokenHash:      security.HashToken("token"),
+		EmailTokenExpiresAt: &expires,
+	}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+
+	// act
+	result, err := s.VerifyEmail(1, &dto.EmailVerification{Token: "token"})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrInvalidEmailToken, err)
+	assert.Equal(t, "old@mail.com", user.Email)
+}
+
+func Test_User_ShouldNotVerifyEmailWrongToken(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+	expires := time.Now().Add(time.Minute)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{
+		PendingEmail:        "new@mail.com",
+		EmailTokenHash:      security.HashToken("token"),
+		EmailTokenExpiresAt: &expires,
+	})
+
+	// act
+	result, err := s.VerifyEmail(1, &dto.EmailVerification{Token: "other"})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrInvalidEmailToken, err)
+}
+
+func Test_User_ShouldExport(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	colRepo := newMockCollectionRepository()
+	cartRepo := newMockCartRepository()
+	wishlistRepo := newMockWishlistRepository()
+	orderRepo := newMockOrderRepository()
+	service := newUserServiceWithRepos(userRepo, colRepo, cartRepo, wishlistRepo, orderRepo, newMockLanguageRepository(), newMockMailer())
+	col := &model.Collection{Name: "collection"}
+	col.ID = 3
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{Username: "user"})
+	colRepo.On("FindByOwnerId", uint(1)).Return([]*model.Collection{col})
+	colRepo.On("FindById", uint(3)).Return(&model.Collection{Name: "collection", Cards: []model.CollectionSlot{{Amount: 2}}})
+	cartRepo.On("FindSingleByUserId", uint(1)).Return(&model.Cart{Cards: []model.CartSlot{{Amount: 1}}})
+	wishlistRepo.On("FindByUserId", uint(1)).Return([]*model.Wishlist{{CardKeyID: strPtr("key")}})
+	orderRepo.On("FindByUserId", uint(1)).Return([]*model.Order{{Total: 10, Lines: []model.OrderLine{{CardID: 2, Amount: 1}}}})
+
+	// act
+	result, err := service.Export(1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "user", result.Profile.Username)
+	assert.Len(t, result.Collections, 1)
+	assert.Len(t, result.Collections[0].Cards, 1)
+	assert.Len(t, result.Cart.Cards, 1)
+	assert.Len(t, result.Wishlist, 1)
+	assert.Len(t, result.Orders, 1)
+	assert.Len(t, result.Orders[0].Lines, 1)
+}
+
+func Test_User_ShouldDelete(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	service := newUserService(userRepo)
+	hash, _ := security.HashPassword("password")
+	user := &model.User{PasswordHash: hash}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("Anonymise", user).Return(nil)
+
+	// act
+	err := service.Delete(1, &dto.AccountDeletion{Password: "password"}, time.Time{})
+
+	// assert
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_User_ShouldDeleteWithoutPasswordAfterRecentLogin(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+	user := &model.User{}
+
+	userRepo.On("FindById", uint(1)).Return(user)
+	userRepo.On("Anonymise", user).Return(nil)
+
+	// act
+	err := s.Delete(1, &dto.AccountDeletion{}, time.Now().Add(-time.Minute))
+
+	// assert
+	assert.Nil(t, err)
+	userRepo.AssertExpectations(t)
+}
+
+func Test_User_ShouldNotDeleteWithoutPasswordAfterOldLogin(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{})
+
+	// act
+	err := s.Delete(1, &dto.AccountDeletion{}, time.Now().Add(-time.Hour))
+
+	// assert
+	assert.Equal(t, service.ErrLoginTooOld, err)
+	userRepo.AssertNotCalled(t, "Anonymise", mock.Anything)
+}
+
+func Test_User_ShouldNotDeleteIncorrectPassword(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+	hash, _ := security.HashPassword("password")
+
+	userRepo.On("FindById", uint(1)).Return(&model.User{PasswordHash: hash})
+
+	// act
+	err := s.Delete(1, &dto.AccountDeletion{Password: "wrong"}, time.Now())
+
+	// assert
+	assert.Equal(t, service.ErrIncorrectPassword, err)
+	userRepo.AssertNotCalled(t, "Anonymise", mock.Anything)
+}
+
+func Test_User_ShouldQuery(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	service := newUserService(userRepo)
+
+	userRepo.On("Query", mock.Anything).Return([]*model.User{{Username: "user"}}, int64(1))
+
+	// act
+	result := service.Query(&query.UserQuery{Search: "us", Page: 1})
+
+	// assert
+	assert.Len(t, result.Users, 1)
+	assert.Equal(t, int64(1), result.TotalCount)
+}
+
+func Test_User_ShouldSuspend(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	service := newUserService(userRepo)
+	user := &model.User{}
+	until := time.Now().Add(time.Hour)
+
+	userRepo.On("FindById", uint(2)).Return(user)
+	userRepo.On("Update", user).Return(nil)
+
+	// act
+	result, err := service.Suspend(1, 2, &dto.Suspension{Reason: "spam", Until: &until})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, result.Suspended)
+	assert.True(t, user.IsSuspended(time.Now()))
+	assert.False(t, user.IsSuspended(until.Add(time.Second)))
+}
+
+func Test_User_ShouldNotSuspendSelf(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+
+	// act
+	result, err := s.Suspend(1, 1, &dto.Suspension{Reason: "spam"})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrCannotModifySelf, err)
+}
+
+func Test_User_ShouldNotDemoteSelf(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	s := newUserService(userRepo)
+
+	// act
+	result, err := s.SetAdmin(1, 1, false)
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrCannotModifySelf, err)
+}
+
+func Test_User_ShouldPromote(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	service := newUserService(userRepo)
+	user := &model.User{}
+
+	userRepo.On("FindById", uint(2)).Return(user)
+	userRepo.On("Update", user).Return(nil)
+
+	// act
+	result, err := service.SetAdmin(1, 2, true)
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, result.IsAdmin)
+}
+
+func Test_User_ShouldForcePasswordReset(t *testing.T) {
+	// arrange
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	service := newUserServiceWithRepos(userRepo, newMockCollectionRepository(), newMockCartRepository(), newMockWishlistRepository(), newMockOrderRepository(), newMockLanguageRepository(), mailer)
+	user := &model.User{Email: "mail@mail.com"}
+
+	userRepo.On("FindById", uint(2)).Return(user)
+	userRepo.On("Update", user).Return(nil)
+	mailer.On("Send", "mail@mail.com", mock.Anything, mock.Anything).Return(nil)
+
+	// act
+	result, err := service.ForcePasswordReset(2)
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, result.PasswordReset)
+	assert.NotEmpty(t, user.PasswordResetTokenHash)
+	mailer.AssertExpectations(t)
+}
@@ -0,0 +1,275 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"store.api/dto"
+	"store.api/model"
+	"store.api/service"
+)
+
+func newWishlistService(wishlistRepo *MockWishlistRepository, cardRepo *MockCardRepository, cardKeyRepo *MockCardKeyRepository) service.WishlistService {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return service.NewWishlistServiceImpl(
+		wishlistRepo,
+		cardRepo,
+		cardKeyRepo,
+		validate,
+	)
+}
+
+func moneyPtr(m model.Money) *model.Money {
+	return &m
+}
+
+func uintPtr(u uint) *uint {
+	return &u
+}
+
+func Test_Wishlist_ShouldAddCardKey(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	cardKeyRepo := newMockCardKeyRepository()
+	service := newWishlistService(wishlistRepo, newMockCardRepository(), cardKeyRepo)
+
+	cardKeyRepo.On("FindById", "bolt").Return(&model.CardKey{ID: "bolt"})
+	wishlistRepo.On("FindByUserId", uint(1)).Return([]*model.Wishlist{{CardID: uintPtr(2)}})
+	wishlistRepo.On("Save", mock.MatchedBy(func(w *model.Wishlist) bool {
+		return w.UserID == 1 && *w.CardKeyID == "bolt" && *w.MaxPrice == 200 && w.MinCondition == model.ConditionLightlyPlayed
+	})).Return(nil)
+
+	// act
+	result, err := service.Add(1, &dto.PostWishlist{
+		CardKeyId:    strPtr("bolt"),
+		MaxPrice:     moneyPtr(200),
+		MinCondition: "LP",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "bolt", *result.CardKeyId)
+	wishlistRepo.AssertExpectations(t)
+}
+
+func Test_Wishlist_ShouldNotAddCardAndCardKey(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	service := newWishlistService(wishlistRepo, newMockCardRepository(), newMockCardKeyRepository())
+
+	// act
+	result, err := service.Add(1, &dto.PostWishlist{
+		CardId:    uintPtr(1),
+		CardKeyId: strPtr("bolt"),
+	})
+
+	// assert
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	wishlistRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func Test_Wishlist_ShouldNotAddUnknownCard(t *testing.T) {
+	// arrange
+	cardRepo := newMockCardRepository()
+	s := newWishlistService(newMockWishlistRepository(), cardRepo, newMockCardKeyRepository())
+
+	cardRepo.On("FindById", uint(1)).Return(nil)
+
+	// act
+	result, err := s.Add(1, &dto.PostWishlist{CardId: uintPtr(1)})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrCardNotFound, err)
+}
+
+func Test_Wishlist_ShouldNotAddTwice(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	cardRepo := newMockCardRepository()
+	s := newWishlistService(wishlistRepo, cardRepo, newMockCardKeyRepository())
+
+	cardRepo.On("FindById", uint(1)).Return(&model.Card{})
+	wishlistRepo.On("FindByUserId", uint(1)).Return([]*model.Wishlist{{CardID: uintPtr(1)}})
+
+	// act
+	result, err := s.Add(1, &dto.PostWishlist{CardId: uintPtr(1)})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrAlreadyWished, err)
+}
+
+func Test_Wishlist_ShouldUpdate(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	service := newWishlistService(wishlistRepo, newMockCardRepository(), newMockCardKeyRepository())
+	existing := &model.Wishlist{UserID: 1, MaxPrice: moneyPtr(200), MinCondition: model.ConditionNearMint}
+
+	wishlistRepo.On("FindById", uint(3)).Return(existing)
+	wishlistRepo.On("Update", existing).Return(nil)
+
+	// act
+	result, err := service.Update(1, 3, &dto.PatchWishlist{
+		MaxPrice:     moneyPtr(0),
+		MinCondition: strPtr(""),
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, result.MaxPrice)
+	assert.Equal(t, "", result.MinCondition)
+}
+
+func Test_Wishlist_ShouldNotUpdateOtherUsers(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	s := newWishlistService(wishlistRepo, newMockCardRepository(), newMockCardKeyRepository())
+
+	wishlistRepo.On("FindById", uint(3)).Return(&model.Wishlist{UserID: 2})
+
+	// act
+	result, err := s.Update(1, 3, &dto.PatchWishlist{MaxPrice: moneyPtr(100)})
+
+	// assert
+	assert.Nil(t, result)
+	assert.Equal(t, service.ErrWishlistNotFound, err)
+	wishlistRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func Test_Wishlist_ShouldDelete(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	service := newWishlistService(wishlistRepo, newMockCardRepository(), newMockCardKeyRepository())
+
+	wishlistRepo.On("FindById", uint(3)).Return(&model.Wishlist{UserID: 1})
+	wishlistRepo.On("Delete", uint(3)).Return(nil)
+
+	// act
+	err := service.Delete(1, 3)
+
+	// assert
+	assert.Nil(t, err)
+	wishlistRepo.AssertExpectations(t)
+}
+
+func wishedCard(price model.Money, amount uint) *model.Card {
+	return &model.Card{
+		Model:         gorm.Model{ID: 1},
+		Name:          "Lightning Bolt",
+		CardKeyID:     "bolt",
+		Price:         price,
+		InStockAmount: amount,
+		Condition:     model.ConditionNearMint,
+	}
+}
+
+func Test_WishlistNotifier_ShouldNotifyRestock(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	notifier := service.NewWishlistNotifier(wishlistRepo, userRepo, mailer)
+	card := wishedCard(200, 3)
+
+	wishlistRepo.On("FindForCard", card).Return([]*model.Wishlist{
+		{UserID: 1, CardKeyID: strPtr("bolt")},
+		// too expensive
+		{UserID: 2, CardKeyID: strPtr("bolt"), MaxPrice: moneyPtr(100)},
+		// condition too bad
+		{UserID: 3, CardID: uintPtr(1), MinCondition: model.ConditionMint},
+	})
+	userRepo.On("FindById", uint(1)).Return(&model.User{Username: "user", Email: "user@mail.com"})
+	mailer.On("Send", "user@mail.com", "Back in stock", mock.Anything).Return(nil)
+
+	// act
+	notifier.StockChanged(card, 0)
+
+	// assert
+	mailer.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func Test_WishlistNotifier_ShouldNotNotifyStillInStock(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	mailer := newMockMailer()
+	notifier := service.NewWishlistNotifier(wishlistRepo, newMockUserRepository(), mailer)
+
+	// act
+	notifier.StockChanged(wishedCard(200, 3), 1)
+	notifier.StockChanged(wishedCard(200, 0), 1)
+
+	// assert
+	wishlistRepo.AssertNotCalled(t, "FindForCard", mock.Anything)
+	mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_WishlistNotifier_ShouldNotifyPriceDrop(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	notifier := service.NewWishlistNotifier(wishlistRepo, userRepo, mailer)
+	card := wishedCard(200, 3)
+
+	wishlistRepo.On("FindForCard", card).Return([]*model.Wishlist{
+		{UserID: 1, CardKeyID: strPtr("bolt"), MaxPrice: moneyPtr(250)},
+		// no target price
+		{UserID: 2, CardKeyID: strPtr("bolt")},
+		// already below the target before the drop
+		{UserID: 3, CardKeyID: strPtr("bolt"), MaxPrice: moneyPtr(400)},
+		// still above the target
+		{UserID: 4, CardKeyID: strPtr("bolt"), MaxPrice: moneyPtr(100)},
+	})
+	userRepo.On("FindById", uint(1)).Return(&model.User{Username: "user", Email: "user@mail.com"})
+	mailer.On("Send", "user@mail.com", "Price drop", mock.Anything).Return(nil)
+
+	// act
+	notifier.PriceChanged(card, 300)
+
+	// assert
+	mailer.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func Test_WishlistNotifier_ShouldNotNotifyPriceDropOutOfStock(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	mailer := newMockMailer()
+	notifier := service.NewWishlistNotifier(wishlistRepo, newMockUserRepository(), mailer)
+
+	// act
+	notifier.PriceChanged(wishedCard(200, 0), 300)
+
+	// assert
+	wishlistRepo.AssertNotCalled(t, "FindForCard", mock.Anything)
+}
+
+func Test_WishlistNotifier_ShouldIgnoreMailFailure(t *testing.T) {
+	// arrange
+	wishlistRepo := newMockWishlistRepository()
+	userRepo := newMockUserRepository()
+	mailer := newMockMailer()
+	notifier := service.NewWishlistNotifier(wishlistRepo, userRepo, mailer)
+	card := wishedCard(200, 3)
+
+	wishlistRepo.On("FindForCard", card).Return([]*model.Wishlist{
+		{UserID: 1, CardKeyID: strPtr("bolt")},
+		{UserID: 2, CardKeyID: strPtr("bolt")},
+	})
+	userRepo.On("FindById", uint(1)).Return(&model.User{Email: "user1@mail.com"})
+	userRepo.On("FindById", uint(2)).Return(&model.User{Email: "user2@mail.com"})
+	mailer.On("Send", "user1@mail.com", mock.Anything, mock.Anything).Return(errors.New("relay down"))
+	mailer.On("Send", "user2@mail.com", mock.Anything, mock.Anything).Return(nil)
+
+	// act
+	notifier.StockChanged(card, 0)
+
+	// assert
+	mailer.AssertExpectations(t)
+}